@@ -0,0 +1,111 @@
+package gogobot
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale is a language range from an Accept-Language header
+type Locale struct {
+	// Tag is the language range as sent, e.g. "en-US" or "*"
+	Tag string `json:"tag"`
+	// Language is the lower-cased primary language subtag, e.g. "en"
+	Language string `json:"language"`
+	// Region is the upper-cased region subtag if present, e.g. "US"
+	Region string `json:"region,omitempty"`
+	// Quality is the q-value weight in the range (0, 1]
+	Quality float64 `json:"quality"`
+}
+
+// languageRangePattern matches an RFC 4647 basic language range
+var languageRangePattern = regexp.MustCompile(`^(?:\*|[a-zA-Z]{1,8}(?:-[a-zA-Z0-9]{1,8})*)$`)
+
+// ParseAcceptLanguage parses an Accept-Language header into locales ordered by
+// descending quality. Malformed entries and entries with q=0 are omitted.
+func ParseAcceptLanguage(header string) []Locale {
+	locales, _ := parseAcceptLanguage(header)
+	return locales
+}
+
+// parseAcceptLanguage parses an Accept-Language header and reports how many entries were malformed
+func parseAcceptLanguage(header string) ([]Locale, int) {
+	var locales []Locale
+	malformed := 0
+
+	for _, entry := range strings.Split(header, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		params := strings.Split(entry, ";")
+		tag := strings.TrimSpace(params[0])
+		if !languageRangePattern.MatchString(tag) {
+			malformed++
+			continue
+		}
+
+		quality := 1.0
+		valid := true
+		for _, param := range params[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(key) != "q" {
+				valid = false
+				break
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			quality = q
+		}
+		if !valid {
+			malformed++
+			continue
+		}
+		if quality == 0 {
+			continue
+		}
+
+		locale := Locale{Tag: tag, Quality: quality}
+		subtags := strings.Split(tag, "-")
+		locale.Language = strings.ToLower(subtags[0])
+		for _, subtag := range subtags[1:] {
+			// Region subtags are two letters or three digits
+			if len(subtag) == 2 || (len(subtag) == 3 && isDigit(subtag[0])) {
+				locale.Region = strings.ToUpper(subtag)
+				break
+			}
+		}
+		locales = append(locales, locale)
+	}
+
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].Quality > locales[j].Quality
+	})
+
+	return locales, malformed
+}
+
+// AcceptLanguagePlausibility scores how browser-like an Accept-Language header is,
+// from 0 (no evidence of a real browser) to 1 (typical browser header).
+// Empty headers, malformed lists, and a lone region-less language such as "en" are
+// weak bot signals: real browsers almost always send a regional locale.
+func AcceptLanguagePlausibility(header string) float64 {
+	if strings.TrimSpace(header) == "" {
+		return 0
+	}
+
+	locales, malformed := parseAcceptLanguage(header)
+	switch {
+	case malformed > 0 || len(locales) == 0:
+		return 0.2
+	case len(locales) == 1 && locales[0].Region == "":
+		return 0.5
+	default:
+		return 1
+	}
+}
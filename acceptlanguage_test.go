@@ -0,0 +1,67 @@
+package gogobot
+
+import (
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	locales := ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5")
+
+	expected := []Locale{
+		{Tag: "fr-CH", Language: "fr", Region: "CH", Quality: 1},
+		{Tag: "fr", Language: "fr", Quality: 0.9},
+		{Tag: "en", Language: "en", Quality: 0.8},
+		{Tag: "de", Language: "de", Quality: 0.7},
+		{Tag: "*", Language: "*", Quality: 0.5},
+	}
+
+	if len(locales) != len(expected) {
+		t.Fatalf("Expected %d locales, got %d", len(expected), len(locales))
+	}
+	for i, locale := range locales {
+		if locale != expected[i] {
+			t.Errorf("Locale %d: expected %+v, got %+v", i, expected[i], locale)
+		}
+	}
+}
+
+func TestParseAcceptLanguage_Ordering(t *testing.T) {
+	locales := ParseAcceptLanguage("en;q=0.5, zh-Hant-TW, es-419;q=0.8, ja;q=0")
+
+	if len(locales) != 3 {
+		t.Fatalf("Expected 3 locales (q=0 omitted), got %d", len(locales))
+	}
+	if locales[0].Tag != "zh-Hant-TW" || locales[0].Region != "TW" {
+		t.Errorf("Expected zh-Hant-TW first, got %+v", locales[0])
+	}
+	if locales[1].Tag != "es-419" || locales[1].Region != "419" {
+		t.Errorf("Expected es-419 second, got %+v", locales[1])
+	}
+	if locales[2].Tag != "en" {
+		t.Errorf("Expected en last, got %+v", locales[2])
+	}
+}
+
+func TestAcceptLanguagePlausibility(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected float64
+	}{
+		{"en-US,en;q=0.9", 1},
+		{"de-DE", 1},
+		{"", 0},
+		{"   ", 0},
+		{"en", 0.5},
+		{"en-US;q=abc", 0.2},
+		{"en_US", 0.2},
+		{"en-US;q=1.5", 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if score := AcceptLanguagePlausibility(tt.header); score != tt.expected {
+				t.Errorf("AcceptLanguagePlausibility(%q) = %v, expected %v", tt.header, score, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package gogobot
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// AcceptProfile is the Accept header a browser engine sends for document navigations
+type AcceptProfile struct {
+	// Required are media types the engine always lists
+	Required []string
+	// Forbidden are media types the engine never lists
+	Forbidden []string
+}
+
+// DefaultAcceptProfiles returns the navigation Accept profiles of current browsers, keyed by
+// engine (see BrowserInfo.Engine). Chromium lists WebP and APNG images next to HTML; Firefox and
+// Safari list HTML and XML only, except Firefox 65-127 listing WebP and AVIF.
+func DefaultAcceptProfiles() map[string]AcceptProfile {
+	return map[string]AcceptProfile{
+		EngineBlink: {
+			Required: []string{"text/html", "application/xhtml+xml", "image/webp", "image/apng"},
+		},
+		EngineGecko: {
+			Required:  []string{"text/html", "application/xhtml+xml"},
+			Forbidden: []string{"image/apng", "application/signed-exchange"},
+		},
+		EngineWebKit: {
+			Required:  []string{"text/html", "application/xhtml+xml"},
+			Forbidden: []string{"image/webp", "image/avif", "image/apng", "application/signed-exchange"},
+		},
+	}
+}
+
+// NavigationAccept is the Accept header of a document navigation, with the profile of the
+// engine the user agent claims
+type NavigationAccept struct {
+	Engine     string        `json:"engine"`
+	MediaTypes []string      `json:"mediaTypes"`
+	Profile    AcceptProfile `json:"profile"`
+}
+
+// parseAcceptList returns the lower-case values of an Accept or Accept-Encoding header, without
+// parameters
+func parseAcceptList(header string) []string {
+	var values []string
+	for _, item := range strings.Split(header, ",") {
+		value, _, _ := strings.Cut(item, ";")
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// isDocumentRequest reports whether the request fetches a document: by Sec-Fetch-Dest when sent,
+// and otherwise by an Accept header asking for HTML
+func isDocumentRequest(req *http.Request, mediaTypes []string) bool {
+	if dest := req.Header.Get("Sec-Fetch-Dest"); dest != "" {
+		return dest == "document" || dest == "iframe" || dest == "frame"
+	}
+	return slices.Contains(mediaTypes, "text/html")
+}
+
+// getNavigationAccept collects the Accept header of navigations by user agents whose engine has
+// a profile
+func getNavigationAccept(req *http.Request, profiles map[string]AcceptProfile) Component[NavigationAccept] {
+	if profiles == nil {
+		return ErrorComponent[NavigationAccept]{
+			State: StateUndefined,
+			Error: "Accept profiles not configured",
+		}
+	}
+	engine := parseEngine(req.Header.Get("User-Agent"))
+	profile, ok := profiles[engine]
+	if !ok {
+		return ErrorComponent[NavigationAccept]{
+			State: StateNull,
+			Error: "no Accept profile for the user agent's engine",
+		}
+	}
+	mediaTypes := parseAcceptList(req.Header.Get("Accept"))
+	if !isDocumentRequest(req, mediaTypes) {
+		return ErrorComponent[NavigationAccept]{
+			State: StateNull,
+			Error: "not a document navigation",
+		}
+	}
+	return SuccessComponent[NavigationAccept]{
+		State: StateSuccess,
+		Value: NavigationAccept{
+			Engine:     engine,
+			MediaTypes: mediaTypes,
+			Profile:    profile,
+		},
+	}
+}
+
+// detectAcceptPlausibility scores navigations whose Accept header lacks a media type the
+// browser's engine always lists, or lists one it never does. Crawlers rendering with a browser
+// engine are identified by their user agent instead.
+func detectAcceptPlausibility(components *ComponentDict) *BotDetectionResult {
+	if components.NavigationAccept.GetState() != StateSuccess || components.UserAgent.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+	if isBot, _ := IsBotUserAgent(components.UserAgent.GetValue()); isBot {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	accept := components.NavigationAccept.GetValue()
+	var reasons []string
+	for _, mediaType := range accept.Profile.Required {
+		if !slices.Contains(accept.MediaTypes, mediaType) {
+			reasons = append(reasons, "Accept of a "+accept.Engine+" navigation lacks "+mediaType)
+		}
+	}
+	for _, mediaType := range accept.Profile.Forbidden {
+		if slices.Contains(accept.MediaTypes, mediaType) {
+			reasons = append(reasons, "Accept of a "+accept.Engine+" navigation lists "+mediaType)
+		}
+	}
+	if len(reasons) == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindUnknown,
+		Score:   0.5,
+		Reasons: reasons,
+	}
+}
@@ -0,0 +1,120 @@
+package gogobot
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	chromeNavigationAccept  = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
+	firefoxNavigationAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	safariUA                = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15"
+	firefoxUA               = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0"
+)
+
+func TestParseAcceptList(t *testing.T) {
+	got := parseAcceptList("Text/HTML, application/xml;q=0.9,, */*;q=0.8")
+	expected := []string{"text/html", "application/xml", "*/*"}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestGetNavigationAccept(t *testing.T) {
+	profiles := DefaultAcceptProfiles()
+
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	if state := getNavigationAccept(req, nil).GetState(); state != StateUndefined {
+		t.Errorf("Expected undefined without profiles, got %v", state)
+	}
+	accept := getNavigationAccept(req, profiles)
+	if accept.GetState() != StateSuccess || accept.GetValue().Engine != EngineBlink {
+		t.Errorf("Expected Blink navigation, got %+v", accept)
+	}
+
+	req.Header.Set("Accept", "*/*")
+	if state := getNavigationAccept(req, profiles).GetState(); state != StateNull {
+		t.Errorf("Expected subresource request not to be judged, got %v", state)
+	}
+	req.Header.Set("Sec-Fetch-Dest", "document")
+	if state := getNavigationAccept(req, profiles).GetState(); state != StateSuccess {
+		t.Errorf("Expected Sec-Fetch-Dest document to be a navigation, got %v", state)
+	}
+
+	req.Header.Set("User-Agent", "custom-client/1.0")
+	if state := getNavigationAccept(req, profiles).GetState(); state != StateNull {
+		t.Errorf("Expected no profile for unknown engine, got %v", state)
+	}
+}
+
+func TestDetectAcceptPlausibility(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		accept    string
+		score     float64
+	}{
+		{"chrome", testBrowserUA, chromeNavigationAccept, 0},
+		{"chrome with library accept", testBrowserUA, "text/html", 0.5},
+		{"firefox", firefoxUA, firefoxNavigationAccept, 0},
+		{"firefox with chrome accept", firefoxUA, chromeNavigationAccept, 0.5},
+		{"safari", safariUA, firefoxNavigationAccept, 0},
+		{"safari listing webp", safariUA, "text/html,application/xhtml+xml,image/webp,*/*;q=0.8", 0.5},
+		{"declared crawler", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/120.0.0.0 Safari/537.36", "text/html", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newBrowserRequest("/", "203.0.113.7:1234")
+			req.Header.Set("User-Agent", tt.userAgent)
+			req.Header.Set("Accept", tt.accept)
+			components := &ComponentDict{
+				UserAgent:        getUserAgent(req),
+				NavigationAccept: getNavigationAccept(req, DefaultAcceptProfiles()),
+			}
+			result := detectAcceptPlausibility(components)
+			if result.Bot || result.Score != tt.score {
+				t.Errorf("Expected score %v, got %+v", tt.score, result)
+			}
+		})
+	}
+}
+
+func TestDetectAcceptPlausibility_Reasons(t *testing.T) {
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	req.Header.Set("User-Agent", safariUA)
+	req.Header.Set("Accept", "text/html,image/avif")
+	components := &ComponentDict{
+		UserAgent:        getUserAgent(req),
+		NavigationAccept: getNavigationAccept(req, DefaultAcceptProfiles()),
+	}
+	result := detectAcceptPlausibility(components)
+	expected := []string{
+		"Accept of a WebKit navigation lacks application/xhtml+xml",
+		"Accept of a WebKit navigation lists image/avif",
+	}
+	if strings.Join(result.Reasons, "; ") != strings.Join(expected, "; ") {
+		t.Errorf("Expected reasons %v, got %v", expected, result.Reasons)
+	}
+}
+
+func TestDetector_AcceptPlausibility(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.AcceptProfiles = DefaultAcceptProfiles()
+	detector := NewDetectorWithConfig(config)
+
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if detection := detector.GetDetections().AcceptPlausibility; detection.Score != 0.5 {
+		t.Errorf("Expected Accept plausibility signal, got %+v", detection)
+	}
+
+	req.Header.Set("Accept", chromeNavigationAccept)
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if detection := detector.GetDetections().AcceptPlausibility; detection.Score != 0 {
+		t.Errorf("Expected no signal for Chrome's Accept, got %+v", detection)
+	}
+}
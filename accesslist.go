@@ -0,0 +1,203 @@
+package gogobot
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// IPAccess is the verdict of an IPAccessList for an address
+type IPAccess int
+
+const (
+	// IPAccessNone means the address is on neither list and detection runs normally
+	IPAccessNone IPAccess = iota
+	// IPAccessAllow means the address bypasses detection
+	IPAccessAllow
+	// IPAccessDeny means the address is always flagged as a bot
+	IPAccessDeny
+)
+
+// String returns the name of the verdict
+func (a IPAccess) String() string {
+	switch a {
+	case IPAccessAllow:
+		return "allow"
+	case IPAccessDeny:
+		return "deny"
+	default:
+		return "none"
+	}
+}
+
+// IPAccessList holds allow and deny CIDRs enforced before detectors run. The most specific
+// prefix wins, so a single address can be denied inside an allowed range and vice versa.
+// Lists can be replaced at runtime with Update, ReloadFile or WatchFile.
+type IPAccessList struct {
+	trie atomic.Pointer[prefixTrie[IPAccess]]
+}
+
+// NewIPAccessList creates an access list from allow and deny CIDRs or IP addresses
+func NewIPAccessList(allow, deny []string) (*IPAccessList, error) {
+	l := &IPAccessList{}
+	if err := l.Update(allow, deny); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Update atomically replaces the allow and deny lists
+func (l *IPAccessList) Update(allow, deny []string) error {
+	trie, err := buildAccessTrie(allow, deny)
+	if err != nil {
+		return err
+	}
+	l.trie.Store(trie)
+	return nil
+}
+
+// Check returns the verdict for addr
+func (l *IPAccessList) Check(addr netip.Addr) IPAccess {
+	if l == nil {
+		return IPAccessNone
+	}
+	trie := l.trie.Load()
+	if trie == nil {
+		return IPAccessNone
+	}
+	access, _, _ := trie.lookup(addr)
+	return access
+}
+
+// LoadIPAccessListFile creates an access list from a file (see ParseIPAccessList)
+func LoadIPAccessListFile(path string) (*IPAccessList, error) {
+	l := &IPAccessList{}
+	if err := l.ReloadFile(path); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ReloadFile atomically replaces the lists with the contents of a file. The current lists
+// are kept if the file cannot be read or parsed.
+func (l *IPAccessList) ReloadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	allow, deny, err := ParseIPAccessList(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return l.Update(allow, deny)
+}
+
+// WatchFile reloads the lists whenever the file's modification time changes, checking every
+// interval until ctx is cancelled. Reload errors are passed to onError, which may be nil.
+func (l *IPAccessList) WatchFile(ctx context.Context, path string, interval time.Duration, onError func(error)) {
+	var lastModified time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastModified = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			continue
+		}
+		if info.ModTime().Equal(lastModified) {
+			continue
+		}
+		lastModified = info.ModTime()
+
+		if err := l.ReloadFile(path); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// ParseIPAccessList parses lines of the form "allow <cidr>" or "deny <cidr>".
+// Blank lines and lines starting with "#" are ignored.
+func ParseIPAccessList(r io.Reader) (allow, deny []string, err error) {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("line %d: expected \"allow <cidr>\" or \"deny <cidr>\"", lineNo)
+		}
+		switch strings.ToLower(fields[0]) {
+		case "allow":
+			allow = append(allow, fields[1])
+		case "deny":
+			deny = append(deny, fields[1])
+		default:
+			return nil, nil, fmt.Errorf("line %d: unknown action %q", lineNo, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return allow, deny, nil
+}
+
+// buildAccessTrie compiles allow and deny entries; deny wins for identical prefixes
+func buildAccessTrie(allow, deny []string) (*prefixTrie[IPAccess], error) {
+	allowPrefixes, err := ParsePrefixes(allow...)
+	if err != nil {
+		return nil, fmt.Errorf("allow list: %w", err)
+	}
+	denyPrefixes, err := ParsePrefixes(deny...)
+	if err != nil {
+		return nil, fmt.Errorf("deny list: %w", err)
+	}
+
+	trie := newPrefixTrie[IPAccess]()
+	for _, prefix := range allowPrefixes {
+		trie.insert(prefix, IPAccessAllow)
+	}
+	for _, prefix := range denyPrefixes {
+		trie.insert(prefix, IPAccessDeny)
+	}
+	return trie, nil
+}
+
+// getIPAccess checks the resolved client IP against the access list
+func getIPAccess(clientIP Component[netip.Addr], list *IPAccessList) Component[IPAccess] {
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[IPAccess]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+	return SuccessComponent[IPAccess]{
+		State: StateSuccess,
+		Value: list.Check(clientIP.GetValue()),
+	}
+}
@@ -0,0 +1,139 @@
+package gogobot
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIPAccessList_Check(t *testing.T) {
+	list, err := NewIPAccessList(
+		[]string{"10.0.0.0/8", "2001:db8::/32"},
+		[]string{"10.6.6.6", "203.0.113.0/24"},
+	)
+	if err != nil {
+		t.Fatalf("NewIPAccessList returned error: %v", err)
+	}
+
+	tests := []struct {
+		addr     string
+		expected IPAccess
+	}{
+		{"10.1.2.3", IPAccessAllow},
+		{"10.6.6.6", IPAccessDeny},
+		{"203.0.113.50", IPAccessDeny},
+		{"2001:db8::1", IPAccessAllow},
+		{"::ffff:10.1.2.3", IPAccessAllow},
+		{"198.51.100.1", IPAccessNone},
+	}
+
+	for _, tt := range tests {
+		if access := list.Check(netip.MustParseAddr(tt.addr)); access != tt.expected {
+			t.Errorf("Check(%s) = %s, want %s", tt.addr, access, tt.expected)
+		}
+	}
+
+	// Hot reload replaces both lists
+	if err := list.Update(nil, []string{"10.0.0.0/8"}); err != nil {
+		t.Fatal(err)
+	}
+	if access := list.Check(netip.MustParseAddr("10.1.2.3")); access != IPAccessDeny {
+		t.Errorf("Expected updated list to deny 10.1.2.3, got %s", access)
+	}
+
+	// Invalid updates keep the current lists
+	if err := list.Update([]string{"not-a-cidr"}, nil); err == nil {
+		t.Error("Expected error for invalid CIDR")
+	}
+	if access := list.Check(netip.MustParseAddr("10.1.2.3")); access != IPAccessDeny {
+		t.Errorf("Expected failed update to keep the current lists, got %s", access)
+	}
+
+	var nilList *IPAccessList
+	if access := nilList.Check(netip.MustParseAddr("10.1.2.3")); access != IPAccessNone {
+		t.Errorf("Expected nil list to return none, got %s", access)
+	}
+}
+
+func TestParseIPAccessList(t *testing.T) {
+	allow, deny, err := ParseIPAccessList(strings.NewReader("# partners\nallow 192.0.2.0/24\n\nDENY 198.51.100.7\n"))
+	if err != nil {
+		t.Fatalf("ParseIPAccessList returned error: %v", err)
+	}
+	if len(allow) != 1 || allow[0] != "192.0.2.0/24" || len(deny) != 1 || deny[0] != "198.51.100.7" {
+		t.Errorf("Unexpected lists allow=%v deny=%v", allow, deny)
+	}
+
+	for _, invalid := range []string{"block 1.2.3.4\n", "allow\n"} {
+		if _, _, err := ParseIPAccessList(strings.NewReader(invalid)); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestIPAccessList_WatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.txt")
+	if err := os.WriteFile(path, []byte("allow 192.0.2.0/24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := LoadIPAccessListFile(path)
+	if err != nil {
+		t.Fatalf("LoadIPAccessListFile returned error: %v", err)
+	}
+	addr := netip.MustParseAddr("192.0.2.1")
+	if access := list.Check(addr); access != IPAccessAllow {
+		t.Fatalf("Expected allow, got %s", access)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go list.WatchFile(ctx, path, 5*time.Millisecond, nil)
+
+	if err := os.WriteFile(path, []byte("deny 192.0.2.0/24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Keep moving the modification time forward so the change is seen regardless of
+	// when the watcher took its initial snapshot or the filesystem's timestamp granularity
+	deadline := time.Now().Add(time.Second)
+	for i := 1; list.Check(addr) != IPAccessDeny; i++ {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected watched file change to be reloaded")
+		}
+		modified := time.Now().Add(time.Duration(i) * time.Second)
+		os.Chtimes(path, modified, modified)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBotDetector_IPAccessList(t *testing.T) {
+	list, _ := NewIPAccessList([]string{"192.0.2.0/24"}, []string{"198.51.100.0/24"})
+	config := DefaultDetectorConfig()
+	config.IPAccessList = list
+	detector := NewDetectorWithConfig(config)
+
+	// A curl request from an allowed partner range bypasses detection
+	req := createTestRequest("GET", "/", map[string]string{"User-Agent": "curl/7.68.0"})
+	req.RemoteAddr = "192.0.2.10:1234"
+	result, _ := detector.DetectFromRequest(req)
+	if result.Bot {
+		t.Errorf("Expected allowed address to bypass detection, got %+v", result)
+	}
+
+	// A browser request from a denied range is always flagged
+	req = createTestRequest("GET", "/", map[string]string{
+		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Accept":          "text/html",
+		"Accept-Language": "en-US",
+		"Accept-Encoding": "gzip",
+	})
+	req.RemoteAddr = "198.51.100.10:1234"
+	result, _ = detector.DetectFromRequest(req)
+	if !result.Bot || result.BotKind != BotKindDenylisted {
+		t.Errorf("Expected denied address to be flagged, got %+v", result)
+	}
+}
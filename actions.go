@@ -0,0 +1,184 @@
+package gogobot
+
+import (
+	"net/http"
+	"slices"
+)
+
+// BotCategory groups bot kinds that sites usually treat alike
+type BotCategory string
+
+const (
+	// BotCategoryCrawler covers search engine crawlers and bots a trusted CDN verified
+	BotCategoryCrawler BotCategory = "crawler"
+	// BotCategoryAI covers AI crawlers and agents
+	BotCategoryAI BotCategory = "ai"
+	// BotCategoryAutomation covers headless browsers, browser automation frameworks and HTTP
+	// tools
+	BotCategoryAutomation BotCategory = "automation"
+	// BotCategoryNetwork covers clients flagged by their IP address: datacenters, anonymizers,
+	// bad reputation and deny lists
+	BotCategoryNetwork BotCategory = "network"
+	// BotCategoryAbuse covers clients flagged by their behavior, such as scrapers, scanners and
+	// impersonators
+	BotCategoryAbuse BotCategory = "abuse"
+	// BotCategoryUnknown covers header anomalies, user agents only calling themselves a bot,
+	// crawler or scraper, and kinds added by custom detectors
+	BotCategoryUnknown BotCategory = "unknown"
+)
+
+// botKindCategories maps the built-in bot kinds to their category
+var botKindCategories = map[BotKind]BotCategory{
+	BotKindBot:                BotCategoryUnknown,
+	BotKindCrawler:            BotCategoryCrawler,
+	BotKindSpider:             BotCategoryCrawler,
+	BotKindGPTBot:             BotCategoryAI,
+	BotKindChatGPT:            BotCategoryAI,
+	BotKindOpenAI:             BotCategoryAI,
+	BotKindClaude:             BotCategoryAI,
+	BotKindAIAgent:            BotCategoryAI,
+	BotKindAwesomium:          BotCategoryAutomation,
+	BotKindCef:                BotCategoryAutomation,
+	BotKindCefSharp:           BotCategoryAutomation,
+	BotKindCoachJS:            BotCategoryAutomation,
+	BotKindElectron:           BotCategoryAutomation,
+	BotKindFMiner:             BotCategoryAutomation,
+	BotKindGeb:                BotCategoryAutomation,
+	BotKindNightmareJS:        BotCategoryAutomation,
+	BotKindPhantomas:          BotCategoryAutomation,
+	BotKindPhantomJS:          BotCategoryAutomation,
+	BotKindRhino:              BotCategoryAutomation,
+	BotKindSelenium:           BotCategoryAutomation,
+	BotKindSequentum:          BotCategoryAutomation,
+	BotKindSlimerJS:           BotCategoryAutomation,
+	BotKindWebDriverIO:        BotCategoryAutomation,
+	BotKindWebDriver:          BotCategoryAutomation,
+	BotKindHeadlessChrome:     BotCategoryAutomation,
+	BotKindPlaywright:         BotCategoryAutomation,
+	BotKindPuppeteer:          BotCategoryAutomation,
+	BotKindCurl:               BotCategoryAutomation,
+	BotKindWget:               BotCategoryAutomation,
+	BotKindDatacenter:         BotCategoryNetwork,
+	BotKindVPN:                BotCategoryNetwork,
+	BotKindProxy:              BotCategoryNetwork,
+	BotKindAbusiveIP:          BotCategoryNetwork,
+	BotKindDenylisted:         BotCategoryNetwork,
+	BotKindSpoofedIP:          BotCategoryNetwork,
+	BotKindImpersonator:       BotCategoryAbuse,
+	BotKindRateAbuse:          BotCategoryAbuse,
+	BotKindScraper:            BotCategoryAbuse,
+	BotKindHoneypot:           BotCategoryAbuse,
+	BotKindNoCookies:          BotCategoryAbuse,
+	BotKindInhumanTiming:      BotCategoryAbuse,
+	BotKindRobotsViolation:    BotCategoryAbuse,
+	BotKindScanner:            BotCategoryAbuse,
+	BotKindCredentialStuffing: BotCategoryAbuse,
+}
+
+// Category returns the category of the bot kind, BotCategoryUnknown for kinds without one
+func (k BotKind) Category() BotCategory {
+	if category, ok := botKindCategories[k]; ok {
+		return category
+	}
+	return BotCategoryUnknown
+}
+
+// Action is what the middleware does with a detected bot (see MiddlewareConfig.Actions)
+type Action string
+
+const (
+	// ActionAllow serves the request
+	ActionAllow Action = "allow"
+	// ActionLog serves the request after reporting it to MiddlewareConfig.OnAction
+	ActionLog Action = "log"
+	// ActionBlock answers with BlockedStatusCode and BlockedMessage
+	ActionBlock Action = "block"
+	// ActionRateLimit answers with 429 Too Many Requests and a Retry-After, as configured by
+	// MiddlewareConfig.RateLimitResponse; with a MiddlewareConfig.RateLimiter, only requests
+	// over the bot's rate are refused
+	ActionRateLimit Action = "rate_limit"
+	// ActionChallenge presents MiddlewareConfig.Challenge, blocking when there is none or the
+	// visitor cannot be challenged
+	ActionChallenge Action = "challenge"
+	// ActionTarpit blocks after holding the request as configured by MiddlewareConfig.Tarpit,
+	// raising the cost of scraping
+	ActionTarpit Action = "tarpit"
+	// ActionDegrade serves the request with MiddlewareConfig.DegradedHandler instead of the
+	// protected handler, e.g. cached or stripped pages without prices or email addresses, so
+	// scrapers get no refusal to react to; it blocks when there is no DegradedHandler
+	ActionDegrade Action = "degrade"
+)
+
+// action returns the action configured for the category of a detected bot; the kinds selected
+// by the Tarpit are tarpitted whatever their category
+func (c MiddlewareConfig) action(result *BotDetectionResult) (Action, bool) {
+	if !result.Bot {
+		return "", false
+	}
+	if c.Tarpit != nil && slices.Contains(c.Tarpit.Kinds, result.BotKind) {
+		return ActionTarpit, true
+	}
+	action, ok := c.Actions[result.BotKind.Category()]
+	return action, ok
+}
+
+// blocked returns the status code and message of blocked responses
+func (c MiddlewareConfig) blocked() (int, string) {
+	// Ensure we have a valid status code
+	statusCode := c.BlockedStatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusForbidden
+	}
+	message := c.BlockedMessage
+	if message == "" {
+		message = "Bot traffic is not allowed"
+	}
+	return statusCode, message
+}
+
+// blockBot answers with the configured blocked status and message, rendered by the
+// BlockedResponse when one is configured
+func blockBot(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, result *BotDetectionResult) {
+	statusCode, message := config.blocked()
+	refuse(w, req, config, BlockedData{StatusCode: statusCode, Message: message, Result: result})
+}
+
+// refuse writes a refusal through the BlockedResponse, or as plain text without one
+func refuse(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, data BlockedData) {
+	if config.BlockedResponse != nil {
+		config.BlockedResponse.serve(w, req, data)
+		return
+	}
+	http.Error(w, data.Message, data.StatusCode)
+}
+
+// applyAction carries out the action configured for a detected bot, reporting whether the
+// response was written
+func applyAction(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, result *BotDetectionResult, action Action) bool {
+	// With a RateLimiter, rate limited bots are served within their rate
+	if action == ActionRateLimit && config.RateLimiter != nil {
+		return false
+	}
+	if action != ActionAllow && config.OnAction != nil {
+		config.OnAction(req, result, action)
+	}
+
+	switch action {
+	case ActionAllow, ActionLog:
+		return false
+	case ActionRateLimit:
+		config.RateLimitResponse.serve(w, req, config, result, 0)
+	case ActionTarpit:
+		config.Tarpit.serve(w, req, config, result)
+	case ActionDegrade:
+		if config.DegradedHandler == nil {
+			blockBot(w, req, config, result)
+			break
+		}
+		config.DegradedHandler.ServeHTTP(w, req)
+	default:
+		// Block, and challenges the middleware could not present
+		blockBot(w, req, config, result)
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package gogobot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBotKind_Category(t *testing.T) {
+	tests := map[BotKind]BotCategory{
+		BotKindCrawler:        BotCategoryCrawler,
+		BotKindBot:            BotCategoryUnknown,
+		BotKindGPTBot:         BotCategoryAI,
+		BotKindHeadlessChrome: BotCategoryAutomation,
+		BotKindCurl:           BotCategoryAutomation,
+		BotKindDatacenter:     BotCategoryNetwork,
+		BotKindScanner:        BotCategoryAbuse,
+		BotKindUnknown:        BotCategoryUnknown,
+		BotKind("custom"):     BotCategoryUnknown,
+	}
+	for kind, expected := range tests {
+		if got := kind.Category(); got != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, kind, got)
+		}
+	}
+}
+
+func TestMiddleware_Actions(t *testing.T) {
+	var logged []Action
+	config := DefaultMiddlewareConfig()
+	config.Actions = map[BotCategory]Action{
+		BotCategoryCrawler:    ActionAllow,
+		BotCategoryAI:         ActionRateLimit,
+		BotCategoryAutomation: ActionBlock,
+		BotCategoryUnknown:    ActionLog,
+	}
+	config.OnAction = func(r *http.Request, result *BotDetectionResult, action Action) {
+		logged = append(logged, action)
+	}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		userAgent string
+		status    int
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", http.StatusOK},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)", http.StatusTooManyRequests},
+		{"curl/8.4.0", http.StatusForbidden},
+		// Calling itself a scraper does not make it a crawler
+		{"Mozilla/5.0 (compatible; AcmeScraper/1.0)", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", tt.userAgent)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("Expected %d for %q, got %d", tt.status, tt.userAgent, rec.Code)
+		}
+	}
+	if len(logged) != 3 || logged[0] != ActionRateLimit || logged[1] != ActionBlock || logged[2] != ActionLog {
+		t.Errorf("Expected rate limit, block and log to be reported, got %v", logged)
+	}
+}
+
+func TestApplyAction_TarpitCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	if !applyAction(rec, req, DefaultMiddlewareConfig(), &BotDetectionResult{Bot: true}, ActionTarpit) {
+		t.Error("Expected tarpit to handle the request")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected tarpit to stop when the client goes away, took %v", elapsed)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("Expected no response to a client that went away, got %q", rec.Body.String())
+	}
+}
+
+func TestMiddleware_ActionDegrade(t *testing.T) {
+	config := DefaultMiddlewareConfig()
+	config.Actions = map[BotCategory]Action{BotCategoryAutomation: ActionDegrade}
+	config.DegradedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if result, ok := GetResultFromContext(r.Context()); !ok || !result.Bot {
+			t.Error("Expected the verdict in the degraded handler's context")
+		}
+		w.Write([]byte("cached page"))
+	})
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("live page"))
+	}))
+
+	tests := []struct {
+		userAgent string
+		body      string
+	}{
+		{testBrowserUA, "live page"},
+		{"curl/8.4.0", "cached page"},
+	}
+	for _, tt := range tests {
+		req := newBrowserRequest("/products", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", tt.userAgent)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Body.String() != tt.body {
+			t.Errorf("Expected %q for %q, got %d %q", tt.body, tt.userAgent, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Without a degraded handler, degraded bots are blocked
+	rec := httptest.NewRecorder()
+	if !applyAction(rec, httptest.NewRequest("GET", "/", nil), DefaultMiddlewareConfig(), &BotDetectionResult{Bot: true}, ActionDegrade) || rec.Code != http.StatusForbidden {
+		t.Errorf("Expected a block without a degraded handler, got %d", rec.Code)
+	}
+}
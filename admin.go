@@ -0,0 +1,235 @@
+package gogobot
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// runtimeControls hold the detector settings an Admin changes while the detector is serving
+type runtimeControls struct {
+	// disabled is the set of detector names skipped by runDetectors
+	disabled atomic.Pointer[map[string]bool]
+	// mode overrides the middleware's configured mode when set
+	mode atomic.Pointer[MiddlewareMode]
+	// counts are the middleware's verdicts per bot kind, "human" for clients not flagged
+	counts sync.Map
+	// toggle serializes updates of the disabled set
+	toggle sync.Mutex
+}
+
+// detectorDisabled reports whether the detector was disabled at runtime
+func (c *runtimeControls) detectorDisabled(name string) bool {
+	disabled := c.disabled.Load()
+	return disabled != nil && (*disabled)[name]
+}
+
+// setDetectorEnabled enables or disables the detector; the set is replaced rather than
+// modified, so running detections keep a consistent view
+func (c *runtimeControls) setDetectorEnabled(name string, enabled bool) {
+	c.toggle.Lock()
+	defer c.toggle.Unlock()
+	disabled := map[string]bool{}
+	if current := c.disabled.Load(); current != nil {
+		disabled = maps.Clone(*current)
+	}
+	if enabled {
+		delete(disabled, name)
+	} else {
+		disabled[name] = true
+	}
+	c.disabled.Store(&disabled)
+}
+
+// count records a middleware verdict
+func (c *runtimeControls) count(result *BotDetectionResult) {
+	kind := "human"
+	if result.Bot {
+		kind = string(result.BotKind)
+		if kind == "" {
+			kind = string(BotKindUnknown)
+		}
+	}
+	counter, _ := c.counts.LoadOrStore(kind, new(atomic.Uint64))
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// snapshot returns the verdict counts
+func (c *runtimeControls) snapshot() map[string]uint64 {
+	counts := map[string]uint64{}
+	c.counts.Range(func(kind, counter any) bool {
+		counts[kind.(string)] = counter.(*atomic.Uint64).Load()
+		return true
+	})
+	return counts
+}
+
+// middlewareMode returns the mode set at runtime, the configured mode otherwise
+func (d *BotDetector) middlewareMode(config MiddlewareConfig) MiddlewareMode {
+	if mode := d.controls.mode.Load(); mode != nil {
+		return *mode
+	}
+	return config.Mode
+}
+
+// Admin is a control endpoint for incident response: it reports the detector set, the rules
+// version and the middleware's verdicts per bot kind, and lets operators disable a misbehaving
+// detector, switch every middleware of the detector between shadow and enforce mode, and flush
+// the caches, without a deploy. It performs no authentication; mount it behind your own.
+type Admin struct {
+	// RulesVersion identifies the deployed rules, e.g. a configuration hash or release
+	RulesVersion string
+
+	detector *BotDetector
+	mux      *http.ServeMux
+}
+
+// AdminStatus is the state reported by an Admin
+type AdminStatus struct {
+	RulesVersion string `json:"rulesVersion,omitempty"`
+	// EOLVersion is the version of the installed browser end-of-life database
+	EOLVersion string `json:"eolVersion,omitempty"`
+	// Mode is the mode set at runtime, empty when each middleware uses its configured mode
+	Mode MiddlewareMode `json:"mode,omitempty"`
+	// Detectors maps each detector name to whether it is enabled
+	Detectors map[string]bool `json:"detectors"`
+	// Counts are the middleware's verdicts per bot kind since start, "human" for clients not
+	// flagged
+	Counts map[string]uint64 `json:"counts"`
+	// Caches are the statistics of the installed caches
+	Caches map[string]ResultCacheStats `json:"caches"`
+}
+
+// NewAdmin creates an admin endpoint for the detector. Its routes are relative to where it is
+// mounted, so mount it with http.StripPrefix:
+//
+//	GET  /                            the AdminStatus
+//	POST /detectors/{name}/enable     re-enable a detector
+//	POST /detectors/{name}/disable    skip a detector until re-enabled
+//	POST /mode/{mode}                 switch to "shadow" or "enforce", "config" to restore the configured modes
+//	POST /caches/flush                purge the result, user agent and verification caches
+func NewAdmin(detector *BotDetector) *Admin {
+	a := &Admin{detector: detector, mux: http.NewServeMux()}
+	a.mux.HandleFunc("GET /{$}", a.serveStatus)
+	a.mux.HandleFunc("POST /detectors/{name}/{state}", a.serveDetector)
+	a.mux.HandleFunc("POST /mode/{mode}", a.serveMode)
+	a.mux.HandleFunc("POST /caches/flush", a.serveFlush)
+	return a
+}
+
+// ServeHTTP serves the admin routes
+func (a *Admin) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	a.mux.ServeHTTP(w, req)
+}
+
+// Status returns the current state
+func (a *Admin) Status() AdminStatus {
+	d := a.detector
+	status := AdminStatus{
+		RulesVersion: a.RulesVersion,
+		Detectors:    map[string]bool{},
+		Counts:       d.controls.snapshot(),
+		Caches:       map[string]ResultCacheStats{},
+	}
+	if db := activeEOLDatabase.Load(); db != nil {
+		status.EOLVersion = db.Version
+	}
+	if mode := d.controls.mode.Load(); mode != nil {
+		status.Mode = *mode
+	}
+	for _, name := range d.GetDetectorNames() {
+		status.Detectors[name] = !d.controls.detectorDisabled(name)
+	}
+	if d.config.ResultCache != nil {
+		status.Caches["result"] = d.config.ResultCache.Stats()
+	}
+	if cache := GetUACache(); cache != nil {
+		status.Caches["userAgent"] = ResultCacheStats(cache.Stats())
+	}
+	if cache := GetVerificationCache(); cache != nil {
+		status.Caches["verification"] = ResultCacheStats(cache.Stats())
+	}
+	return status
+}
+
+// SetDetectorEnabled enables or disables the named detector at runtime, reporting whether the
+// detector exists
+func (a *Admin) SetDetectorEnabled(name string, enabled bool) bool {
+	if !slices.Contains(a.detector.GetDetectorNames(), name) {
+		return false
+	}
+	a.detector.controls.setDetectorEnabled(name, enabled)
+	return true
+}
+
+// SetMode switches every middleware of the detector to the mode; an empty mode restores their
+// configured modes
+func (a *Admin) SetMode(mode MiddlewareMode) {
+	if mode == "" {
+		a.detector.controls.mode.Store(nil)
+		return
+	}
+	a.detector.controls.mode.Store(&mode)
+}
+
+// FlushCaches purges the detector's result cache and the installed user agent and verification
+// caches
+func (a *Admin) FlushCaches() {
+	if a.detector.config.ResultCache != nil {
+		a.detector.config.ResultCache.Purge()
+	}
+	if cache := GetUACache(); cache != nil {
+		cache.Purge()
+	}
+	if cache := GetVerificationCache(); cache != nil {
+		cache.Purge()
+	}
+}
+
+// serveStatus writes the status as JSON
+func (a *Admin) serveStatus(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(a.Status())
+}
+
+// serveDetector enables or disables a detector
+func (a *Admin) serveDetector(w http.ResponseWriter, req *http.Request) {
+	var enabled bool
+	switch req.PathValue("state") {
+	case "enable":
+		enabled = true
+	case "disable":
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	if !a.SetDetectorEnabled(req.PathValue("name"), enabled) {
+		http.Error(w, "unknown detector", http.StatusNotFound)
+		return
+	}
+	a.serveStatus(w, req)
+}
+
+// serveMode switches the mode
+func (a *Admin) serveMode(w http.ResponseWriter, req *http.Request) {
+	switch mode := MiddlewareMode(req.PathValue("mode")); mode {
+	case ModeEnforce, ModeShadow:
+		a.SetMode(mode)
+	case "config":
+		a.SetMode("")
+	default:
+		http.Error(w, "unknown mode", http.StatusBadRequest)
+		return
+	}
+	a.serveStatus(w, req)
+}
+
+// serveFlush purges the caches
+func (a *Admin) serveFlush(w http.ResponseWriter, req *http.Request) {
+	a.FlushCaches()
+	a.serveStatus(w, req)
+}
@@ -0,0 +1,118 @@
+package gogobot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func adminRequest(t *testing.T, handler http.Handler, method, target string) (int, AdminStatus) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	var status AdminStatus
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatalf("Failed to decode status: %v", err)
+		}
+	}
+	return rec.Code, status
+}
+
+func TestAdmin_Status(t *testing.T) {
+	detector := NewDetectorWithConfig(DetectorConfig{ResultCache: NewResultCache(10, time.Minute)})
+	admin := NewAdmin(detector)
+	admin.RulesVersion = "2026-10-01"
+	handler := http.StripPrefix("/admin", admin)
+
+	middleware := detector.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, userAgent := range []string{testBrowserUA, "curl/8.4.0", "curl/8.4.0"} {
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", userAgent)
+		middleware.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	code, status := adminRequest(t, handler, "GET", "/admin/")
+	if code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if status.RulesVersion != "2026-10-01" || status.EOLVersion == "" || status.Mode != "" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if len(status.Detectors) != len(detector.GetDetectorNames()) || !status.Detectors["userAgent"] {
+		t.Errorf("Expected every detector to be enabled, got %v", status.Detectors)
+	}
+	if status.Counts["human"] != 1 || status.Counts[string(BotKindCurl)] != 2 {
+		t.Errorf("Unexpected counts %v", status.Counts)
+	}
+	if status.Caches["result"].Size != 2 {
+		t.Errorf("Expected two cached verdicts, got %+v", status.Caches["result"])
+	}
+
+	if code, status = adminRequest(t, handler, "POST", "/admin/caches/flush"); code != http.StatusOK || status.Caches["result"].Size != 0 {
+		t.Errorf("Expected the result cache to be flushed, got %d %+v", code, status.Caches)
+	}
+	if code, _ := adminRequest(t, handler, "DELETE", "/admin/caches/flush"); code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", code)
+	}
+}
+
+func TestAdmin_DisableDetector(t *testing.T) {
+	detector := NewDetector()
+	handler := http.StripPrefix("/admin", NewAdmin(detector))
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	req.Header.Set("User-Agent", "curl/8.4.0")
+
+	if result, _ := detector.DetectFromRequest(req); !result.Bot {
+		t.Fatal("Expected curl to be detected")
+	}
+	code, status := adminRequest(t, handler, "POST", "/admin/detectors/userAgent/disable")
+	if code != http.StatusOK || status.Detectors["userAgent"] {
+		t.Fatalf("Expected the detector to be disabled, got %d %v", code, status.Detectors)
+	}
+	if result, _ := detector.DetectFromRequest(req); result.BotKind == BotKindCurl {
+		t.Errorf("Expected the disabled detector to be skipped, got %+v", result)
+	}
+	if code, status = adminRequest(t, handler, "POST", "/admin/detectors/userAgent/enable"); code != http.StatusOK || !status.Detectors["userAgent"] {
+		t.Errorf("Expected the detector to be enabled, got %d %v", code, status.Detectors)
+	}
+	if code, _ := adminRequest(t, handler, "POST", "/admin/detectors/missing/disable"); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown detector, got %d", code)
+	}
+}
+
+func TestAdmin_Mode(t *testing.T) {
+	detector := NewDetector()
+	handler := http.StripPrefix("/admin", NewAdmin(detector))
+	config := DefaultMiddlewareConfig()
+	config.BlockBots = true
+	middleware := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func() int {
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		rec := httptest.NewRecorder()
+		middleware.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(); code != http.StatusForbidden {
+		t.Fatalf("Expected the bot to be blocked, got %d", code)
+	}
+	if code, status := adminRequest(t, handler, "POST", "/admin/mode/shadow"); code != http.StatusOK || status.Mode != ModeShadow {
+		t.Fatalf("Expected shadow mode, got %d %q", code, status.Mode)
+	}
+	if code := serve(); code != http.StatusOK {
+		t.Errorf("Expected the bot to be served in shadow mode, got %d", code)
+	}
+	if code, status := adminRequest(t, handler, "POST", "/admin/mode/config"); code != http.StatusOK || status.Mode != "" {
+		t.Fatalf("Expected the configured mode to be restored, got %d %q", code, status.Mode)
+	}
+	if code := serve(); code != http.StatusForbidden {
+		t.Errorf("Expected the bot to be blocked again, got %d", code)
+	}
+	if code, _ := adminRequest(t, handler, "POST", "/admin/mode/off"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown mode, got %d", code)
+	}
+}
@@ -0,0 +1,165 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultFlushInterval is how often an AggregatingStore pushes its counters to the shared store
+const DefaultFlushInterval = time.Second
+
+// AggregatingStore keeps counters locally and adds their increments to a shared Store in
+// batches, so counting requests costs no round trip to the backend. The first increment of a
+// counter goes to the shared store to learn its value; later ones are applied locally and
+// returned on top of the last shared value, so counts lag the other replicas by up to
+// Interval. Values and sets are read and written through to the shared store.
+type AggregatingStore struct {
+	shared Store
+	// Interval is how often Run flushes; zero uses DefaultFlushInterval
+	Interval time.Duration
+	// OnFlushError is called with errors of background flushes; increments that failed to
+	// flush are retried on the next one
+	OnFlushError func(error)
+
+	mu       sync.Mutex
+	counters map[string]*aggregateCounter
+	now      func() time.Time
+}
+
+// aggregateCounter is a counter's last known shared value and the increments not flushed yet
+type aggregateCounter struct {
+	shared  int64
+	pending int64
+	ttl     time.Duration
+	expires time.Time
+}
+
+// NewAggregatingStore creates a store batching counter increments to shared
+func NewAggregatingStore(shared Store) *AggregatingStore {
+	return &AggregatingStore{
+		shared:   shared,
+		Interval: DefaultFlushInterval,
+		counters: make(map[string]*aggregateCounter),
+		now:      time.Now,
+	}
+}
+
+// Incr implements Store
+func (s *AggregatingStore) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	now := s.now()
+	if counter := s.counters[key]; counter != nil && (counter.expires.IsZero() || now.Before(counter.expires)) {
+		counter.pending += delta
+		value := counter.shared + counter.pending
+		s.mu.Unlock()
+		return value, nil
+	}
+	s.mu.Unlock()
+
+	value, err := s.shared.Incr(ctx, key, delta, ttl)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A concurrent request may have learned the counter meanwhile
+	counter := s.counters[key]
+	if counter == nil || (!counter.expires.IsZero() && !now.Before(counter.expires)) {
+		counter = &aggregateCounter{ttl: ttl, expires: expiry(now, ttl)}
+		s.counters[key] = counter
+	}
+	counter.shared = max(counter.shared, value)
+	return counter.shared + counter.pending, nil
+}
+
+// Get implements Store
+func (s *AggregatingStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.shared.Get(ctx, key)
+}
+
+// Set implements Store
+func (s *AggregatingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.shared.Set(ctx, key, value, ttl)
+}
+
+// AddToSet implements Store
+func (s *AggregatingStore) AddToSet(ctx context.Context, key, member string, ttl time.Duration) (int64, error) {
+	return s.shared.AddToSet(ctx, key, member, ttl)
+}
+
+// Delete implements Store, dropping unflushed increments of the key
+func (s *AggregatingStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.counters, key)
+	s.mu.Unlock()
+	return s.shared.Delete(ctx, key)
+}
+
+// Flush adds the pending increments to the shared store and refreshes the shared values of
+// all live counters, taking one round trip per counter. Expired counters are dropped. It
+// returns the errors of all failed counters joined.
+func (s *AggregatingStore) Flush(ctx context.Context) error {
+	type flush struct {
+		key     string
+		pending int64
+		ttl     time.Duration
+	}
+	s.mu.Lock()
+	now := s.now()
+	var flushes []flush
+	for key, counter := range s.counters {
+		if !counter.expires.IsZero() && !now.Before(counter.expires) {
+			delete(s.counters, key)
+			continue
+		}
+		flushes = append(flushes, flush{key, counter.pending, counter.ttl})
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, f := range flushes {
+		value, err := s.shared.Incr(ctx, f.key, f.pending, f.ttl)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.mu.Lock()
+		if counter := s.counters[f.key]; counter != nil {
+			// Increments made during the round trip stay pending
+			counter.pending -= f.pending
+			counter.shared = value
+		}
+		s.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// Run calls Flush every Interval until ctx is cancelled, then flushes once more so that no
+// increments are lost on shutdown
+func (s *AggregatingStore) Run(ctx context.Context) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.reportFlushError(s.Flush(context.WithoutCancel(ctx)))
+			return
+		case <-ticker.C:
+			s.reportFlushError(s.Flush(ctx))
+		}
+	}
+}
+
+func (s *AggregatingStore) reportFlushError(err error) {
+	if err != nil && s.OnFlushError != nil {
+		s.OnFlushError(err)
+	}
+}
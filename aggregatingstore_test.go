@@ -0,0 +1,115 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingStore counts the operations reaching a Store and can fail them
+type countingStore struct {
+	Store
+	incrs int
+	err   error
+}
+
+func (s *countingStore) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	s.incrs++
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.Store.Incr(ctx, key, delta, ttl)
+}
+
+func TestAggregatingStore_Incr(t *testing.T) {
+	shared := &countingStore{Store: NewMemoryStore(0)}
+	replica := NewAggregatingStore(shared)
+	other := NewAggregatingStore(shared)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 5; i++ {
+		if n, err := replica.Incr(ctx, "counter", 1, time.Minute); err != nil || n != i {
+			t.Fatalf("Expected %d, got %d, %v", i, n, err)
+		}
+	}
+	if shared.incrs != 1 {
+		t.Errorf("Expected only the first increment to reach the shared store, got %d", shared.incrs)
+	}
+
+	// The other replica learns the shared value, which lacks the unflushed increments
+	if n, _ := other.Incr(ctx, "counter", 1, time.Minute); n != 2 {
+		t.Errorf("Expected 2 before the flush, got %d", n)
+	}
+	if err := replica.Flush(ctx); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if n, _ := shared.Store.Incr(ctx, "counter", 0, time.Minute); n != 6 {
+		t.Errorf("Expected 6 in the shared store after the flush, got %d", n)
+	}
+	other.Flush(ctx)
+	if n, _ := other.Incr(ctx, "counter", 0, time.Minute); n != 6 {
+		t.Errorf("Expected flush to refresh the shared value, got %d", n)
+	}
+}
+
+func TestAggregatingStore_Expiry(t *testing.T) {
+	shared := NewMemoryStore(0)
+	now := time.Unix(1700000000, 0)
+	shared.now = func() time.Time { return now }
+	store := NewAggregatingStore(shared)
+	store.now = shared.now
+	ctx := context.Background()
+
+	store.Incr(ctx, "counter", 3, time.Minute)
+	now = now.Add(time.Minute)
+	if n, _ := store.Incr(ctx, "counter", 1, time.Minute); n != 1 {
+		t.Errorf("Expected expired counter to restart, got %d", n)
+	}
+	now = now.Add(time.Minute)
+	store.Flush(ctx)
+	if len(store.counters) != 0 {
+		t.Errorf("Expected flush to drop expired counters, got %d", len(store.counters))
+	}
+}
+
+func TestAggregatingStore_FlushError(t *testing.T) {
+	shared := &countingStore{Store: NewMemoryStore(0)}
+	store := NewAggregatingStore(shared)
+	ctx := context.Background()
+
+	store.Incr(ctx, "counter", 1, time.Minute)
+	store.Incr(ctx, "counter", 1, time.Minute)
+	shared.err = errors.New("unavailable")
+	if err := store.Flush(ctx); err == nil {
+		t.Fatal("Expected flush error")
+	}
+
+	// Increments that failed to flush are retried
+	shared.err = nil
+	store.Flush(ctx)
+	if n, _ := shared.Store.Incr(ctx, "counter", 0, time.Minute); n != 2 {
+		t.Errorf("Expected retried increments in the shared store, got %d", n)
+	}
+}
+
+func TestAggregatingStore_Run(t *testing.T) {
+	shared := NewMemoryStore(0)
+	store := NewAggregatingStore(shared)
+	store.Interval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+
+	store.Incr(ctx, "counter", 1, time.Minute)
+	store.Incr(ctx, "counter", 1, time.Minute)
+	done := make(chan struct{})
+	go func() {
+		store.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	if n, _ := shared.Incr(context.Background(), "counter", 0, time.Minute); n != 2 {
+		t.Errorf("Expected increments to be flushed on shutdown, got %d", n)
+	}
+}
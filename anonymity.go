@@ -0,0 +1,97 @@
+package gogobot
+
+import (
+	"net/netip"
+)
+
+// Weights contributed by anonymity verdicts. VPNs are common among privacy-conscious people,
+// so they weigh less than open or residential proxies.
+const (
+	vpnSignalWeight   = 0.3
+	proxySignalWeight = 0.5
+)
+
+// AnonymityProvider classifies IP addresses using an IP-intelligence feed
+type AnonymityProvider interface {
+	// IsVPN reports whether addr is a VPN exit node
+	IsVPN(addr netip.Addr) bool
+	// IsProxy reports whether addr is an open, anonymizing or residential proxy (including Tor)
+	IsProxy(addr netip.Addr) bool
+	// IsHosting reports whether addr belongs to a hosting or cloud provider
+	IsHosting(addr netip.Addr) bool
+}
+
+// TorChecker is optionally implemented by an AnonymityProvider that can tell Tor exit nodes
+// apart from other proxies
+type TorChecker interface {
+	IsTor(addr netip.Addr) bool
+}
+
+// NoopAnonymityProvider is the default AnonymityProvider; it reports no anonymization
+type NoopAnonymityProvider struct{}
+
+func (NoopAnonymityProvider) IsVPN(netip.Addr) bool     { return false }
+func (NoopAnonymityProvider) IsProxy(netip.Addr) bool   { return false }
+func (NoopAnonymityProvider) IsHosting(netip.Addr) bool { return false }
+
+// AnonymityInfo holds the AnonymityProvider verdicts for the client IP
+type AnonymityInfo struct {
+	VPN     bool `json:"vpn,omitempty"`
+	Proxy   bool `json:"proxy,omitempty"`
+	Hosting bool `json:"hosting,omitempty"`
+	// Tor is only set by providers implementing TorChecker
+	Tor bool `json:"tor,omitempty"`
+}
+
+// getAnonymity classifies the resolved client IP with provider (nil uses NoopAnonymityProvider)
+func getAnonymity(clientIP Component[netip.Addr], provider AnonymityProvider) Component[AnonymityInfo] {
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[AnonymityInfo]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+	if provider == nil {
+		provider = NoopAnonymityProvider{}
+	}
+
+	addr := clientIP.GetValue()
+	info := AnonymityInfo{
+		VPN:     provider.IsVPN(addr),
+		Proxy:   provider.IsProxy(addr),
+		Hosting: provider.IsHosting(addr),
+	}
+	if tor, ok := provider.(TorChecker); ok {
+		info.Tor = tor.IsTor(addr)
+	}
+	return SuccessComponent[AnonymityInfo]{
+		State: StateSuccess,
+		Value: info,
+	}
+}
+
+// detectAnonymity contributes a weighted signal for requests through VPNs and proxies.
+// Hosting verdicts feed the "datacenter" detector instead.
+func detectAnonymity(components *ComponentDict) *BotDetectionResult {
+	if components.Anonymity.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	info := components.Anonymity.GetValue()
+	switch {
+	case info.Proxy, info.Tor:
+		return &BotDetectionResult{
+			Bot:     false,
+			BotKind: BotKindProxy,
+			Score:   proxySignalWeight,
+		}
+	case info.VPN:
+		return &BotDetectionResult{
+			Bot:     false,
+			BotKind: BotKindVPN,
+			Score:   vpnSignalWeight,
+		}
+	}
+
+	return &BotDetectionResult{Bot: false}
+}
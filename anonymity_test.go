@@ -0,0 +1,70 @@
+package gogobot
+
+import (
+	"net/netip"
+	"testing"
+)
+
+// staticAnonymityProvider classifies fixed prefixes
+type staticAnonymityProvider struct {
+	vpn, proxy, hosting netip.Prefix
+}
+
+func (p staticAnonymityProvider) IsVPN(addr netip.Addr) bool     { return p.vpn.Contains(addr) }
+func (p staticAnonymityProvider) IsProxy(addr netip.Addr) bool   { return p.proxy.Contains(addr) }
+func (p staticAnonymityProvider) IsHosting(addr netip.Addr) bool { return p.hosting.Contains(addr) }
+
+func TestDetectAnonymity(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.AnonymityProvider = staticAnonymityProvider{
+		vpn:     netip.MustParsePrefix("198.51.100.0/25"),
+		proxy:   netip.MustParsePrefix("198.51.100.128/25"),
+		hosting: netip.MustParsePrefix("203.0.113.0/24"),
+	}
+	detector := NewDetectorWithConfig(config)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		anonymity  AnonymityInfo
+		kind       BotKind
+		datacenter bool
+	}{
+		{"VPN exit", "198.51.100.1:1234", AnonymityInfo{VPN: true}, BotKindVPN, false},
+		{"Proxy", "198.51.100.200:1234", AnonymityInfo{Proxy: true}, BotKindProxy, false},
+		{"Hosting feeds datacenter signal", "203.0.113.9:1234", AnonymityInfo{Hosting: true}, "", true},
+		{"Residential", "192.0.2.1:1234", AnonymityInfo{}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest("GET", "/", map[string]string{"User-Agent": "Mozilla/5.0"})
+			req.RemoteAddr = tt.remoteAddr
+			detector.DetectFromRequest(req)
+
+			if info := detector.GetComponents().Anonymity.GetValue(); info != tt.anonymity {
+				t.Errorf("Expected anonymity %+v, got %+v", tt.anonymity, info)
+			}
+			detections := detector.GetDetections()
+			if detections.Anonymity.BotKind != tt.kind {
+				t.Errorf("Expected anonymity kind %q, got %+v", tt.kind, detections.Anonymity)
+			}
+			if (detections.Datacenter.BotKind == BotKindDatacenter) != tt.datacenter {
+				t.Errorf("Expected datacenter signal %t, got %+v", tt.datacenter, detections.Datacenter)
+			}
+		})
+	}
+}
+
+func TestNoopAnonymityProvider(t *testing.T) {
+	req := createTestRequest("GET", "/", map[string]string{"User-Agent": "Mozilla/5.0"})
+	req.RemoteAddr = "198.51.100.1:1234"
+	components, _ := NewDetector().Collect(req)
+
+	if components.Anonymity.GetState() != StateSuccess {
+		t.Fatalf("Expected anonymity component, got error %s", components.Anonymity.GetError())
+	}
+	if info := components.Anonymity.GetValue(); info != (AnonymityInfo{}) {
+		t.Errorf("Expected no verdicts from the no-op provider, got %+v", info)
+	}
+}
@@ -43,6 +43,39 @@ func QuickCheck(req *http.Request) (BotDetectionResult, error) {
 // IsBotUserAgent checks if a user agent string indicates a bot
 // This is a utility function for checking user agents without a full HTTP request
 func IsBotUserAgent(userAgent string) (bool, BotKind) {
+	cache := activeUACache.Load()
+	if cache != nil {
+		if isBot, botKind, ok := cache.getBot(userAgent); ok {
+			return isBot, botKind
+		}
+	}
+
+	isBot, botKind := classifyUserAgent(userAgent)
+	if cache != nil {
+		cache.setBot(userAgent, isBot, botKind)
+	}
+	return isBot, botKind
+}
+
+// IsVerifiedBot checks whether the request comes from a crawler whose user agent claim is
+// confirmed by reverse DNS and forward confirmation of RemoteAddr. It returns the crawler
+// name, e.g. "Googlebot"; requests behind proxies should use DetectorConfig.CrawlerVerifier
+// together with a ClientIPResolver instead.
+func IsVerifiedBot(req *http.Request) (bool, string) {
+	clientIP := getResolvedClientIP(req, nil)
+	if clientIP.GetState() != StateSuccess {
+		return false, ""
+	}
+
+	verification, claimed := defaultCrawlerVerifier.Verify(req.Context(), req.Header.Get("User-Agent"), clientIP.GetValue())
+	if !claimed || !verification.Verified {
+		return false, ""
+	}
+	return true, verification.Crawler
+}
+
+// classifyUserAgent runs the user agent detector against a bare user agent string
+func classifyUserAgent(userAgent string) (bool, BotKind) {
 	// Create a minimal HTTP request just for user agent analysis
 	req, _ := http.NewRequest("GET", "/", nil)
 	req.Header.Set("User-Agent", userAgent)
@@ -126,18 +159,54 @@ func IsSupportedBrowser(req *http.Request, minVersions map[BrowserName]string) b
 	return browserInfo.IsSupported(minVersions)
 }
 
+// IsSupportedBrowserWithPolicy checks minimum version requirements, applying policy to unlisted browsers
+func IsSupportedBrowserWithPolicy(req *http.Request, minVersions map[BrowserName]string, policy UnknownBrowserPolicy) bool {
+	browserInfo := ParseBrowserFromRequest(req)
+	return browserInfo.IsSupportedWithPolicy(minVersions, policy)
+}
+
+// IsOutdatedBrowser checks if the request comes from an end-of-life browser version
+func IsOutdatedBrowser(req *http.Request) bool {
+	browserInfo := ParseBrowserFromRequest(req)
+	return IsOutdated(browserInfo)
+}
+
 // GetBrowserFamily returns the browser family from a user agent string
 func GetBrowserFamily(userAgent string) string {
 	browserInfo := ParseBrowserFromUserAgent(userAgent)
 	return browserInfo.GetBrowserFamily()
 }
 
+// GetOSFamily returns the operating system family from a user agent string
+func GetOSFamily(userAgent string) string {
+	browserInfo := BrowserInfo{RawUA: userAgent}
+	return browserInfo.GetOSFamily()
+}
+
 // IsMobileBrowser checks if the request comes from a mobile browser
 func IsMobileBrowser(req *http.Request) bool {
 	browserInfo := ParseBrowserFromRequest(req)
 	return browserInfo.IsMobile()
 }
 
+// IsTabletBrowser checks if the request comes from a tablet browser
+func IsTabletBrowser(req *http.Request) bool {
+	browserInfo := ParseBrowserFromRequest(req)
+	return browserInfo.IsTablet()
+}
+
+// IsDesktopBrowser checks if the request comes from a desktop browser
+func IsDesktopBrowser(req *http.Request) bool {
+	browserInfo := ParseBrowserFromRequest(req)
+	return browserInfo.IsDesktop()
+}
+
+// IsInAppBrowser checks if the request comes from an in-app browser webview and returns the app name
+func IsInAppBrowser(req *http.Request) (bool, string) {
+	browserInfo := ParseBrowserFromRequest(req)
+	return browserInfo.IsWebview(), browserInfo.WebviewApp
+}
+
 // IsGPTAgent checks if a user agent string indicates a GPT or AI agent
 func IsGPTAgent(userAgent string) (bool, BotKind) {
 	isBot, botKind := IsBotUserAgent(userAgent)
@@ -173,7 +242,8 @@ func IsOpenAIBot(userAgent string) bool {
 }
 
 // GetAIAgentInfo performs comprehensive AI agent analysis of an HTTP request
-// Returns whether it's an AI agent, the specific type, and any errors
+// Returns whether it's an AI agent, the specific type, and any errors. It runs full detection;
+// behind the middleware, GetAIInfoFromContext returns the classification already made.
 func GetAIAgentInfo(req *http.Request) (isAI bool, agentType BotKind, botResult BotDetectionResult, err error) {
 	// Perform full bot detection
 	detector := NewDetector()
@@ -183,12 +253,6 @@ func GetAIAgentInfo(req *http.Request) (isAI bool, agentType BotKind, botResult
 	}
 
 	// Check if it's specifically an AI agent
-	if botResult.Bot {
-		switch botResult.BotKind {
-		case BotKindGPTBot, BotKindChatGPT, BotKindOpenAI, BotKindClaude, BotKindAIAgent:
-			return true, botResult.BotKind, botResult, nil
-		}
-	}
-
-	return false, "", botResult, nil
+	info := newAIInfo(&botResult)
+	return info.IsAI, info.AgentType, botResult, nil
 }
@@ -326,3 +326,13 @@ func createTestHTTPRequest(method, path string, headers map[string]string) *http
 
 	return req
 }
+
+func TestIsVerifiedBot_NoClaim(t *testing.T) {
+	req := createTestRequest("GET", "/", map[string]string{"User-Agent": "curl/7.68.0"})
+	req.RemoteAddr = "66.249.66.1:1234"
+
+	// No DNS lookups happen for user agents that do not claim a verifiable crawler
+	if verified, crawler := IsVerifiedBot(req); verified || crawler != "" {
+		t.Errorf("Expected unverified result, got %t %q", verified, crawler)
+	}
+}
@@ -0,0 +1,112 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ASNInfo identifies the autonomous system announcing an IP address
+type ASNInfo struct {
+	Number       uint32 `json:"number"`
+	Organization string `json:"organization,omitempty"`
+}
+
+// String formats the ASN as e.g. "AS16509 Amazon.com, Inc."
+func (a ASNInfo) String() string {
+	if a.Number == 0 {
+		return ""
+	}
+	if a.Organization == "" {
+		return fmt.Sprintf("AS%d", a.Number)
+	}
+	return fmt.Sprintf("AS%d %s", a.Number, a.Organization)
+}
+
+// ASNProvider looks up the autonomous system of an IP address
+type ASNProvider interface {
+	LookupASN(addr netip.Addr) (ASNInfo, bool)
+}
+
+// MMDBASNProvider is an ASNProvider backed by a MaxMind GeoLite2-ASN or compatible database
+type MMDBASNProvider struct {
+	reader *MMDBReader
+}
+
+// NewMMDBASNProvider creates an ASNProvider from an open MaxMind DB reader
+func NewMMDBASNProvider(reader *MMDBReader) *MMDBASNProvider {
+	return &MMDBASNProvider{reader: reader}
+}
+
+// OpenMMDBASNProvider opens a GeoLite2-ASN database file
+func OpenMMDBASNProvider(path string) (*MMDBASNProvider, error) {
+	reader, err := OpenMMDB(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMMDBASNProvider(reader), nil
+}
+
+// LookupASN implements ASNProvider
+func (p *MMDBASNProvider) LookupASN(addr netip.Addr) (ASNInfo, bool) {
+	record, err := p.reader.Lookup(addr)
+	if err != nil {
+		return ASNInfo{}, false
+	}
+	fields, ok := record.(map[string]any)
+	if !ok {
+		return ASNInfo{}, false
+	}
+
+	number, _ := fields["autonomous_system_number"].(uint64)
+	if number == 0 {
+		return ASNInfo{}, false
+	}
+	organization, _ := fields["autonomous_system_organization"].(string)
+	return ASNInfo{Number: uint32(number), Organization: organization}, true
+}
+
+// hostingASNs maps autonomous systems operated by hosting and cloud providers
+var hostingASNs = map[uint32]DatacenterProvider{
+	16509:  DatacenterAWS,
+	14618:  DatacenterAWS,
+	396982: DatacenterGCP,
+	8075:   DatacenterAzure,
+	16276:  DatacenterOVH,
+	24940:  DatacenterHetzner,
+	14061:  DatacenterDigitalOcean,
+}
+
+// hostingASNProvider returns the hosting provider operating an autonomous system
+func hostingASNProvider(asn ASNInfo) (DatacenterProvider, bool) {
+	provider, ok := hostingASNs[asn.Number]
+	return provider, ok
+}
+
+// getASN looks up the resolved client IP with the configured ASNProvider
+func getASN(clientIP Component[netip.Addr], provider ASNProvider) Component[ASNInfo] {
+	if provider == nil {
+		return ErrorComponent[ASNInfo]{
+			State: StateUndefined,
+			Error: "no ASN provider configured",
+		}
+	}
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[ASNInfo]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+
+	asn, ok := provider.LookupASN(clientIP.GetValue())
+	if !ok {
+		return ErrorComponent[ASNInfo]{
+			State: StateNull,
+			Error: "no ASN found for " + strings.TrimSpace(clientIP.GetValue().String()),
+		}
+	}
+	return SuccessComponent[ASNInfo]{
+		State: StateSuccess,
+		Value: asn,
+	}
+}
@@ -0,0 +1,76 @@
+package gogobot
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestMMDBASNProvider_LookupASN(t *testing.T) {
+	reader, err := NewMMDBReader(buildTestASNDatabase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := NewMMDBASNProvider(reader)
+
+	tests := []struct {
+		addr     string
+		expected string
+		found    bool
+	}{
+		{"3.5.140.1", "AS16509 Amazon.com, Inc.", true},
+		{"::ffff:3.5.140.1", "AS16509 Amazon.com, Inc.", true},
+		{"2a01:4f8:c0c:1::1", "AS24940 Hetzner Online GmbH", true},
+		{"8.8.8.8", "", false},
+		{"2001:db8::1", "", false},
+	}
+
+	for _, tt := range tests {
+		asn, ok := provider.LookupASN(netip.MustParseAddr(tt.addr))
+		if ok != tt.found || asn.String() != tt.expected {
+			t.Errorf("LookupASN(%s) = %q, %t; want %q, %t", tt.addr, asn, ok, tt.expected, tt.found)
+		}
+	}
+}
+
+func TestASNInfo_String(t *testing.T) {
+	if s := (ASNInfo{Number: 13335}).String(); s != "AS13335" {
+		t.Errorf("Expected AS13335, got %q", s)
+	}
+	if s := (ASNInfo{}).String(); s != "" {
+		t.Errorf("Expected empty string, got %q", s)
+	}
+}
+
+type staticASNProvider map[netip.Addr]ASNInfo
+
+func (p staticASNProvider) LookupASN(addr netip.Addr) (ASNInfo, bool) {
+	asn, ok := p[addr]
+	return asn, ok
+}
+
+func TestBotDetector_ASNComponent(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.ASNProvider = staticASNProvider{
+		netip.MustParseAddr("198.51.100.10"): {Number: 14061, Organization: "DigitalOcean, LLC"},
+	}
+	detector := NewDetectorWithConfig(config)
+
+	req := createTestRequest("GET", "/", map[string]string{"User-Agent": "Mozilla/5.0"})
+	req.RemoteAddr = "198.51.100.10:1234"
+
+	components, _ := detector.Collect(req)
+	if components.ASN.GetState() != StateSuccess || components.ASN.GetValue().Number != 14061 {
+		t.Fatalf("Expected ASN component, got %+v (%s)", components.ASN.GetValue(), components.ASN.GetError())
+	}
+
+	// The range is not in the embedded datacenter set, but the ASN belongs to a hosting provider
+	detector.Detect()
+	if detections := detector.GetDetections(); detections.Datacenter.BotKind != BotKindDatacenter {
+		t.Errorf("Expected hosting ASN to produce a datacenter signal, got %+v", detections.Datacenter)
+	}
+
+	components, _ = NewDetector().Collect(req)
+	if components.ASN.GetState() == StateSuccess {
+		t.Error("Expected ASN component to be unavailable without a provider")
+	}
+}
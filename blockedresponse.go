@@ -0,0 +1,147 @@
+package gogobot
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// BlockedResponse renders the response to blocked bots by content negotiation, so API clients
+// get a structured error and people a branded page. Clients accepting neither representation
+// get BlockedMessage as plain text.
+type BlockedResponse struct {
+	// HTML renders the page for clients accepting text/html; nil omits the representation
+	HTML *template.Template
+	// JSON returns the value encoded for clients accepting application/json; nil omits the
+	// representation
+	JSON func(BlockedData) any
+	// Header is added to every blocked response, e.g. Cache-Control or a support link
+	Header http.Header
+}
+
+// BlockedData is what the representations of a blocked response are rendered from
+type BlockedData struct {
+	// StatusCode is the status of the response
+	StatusCode int
+	// Message is the middleware's BlockedMessage, or the status text of rate limited requests
+	Message string
+	// Result is the verdict behind the block
+	Result *BotDetectionResult
+}
+
+// defaultBlockedPage is the page of DefaultBlockedResponse
+var defaultBlockedPage = template.Must(template.New("blocked").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="robots" content="noindex"><title>Access denied</title></head>
+<body><h1>Access denied</h1>
+<p>{{.Message}}</p>
+</body></html>
+`))
+
+// DefaultBlockedResponse returns a minimal HTML page and a JSON error carrying the bot kind
+func DefaultBlockedResponse() *BlockedResponse {
+	return &BlockedResponse{
+		HTML: defaultBlockedPage,
+		JSON: func(data BlockedData) any {
+			return map[string]any{
+				"error":   data.Message,
+				"botKind": data.Result.BotKind,
+			}
+		},
+		Header: http.Header{"Cache-Control": {"no-store"}},
+	}
+}
+
+// serve writes the representation of the blocked response the client prefers
+func (b *BlockedResponse) serve(w http.ResponseWriter, req *http.Request, data BlockedData) {
+	for name, values := range b.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	var offers []string
+	if b.JSON != nil {
+		offers = append(offers, "application/json")
+	}
+	if b.HTML != nil {
+		offers = append(offers, "text/html")
+	}
+	offers = append(offers, "text/plain")
+
+	var (
+		body        bytes.Buffer
+		contentType string
+		err         error
+	)
+	switch negotiate(req.Header.Get("Accept"), offers) {
+	case "application/json":
+		contentType = "application/json"
+		err = json.NewEncoder(&body).Encode(b.JSON(data))
+	case "text/html":
+		contentType = "text/html; charset=utf-8"
+		err = b.HTML.Execute(&body, data)
+	default:
+		http.Error(w, data.Message, data.StatusCode)
+		return
+	}
+	// A broken template must still block
+	if err != nil {
+		http.Error(w, data.Message, data.StatusCode)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(data.StatusCode)
+	w.Write(body.Bytes())
+}
+
+// negotiate returns the offered media type the Accept header gives the highest quality, the
+// first offer breaking ties; a missing header accepts the first offer. Each offer takes the
+// quality of the most specific media range matching it.
+func negotiate(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	best, bestQuality := offers[len(offers)-1], 0.0
+	for _, offer := range offers {
+		quality, specificity := 0.0, -1
+		for _, item := range strings.Split(accept, ",") {
+			mediaRange, params, _ := strings.Cut(item, ";")
+			mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+
+			rangeSpecificity := -1
+			switch {
+			case mediaRange == offer:
+				rangeSpecificity = 2
+			case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaRange, "*")):
+				rangeSpecificity = 1
+			case mediaRange == "*/*":
+				rangeSpecificity = 0
+			}
+			if rangeSpecificity <= specificity {
+				continue
+			}
+			specificity, quality = rangeSpecificity, acceptQuality(params)
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the q parameter of a media range, 1 when absent or malformed
+func acceptQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.EqualFold(name, "q") {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				return q
+			}
+		}
+	}
+	return 1
+}
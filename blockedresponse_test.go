@@ -0,0 +1,71 @@
+package gogobot
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "text/html", "text/plain"}
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", "application/json"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "text/html"},
+		{"application/json", "application/json"},
+		{"*/*", "application/json"},
+		{"text/*", "text/html"},
+		{"text/*;q=0.5, text/plain", "text/plain"},
+		{"*/*;q=0.1, application/json;q=0", "text/html"},
+		{"image/png", "text/plain"},
+	}
+	for _, tt := range tests {
+		if got := negotiate(tt.accept, offers); got != tt.expected {
+			t.Errorf("Expected %s for %q, got %s", tt.expected, tt.accept, got)
+		}
+	}
+}
+
+func TestMiddleware_BlockedResponse(t *testing.T) {
+	config := DefaultMiddlewareConfig()
+	config.BlockBots = true
+	config.BlockedResponse = DefaultBlockedResponse()
+	config.BlockedResponse.HTML = template.Must(template.New("blocked").Parse(`<p>{{.Message}} ({{.Result.BotKind}})</p>`))
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("application/json")
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got %q: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusForbidden || body["botKind"] != string(BotKindCurl) || body["error"] != config.BlockedMessage {
+		t.Errorf("Expected JSON error for curl, got %d %v", rec.Code, body)
+	}
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected configured headers, got %v", rec.Header())
+	}
+
+	rec = serve("text/html")
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") || rec.Body.String() != "<p>Bot traffic is not allowed (curl)</p>" {
+		t.Errorf("Expected HTML page, got %q", rec.Body.String())
+	}
+
+	rec = serve("image/png")
+	if rec.Code != http.StatusForbidden || strings.TrimSpace(rec.Body.String()) != config.BlockedMessage {
+		t.Errorf("Expected plain text message, got %q", rec.Body.String())
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync/atomic"
+
+	"github.com/lytics/gogobot/core"
 )
 
 // ParseBrowserFromUserAgent extracts browser information from a user agent string
@@ -17,66 +20,99 @@ func ParseBrowserFromUserAgent(userAgent string) BrowserInfo {
 	}
 
 	ua := strings.TrimSpace(userAgent)
+	cache := activeUACache.Load()
+	if cache != nil {
+		if cached, ok := cache.getBrowser(ua); ok {
+			return cached
+		}
+	}
+
 	browserInfo := BrowserInfo{
 		RawUA: ua,
 	}
 
-	// First check if it's a bot
+	// Parse the underlying browser even for bots, since automation tools and crawlers
+	// frequently embed a real browser token (e.g. HeadlessChrome/91 or Googlebot's Chrome/)
+	browserInfo.Name, browserInfo.Version = parseBrowserNameAndVersion(ua)
+	browserInfo.Engine = parseEngine(ua)
+	browserInfo.DeviceType = browserInfo.parseDeviceType()
+	browserInfo.IsReducedUA = isReducedUserAgent(ua, browserInfo.Name, browserInfo.Version)
+
 	isBot, botKind := IsBotUserAgent(ua)
 	if isBot {
 		browserInfo.BotKind = botKind
-		browserInfo.Name = BrowserUnknown
-		return browserInfo
+	} else {
+		browserInfo.EmbeddedApp, browserInfo.IsEmbedded = parseEmbeddedApp(ua)
+		browserInfo.WebviewApp = parseWebviewApp(ua)
 	}
 
-	// Parse browser name and version
-	browserInfo.Name, browserInfo.Version = parseBrowserNameAndVersion(ua)
+	if cache != nil {
+		cache.setBrowser(ua, browserInfo)
+	}
 	return browserInfo
 }
 
-// parseBrowserNameAndVersion extracts browser name and version from user agent
-func parseBrowserNameAndVersion(ua string) (BrowserName, string) {
-	ua = strings.ToLower(ua)
-
-	// Browser patterns ordered by specificity (most specific first)
-	patterns := []struct {
-		name    BrowserName
-		pattern string
-	}{
-		// Microsoft Edge (must come before Chrome as it contains "chrome")
-		{BrowserEdge, `edg(?:e|a|ios)?\/([0-9]+(?:\.[0-9]+)*)`},
-
-		// Chrome-based browsers (must come before generic Chrome)
-		{BrowserYandex, `yabrowser\/([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserVivaldi, `vivaldi\/([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserBrave, `brave\/([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserSamsung, `samsungbrowser\/([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserUCBrowser, `ucbrowser\/([0-9]+(?:\.[0-9]+)*)`},
-
-		// Opera (various versions and formats)
-		{BrowserOpera, `(?:opera|opr)\/([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserOpera, `version\/([0-9]+(?:\.[0-9]+)*).*opera`},
-
-		// Chrome (must come after Chrome-based browsers)
-		{BrowserChrome, `chrome\/([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserChrome, `chromium\/([0-9]+(?:\.[0-9]+)*)`},
-
-		// Firefox
-		{BrowserFirefox, `firefox\/([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserFirefox, `fxios\/([0-9]+(?:\.[0-9]+)*)`}, // Firefox iOS
+// browserPattern pairs a browser name with a compiled version-extracting regexp
+type browserPattern struct {
+	name    BrowserName
+	pattern *regexp.Regexp
+}
 
-		// Safari (must come after other webkit browsers)
-		{BrowserSafari, `version\/([0-9]+(?:\.[0-9]+)*).*safari`},
-		{BrowserSafari, `mobile\/[0-9a-z]+.*safari\/([0-9]+(?:\.[0-9]+)*)`}, // Mobile Safari
+// browserPatterns are ordered by specificity (most specific first)
+var browserPatterns = []browserPattern{
+	// Smart TV and game console browsers (must come before the browsers they embed)
+	{BrowserNintendo, regexp.MustCompile(`nintendobrowser\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserPlayStation, regexp.MustCompile(`playstation (?:[0-9]+|vita)[ \/]([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserTizen, regexp.MustCompile(`smart-tv.*tizen ([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserTizen, regexp.MustCompile(`tizen ([0-9]+(?:\.[0-9]+)*).*\btv\b`)},
+	{BrowserWebOS, regexp.MustCompile(`(?:web0s|webos).*?(?:chrome|chromium)\/([0-9]+(?:\.[0-9]+)*)`)},
+
+	// Microsoft Edge (must come before Chrome as it contains "chrome")
+	{BrowserEdge, regexp.MustCompile(`edg(?:e|a|ios)?\/([0-9]+(?:\.[0-9]+)*)`)},
+
+	// Chrome-based browsers (must come before generic Chrome)
+	{BrowserYandex, regexp.MustCompile(`yabrowser\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserVivaldi, regexp.MustCompile(`vivaldi\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserBrave, regexp.MustCompile(`brave\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserSamsung, regexp.MustCompile(`samsungbrowser\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserUCBrowser, regexp.MustCompile(`ucbrowser\/([0-9]+(?:\.[0-9]+)*)`)},
+
+	// Opera (various versions and formats)
+	{BrowserOpera, regexp.MustCompile(`(?:opera|opr)\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserOpera, regexp.MustCompile(`version\/([0-9]+(?:\.[0-9]+)*).*opera`)},
+
+	// Chrome (must come after Chrome-based browsers)
+	{BrowserChrome, regexp.MustCompile(`chrome\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserChrome, regexp.MustCompile(`chromium\/([0-9]+(?:\.[0-9]+)*)`)},
+
+	// Firefox
+	{BrowserFirefox, regexp.MustCompile(`firefox\/([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserFirefox, regexp.MustCompile(`fxios\/([0-9]+(?:\.[0-9]+)*)`)}, // Firefox iOS
+
+	// Safari (must come after other webkit browsers)
+	{BrowserSafari, regexp.MustCompile(`version\/([0-9]+(?:\.[0-9]+)*).*safari`)},
+	{BrowserSafari, regexp.MustCompile(`mobile\/[0-9a-z]+.*safari\/([0-9]+(?:\.[0-9]+)*)`)}, // Mobile Safari
+
+	// Internet Explorer
+	{BrowserIE, regexp.MustCompile(`msie ([0-9]+(?:\.[0-9]+)*)`)},
+	{BrowserIE, regexp.MustCompile(`trident\/.*rv:([0-9]+(?:\.[0-9]+)*)`)}, // IE 11+
+}
 
-		// Internet Explorer
-		{BrowserIE, `msie ([0-9]+(?:\.[0-9]+)*)`},
-		{BrowserIE, `trident\/.*rv:([0-9]+(?:\.[0-9]+)*)`}, // IE 11+
+// parseBrowserNameAndVersion extracts browser name and version from user agent
+func parseBrowserNameAndVersion(ua string) (BrowserName, string) {
+	if loaded := loadedBrowserPatterns.Load(); loaded != nil {
+		if name, version, ok := loaded.db.Match(ua); ok {
+			return name, version
+		}
+		if loaded.mode == PatternModeReplace {
+			return BrowserUnknown, ""
+		}
 	}
 
-	for _, p := range patterns {
-		re := regexp.MustCompile(p.pattern)
-		matches := re.FindStringSubmatch(ua)
+	ua = strings.ToLower(ua)
+
+	for _, p := range browserPatterns {
+		matches := p.pattern.FindStringSubmatch(ua)
 		if len(matches) >= 2 {
 			version := matches[1]
 			// For Safari, handle special cases
@@ -108,6 +144,82 @@ func parseBrowserNameAndVersion(ua string) (BrowserName, string) {
 	return BrowserUnknown, ""
 }
 
+// Rendering engines reported in BrowserInfo.Engine
+const (
+	EngineBlink    = "Blink"
+	EngineWebKit   = "WebKit"
+	EngineGecko    = "Gecko"
+	EngineTrident  = "Trident"
+	EngineEdgeHTML = "EdgeHTML"
+	EnginePresto   = "Presto"
+)
+
+// legacyEdgePattern matches the EdgeHTML-based Edge token (Chromium Edge uses "Edg/")
+var legacyEdgePattern = regexp.MustCompile(`\bedge/[0-9]`)
+
+// parseEngine infers the rendering engine from the user agent
+func parseEngine(ua string) string {
+	ua = strings.ToLower(ua)
+
+	switch {
+	case strings.Contains(ua, "trident/") || strings.Contains(ua, "msie "):
+		return EngineTrident
+	case legacyEdgePattern.MatchString(ua):
+		return EngineEdgeHTML
+	case strings.Contains(ua, "presto/"):
+		return EnginePresto
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		// Every browser on iOS is required to use WebKit
+		return EngineWebKit
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "chromium/"):
+		return EngineBlink
+	case strings.Contains(ua, "applewebkit/"):
+		return EngineWebKit
+	case strings.Contains(ua, "gecko/") || strings.Contains(ua, "rv:"):
+		return EngineGecko
+	default:
+		return ""
+	}
+}
+
+// parseEmbeddedApp extracts the host application name from Electron-embedded user agents
+// (see core.EmbeddedApp)
+func parseEmbeddedApp(ua string) (string, bool) {
+	return core.EmbeddedApp(ua)
+}
+
+// In-app browser names reported in BrowserInfo.WebviewApp
+const (
+	WebviewFacebook  = "Facebook"
+	WebviewInstagram = "Instagram"
+	WebviewWeChat    = "WeChat"
+	WebviewTikTok    = "TikTok"
+	WebviewLine      = "Line"
+)
+
+// webviewPatterns match the tokens in-app browsers append to the platform user agent
+var webviewPatterns = []struct {
+	app     string
+	pattern *regexp.Regexp
+}{
+	{WebviewInstagram, regexp.MustCompile(`\binstagram\b`)},
+	{WebviewFacebook, regexp.MustCompile(`\bfb(?:an|av)/|\bfb_iab\b|\bfbios\b`)},
+	{WebviewWeChat, regexp.MustCompile(`\bmicromessenger/`)},
+	{WebviewTikTok, regexp.MustCompile(`\bmusical_ly|\bbytedancewebview\b|\btiktok\b`)},
+	{WebviewLine, regexp.MustCompile(`\bline/[0-9]`)},
+}
+
+// parseWebviewApp returns the app name when the user agent belongs to an in-app browser
+func parseWebviewApp(ua string) string {
+	ua = strings.ToLower(ua)
+	for _, w := range webviewPatterns {
+		if w.pattern.MatchString(ua) {
+			return w.app
+		}
+	}
+	return ""
+}
+
 // cleanSafariVersion handles Safari version parsing edge cases
 func cleanSafariVersion(version, ua string) string {
 	// Safari version mapping for iOS
@@ -121,28 +233,126 @@ func cleanSafariVersion(version, ua string) string {
 	return version
 }
 
-// ParseBrowserFromRequest extracts browser information from an HTTP request
+// ParseBrowserFromRequest extracts browser information from an HTTP request.
+// When Chromium sends a reduced user agent, the full version is taken from the
+// Sec-CH-UA-Full-Version-List client hint if present.
 func ParseBrowserFromRequest(req *http.Request) BrowserInfo {
 	userAgent := req.Header.Get("User-Agent")
-	return ParseBrowserFromUserAgent(userAgent)
+	browserInfo := ParseBrowserFromUserAgent(userAgent)
+
+	if version, ok := hintedFullVersion(req.Header, browserInfo.Name); ok {
+		browserInfo.Version = version
+	}
+	return browserInfo
+}
+
+// Browser families returned by GetBrowserFamily
+const (
+	FamilyChromium = "chromium"
+	FamilyGecko    = "gecko"
+	FamilyWebKit   = "webkit"
+	FamilyOpera    = "opera"
+	FamilyTrident  = "trident"
+	FamilyUnknown  = "unknown"
+)
+
+// defaultBrowserFamilies maps each built-in browser to its family
+var defaultBrowserFamilies = map[BrowserName]string{
+	BrowserChrome:      FamilyChromium,
+	BrowserEdge:        FamilyChromium,
+	BrowserYandex:      FamilyChromium,
+	BrowserVivaldi:     FamilyChromium,
+	BrowserBrave:       FamilyChromium,
+	BrowserSamsung:     FamilyChromium,
+	BrowserUCBrowser:   FamilyChromium,
+	BrowserTizen:       FamilyChromium,
+	BrowserWebOS:       FamilyChromium,
+	BrowserFirefox:     FamilyGecko,
+	BrowserSafari:      FamilyWebKit,
+	BrowserPlayStation: FamilyWebKit,
+	BrowserNintendo:    FamilyWebKit,
+	BrowserOpera:       FamilyOpera,
+	BrowserIE:          FamilyTrident,
+}
+
+// browserFamilies holds the active browser to family mapping (copy-on-write)
+var browserFamilies atomic.Pointer[map[BrowserName]string]
+
+func init() {
+	families := copyBrowserFamilies(defaultBrowserFamilies)
+	browserFamilies.Store(&families)
+}
+
+// GetBrowserFamilies returns a copy of the active browser to family mapping
+func GetBrowserFamilies() map[BrowserName]string {
+	return copyBrowserFamilies(*browserFamilies.Load())
+}
+
+// SetBrowserFamilies replaces the browser to family mapping. Passing nil restores the defaults.
+func SetBrowserFamilies(families map[BrowserName]string) {
+	if families == nil {
+		families = defaultBrowserFamilies
+	}
+	updated := copyBrowserFamilies(families)
+	browserFamilies.Store(&updated)
+}
+
+// SetBrowserFamily maps a browser name (e.g. a new Chromium fork or an enterprise browser
+// loaded via SetBrowserPatterns) to a family, leaving other mappings unchanged
+func SetBrowserFamily(name BrowserName, family string) {
+	for {
+		current := browserFamilies.Load()
+		updated := copyBrowserFamilies(*current)
+		updated[name] = family
+		if browserFamilies.CompareAndSwap(current, &updated) {
+			return
+		}
+	}
+}
+
+func copyBrowserFamilies(families map[BrowserName]string) map[BrowserName]string {
+	copied := make(map[BrowserName]string, len(families))
+	for name, family := range families {
+		copied[name] = family
+	}
+	return copied
+}
+
+// botKindFamilies maps automation tools that execute JavaScript to the engine they drive
+var botKindFamilies = map[BotKind]string{
+	BotKindHeadlessChrome: FamilyChromium,
+	BotKindPuppeteer:      FamilyChromium,
+	BotKindElectron:       FamilyChromium,
+	BotKindPhantomJS:      FamilyWebKit,
+	BotKindSlimerJS:       FamilyGecko,
+}
+
+// engineFamilies maps rendering engines to browser families
+var engineFamilies = map[string]string{
+	EngineBlink:   FamilyChromium,
+	EngineWebKit:  FamilyWebKit,
+	EngineGecko:   FamilyGecko,
+	EngineTrident: FamilyTrident,
 }
 
-// GetBrowserFamily returns the browser family (useful for grouping similar browsers)
+// GetBrowserFamily returns the browser family (useful for grouping similar browsers).
+// For bots without a recognizable browser token, the family is inferred from the
+// automation tool or rendering engine so JS-executing bots can still be grouped.
 func (bi BrowserInfo) GetBrowserFamily() string {
-	switch bi.Name {
-	case BrowserChrome, BrowserEdge, BrowserYandex, BrowserVivaldi, BrowserBrave, BrowserSamsung, BrowserUCBrowser:
-		return "chromium"
-	case BrowserFirefox:
-		return "gecko"
-	case BrowserSafari:
-		return "webkit"
-	case BrowserOpera:
-		return "opera"
-	case BrowserIE:
-		return "trident"
-	default:
-		return "unknown"
+	if family, ok := (*browserFamilies.Load())[bi.Name]; ok {
+		return family
+	}
+
+	if bi.IsBot() {
+		if family, ok := botKindFamilies[bi.BotKind]; ok {
+			return family
+		}
+		if family, ok := engineFamilies[bi.Engine]; ok {
+			return family
+		}
 	}
+
+	return FamilyUnknown
 }
 
 // IsMobile attempts to detect if the browser is on a mobile device
@@ -161,6 +371,96 @@ func (bi BrowserInfo) IsMobile() bool {
 	return false
 }
 
+// IsTablet attempts to detect if the browser is on a tablet device.
+// iPadOS 13+ reports a macOS user agent by default, so a "Macintosh" user agent is only
+// treated as an iPad when it also carries iOS-only hints (a Mobile/ build token or an
+// iOS browser token such as CriOS/FxiOS/EdgiOS). Plain desktop-mode Safari on an iPad
+// is indistinguishable from macOS Safari on the server.
+func (bi BrowserInfo) IsTablet() bool {
+	ua := strings.ToLower(bi.RawUA)
+	if ua == "" {
+		return false
+	}
+
+	tabletIndicators := []string{
+		"ipad", "tablet", "playbook", "kindle", "silk/", "nexus 7", "nexus 9", "nexus 10", "sm-t", "kfapwi",
+	}
+	for _, indicator := range tabletIndicators {
+		if strings.Contains(ua, indicator) {
+			return true
+		}
+	}
+
+	// Android tablets omit the "Mobile" token that Android phones send
+	if strings.Contains(ua, "android") && !strings.Contains(ua, "mobile") {
+		return true
+	}
+
+	return isMasqueradingIPad(ua)
+}
+
+// isMasqueradingIPad detects iPadOS devices that report a macOS user agent
+func isMasqueradingIPad(ua string) bool {
+	if !strings.Contains(ua, "macintosh") {
+		return false
+	}
+
+	iosHints := []string{"mobile/", "crios/", "fxios/", "edgios/", "opt/"}
+	for _, hint := range iosHints {
+		if strings.Contains(ua, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDesktop attempts to detect if the browser is on a desktop device
+func (bi BrowserInfo) IsDesktop() bool {
+	if bi.RawUA == "" {
+		return false
+	}
+	return !bi.IsMobile() && !bi.IsTablet() && !bi.IsTV() && !bi.IsConsole()
+}
+
+// IsTV attempts to detect if the browser is on a smart TV or streaming device
+func (bi BrowserInfo) IsTV() bool {
+	ua := strings.ToLower(bi.RawUA)
+	tvIndicators := []string{
+		"smart-tv", "smarttv", "web0s", "webos tv", "netcast", "hbbtv", "googletv", "android tv",
+		"appletv", "apple tv", "roku", "aftb", "aftt", "aftm", "crkey", "bravia", "viera", "philipstv",
+	}
+	if containsAny(ua, tvIndicators...) {
+		return true
+	}
+	// Tizen is also used on phones and watches, which identify as mobile
+	return strings.Contains(ua, "tizen") && strings.Contains(ua, " tv")
+}
+
+// IsConsole attempts to detect if the browser is on a game console
+func (bi BrowserInfo) IsConsole() bool {
+	ua := strings.ToLower(bi.RawUA)
+	return containsAny(ua, "playstation", "xbox", "nintendo")
+}
+
+// parseDeviceType classifies the device, checking TV and console tokens before the
+// mobile/tablet heuristics since console and TV user agents often contain them
+func (bi BrowserInfo) parseDeviceType() DeviceType {
+	switch {
+	case bi.RawUA == "":
+		return DeviceUnknown
+	case bi.IsConsole():
+		return DeviceConsole
+	case bi.IsTV():
+		return DeviceTV
+	case bi.IsTablet():
+		return DeviceTablet
+	case bi.IsMobile():
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
 // GetMajorVersion returns just the major version number
 func (bi BrowserInfo) GetMajorVersion() string {
 	if bi.Version == "" {
@@ -174,69 +474,72 @@ func (bi BrowserInfo) GetMajorVersion() string {
 	return bi.Version
 }
 
+// UnknownBrowserPolicy controls how IsSupportedWithPolicy treats browsers missing from the minimum versions map
+type UnknownBrowserPolicy int
+
+const (
+	// UnknownBrowserDeny treats unlisted browsers as unsupported
+	UnknownBrowserDeny UnknownBrowserPolicy = iota
+	// UnknownBrowserAllow treats unlisted browsers as supported
+	UnknownBrowserAllow
+	// UnknownBrowserMapToFamily checks unlisted browsers against the minimum of their engine family's
+	// reference browser (e.g. Brave and Vivaldi inherit the Chrome minimum)
+	UnknownBrowserMapToFamily
+)
+
+// familyReferenceBrowsers maps a browser family to the browser whose minimum version forks inherit
+var familyReferenceBrowsers = map[string]BrowserName{
+	FamilyChromium: BrowserChrome,
+	FamilyGecko:    BrowserFirefox,
+	FamilyWebKit:   BrowserSafari,
+}
+
 // IsSupported checks if the browser version meets minimum requirements
 func (bi BrowserInfo) IsSupported(minVersions map[BrowserName]string) bool {
+	return bi.IsSupportedWithPolicy(minVersions, UnknownBrowserDeny)
+}
+
+// IsSupportedWithPolicy checks if the browser version meets minimum requirements,
+// applying policy when the browser is not listed in minVersions
+func (bi BrowserInfo) IsSupportedWithPolicy(minVersions map[BrowserName]string, policy UnknownBrowserPolicy) bool {
 	if bi.IsBot() {
 		return false
 	}
 
 	minVersion, exists := minVersions[bi.Name]
-	if !exists {
-		return false
-	}
-
-	return compareVersions(bi.Version, minVersion) >= 0
-}
-
-// compareVersions compares two version strings
-// Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
-func compareVersions(v1, v2 string) int {
-	if v1 == v2 {
-		return 0
+	if exists {
+		return compareVersions(bi.Version, minVersion) >= 0
 	}
 
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-
-	maxLen := len(parts1)
-	if len(parts2) > maxLen {
-		maxLen = len(parts2)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		var p1, p2 int
-
-		if i < len(parts1) {
-			p1 = parseVersionPart(parts1[i])
-		}
-		if i < len(parts2) {
-			p2 = parseVersionPart(parts2[i])
-		}
-
-		if p1 < p2 {
-			return -1
+	switch policy {
+	case UnknownBrowserAllow:
+		return true
+	case UnknownBrowserMapToFamily:
+		reference, ok := familyReferenceBrowsers[bi.GetBrowserFamily()]
+		if !ok {
+			return false
 		}
-		if p1 > p2 {
-			return 1
+		minVersion, exists = minVersions[reference]
+		if !exists {
+			return false
 		}
+		return compareVersions(bi.referenceVersion(reference), minVersion) >= 0
+	default:
+		return false
 	}
-
-	return 0
 }
 
-// parseVersionPart extracts numeric part from version component
-func parseVersionPart(part string) int {
-	re := regexp.MustCompile(`\d+`)
-	match := re.FindString(part)
-	if match == "" {
-		return 0
-	}
-
-	var result int
-	for _, char := range match {
-		if char >= '0' && char <= '9' {
-			result = result*10 + int(char-'0')
+// referenceVersion returns the version of the reference browser token embedded in the user agent
+// (e.g. the Chrome/ token of a Vivaldi user agent), falling back to the parsed version
+func (bi BrowserInfo) referenceVersion(reference BrowserName) string {
+	ua := strings.ToLower(bi.RawUA)
+	for _, p := range browserPatterns {
+		if p.name != reference {
+			continue
+		}
+		if matches := p.pattern.FindStringSubmatch(ua); len(matches) >= 2 {
+			return matches[1]
 		}
 	}
-	return result
+	return bi.Version
 }
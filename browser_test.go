@@ -129,7 +129,7 @@ func TestParseBrowserFromUserAgent(t *testing.T) {
 			expectedBrowser: BrowserUnknown,
 			expectedVersion: "",
 			expectedIsBot:   true,
-			expectedFamily:  "unknown",
+			expectedFamily:  "webkit",
 			expectedMobile:  false,
 		},
 		{
@@ -356,3 +356,503 @@ func BenchmarkParseBrowserFromUserAgent(b *testing.B) {
 		ParseBrowserFromUserAgent(userAgent)
 	}
 }
+
+func TestBrowserInfoDeviceType(t *testing.T) {
+	tests := []struct {
+		name            string
+		userAgent       string
+		expectedMobile  bool
+		expectedTablet  bool
+		expectedDesktop bool
+	}{
+		{
+			name:            "Windows Chrome",
+			userAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expectedDesktop: true,
+		},
+		{
+			name:            "macOS Safari",
+			userAgent:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			expectedDesktop: true,
+		},
+		{
+			name:           "iPhone Safari",
+			userAgent:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			expectedMobile: true,
+		},
+		{
+			name:           "iPad Safari legacy UA",
+			userAgent:      "Mozilla/5.0 (iPad; CPU OS 12_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/12.1 Mobile/15E148 Safari/604.1",
+			expectedMobile: true,
+			expectedTablet: true,
+		},
+		{
+			name:           "iPadOS in-app webview masquerading as macOS",
+			userAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148",
+			expectedMobile: true,
+			expectedTablet: true,
+		},
+		{
+			name:           "iPadOS Chrome masquerading as macOS",
+			userAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Safari/604.1",
+			expectedTablet: true,
+		},
+		{
+			name:           "Android phone",
+			userAgent:      "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			expectedMobile: true,
+		},
+		{
+			name:           "Android tablet",
+			userAgent:      "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expectedMobile: true,
+			expectedTablet: true,
+		},
+		{
+			name:      "Empty user agent",
+			userAgent: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			browserInfo := BrowserInfo{RawUA: tt.userAgent}
+
+			if browserInfo.IsMobile() != tt.expectedMobile {
+				t.Errorf("Expected IsMobile=%t, got %t", tt.expectedMobile, browserInfo.IsMobile())
+			}
+			if browserInfo.IsTablet() != tt.expectedTablet {
+				t.Errorf("Expected IsTablet=%t, got %t", tt.expectedTablet, browserInfo.IsTablet())
+			}
+			if browserInfo.IsDesktop() != tt.expectedDesktop {
+				t.Errorf("Expected IsDesktop=%t, got %t", tt.expectedDesktop, browserInfo.IsDesktop())
+			}
+		})
+	}
+}
+
+func TestParseEmbeddedApp(t *testing.T) {
+	tests := []struct {
+		name             string
+		userAgent        string
+		expectedBrowser  BrowserName
+		expectedVersion  string
+		expectedEmbedded bool
+		expectedApp      string
+		expectedBot      bool
+	}{
+		{
+			name:             "Slack desktop",
+			userAgent:        "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Slack/4.36.140 Chrome/120.0.6099.56 Electron/28.1.0 Safari/537.36",
+			expectedBrowser:  BrowserChrome,
+			expectedVersion:  "120.0.6099.56",
+			expectedEmbedded: true,
+			expectedApp:      "Slack",
+		},
+		{
+			name:             "VS Code webview",
+			userAgent:        "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Code/1.85.1 Chrome/114.0.5735.289 Electron/25.9.7 Safari/537.36",
+			expectedBrowser:  BrowserChrome,
+			expectedVersion:  "114.0.5735.289",
+			expectedEmbedded: true,
+			expectedApp:      "Code",
+		},
+		{
+			name:        "Bare Electron runtime",
+			userAgent:   "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.56 Electron/28.1.0 Safari/537.36",
+			expectedBot: true,
+		},
+		{
+			name:            "Regular Chrome",
+			userAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expectedBrowser: BrowserChrome,
+			expectedVersion: "120.0.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseBrowserFromUserAgent(tt.userAgent)
+
+			if result.IsBot() != tt.expectedBot {
+				t.Fatalf("Expected IsBot=%t, got %t (kind %s)", tt.expectedBot, result.IsBot(), result.BotKind)
+			}
+			if tt.expectedBot {
+				if result.BotKind != BotKindElectron {
+					t.Errorf("Expected bot kind %s, got %s", BotKindElectron, result.BotKind)
+				}
+				return
+			}
+			if result.Name != tt.expectedBrowser {
+				t.Errorf("Expected browser %s, got %s", tt.expectedBrowser, result.Name)
+			}
+			if result.Version != tt.expectedVersion {
+				t.Errorf("Expected version %s, got %s", tt.expectedVersion, result.Version)
+			}
+			if result.IsEmbedded != tt.expectedEmbedded {
+				t.Errorf("Expected IsEmbedded=%t, got %t", tt.expectedEmbedded, result.IsEmbedded)
+			}
+			if result.EmbeddedApp != tt.expectedApp {
+				t.Errorf("Expected embedded app %q, got %q", tt.expectedApp, result.EmbeddedApp)
+			}
+		})
+	}
+}
+
+func TestParseWebviewApp(t *testing.T) {
+	tests := []struct {
+		name            string
+		userAgent       string
+		expectedApp     string
+		expectedBrowser BrowserName
+	}{
+		{
+			name:            "Facebook iOS",
+			userAgent:       "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 [FBAN/FBIOS;FBDV/iPhone14,5;FBMD/iPhone;FBSN/iOS;FBSV/17.1;FBSS/3;FBID/phone;FBLC/en_US;FBOP/5]",
+			expectedApp:     WebviewFacebook,
+			expectedBrowser: BrowserUnknown,
+		},
+		{
+			name:            "Facebook Android",
+			userAgent:       "Mozilla/5.0 (Linux; Android 14; Pixel 8 Build/UD1A.230803.041; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.43 Mobile Safari/537.36 [FB_IAB/FB4A;FBAV/443.0.0.23.229;]",
+			expectedApp:     WebviewFacebook,
+			expectedBrowser: BrowserChrome,
+		},
+		{
+			name:            "Instagram",
+			userAgent:       "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Instagram 309.0.0.40.113 (iPhone14,5; iOS 17_1; en_US; en; scale=3.00; 1170x2532; 537288532)",
+			expectedApp:     WebviewInstagram,
+			expectedBrowser: BrowserUnknown,
+		},
+		{
+			name:            "WeChat",
+			userAgent:       "Mozilla/5.0 (Linux; Android 13; V2055A Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/107.0.5304.141 Mobile Safari/537.36 XWEB/5235 MMWEBSDK/20230805 MicroMessenger/8.0.42.2460(0x28002A3B) WeChat/arm64 Weixin NetType/WIFI Language/zh_CN ABI/arm64",
+			expectedApp:     WebviewWeChat,
+			expectedBrowser: BrowserChrome,
+		},
+		{
+			name:            "TikTok",
+			userAgent:       "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 musical_ly_32.5.0 JsSdk/2.0 NetType/WIFI Channel/App Store ByteLocale/en Region/US",
+			expectedApp:     WebviewTikTok,
+			expectedBrowser: BrowserUnknown,
+		},
+		{
+			name:            "Line",
+			userAgent:       "Mozilla/5.0 (Linux; Android 14; SM-S918B Build/UP1A.231005.007; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.144 Mobile Safari/537.36 Line/13.20.1/IAB",
+			expectedApp:     WebviewLine,
+			expectedBrowser: BrowserChrome,
+		},
+		{
+			name:            "Mobile Safari",
+			userAgent:       "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			expectedBrowser: BrowserSafari,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseBrowserFromUserAgent(tt.userAgent)
+
+			if result.IsBot() {
+				t.Fatalf("Expected in-app browser not to be a bot, got %s", result.BotKind)
+			}
+			if result.WebviewApp != tt.expectedApp {
+				t.Errorf("Expected webview app %q, got %q", tt.expectedApp, result.WebviewApp)
+			}
+			if result.IsWebview() != (tt.expectedApp != "") {
+				t.Errorf("Expected IsWebview=%t", tt.expectedApp != "")
+			}
+			if result.Name != tt.expectedBrowser {
+				t.Errorf("Expected browser %s, got %s", tt.expectedBrowser, result.Name)
+			}
+		})
+	}
+}
+
+func TestIsSupportedWithPolicy(t *testing.T) {
+	minVersions := map[BrowserName]string{
+		BrowserChrome:  "119.0.0.0",
+		BrowserFirefox: "115.0",
+	}
+
+	vivaldi := ParseBrowserFromUserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Vivaldi/6.5.3206.48")
+	oldVivaldi := ParseBrowserFromUserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/100.0.0.0 Safari/537.36 Vivaldi/5.2.2623.41")
+	safari := ParseBrowserFromUserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15")
+
+	tests := []struct {
+		name     string
+		browser  BrowserInfo
+		policy   UnknownBrowserPolicy
+		expected bool
+	}{
+		{"Vivaldi deny", vivaldi, UnknownBrowserDeny, false},
+		{"Vivaldi allow", vivaldi, UnknownBrowserAllow, true},
+		{"Vivaldi maps to Chrome minimum", vivaldi, UnknownBrowserMapToFamily, true},
+		{"Old Vivaldi maps to Chrome minimum", oldVivaldi, UnknownBrowserMapToFamily, false},
+		{"Safari without webkit minimum", safari, UnknownBrowserMapToFamily, false},
+		{"Safari allow", safari, UnknownBrowserAllow, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.browser.IsSupportedWithPolicy(minVersions, tt.policy); result != tt.expected {
+				t.Errorf("Expected IsSupportedWithPolicy=%t, got %t", tt.expected, result)
+			}
+		})
+	}
+
+	bot := BrowserInfo{Name: BrowserChrome, Version: "120.0", BotKind: BotKindBot}
+	if bot.IsSupportedWithPolicy(minVersions, UnknownBrowserAllow) {
+		t.Error("Expected bots to be unsupported regardless of policy")
+	}
+}
+
+func TestParseBrowserFromBotUserAgent(t *testing.T) {
+	tests := []struct {
+		name            string
+		userAgent       string
+		expectedKind    BotKind
+		expectedBrowser BrowserName
+		expectedVersion string
+		expectedEngine  string
+	}{
+		{
+			name:            "HeadlessChrome",
+			userAgent:       "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/91.0.4472.124 Safari/537.36",
+			expectedKind:    BotKindHeadlessChrome,
+			expectedBrowser: BrowserChrome,
+			expectedVersion: "91.0.4472.124",
+			expectedEngine:  EngineBlink,
+		},
+		{
+			name:            "Evergreen Googlebot",
+			userAgent:       "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/120.0.6099.216 Safari/537.36",
+			expectedKind:    BotKindCrawler,
+			expectedBrowser: BrowserChrome,
+			expectedVersion: "120.0.6099.216",
+			expectedEngine:  EngineBlink,
+		},
+		{
+			name:            "PhantomJS",
+			userAgent:       "Mozilla/5.0 (Unknown; Linux x86_64) AppleWebKit/538.1 (KHTML, like Gecko) PhantomJS/2.1.1 Safari/538.1",
+			expectedKind:    BotKindPhantomJS,
+			expectedBrowser: BrowserUnknown,
+			expectedEngine:  EngineWebKit,
+		},
+		{
+			name:            "curl",
+			userAgent:       "curl/7.68.0",
+			expectedKind:    BotKindCurl,
+			expectedBrowser: BrowserUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseBrowserFromUserAgent(tt.userAgent)
+
+			if result.BotKind != tt.expectedKind {
+				t.Errorf("Expected bot kind %s, got %s", tt.expectedKind, result.BotKind)
+			}
+			if result.Name != tt.expectedBrowser {
+				t.Errorf("Expected browser %s, got %s", tt.expectedBrowser, result.Name)
+			}
+			if result.Version != tt.expectedVersion {
+				t.Errorf("Expected version %s, got %s", tt.expectedVersion, result.Version)
+			}
+			if result.Engine != tt.expectedEngine {
+				t.Errorf("Expected engine %q, got %q", tt.expectedEngine, result.Engine)
+			}
+		})
+	}
+}
+
+func TestParseEngine(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", EngineBlink},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", EngineGecko},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", EngineWebKit},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1", EngineWebKit},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19582", EngineEdgeHTML},
+		{"Mozilla/5.0 (Windows NT 10.0; Trident/7.0; rv:11.0) like Gecko", EngineTrident},
+		{"Opera/9.80 (Windows NT 6.1; WOW64) Presto/2.12.388 Version/12.18", EnginePresto},
+		{"SomeCustomBrowser/1.0", ""},
+	}
+
+	for _, tt := range tests {
+		if result := parseEngine(tt.userAgent); result != tt.expected {
+			t.Errorf("parseEngine(%q) = %q, expected %q", tt.userAgent, result, tt.expected)
+		}
+	}
+}
+
+func TestParseTVAndConsoleBrowsers(t *testing.T) {
+	tests := []struct {
+		name            string
+		userAgent       string
+		expectedBrowser BrowserName
+		expectedVersion string
+		expectedDevice  DeviceType
+	}{
+		{
+			name:            "Samsung Tizen TV",
+			userAgent:       "Mozilla/5.0 (SMART-TV; LINUX; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36",
+			expectedBrowser: BrowserTizen,
+			expectedVersion: "6.0",
+			expectedDevice:  DeviceTV,
+		},
+		{
+			name:            "LG webOS TV",
+			userAgent:       "Mozilla/5.0 (Web0S; Linux/SmartTV) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.79 Safari/537.36 WebAppManager",
+			expectedBrowser: BrowserWebOS,
+			expectedVersion: "79.0.3945.79",
+			expectedDevice:  DeviceTV,
+		},
+		{
+			name:            "PlayStation 5",
+			userAgent:       "Mozilla/5.0 (PlayStation; PlayStation 5/2.26) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0 Safari/605.1.15",
+			expectedBrowser: BrowserPlayStation,
+			expectedVersion: "2.26",
+			expectedDevice:  DeviceConsole,
+		},
+		{
+			name:            "PlayStation 4",
+			userAgent:       "Mozilla/5.0 (PlayStation 4 8.52) AppleWebKit/605.1.15 (KHTML, like Gecko)",
+			expectedBrowser: BrowserPlayStation,
+			expectedVersion: "8.52",
+			expectedDevice:  DeviceConsole,
+		},
+		{
+			name:            "Xbox One Edge",
+			userAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64; Xbox; Xbox One) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			expectedBrowser: BrowserEdge,
+			expectedVersion: "120.0.2210.91",
+			expectedDevice:  DeviceConsole,
+		},
+		{
+			name:            "Nintendo Switch",
+			userAgent:       "Mozilla/5.0 (Nintendo Switch; WifiWebAuthApplet) AppleWebKit/606.4 (KHTML, like Gecko) NF/6.0.1.15.4 NintendoBrowser/5.1.0.20393",
+			expectedBrowser: BrowserNintendo,
+			expectedVersion: "5.1.0.20393",
+			expectedDevice:  DeviceConsole,
+		},
+		{
+			name:            "Desktop Chrome",
+			userAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expectedBrowser: BrowserChrome,
+			expectedVersion: "120.0.0.0",
+			expectedDevice:  DeviceDesktop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseBrowserFromUserAgent(tt.userAgent)
+
+			if result.Name != tt.expectedBrowser {
+				t.Errorf("Expected browser %s, got %s", tt.expectedBrowser, result.Name)
+			}
+			if result.Version != tt.expectedVersion {
+				t.Errorf("Expected version %s, got %s", tt.expectedVersion, result.Version)
+			}
+			if result.DeviceType != tt.expectedDevice {
+				t.Errorf("Expected device type %s, got %s", tt.expectedDevice, result.DeviceType)
+			}
+			if tt.expectedDevice != DeviceDesktop && result.IsDesktop() {
+				t.Error("Expected TV/console not to be reported as desktop")
+			}
+		})
+	}
+}
+
+func TestSetBrowserFamily(t *testing.T) {
+	defer SetBrowserFamilies(nil)
+
+	arc := BrowserInfo{Name: "Arc", Version: "1.23.4"}
+	if family := arc.GetBrowserFamily(); family != FamilyUnknown {
+		t.Errorf("Expected unknown family before mapping, got %s", family)
+	}
+
+	SetBrowserFamily("Arc", FamilyChromium)
+	if family := arc.GetBrowserFamily(); family != FamilyChromium {
+		t.Errorf("Expected chromium family after mapping, got %s", family)
+	}
+	if family := (BrowserInfo{Name: BrowserFirefox}).GetBrowserFamily(); family != FamilyGecko {
+		t.Errorf("Expected existing mappings to be preserved, got %s", family)
+	}
+
+	families := GetBrowserFamilies()
+	families[BrowserFirefox] = "modified"
+	if family := (BrowserInfo{Name: BrowserFirefox}).GetBrowserFamily(); family != FamilyGecko {
+		t.Error("Expected GetBrowserFamilies to return a copy")
+	}
+
+	SetBrowserFamilies(map[BrowserName]string{BrowserOpera: FamilyChromium})
+	if family := (BrowserInfo{Name: BrowserOpera}).GetBrowserFamily(); family != FamilyChromium {
+		t.Errorf("Expected replaced mapping for Opera, got %s", family)
+	}
+	if family := (BrowserInfo{Name: BrowserFirefox}).GetBrowserFamily(); family != FamilyUnknown {
+		t.Errorf("Expected Firefox to be unmapped after replacement, got %s", family)
+	}
+
+	SetBrowserFamilies(nil)
+	if family := arc.GetBrowserFamily(); family != FamilyUnknown {
+		t.Errorf("Expected defaults to be restored, got %s", family)
+	}
+}
+
+func TestGetBrowserFamily_Bots(t *testing.T) {
+	tests := []struct {
+		name           string
+		userAgent      string
+		expectedKind   BotKind
+		expectedFamily string
+	}{
+		{
+			name:           "HeadlessChrome",
+			userAgent:      "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/91.0.4472.124 Safari/537.36",
+			expectedKind:   BotKindHeadlessChrome,
+			expectedFamily: FamilyChromium,
+		},
+		{
+			name:           "PhantomJS",
+			userAgent:      "Mozilla/5.0 (Unknown; Linux x86_64) AppleWebKit/538.1 (KHTML, like Gecko) PhantomJS/2.1.1 Safari/538.1",
+			expectedKind:   BotKindPhantomJS,
+			expectedFamily: FamilyWebKit,
+		},
+		{
+			name:           "SlimerJS",
+			userAgent:      "Mozilla/5.0 (X11; Linux x86_64; rv:38.0) Gecko/20100101 SlimerJS/0.10.3",
+			expectedKind:   BotKindSlimerJS,
+			expectedFamily: FamilyGecko,
+		},
+		{
+			name:           "Classic Googlebot",
+			userAgent:      "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expectedKind:   BotKindCrawler,
+			expectedFamily: FamilyUnknown,
+		},
+		{
+			name:           "curl",
+			userAgent:      "curl/7.68.0",
+			expectedKind:   BotKindCurl,
+			expectedFamily: FamilyUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseBrowserFromUserAgent(tt.userAgent)
+
+			if result.BotKind != tt.expectedKind {
+				t.Errorf("Expected bot kind %s, got %s", tt.expectedKind, result.BotKind)
+			}
+			if family := result.GetBrowserFamily(); family != tt.expectedFamily {
+				t.Errorf("Expected family %s, got %s", tt.expectedFamily, family)
+			}
+		})
+	}
+}
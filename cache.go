@@ -0,0 +1,114 @@
+package gogobot
+
+import (
+	"hash/fnv"
+	"sync/atomic"
+)
+
+// UACache is an optional bounded LRU cache for user agent parsing and classification.
+// User agent strings repeat heavily in real traffic, so caching avoids re-running
+// the pattern matching for every request on high-QPS services.
+type UACache struct {
+	browsers *lruCache[uint64, BrowserInfo]
+	bots     *lruCache[uint64, uaBotEntry]
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+}
+
+// UACacheStats holds hit/miss statistics for a UACache
+type UACacheStats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+}
+
+// HitRate returns the fraction of lookups served from the cache
+func (s UACacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type uaBotEntry struct {
+	ua      string
+	isBot   bool
+	botKind BotKind
+}
+
+// activeUACache is the package-wide cache used by ParseBrowserFromUserAgent and IsBotUserAgent
+var activeUACache atomic.Pointer[UACache]
+
+// NewUACache creates a UACache holding up to capacity entries per lookup type
+func NewUACache(capacity int) *UACache {
+	return &UACache{
+		browsers: newLRUCache[uint64, BrowserInfo](capacity),
+		bots:     newLRUCache[uint64, uaBotEntry](capacity),
+	}
+}
+
+// SetUACache installs the cache used by ParseBrowserFromUserAgent and IsBotUserAgent.
+// Passing nil disables caching.
+func SetUACache(cache *UACache) {
+	activeUACache.Store(cache)
+}
+
+// GetUACache returns the currently installed UACache, or nil if caching is disabled
+func GetUACache() *UACache {
+	return activeUACache.Load()
+}
+
+// Stats returns the current hit/miss statistics
+func (c *UACache) Stats() UACacheStats {
+	return UACacheStats{
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Size:     c.browsers.len() + c.bots.len(),
+		Capacity: c.browsers.capacity + c.bots.capacity,
+	}
+}
+
+// Purge removes all cached entries and resets the statistics
+func (c *UACache) Purge() {
+	c.browsers.purge()
+	c.bots.purge()
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+func (c *UACache) getBrowser(ua string) (BrowserInfo, bool) {
+	info, ok := c.browsers.get(hashUserAgent(ua))
+	if !ok || info.RawUA != ua {
+		c.misses.Add(1)
+		return BrowserInfo{}, false
+	}
+	c.hits.Add(1)
+	return info, true
+}
+
+func (c *UACache) setBrowser(ua string, info BrowserInfo) {
+	c.browsers.set(hashUserAgent(ua), info)
+}
+
+func (c *UACache) getBot(ua string) (bool, BotKind, bool) {
+	entry, ok := c.bots.get(hashUserAgent(ua))
+	if !ok || entry.ua != ua {
+		c.misses.Add(1)
+		return false, "", false
+	}
+	c.hits.Add(1)
+	return entry.isBot, entry.botKind, true
+}
+
+func (c *UACache) setBot(ua string, isBot bool, botKind BotKind) {
+	c.bots.set(hashUserAgent(ua), uaBotEntry{ua: ua, isBot: isBot, botKind: botKind})
+}
+
+// hashUserAgent returns the cache key for a user agent string
+func hashUserAgent(ua string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(ua))
+	return h.Sum64()
+}
@@ -0,0 +1,99 @@
+package gogobot
+
+import (
+	"testing"
+)
+
+func TestUACache_HitMiss(t *testing.T) {
+	cache := NewUACache(10)
+	SetUACache(cache)
+	defer SetUACache(nil)
+
+	userAgent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	first := ParseBrowserFromUserAgent(userAgent)
+	second := ParseBrowserFromUserAgent(userAgent)
+
+	if first != second {
+		t.Errorf("Expected cached result %+v to equal %+v", second, first)
+	}
+	if second.Name != BrowserChrome {
+		t.Errorf("Expected Chrome, got %s", second.Name)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits == 0 {
+		t.Error("Expected at least one cache hit")
+	}
+	if stats.Misses == 0 {
+		t.Error("Expected at least one cache miss")
+	}
+	if stats.HitRate() <= 0 || stats.HitRate() >= 1 {
+		t.Errorf("Expected hit rate between 0 and 1, got %f", stats.HitRate())
+	}
+}
+
+func TestUACache_IsBotUserAgent(t *testing.T) {
+	cache := NewUACache(10)
+	SetUACache(cache)
+	defer SetUACache(nil)
+
+	for i := 0; i < 3; i++ {
+		isBot, botKind := IsBotUserAgent("curl/7.68.0")
+		if !isBot || botKind != BotKindCurl {
+			t.Errorf("Expected curl bot, got bot=%t kind=%s", isBot, botKind)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+}
+
+func TestUACache_Eviction(t *testing.T) {
+	cache := NewUACache(2)
+
+	cache.setBot("a", true, BotKindCurl)
+	cache.setBot("b", true, BotKindWget)
+	cache.getBot("a")
+	cache.setBot("c", false, "")
+
+	if _, _, ok := cache.getBot("b"); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, _, ok := cache.getBot("a"); !ok {
+		t.Error("Expected recently used entry to be retained")
+	}
+
+	cache.Purge()
+	stats := cache.Stats()
+	if stats.Size != 0 || stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("Expected empty cache after purge, got %+v", stats)
+	}
+}
+
+func TestUACache_Disabled(t *testing.T) {
+	SetUACache(nil)
+
+	if GetUACache() != nil {
+		t.Error("Expected no cache to be installed")
+	}
+
+	isBot, botKind := IsBotUserAgent("curl/7.68.0")
+	if !isBot || botKind != BotKindCurl {
+		t.Errorf("Expected curl bot without cache, got bot=%t kind=%s", isBot, botKind)
+	}
+}
+
+func BenchmarkParseBrowserFromUserAgentCached(b *testing.B) {
+	SetUACache(NewUACache(1024))
+	defer SetUACache(nil)
+
+	userAgent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParseBrowserFromUserAgent(userAgent)
+	}
+}
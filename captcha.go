@@ -0,0 +1,241 @@
+package gogobot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ChallengeAction presents suspected bots with a challenge in the middleware instead of
+// blocking them, admitting visitors that solve it (see MiddlewareConfig.Challenge)
+type ChallengeAction interface {
+	// Passed reports whether the visitor has solved the challenge
+	Passed(req *http.Request, visitor string) bool
+	// ServeChallenge renders the challenge page, or checks a submitted solution and redirects
+	// back to the requested URL
+	ServeChallenge(w http.ResponseWriter, req *http.Request, visitor string)
+}
+
+// ChallengeProvider is a CAPTCHA service rendering a widget and validating its tokens
+type ChallengeProvider interface {
+	// Widget returns the markup rendering the widget inside a form
+	Widget() template.HTML
+	// ResponseField is the form field the widget submits its token in
+	ResponseField() string
+	// Verify validates a token with the service
+	Verify(ctx context.Context, token string) (bool, error)
+}
+
+// CaptchaProvider is a ChallengeProvider for services following the siteverify protocol:
+// Cloudflare Turnstile, hCaptcha and Google reCAPTCHA v2
+type CaptchaProvider struct {
+	// SiteKey is the public key embedded in the widget
+	SiteKey string
+	// HTTPClient is used for verification requests
+	HTTPClient *http.Client
+
+	secret        string
+	verifyURL     string
+	scriptURL     string
+	widgetClass   string
+	responseField string
+}
+
+// NewTurnstileProvider creates a Cloudflare Turnstile provider
+func NewTurnstileProvider(siteKey, secret string) *CaptchaProvider {
+	return newCaptchaProvider(siteKey, secret,
+		"https://challenges.cloudflare.com/turnstile/v0/siteverify",
+		"https://challenges.cloudflare.com/turnstile/v0/api.js",
+		"cf-turnstile", "cf-turnstile-response")
+}
+
+// NewHCaptchaProvider creates an hCaptcha provider
+func NewHCaptchaProvider(siteKey, secret string) *CaptchaProvider {
+	return newCaptchaProvider(siteKey, secret,
+		"https://api.hcaptcha.com/siteverify",
+		"https://js.hcaptcha.com/1/api.js",
+		"h-captcha", "h-captcha-response")
+}
+
+// NewReCAPTCHAProvider creates a Google reCAPTCHA v2 checkbox provider
+func NewReCAPTCHAProvider(siteKey, secret string) *CaptchaProvider {
+	return newCaptchaProvider(siteKey, secret,
+		"https://www.google.com/recaptcha/api/siteverify",
+		"https://www.google.com/recaptcha/api.js",
+		"g-recaptcha", "g-recaptcha-response")
+}
+
+func newCaptchaProvider(siteKey, secret, verifyURL, scriptURL, widgetClass, responseField string) *CaptchaProvider {
+	return &CaptchaProvider{
+		SiteKey:       siteKey,
+		HTTPClient:    &http.Client{Timeout: 10 * time.Second},
+		secret:        secret,
+		verifyURL:     verifyURL,
+		scriptURL:     scriptURL,
+		widgetClass:   widgetClass,
+		responseField: responseField,
+	}
+}
+
+// Widget implements ChallengeProvider
+func (p *CaptchaProvider) Widget() template.HTML {
+	return template.HTML(fmt.Sprintf(`<script src="%s" async defer></script><div class="%s" data-sitekey="%s"></div>`,
+		html.EscapeString(p.scriptURL), p.widgetClass, html.EscapeString(p.SiteKey)))
+}
+
+// ResponseField implements ChallengeProvider
+func (p *CaptchaProvider) ResponseField() string {
+	return p.responseField
+}
+
+// Verify implements ChallengeProvider
+func (p *CaptchaProvider) Verify(ctx context.Context, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	form := url.Values{"secret": {p.secret}, "response": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
+
+// DefaultCaptchaPassTTL is how long a visitor who solved a CAPTCHA is admitted
+const DefaultCaptchaPassTTL = time.Hour
+
+// CaptchaChallenge is a ChallengeAction rendering a CAPTCHA page for suspected bots. Solved
+// challenges are recorded in a Store, so a pass is honored by every replica.
+type CaptchaChallenge struct {
+	// Provider renders and verifies the CAPTCHA
+	Provider ChallengeProvider
+	// PassTTL is how long a visitor who solved the CAPTCHA is admitted
+	PassTTL time.Duration
+	// Prefix is prepended to store keys
+	Prefix string
+	// OnError is called when the provider cannot be reached
+	OnError func(error)
+
+	store Store
+}
+
+// NewCaptchaChallenge creates a CAPTCHA challenge recording passes in store
+func NewCaptchaChallenge(provider ChallengeProvider, store Store) *CaptchaChallenge {
+	return &CaptchaChallenge{
+		Provider: provider,
+		PassTTL:  DefaultCaptchaPassTTL,
+		Prefix:   "gogobot:captcha:",
+		store:    store,
+	}
+}
+
+// captchaPage is the challenge page; the form posts back to the requested URL
+const captchaPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="robots" content="noindex"><title>Verify you are human</title></head>
+<body><form method="post">
+<p>Please confirm you are human to continue.</p>
+%s
+<button type="submit">Continue</button>
+</form></body></html>
+`
+
+// Passed implements ChallengeAction
+func (c *CaptchaChallenge) Passed(req *http.Request, visitor string) bool {
+	_, ok, err := c.store.Get(req.Context(), c.Prefix+visitor)
+	return ok && err == nil
+}
+
+// ServeChallenge implements ChallengeAction
+func (c *CaptchaChallenge) ServeChallenge(w http.ResponseWriter, req *http.Request, visitor string) {
+	w.Header().Set("Cache-Control", "no-store")
+	if req.Method == http.MethodPost {
+		req.Body = http.MaxBytesReader(w, req.Body, 64<<10)
+		if token := req.PostFormValue(c.Provider.ResponseField()); token != "" {
+			ok, err := c.Provider.Verify(req.Context(), token)
+			if err != nil && c.OnError != nil {
+				c.OnError(err)
+			}
+			if ok {
+				ttl := c.PassTTL
+				if ttl <= 0 {
+					ttl = DefaultCaptchaPassTTL
+				}
+				if err := c.store.Set(req.Context(), c.Prefix+visitor, []byte{1}, ttl); err != nil && c.OnError != nil {
+					c.OnError(err)
+				}
+				http.Redirect(w, req, sameOriginURI(req.URL), http.StatusSeeOther)
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	fmt.Fprintf(w, captchaPage, c.Provider.Widget())
+}
+
+// addressComponents returns components, or for verdicts served without components (such as
+// cached ones) just the resolved client IP, which session keys are derived from
+func (d *BotDetector) addressComponents(req *http.Request, components *ComponentDict) *ComponentDict {
+	if components != nil {
+		return components
+	}
+	clientIP, _ := d.resolveClientIP(req)
+	return &ComponentDict{ResolvedClientIP: clientIP}
+}
+
+// visitorKey identifies the visitor for challenges: the session key when a SessionTracker is
+// configured, otherwise the aggregated client IP
+func (d *BotDetector) visitorKey(req *http.Request, components *ComponentDict) string {
+	components = d.addressComponents(req, components)
+	if d.config.SessionTracker != nil {
+		return d.config.SessionTracker.key(req, components)
+	}
+	return SessionKeyByIP(DefaultIPAggregation())(req, components)
+}
+
+// challengeBot lets the middleware's ChallengeAction handle a suspected bot. It reports
+// whether the response was written; admitted visitors are marked on the result. Denylisted
+// clients are never challenged.
+func (d *BotDetector) challengeBot(w http.ResponseWriter, req *http.Request, components *ComponentDict, challenge ChallengeAction, result *BotDetectionResult) bool {
+	if result.BotKind == BotKindDenylisted {
+		return false
+	}
+	visitor := d.visitorKey(req, components)
+	if visitor == "" {
+		return false
+	}
+	if challenge.Passed(req, visitor) {
+		result.ChallengePassed = true
+		return false
+	}
+	challenge.ServeChallenge(w, req, visitor)
+	return true
+}
@@ -0,0 +1,164 @@
+package gogobot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newSiteVerifyServer accepts the token "good" for the secret "secret"
+func newSiteVerifyServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.PostFormValue("secret") != "secret" {
+			w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-secret"]}`))
+			return
+		}
+		if r.PostFormValue("response") == "good" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCaptchaProvider_Verify(t *testing.T) {
+	server := newSiteVerifyServer(t)
+	provider := NewTurnstileProvider("site-key", "secret")
+	provider.verifyURL = server.URL
+
+	if ok, err := provider.Verify(context.Background(), "good"); !ok || err != nil {
+		t.Errorf("Expected good token to verify, got %t, %v", ok, err)
+	}
+	if ok, err := provider.Verify(context.Background(), "bad"); ok || err != nil {
+		t.Errorf("Expected bad token to fail, got %t, %v", ok, err)
+	}
+	if ok, _ := provider.Verify(context.Background(), ""); ok {
+		t.Error("Expected empty token to fail")
+	}
+
+	provider.verifyURL = server.URL + "/missing\x7f"
+	if _, err := provider.Verify(context.Background(), "good"); err == nil {
+		t.Error("Expected invalid URL to fail")
+	}
+}
+
+func TestCaptchaProviders_Widget(t *testing.T) {
+	tests := []struct {
+		provider *CaptchaProvider
+		class    string
+		field    string
+	}{
+		{NewTurnstileProvider("key", "secret"), "cf-turnstile", "cf-turnstile-response"},
+		{NewHCaptchaProvider("key", "secret"), "h-captcha", "h-captcha-response"},
+		{NewReCAPTCHAProvider("key", "secret"), "g-recaptcha", "g-recaptcha-response"},
+	}
+	for _, tt := range tests {
+		widget := string(tt.provider.Widget())
+		if !strings.Contains(widget, `class="`+tt.class+`" data-sitekey="key"`) || !strings.Contains(widget, "<script src=\"https://") {
+			t.Errorf("Unexpected widget markup: %s", widget)
+		}
+		if tt.provider.ResponseField() != tt.field {
+			t.Errorf("Expected response field %s, got %s", tt.field, tt.provider.ResponseField())
+		}
+	}
+}
+
+func TestMiddleware_CaptchaChallenge(t *testing.T) {
+	server := newSiteVerifyServer(t)
+	provider := NewHCaptchaProvider("site-key", "secret")
+	provider.verifyURL = server.URL
+
+	detector := NewDetector()
+	challenge := NewCaptchaChallenge(provider, NewMemoryStore(0))
+	var results []*BotDetectionResult
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{
+		BlockBots: true,
+		Challenge: challenge,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, _ := GetResultFromContext(r.Context())
+		results = append(results, result)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method, remoteAddr string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/article?id=7", strings.NewReader(form.Encode()))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		if form != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("GET", "203.0.113.7:1234", nil)
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), `class="h-captcha" data-sitekey="site-key"`) {
+		t.Fatalf("Expected CAPTCHA page, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := serve("POST", "203.0.113.7:1234", url.Values{"h-captcha-response": {"bad"}}); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected failed CAPTCHA to render the page again, got %d", rec.Code)
+	}
+	rec = serve("POST", "203.0.113.7:1234", url.Values{"h-captcha-response": {"good"}})
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/article?id=7" {
+		t.Fatalf("Expected redirect after solving, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	if rec := serve("GET", "203.0.113.7:1234", nil); rec.Code != http.StatusOK {
+		t.Fatalf("Expected solved visitor to be admitted, got %d", rec.Code)
+	}
+	if len(results) != 1 || !results[0].Bot || !results[0].ChallengePassed {
+		t.Errorf("Expected admitted bot to be marked, got %+v", results)
+	}
+
+	if rec := serve("GET", "198.51.100.9:1234", nil); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected other visitors to be challenged, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware_ChallengeSkipsDenylisted(t *testing.T) {
+	list, err := NewIPAccessList(nil, []string{"203.0.113.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultDetectorConfig()
+	config.IPAccessList = list
+	detector := NewDetectorWithConfig(config)
+
+	challenge := NewCaptchaChallenge(NewTurnstileProvider("key", "secret"), NewMemoryStore(0))
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true, BlockedMessage: "denied", Challenge: challenge})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "denied") {
+		t.Errorf("Expected denylisted client to be blocked without a challenge, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMiddleware_CaptchaChallengeSameOriginRedirect(t *testing.T) {
+	server := newSiteVerifyServer(t)
+	provider := NewHCaptchaProvider("site-key", "secret")
+	provider.verifyURL = server.URL
+	handler := NewDetector().MiddlewareWithConfig(MiddlewareConfig{
+		BlockBots: true,
+		Challenge: NewCaptchaChallenge(provider, NewMemoryStore(0)),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("POST", "//evil.example/x", strings.NewReader(url.Values{"h-captcha-response": {"good"}}.Encode()))
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/evil.example/x" {
+		t.Errorf("Expected redirect on the same host, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
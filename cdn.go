@@ -0,0 +1,196 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// CDNProvider identifies a CDN whose bot-management headers can be trusted
+type CDNProvider string
+
+const (
+	CDNCloudflare CDNProvider = "cloudflare"
+	CDNAkamai     CDNProvider = "akamai"
+	CDNFastly     CDNProvider = "fastly"
+)
+
+// CDNHeaders names the request headers a CDN uses to pass its verdicts to the origin.
+// Empty names are not read.
+type CDNHeaders struct {
+	// ClientIP carries the address of the client connecting to the CDN edge
+	ClientIP string
+	// VerifiedBot carries "true" or "1" when the CDN verified the client as a known good bot
+	VerifiedBot string
+	// BotScore carries the CDN's bot score from 1 (automated) to 99 (human)
+	BotScore string
+}
+
+// DefaultCDNHeaders returns the headers sent by provider. Cloudflare's bot headers are added by
+// the "Add bot protection headers" managed transform. Akamai and Fastly only send the client IP
+// by default; bot verdicts must be mapped to headers in the edge configuration and registered
+// with CDNTrust.SetHeaders.
+func DefaultCDNHeaders(provider CDNProvider) CDNHeaders {
+	switch provider {
+	case CDNCloudflare:
+		return CDNHeaders{ClientIP: "CF-Connecting-IP", VerifiedBot: "CF-Verified-Bot", BotScore: "CF-Bot-Score"}
+	case CDNAkamai:
+		return CDNHeaders{ClientIP: "True-Client-IP"}
+	case CDNFastly:
+		return CDNHeaders{ClientIP: "Fastly-Client-IP"}
+	default:
+		return CDNHeaders{}
+	}
+}
+
+// CloudflareRanges returns the IP ranges Cloudflare publishes at https://www.cloudflare.com/ips/
+func CloudflareRanges() []string {
+	return []string{
+		"173.245.48.0/20", "103.21.244.0/22", "103.22.200.0/22", "103.31.4.0/22",
+		"141.101.64.0/18", "108.162.192.0/18", "190.93.240.0/20", "188.114.96.0/20",
+		"197.234.240.0/22", "198.41.128.0/17", "162.158.0.0/15", "104.16.0.0/13",
+		"104.24.0.0/14", "172.64.0.0/13", "131.0.72.0/22",
+		"2400:cb00::/32", "2606:4700::/32", "2803:f800::/32", "2405:b500::/32",
+		"2405:8100::/32", "2a06:98c0::/29", "2c0f:f248::/32",
+	}
+}
+
+// cdnBotScoreCutoff is the CDN bot score below which a request is treated as automated.
+// Cloudflare documents scores of 1-29 as "likely automated".
+const cdnBotScoreCutoff = 30
+
+// CDNTrust holds the edge ranges of CDNs whose headers are trusted. Headers are only honored
+// when the request arrives from a registered range, since any client can send them directly.
+type CDNTrust struct {
+	ranges  *prefixTrie[CDNProvider]
+	headers map[CDNProvider]CDNHeaders
+}
+
+// NewCDNTrust creates an empty CDNTrust
+func NewCDNTrust() *CDNTrust {
+	return &CDNTrust{
+		ranges:  newPrefixTrie[CDNProvider](),
+		headers: make(map[CDNProvider]CDNHeaders),
+	}
+}
+
+// Add trusts the given CIDRs or IP addresses as edge servers of provider. Providers without
+// headers registered by SetHeaders use DefaultCDNHeaders.
+func (t *CDNTrust) Add(provider CDNProvider, cidrs ...string) error {
+	prefixes, err := ParsePrefixes(cidrs...)
+	if err != nil {
+		return fmt.Errorf("%s ranges: %w", provider, err)
+	}
+	for _, prefix := range prefixes {
+		t.ranges.insert(prefix, provider)
+	}
+	if _, ok := t.headers[provider]; !ok {
+		t.headers[provider] = DefaultCDNHeaders(provider)
+	}
+	return nil
+}
+
+// SetHeaders overrides the headers read for provider
+func (t *CDNTrust) SetHeaders(provider CDNProvider, headers CDNHeaders) {
+	t.headers[provider] = headers
+}
+
+// Lookup returns the CDN owning addr
+func (t *CDNTrust) Lookup(addr netip.Addr) (CDNProvider, bool) {
+	if t == nil {
+		return "", false
+	}
+	provider, _, ok := t.ranges.lookup(addr)
+	return provider, ok
+}
+
+// CDNVerdict holds the verdicts a trusted CDN passed to the origin
+type CDNVerdict struct {
+	Provider CDNProvider `json:"provider"`
+	// ClientIP is the client address reported by the CDN, if any
+	ClientIP netip.Addr `json:"clientIp,omitzero"`
+	// VerifiedBot is true when the CDN verified the client as a known good bot
+	VerifiedBot bool `json:"verifiedBot,omitempty"`
+	// BotScore is the CDN's bot score from 1 (automated) to 99 (human), or 0 if not reported
+	BotScore int `json:"botScore,omitempty"`
+}
+
+// Verdict reads the CDN headers of req if peer is a trusted edge server
+func (t *CDNTrust) Verdict(req *http.Request, peer netip.Addr) (CDNVerdict, bool) {
+	provider, ok := t.Lookup(peer)
+	if !ok {
+		return CDNVerdict{}, false
+	}
+
+	headers := t.headers[provider]
+	verdict := CDNVerdict{Provider: provider}
+	if headers.ClientIP != "" {
+		if addr, err := parseIP(req.Header.Get(headers.ClientIP)); err == nil {
+			verdict.ClientIP = addr
+		}
+	}
+	if headers.VerifiedBot != "" {
+		value := strings.TrimSpace(req.Header.Get(headers.VerifiedBot))
+		verdict.VerifiedBot = strings.EqualFold(value, "true") || value == "1"
+	}
+	if headers.BotScore != "" {
+		if score, err := strconv.Atoi(strings.TrimSpace(req.Header.Get(headers.BotScore))); err == nil && score >= 1 && score <= 99 {
+			verdict.BotScore = score
+		}
+	}
+	return verdict, true
+}
+
+// getCDNVerdict reads the verdict of the CDN the request arrived from
+func getCDNVerdict(req *http.Request, clientIP Component[netip.Addr], trust *CDNTrust) Component[CDNVerdict] {
+	if trust == nil {
+		return ErrorComponent[CDNVerdict]{
+			State: StateUndefined,
+			Error: "no CDN trust configured",
+		}
+	}
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[CDNVerdict]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+
+	verdict, ok := trust.Verdict(req, clientIP.GetValue())
+	if !ok {
+		return ErrorComponent[CDNVerdict]{
+			State: StateNull,
+			Error: "request did not arrive from a trusted CDN",
+		}
+	}
+	return SuccessComponent[CDNVerdict]{
+		State: StateSuccess,
+		Value: verdict,
+	}
+}
+
+// isCDNVerifiedBot reports whether a trusted CDN verified the client as a known good bot
+func isCDNVerifiedBot(components *ComponentDict) bool {
+	return components.CDN != nil && components.CDN.GetState() == StateSuccess && components.CDN.GetValue().VerifiedBot
+}
+
+// detectCDN turns a low CDN bot score into a weighted signal scaled by how far it falls below
+// the cutoff. Verified bots are reported through BotDetectionResult.VerifiedByCDN instead.
+func detectCDN(components *ComponentDict) *BotDetectionResult {
+	if components.CDN.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	verdict := components.CDN.GetValue()
+	if verdict.VerifiedBot || verdict.BotScore == 0 || verdict.BotScore >= cdnBotScoreCutoff {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindUnknown,
+		Score:   float64(cdnBotScoreCutoff-verdict.BotScore) / float64(cdnBotScoreCutoff-1),
+	}
+}
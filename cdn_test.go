@@ -0,0 +1,140 @@
+package gogobot
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func newTestCDNTrust(t *testing.T) *CDNTrust {
+	t.Helper()
+	trust := NewCDNTrust()
+	if err := trust.Add(CDNCloudflare, CloudflareRanges()...); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	if err := trust.Add(CDNAkamai, "23.32.0.0/11"); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	trust.SetHeaders(CDNAkamai, CDNHeaders{ClientIP: "True-Client-IP", VerifiedBot: "Akamai-Verified-Bot"})
+	return trust
+}
+
+func TestCDNTrust_Verdict(t *testing.T) {
+	trust := newTestCDNTrust(t)
+
+	tests := []struct {
+		name     string
+		peer     string
+		headers  map[string]string
+		trusted  bool
+		expected CDNVerdict
+	}{
+		{
+			name:     "Cloudflare verified bot",
+			peer:     "104.16.0.1",
+			headers:  map[string]string{"CF-Connecting-IP": "66.249.66.1", "CF-Verified-Bot": "true", "CF-Bot-Score": "1"},
+			trusted:  true,
+			expected: CDNVerdict{Provider: CDNCloudflare, ClientIP: netip.MustParseAddr("66.249.66.1"), VerifiedBot: true, BotScore: 1},
+		},
+		{
+			name:     "Cloudflare over IPv6 with out of range score",
+			peer:     "2606:4700::1",
+			headers:  map[string]string{"CF-Connecting-IP": "2001:db8::5", "CF-Bot-Score": "150"},
+			trusted:  true,
+			expected: CDNVerdict{Provider: CDNCloudflare, ClientIP: netip.MustParseAddr("2001:db8::5")},
+		},
+		{
+			name:     "Akamai with custom verified bot header",
+			peer:     "23.32.1.1",
+			headers:  map[string]string{"True-Client-IP": "198.51.100.7", "Akamai-Verified-Bot": "1"},
+			trusted:  true,
+			expected: CDNVerdict{Provider: CDNAkamai, ClientIP: netip.MustParseAddr("198.51.100.7"), VerifiedBot: true},
+		},
+		{
+			name:    "Headers from an untrusted peer are ignored",
+			peer:    "203.0.113.5",
+			headers: map[string]string{"CF-Connecting-IP": "66.249.66.1", "CF-Verified-Bot": "true"},
+			trusted: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createTestRequest("GET", "/", tt.headers)
+			verdict, trusted := trust.Verdict(req, netip.MustParseAddr(tt.peer))
+			if trusted != tt.trusted || verdict != tt.expected {
+				t.Errorf("Verdict() = %+v, %t; want %+v, %t", verdict, trusted, tt.expected, tt.trusted)
+			}
+		})
+	}
+}
+
+func TestBotDetector_CDNVerifiedBot(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.CDNTrust = newTestCDNTrust(t)
+	config.CrawlerVerifier = &CrawlerVerifier{Resolver: newFakeCrawlerDNS()}
+	detector := NewDetectorWithConfig(config)
+
+	// The CDN vouches for a crawler whose edge-facing IP would fail our own DNS checks
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent":       googlebotUA,
+		"CF-Connecting-IP": "203.0.113.5",
+		"CF-Verified-Bot":  "true",
+	})
+	req.RemoteAddr = "104.16.0.1:443"
+	result, _ := detector.DetectFromRequest(req)
+	if !result.Bot || result.BotKind != BotKindCrawler || !result.VerifiedByCDN {
+		t.Errorf("Expected CDN-verified crawler, got %+v", result)
+	}
+	if ip := detector.GetComponents().ResolvedClientIP.GetValue(); ip != netip.MustParseAddr("203.0.113.5") {
+		t.Errorf("Expected client IP from CF-Connecting-IP, got %s", ip)
+	}
+	if detector.GetComponents().CrawlerVerification.GetState() == StateSuccess {
+		t.Error("Expected DNS verification to be skipped for CDN-verified bots")
+	}
+
+	// The same headers sent directly are not trusted
+	req.RemoteAddr = "198.51.100.9:443"
+	result, _ = detector.DetectFromRequest(req)
+	if result.VerifiedByCDN || result.BotKind != BotKindImpersonator {
+		t.Errorf("Expected spoofed CDN headers to be ignored, got %+v", result)
+	}
+}
+
+func TestDetectCDN_BotScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		verdict CDNVerdict
+		score   float64
+	}{
+		{"Certainly automated", CDNVerdict{BotScore: 1}, 1},
+		{"Borderline", CDNVerdict{BotScore: 29}, 1.0 / 29},
+		{"Human", CDNVerdict{BotScore: 80}, 0},
+		{"Not reported", CDNVerdict{}, 0},
+		{"Verified bot", CDNVerdict{BotScore: 1, VerifiedBot: true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := &ComponentDict{CDN: SuccessComponent[CDNVerdict]{State: StateSuccess, Value: tt.verdict}}
+			if result := detectCDN(components); result.Bot || result.Score != tt.score {
+				t.Errorf("detectCDN() = %+v, want score %v", result, tt.score)
+			}
+		})
+	}
+
+	// A score of 1 alone reaches the default threshold
+	config := DefaultDetectorConfig()
+	config.CDNTrust = newTestCDNTrust(t)
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Accept":          "text/html",
+		"Accept-Language": "en-US",
+		"Accept-Encoding": "gzip",
+		"CF-Bot-Score":    "1",
+	})
+	req.RemoteAddr = "104.16.0.1:443"
+	result, _ := NewDetectorWithConfig(config).DetectFromRequest(req)
+	if !result.Bot || result.Score < DefaultScoreThreshold {
+		t.Errorf("Expected low CDN bot score to flag the request, got %+v", result)
+	}
+}
@@ -0,0 +1,134 @@
+package gogobot
+
+import (
+	"net/http"
+	"strings"
+)
+
+// brandBrowserNames maps Sec-CH-UA brand names to browser names
+var brandBrowserNames = map[string]BrowserName{
+	"google chrome":    BrowserChrome,
+	"chromium":         BrowserChrome,
+	"microsoft edge":   BrowserEdge,
+	"opera":            BrowserOpera,
+	"brave":            BrowserBrave,
+	"yandex":           BrowserYandex,
+	"yabrowser":        BrowserYandex,
+	"samsung internet": BrowserSamsung,
+	"vivaldi":          BrowserVivaldi,
+}
+
+// BrandVersion is a brand and version pair from a Sec-CH-UA style header
+type BrandVersion struct {
+	Brand   string
+	Version string
+}
+
+// ParseBrandVersionList parses a Sec-CH-UA or Sec-CH-UA-Full-Version-List header value,
+// e.g. `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`
+func ParseBrandVersionList(header string) []BrandVersion {
+	var brands []BrandVersion
+	for _, item := range splitStructuredList(header) {
+		params := strings.Split(item, ";")
+		brand := strings.Trim(strings.TrimSpace(params[0]), `"`)
+		if brand == "" {
+			continue
+		}
+
+		bv := BrandVersion{Brand: brand}
+		for _, param := range params[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && key == "v" {
+				bv.Version = strings.Trim(value, `"`)
+			}
+		}
+		brands = append(brands, bv)
+	}
+	return brands
+}
+
+// splitStructuredList splits a structured header list on commas outside of quoted strings
+func splitStructuredList(header string) []string {
+	var items []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(header); i++ {
+		switch header[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				items = append(items, strings.TrimSpace(header[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if rest := strings.TrimSpace(header[start:]); rest != "" {
+		items = append(items, rest)
+	}
+	return items
+}
+
+// hintedFullVersion returns the full browser version from client hints for the named browser
+func hintedFullVersion(header http.Header, name BrowserName) (string, bool) {
+	var fallback string
+	for _, bv := range ParseBrandVersionList(header.Get("Sec-CH-UA-Full-Version-List")) {
+		hinted, ok := brandBrowserNames[strings.ToLower(bv.Brand)]
+		if !ok || hinted != name || bv.Version == "" {
+			continue
+		}
+		// Prefer the vendor brand over the generic "Chromium" brand
+		if !strings.EqualFold(bv.Brand, "chromium") {
+			return bv.Version, true
+		}
+		fallback = bv.Version
+	}
+	if fallback != "" {
+		return fallback, true
+	}
+
+	// Sec-CH-UA-Full-Version is deprecated but still sent by older Chromium releases
+	if full := strings.Trim(header.Get("Sec-CH-UA-Full-Version"), `" `); full != "" && isChromiumBrowser(name) {
+		return full, true
+	}
+
+	return "", false
+}
+
+// isChromiumBrowser reports whether the browser sends Chromium user agent client hints
+func isChromiumBrowser(name BrowserName) bool {
+	for _, hinted := range brandBrowserNames {
+		if hinted == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reducedPlatforms are the frozen platform sections Chromium reports in its reduced user agent
+var reducedPlatforms = []string{
+	"(windows nt 10.0; win64; x64)",
+	"(macintosh; intel mac os x 10_15_7)",
+	"(x11; linux x86_64)",
+	"(x11; cros x86_64 14541.0.0)",
+	"(linux; android 10; k)",
+}
+
+// isReducedUserAgent reports whether a Chromium user agent uses the frozen/reduced format,
+// where the minor, build, and patch versions are zeroed and the platform is fixed
+func isReducedUserAgent(ua string, name BrowserName, version string) bool {
+	if version == "" || !strings.HasSuffix(version, ".0.0.0") {
+		return false
+	}
+	if !isChromiumBrowser(name) {
+		return false
+	}
+
+	ua = strings.ToLower(ua)
+	for _, platform := range reducedPlatforms {
+		if strings.Contains(ua, platform) {
+			return true
+		}
+	}
+	return false
+}
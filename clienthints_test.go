@@ -0,0 +1,62 @@
+package gogobot
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseBrandVersionList(t *testing.T) {
+	header := `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.130", "Google Chrome";v="120.0.6099.130"`
+
+	brands := ParseBrandVersionList(header)
+	if len(brands) != 3 {
+		t.Fatalf("Expected 3 brands, got %d", len(brands))
+	}
+	if brands[2].Brand != "Google Chrome" || brands[2].Version != "120.0.6099.130" {
+		t.Errorf("Unexpected brand entry %+v", brands[2])
+	}
+
+	if brands := ParseBrandVersionList(""); len(brands) != 0 {
+		t.Errorf("Expected no brands for empty header, got %d", len(brands))
+	}
+}
+
+func TestParseBrowserFromRequest_ReducedUA(t *testing.T) {
+	reducedUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", reducedUA)
+
+	result := ParseBrowserFromRequest(req)
+	if !result.IsReducedUA {
+		t.Error("Expected reduced user agent to be recognized")
+	}
+	if result.Version != "120.0.0.0" {
+		t.Errorf("Expected reduced version without hints, got %s", result.Version)
+	}
+
+	req.Header.Set("Sec-CH-UA-Full-Version-List", `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.130", "Google Chrome";v="120.0.6099.130"`)
+	result = ParseBrowserFromRequest(req)
+	if result.Version != "120.0.6099.130" {
+		t.Errorf("Expected full version from client hints, got %s", result.Version)
+	}
+
+	minVersions := map[BrowserName]string{BrowserChrome: "120.0.6099.100"}
+	if !IsSupportedBrowser(req, minVersions) {
+		t.Error("Expected hinted full version to satisfy minimum version")
+	}
+
+	edgeReq, _ := http.NewRequest("GET", "/", nil)
+	edgeReq.Header.Set("User-Agent", reducedUA+" Edg/120.0.0.0")
+	edgeReq.Header.Set("Sec-CH-UA-Full-Version-List", `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.130", "Microsoft Edge";v="120.0.2210.91"`)
+	if result := ParseBrowserFromRequest(edgeReq); result.Version != "120.0.2210.91" {
+		t.Errorf("Expected Edge full version from client hints, got %s", result.Version)
+	}
+
+	firefoxReq, _ := http.NewRequest("GET", "/", nil)
+	firefoxReq.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0")
+	firefoxReq.Header.Set("Sec-CH-UA-Full-Version", `"120.0.6099.130"`)
+	if result := ParseBrowserFromRequest(firefoxReq); result.Version != "121.0" || result.IsReducedUA {
+		t.Errorf("Expected Chromium hints to be ignored for Firefox, got %s (reduced=%t)", result.Version, result.IsReducedUA)
+	}
+}
@@ -0,0 +1,333 @@
+package gogobot
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver determines the real client IP of a request behind trusted proxies.
+// The forwarding header is only honored when the request arrives from a trusted proxy, and
+// the forwarded chain is walked from the right so that addresses injected by the client
+// cannot be used to spoof the origin. Only the configured Header is read: a proxy appending
+// to X-Forwarded-For passes a client's Forwarded header through untouched.
+type ClientIPResolver struct {
+	// Header is the header the trusted proxies set; empty uses X-Forwarded-For
+	Header ProxyHeader
+
+	trustedProxies []netip.Prefix
+}
+
+// ProxyHeader is a header proxies pass the client address in
+type ProxyHeader string
+
+const (
+	// ProxyHeaderXForwardedFor is the X-Forwarded-For chain
+	ProxyHeaderXForwardedFor ProxyHeader = "X-Forwarded-For"
+	// ProxyHeaderForwarded is the RFC 7239 Forwarded chain
+	ProxyHeaderForwarded ProxyHeader = "Forwarded"
+	// ProxyHeaderXRealIP is the single address of X-Real-IP
+	ProxyHeaderXRealIP ProxyHeader = "X-Real-IP"
+)
+
+// NewClientIPResolver creates a resolver trusting the given proxy CIDRs or IP addresses
+func NewClientIPResolver(trustedProxies ...string) (*ClientIPResolver, error) {
+	prefixes, err := ParsePrefixes(trustedProxies...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientIPResolver{trustedProxies: prefixes}, nil
+}
+
+// ParsePrefixes parses CIDRs or bare IP addresses into prefixes
+func ParsePrefixes(values ...string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+			}
+			prefixes = append(prefixes, normalizePrefix(prefix))
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address %q: %w", value, err)
+		}
+		addr = normalizeAddr(addr)
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// IsTrusted reports whether addr belongs to a trusted proxy
+func (r *ClientIPResolver) IsTrusted(addr netip.Addr) bool {
+	if r == nil {
+		return false
+	}
+	addr = normalizeAddr(addr)
+	for _, prefix := range r.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the client IP for the request
+func (r *ClientIPResolver) Resolve(req *http.Request) (netip.Addr, error) {
+	remote, err := ParseRemoteAddr(req.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if !r.IsTrusted(remote) {
+		return remote, nil
+	}
+
+	hops, ok := r.forwardedHops(req.Header)
+	if !ok {
+		return remote, nil
+	}
+
+	// Walk right to left: the first address not belonging to a trusted proxy is the client
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := hops[i]
+		if !hop.IsValid() {
+			// A malformed or obfuscated hop means everything to its left is untrustworthy
+			return client, nil
+		}
+		client = hop
+		if !r.IsTrusted(hop) {
+			return hop, nil
+		}
+	}
+	return client, nil
+}
+
+// forwardedHops returns the proxy chain from the configured header. Unparseable or
+// obfuscated hops are returned as invalid addresses.
+func (r *ClientIPResolver) forwardedHops(header http.Header) ([]netip.Addr, bool) {
+	switch r.Header {
+	case ProxyHeaderForwarded:
+		elements, err := ParseForwarded(header)
+		if err != nil || len(elements) == 0 {
+			return nil, false
+		}
+		hops := make([]netip.Addr, len(elements))
+		for i, element := range elements {
+			hops[i] = element.ForAddr
+		}
+		return hops, true
+	case ProxyHeaderXRealIP:
+		value := header.Get("X-Real-IP")
+		if value == "" {
+			return nil, false
+		}
+		hop, _ := parseIP(value)
+		return []netip.Addr{hop}, true
+	}
+
+	chain := forwardedForChain(header)
+	if len(chain) == 0 {
+		return nil, false
+	}
+	hops := make([]netip.Addr, len(chain))
+	for i, hop := range chain {
+		hops[i], _ = parseIP(hop)
+	}
+	return hops, true
+}
+
+// forwardedForChain returns all X-Forwarded-For hops in order, across repeated headers
+func forwardedForChain(header http.Header) []string {
+	var chain []string
+	for _, value := range header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				chain = append(chain, hop)
+			}
+		}
+	}
+	return chain
+}
+
+// ParseRemoteAddr parses an http.Request RemoteAddr ("host:port", "[v6]:port", or a bare IP)
+func ParseRemoteAddr(remoteAddr string) (netip.Addr, error) {
+	if addrPort, err := netip.ParseAddrPort(remoteAddr); err == nil {
+		return normalizeAddr(addrPort.Addr()), nil
+	}
+	return parseIP(remoteAddr)
+}
+
+// parseIP parses an IP address, tolerating surrounding brackets, ports and IPv6 zones
+func parseIP(value string) (netip.Addr, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return netip.Addr{}, fmt.Errorf("empty IP address")
+	}
+
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return normalizeAddr(addr), nil
+}
+
+// normalizeAddr unmaps IPv4-mapped IPv6 addresses and drops IPv6 zones, so that the same
+// client always produces the same address for prefix matching and cache keys
+func normalizeAddr(addr netip.Addr) netip.Addr {
+	return addr.Unmap().WithZone("")
+}
+
+// IPClass is the address class of an IP, as used by the IP helpers
+type IPClass string
+
+const (
+	IPClassPublic      IPClass = "public"
+	IPClassPrivate     IPClass = "private"
+	IPClassSharedNAT   IPClass = "shared_nat"
+	IPClassLoopback    IPClass = "loopback"
+	IPClassLinkLocal   IPClass = "link_local"
+	IPClassMulticast   IPClass = "multicast"
+	IPClassUnspecified IPClass = "unspecified"
+	IPClassReserved    IPClass = "reserved"
+	IPClassInvalid     IPClass = "invalid"
+)
+
+// Address ranges net/netip does not classify
+var (
+	sharedNATPrefix  = netip.MustParsePrefix("100.64.0.0/10")
+	reservedPrefixes = []netip.Prefix{
+		netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+		netip.MustParsePrefix("240.0.0.0/4"),   // former class E
+	}
+)
+
+// ClassifyIP returns the address class of addr. IPv4-mapped addresses are classified as IPv4.
+func ClassifyIP(addr netip.Addr) IPClass {
+	if !addr.IsValid() {
+		return IPClassInvalid
+	}
+	addr = normalizeAddr(addr)
+
+	switch {
+	case addr.IsUnspecified():
+		return IPClassUnspecified
+	case addr.IsLoopback():
+		return IPClassLoopback
+	case addr.IsLinkLocalUnicast():
+		return IPClassLinkLocal
+	case addr.IsMulticast(), addr.IsLinkLocalMulticast(), addr.IsInterfaceLocalMulticast():
+		return IPClassMulticast
+	case addr.IsPrivate():
+		return IPClassPrivate
+	case sharedNATPrefix.Contains(addr):
+		return IPClassSharedNAT
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return IPClassReserved
+		}
+	}
+	return IPClassPublic
+}
+
+// IsPublicIP reports whether addr is a globally routable unicast address
+func IsPublicIP(addr netip.Addr) bool {
+	return ClassifyIP(addr) == IPClassPublic
+}
+
+// IsInternalIP reports whether addr belongs to a private, shared NAT, loopback or link-local
+// network, i.e. the client is on the same network as the server or its proxies
+func IsInternalIP(addr netip.Addr) bool {
+	switch ClassifyIP(addr) {
+	case IPClassPrivate, IPClassSharedNAT, IPClassLoopback, IPClassLinkLocal:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClientIP returns the resolved client IP, after trusted proxy and CDN resolution
+func (c *ComponentDict) ClientIP() (netip.Addr, bool) {
+	if c.ResolvedClientIP == nil || c.ResolvedClientIP.GetState() != StateSuccess {
+		return netip.Addr{}, false
+	}
+	return c.ResolvedClientIP.GetValue(), true
+}
+
+// DefaultIPv6AggregationBits is the prefix length IPv6 clients are grouped by. A /64 is the
+// smallest subnet routinely assigned to a single customer, so keying on the full address lets
+// one host rotate through 2^64 identities.
+const DefaultIPv6AggregationBits = 64
+
+// IPAggregation groups client addresses into prefixes for per-client keys such as rate limits
+type IPAggregation struct {
+	// IPv4Bits is the prefix length for IPv4 addresses; zero means 32
+	IPv4Bits int
+	// IPv6Bits is the prefix length for IPv6 addresses; zero means 128
+	IPv6Bits int
+}
+
+// DefaultIPAggregation keys IPv4 clients by address and IPv6 clients by /64
+func DefaultIPAggregation() IPAggregation {
+	return IPAggregation{IPv4Bits: 32, IPv6Bits: DefaultIPv6AggregationBits}
+}
+
+// Prefix returns the prefix addr is grouped into. IPv4-mapped addresses are treated as IPv4.
+func (a IPAggregation) Prefix(addr netip.Addr) netip.Prefix {
+	if !addr.IsValid() {
+		return netip.Prefix{}
+	}
+	addr = normalizeAddr(addr)
+
+	bits := a.IPv6Bits
+	if addr.Is4() {
+		bits = a.IPv4Bits
+	}
+	if bits <= 0 || bits > addr.BitLen() {
+		bits = addr.BitLen()
+	}
+	prefix, _ := addr.Prefix(bits)
+	return prefix
+}
+
+// Key returns a string key for addr's prefix, e.g. "192.0.2.1/32" or "2001:db8:1:2::/64",
+// or "" for an invalid address
+func (a IPAggregation) Key(addr netip.Addr) string {
+	prefix := a.Prefix(addr)
+	if !prefix.IsValid() {
+		return ""
+	}
+	return prefix.String()
+}
+
+// getResolvedClientIP collects the client IP using resolver (nil uses RemoteAddr only)
+func getResolvedClientIP(req *http.Request, resolver *ClientIPResolver) Component[netip.Addr] {
+	addr, err := resolver.Resolve(req)
+	if err != nil {
+		return ErrorComponent[netip.Addr]{
+			State: StateUnexpectedBehaviour,
+			Error: fmt.Sprintf("unable to resolve client IP: %v", err),
+		}
+	}
+	return SuccessComponent[netip.Addr]{
+		State: StateSuccess,
+		Value: addr,
+	}
+}
@@ -0,0 +1,297 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIPResolver_Resolve(t *testing.T) {
+	resolver, err := NewClientIPResolver("10.0.0.0/8", "192.168.1.1", "2001:db8::/32")
+	if err != nil {
+		t.Fatalf("NewClientIPResolver returned error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     ProxyHeader
+		headers    map[string][]string
+		expected   string
+	}{
+		{
+			name:       "Direct connection ignores forwarded headers",
+			remoteAddr: "203.0.113.7:4242",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4"}},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "Trusted proxy with single hop",
+			remoteAddr: "10.1.2.3:4242",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.10"}},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "Spoofed leftmost entry is ignored",
+			remoteAddr: "10.1.2.3:4242",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.10, 10.9.9.9"}},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "Repeated headers are concatenated",
+			remoteAddr: "10.1.2.3:4242",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4", "198.51.100.10"}},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "All hops trusted returns leftmost",
+			remoteAddr: "10.1.2.3:4242",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.5.5.5, 192.168.1.1"}},
+			expected:   "10.5.5.5",
+		},
+		{
+			name:       "Malformed hop stops the walk",
+			remoteAddr: "10.1.2.3:4242",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, garbage, 10.9.9.9"}},
+			expected:   "10.9.9.9",
+		},
+		{
+			name:       "X-Real-IP from trusted proxy",
+			remoteAddr: "192.168.1.1:4242",
+			header:     ProxyHeaderXRealIP,
+			headers:    map[string][]string{"X-Real-IP": {"198.51.100.20"}},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "X-Real-IP is ignored unless configured",
+			remoteAddr: "192.168.1.1:4242",
+			headers:    map[string][]string{"X-Real-IP": {"198.51.100.20"}},
+			expected:   "192.168.1.1",
+		},
+		{
+			name:       "IPv6 trusted proxy",
+			remoteAddr: "[2001:db8::1]:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"2a00:1450:4001:81c::200e"}},
+			expected:   "2a00:1450:4001:81c::200e",
+		},
+		{
+			name:       "Client Forwarded header is ignored when the proxy sets X-Forwarded-For",
+			remoteAddr: "10.0.0.1:4242",
+			headers: map[string][]string{
+				"Forwarded":       {"for=8.8.8.8"},
+				"X-Forwarded-For": {"203.0.113.9"},
+			},
+			expected: "203.0.113.9",
+		},
+		{
+			name:       "Client X-Forwarded-For header is ignored when the proxy sets Forwarded",
+			remoteAddr: "10.1.2.3:4242",
+			header:     ProxyHeaderForwarded,
+			headers: map[string][]string{
+				"Forwarded":       {`for=198.51.100.30;proto=https, for="[2001:db8::9]:8443"`},
+				"X-Forwarded-For": {"1.2.3.4"},
+			},
+			expected: "198.51.100.30",
+		},
+		{
+			name:       "Obfuscated Forwarded hop stops the walk",
+			remoteAddr: "10.1.2.3:4242",
+			header:     ProxyHeaderForwarded,
+			headers:    map[string][]string{"Forwarded": {"for=198.51.100.30, for=_hidden, for=10.9.9.9"}},
+			expected:   "10.9.9.9",
+		},
+		{
+			name:       "IPv4-mapped IPv6 remote address",
+			remoteAddr: "[::ffff:10.1.2.3]:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.10"}},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "IPv6 zone is dropped",
+			remoteAddr: "[fe80::1%eth0]:443",
+			expected:   "fe80::1",
+		},
+		{
+			name:       "IPv4-mapped hop behind IPv6 proxy",
+			remoteAddr: "[2001:db8::1]:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"::ffff:198.51.100.10, 2001:db8::2"}},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "Unbracketed IPv6 in Forwarded",
+			remoteAddr: "10.1.2.3:4242",
+			header:     ProxyHeaderForwarded,
+			headers:    map[string][]string{"Forwarded": {`for="2a00:1450:4001:81c::200e"`}},
+			expected:   "2a00:1450:4001:81c::200e",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, values := range tt.headers {
+				for _, v := range values {
+					req.Header.Add(k, v)
+				}
+			}
+
+			resolver := *resolver
+			resolver.Header = tt.header
+			addr, err := resolver.Resolve(req)
+			if err != nil {
+				t.Fatalf("Resolve returned error: %v", err)
+			}
+			if addr != netip.MustParseAddr(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, addr)
+			}
+		})
+	}
+}
+
+func TestNewClientIPResolver_Invalid(t *testing.T) {
+	if _, err := NewClientIPResolver("10.0.0.0/33"); err == nil {
+		t.Error("Expected error for invalid CIDR")
+	}
+	if _, err := NewClientIPResolver("not-an-ip"); err == nil {
+		t.Error("Expected error for invalid IP")
+	}
+}
+
+func TestBotDetector_CollectResolvedClientIP(t *testing.T) {
+	resolver, _ := NewClientIPResolver("10.0.0.0/8")
+	config := DefaultDetectorConfig()
+	config.ClientIPResolver = resolver
+	detector := NewDetectorWithConfig(config)
+
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent":      "Mozilla/5.0",
+		"X-Forwarded-For": "198.51.100.10",
+	})
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	components, _ := detector.Collect(req)
+	if components.ResolvedClientIP.GetState() != StateSuccess {
+		t.Fatalf("Expected resolved client IP, got error %s", components.ResolvedClientIP.GetError())
+	}
+	if ip := components.ResolvedClientIP.GetValue(); ip != netip.MustParseAddr("198.51.100.10") {
+		t.Errorf("Expected 198.51.100.10, got %s", ip)
+	}
+
+	// Without a resolver only RemoteAddr is used
+	components, _ = NewDetector().Collect(req)
+	if ip := components.ResolvedClientIP.GetValue(); ip != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("Expected RemoteAddr 10.0.0.1, got %s", ip)
+	}
+
+	req.RemoteAddr = ""
+	components, _ = NewDetector().Collect(req)
+	if components.ResolvedClientIP.GetState() == StateSuccess {
+		t.Error("Expected error component for missing RemoteAddr")
+	}
+}
+
+func TestClientIPResolver_MappedTrustedProxy(t *testing.T) {
+	resolver, err := NewClientIPResolver("::ffff:10.0.0.0/104")
+	if err != nil {
+		t.Fatalf("NewClientIPResolver returned error: %v", err)
+	}
+	if !resolver.IsTrusted(netip.MustParseAddr("10.1.2.3")) {
+		t.Error("Expected IPv4-mapped CIDR to trust IPv4 addresses")
+	}
+	if !resolver.IsTrusted(netip.MustParseAddr("::ffff:10.1.2.3")) {
+		t.Error("Expected IPv4-mapped CIDR to trust IPv4-mapped addresses")
+	}
+}
+
+func TestIPAggregation(t *testing.T) {
+	tests := []struct {
+		name        string
+		aggregation IPAggregation
+		addr        string
+		expected    string
+	}{
+		{"IPv4 default", DefaultIPAggregation(), "192.0.2.1", "192.0.2.1/32"},
+		{"IPv4-mapped treated as IPv4", DefaultIPAggregation(), "::ffff:192.0.2.1", "192.0.2.1/32"},
+		{"IPv6 default /64", DefaultIPAggregation(), "2001:db8:1:2:3:4:5:6", "2001:db8:1:2::/64"},
+		{"IPv6 zone dropped", DefaultIPAggregation(), "fe80::1%eth0", "fe80::/64"},
+		{"IPv4 /24", IPAggregation{IPv4Bits: 24}, "192.0.2.77", "192.0.2.0/24"},
+		{"Zero value keeps full addresses", IPAggregation{}, "2001:db8::1", "2001:db8::1/128"},
+		{"Out of range bits", IPAggregation{IPv4Bits: 64}, "192.0.2.1", "192.0.2.1/32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if key := tt.aggregation.Key(netip.MustParseAddr(tt.addr)); key != tt.expected {
+				t.Errorf("Key(%s) = %q, want %q", tt.addr, key, tt.expected)
+			}
+		})
+	}
+
+	// Two addresses from the same /64 share a key
+	a := DefaultIPAggregation()
+	if a.Key(netip.MustParseAddr("2001:db8::1")) != a.Key(netip.MustParseAddr("2001:db8::ffff:1")) {
+		t.Error("Expected addresses in the same /64 to share a key")
+	}
+	if a.Key(netip.Addr{}) != "" {
+		t.Error("Expected empty key for invalid address")
+	}
+}
+
+func TestClassifyIP(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected IPClass
+	}{
+		{"8.8.8.8", IPClassPublic},
+		{"2a00:1450:4001:81c::200e", IPClassPublic},
+		{"10.1.2.3", IPClassPrivate},
+		{"::ffff:192.168.1.1", IPClassPrivate},
+		{"fd00::1", IPClassPrivate},
+		{"100.64.1.1", IPClassSharedNAT},
+		{"127.0.0.1", IPClassLoopback},
+		{"::1", IPClassLoopback},
+		{"169.254.169.254", IPClassLinkLocal},
+		{"fe80::1%eth0", IPClassLinkLocal},
+		{"224.0.0.1", IPClassMulticast},
+		{"ff02::1", IPClassMulticast},
+		{"0.0.0.0", IPClassUnspecified},
+		{"240.0.0.1", IPClassReserved},
+		{"255.255.255.255", IPClassReserved},
+		{"198.18.0.1", IPClassReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			addr := netip.MustParseAddr(tt.addr)
+			if class := ClassifyIP(addr); class != tt.expected {
+				t.Errorf("ClassifyIP(%s) = %s, want %s", tt.addr, class, tt.expected)
+			}
+			if IsPublicIP(addr) != (tt.expected == IPClassPublic) {
+				t.Errorf("IsPublicIP(%s) = %t", tt.addr, IsPublicIP(addr))
+			}
+		})
+	}
+
+	if ClassifyIP(netip.Addr{}) != IPClassInvalid {
+		t.Error("Expected invalid class for zero address")
+	}
+	if !IsInternalIP(netip.MustParseAddr("100.64.0.1")) || IsInternalIP(netip.MustParseAddr("8.8.8.8")) {
+		t.Error("Unexpected IsInternalIP result")
+	}
+}
+
+func TestComponentDict_ClientIP(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "[::ffff:198.51.100.7]:443"
+	components, _ := NewDetector().Collect(req)
+	if addr, ok := components.ClientIP(); !ok || addr != netip.MustParseAddr("198.51.100.7") {
+		t.Errorf("ClientIP() = %s, %t", addr, ok)
+	}
+
+	req.RemoteAddr = "garbage"
+	components, _ = NewDetector().Collect(req)
+	if _, ok := components.ClientIP(); ok {
+		t.Error("Expected no client IP for unparseable RemoteAddr")
+	}
+}
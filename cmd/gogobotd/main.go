@@ -0,0 +1,71 @@
+// Command gogobotd serves gogobot's detection over HTTP, so services not written in Go can
+// classify their requests with the same logic.
+//
+// Clients POST the request to classify to /v1/detect and get the detection report back:
+//
+//	curl -X POST localhost:8080/v1/detect -d '{
+//	  "ip": "203.0.113.7",
+//	  "method": "GET",
+//	  "url": "/products?page=2",
+//	  "headers": {"User-Agent": "curl/8.4.0", "Accept": "*/*"}
+//	}'
+//
+// /healthz and /readyz serve liveness and readiness probes, and /metrics serves request and
+// verdict counters in the Prometheus text format.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lytics/gogobot"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	threshold := flag.Float64("score-threshold", gogobot.DefaultScoreThreshold, "combined score flagging a client as a bot")
+	cacheSize := flag.Int("result-cache", 10000, "verdicts cached per client IP and user agent; 0 disables the cache")
+	cacheTTL := flag.Duration("result-cache-ttl", time.Minute, "how long cached verdicts are reused")
+	drain := flag.Duration("shutdown-timeout", 10*time.Second, "how long in-flight requests may finish on shutdown")
+	flag.Parse()
+
+	config := gogobot.DetectorConfig{ScoreThreshold: *threshold}
+	if *cacheSize > 0 {
+		config.ResultCache = gogobot.NewResultCache(*cacheSize, *cacheTTL)
+	}
+	s := newServer(gogobot.NewDetectorWithConfig(config), gogobot.DefaultMiddlewareConfig())
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Fail readiness first so load balancers stop sending new requests
+		s.ready.Store(false)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *drain)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("gogobotd: shutdown: %v", err)
+		}
+	}()
+
+	log.Printf("gogobotd: listening on %s", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("gogobotd: %v", err)
+	}
+}
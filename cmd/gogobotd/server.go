@@ -0,0 +1,232 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lytics/gogobot"
+)
+
+// maxDetectBody bounds the size of detect requests
+const maxDetectBody = 64 << 10
+
+// DetectRequest describes the request a client wants classified
+type DetectRequest struct {
+	// IP is the address of the client that sent the request
+	IP string `json:"ip"`
+	// Method is the request method, GET when empty
+	Method string `json:"method,omitempty"`
+	// URL is the request target, e.g. "/products?page=2"; "/" when empty
+	URL string `json:"url,omitempty"`
+	// Proto is the protocol version, e.g. "HTTP/2.0"; HTTP/1.1 when empty
+	Proto string `json:"proto,omitempty"`
+	// TLS reports whether the request arrived over TLS
+	TLS bool `json:"tls,omitempty"`
+	// Headers are the request headers, including Host; values may be a string or a list of
+	// strings
+	Headers map[string]HeaderValues `json:"headers"`
+}
+
+// HeaderValues are the values of a header, decoded from a string or a list of strings
+type HeaderValues []string
+
+// UnmarshalJSON decodes a string or a list of strings
+func (v *HeaderValues) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = HeaderValues{value}
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return errors.New("header values must be a string or a list of strings")
+	}
+	*v = values
+	return nil
+}
+
+// DetectResponse is the detection report
+type DetectResponse struct {
+	Result  gogobot.BotDetectionResult `json:"result"`
+	Browser gogobot.BrowserInfo        `json:"browser"`
+}
+
+// server serves the detection API
+type server struct {
+	middleware func(http.Handler) http.Handler
+
+	requests atomic.Uint64
+	failures atomic.Uint64
+	// verdicts counts the detect responses per bot kind, "human" for clients not flagged
+	verdicts sync.Map
+	ready    atomic.Bool
+}
+
+// newServer creates a server classifying requests with the detector's middleware, which keeps
+// no state between concurrent detections
+func newServer(detector *gogobot.BotDetector, config gogobot.MiddlewareConfig) *server {
+	s := &server{middleware: detector.MiddlewareWithConfig(config)}
+	s.ready.Store(true)
+	return s
+}
+
+// handler returns the server's routes
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/detect", s.serveDetect)
+	mux.HandleFunc("GET /healthz", s.serveHealth)
+	mux.HandleFunc("GET /readyz", s.serveReady)
+	mux.HandleFunc("GET /metrics", s.serveMetrics)
+	return mux
+}
+
+// newRequest builds the request to classify
+func (r DetectRequest) newRequest(parent *http.Request) (*http.Request, error) {
+	addr, err := netip.ParseAddr(r.IP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ip %q", r.IP)
+	}
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	target := r.URL
+	if target == "" {
+		target = "/"
+	}
+	proto := r.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+	major, minor, ok := http.ParseHTTPVersion(proto)
+	if !ok {
+		return nil, fmt.Errorf("invalid proto %q", proto)
+	}
+
+	req, err := http.NewRequestWithContext(parent.Context(), method, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Proto, req.ProtoMajor, req.ProtoMinor = proto, major, minor
+	req.RemoteAddr = net.JoinHostPort(addr.String(), "0")
+	req.RequestURI = req.URL.RequestURI()
+	if r.TLS {
+		req.TLS = &tls.ConnectionState{HandshakeComplete: true}
+	}
+	for name, values := range r.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+	return req, nil
+}
+
+// serveDetect classifies the described request
+func (s *server) serveDetect(w http.ResponseWriter, req *http.Request) {
+	s.requests.Add(1)
+	var body DetectRequest
+	decoder := json.NewDecoder(io.LimitReader(req.Body, maxDetectBody))
+	if err := decoder.Decode(&body); err != nil {
+		s.fail(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	detectReq, err := body.newRequest(req)
+	if err != nil {
+		s.fail(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var response *DetectResponse
+	s.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if result, ok := gogobot.GetResultFromContext(r.Context()); ok {
+			response = &DetectResponse{Result: *result}
+		}
+	})).ServeHTTP(discardResponse{}, detectReq)
+	if response == nil {
+		s.fail(w, "detection failed", http.StatusInternalServerError)
+		return
+	}
+	response.Browser = gogobot.ParseBrowser(detectReq.Header.Get("User-Agent"))
+	s.count(&response.Result)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// fail answers with a JSON error
+func (s *server) fail(w http.ResponseWriter, message string, statusCode int) {
+	s.failures.Add(1)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// count records a verdict
+func (s *server) count(result *gogobot.BotDetectionResult) {
+	kind := "human"
+	if result.Bot {
+		kind = string(result.BotKind)
+	}
+	counter, _ := s.verdicts.LoadOrStore(kind, new(atomic.Uint64))
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// serveHealth reports that the process is up
+func (s *server) serveHealth(w http.ResponseWriter, req *http.Request) {
+	io.WriteString(w, "ok\n")
+}
+
+// serveReady reports whether the server accepts detect requests; it stops while shutting down
+func (s *server) serveReady(w http.ResponseWriter, req *http.Request) {
+	if !s.ready.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	io.WriteString(w, "ok\n")
+}
+
+// serveMetrics writes the counters in the Prometheus text format
+func (s *server) serveMetrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	var b strings.Builder
+	b.WriteString("# HELP gogobotd_requests_total Detect requests received.\n")
+	b.WriteString("# TYPE gogobotd_requests_total counter\n")
+	fmt.Fprintf(&b, "gogobotd_requests_total %d\n", s.requests.Load())
+	b.WriteString("# HELP gogobotd_errors_total Detect requests that failed.\n")
+	b.WriteString("# TYPE gogobotd_errors_total counter\n")
+	fmt.Fprintf(&b, "gogobotd_errors_total %d\n", s.failures.Load())
+	b.WriteString("# HELP gogobotd_verdicts_total Detection verdicts by bot kind.\n")
+	b.WriteString("# TYPE gogobotd_verdicts_total counter\n")
+	var kinds []string
+	s.verdicts.Range(func(kind, _ any) bool {
+		kinds = append(kinds, kind.(string))
+		return true
+	})
+	slices.Sort(kinds)
+	for _, kind := range kinds {
+		counter, _ := s.verdicts.Load(kind)
+		fmt.Fprintf(&b, "gogobotd_verdicts_total{kind=%q} %d\n", kind, counter.(*atomic.Uint64).Load())
+	}
+	io.WriteString(w, b.String())
+}
+
+// discardResponse drops what the middleware writes; the report is taken from the context
+type discardResponse struct{}
+
+func (discardResponse) Header() http.Header         { return http.Header{} }
+func (discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (discardResponse) WriteHeader(int)             {}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lytics/gogobot"
+)
+
+func TestServer_Detect(t *testing.T) {
+	handler := newServer(gogobot.NewDetector(), gogobot.DefaultMiddlewareConfig()).handler()
+
+	tests := []struct {
+		body string
+		bot  bool
+		kind gogobot.BotKind
+	}{
+		{`{"ip": "203.0.113.7", "url": "/products", "headers": {"User-Agent": "curl/8.4.0", "Accept": "*/*"}}`, true, gogobot.BotKindCurl},
+		{`{"ip": "203.0.113.7", "headers": {
+			"Host": "example.com",
+			"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			"Accept-Language": ["en-US,en;q=0.9"],
+			"Accept-Encoding": "gzip, deflate, br",
+			"Connection": "keep-alive",
+			"Cache-Control": "max-age=0",
+			"Upgrade-Insecure-Requests": "1"
+		}}`, false, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("POST", "/v1/detect", strings.NewReader(tt.body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var response DetectResponse
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Result.Bot != tt.bot || response.Result.BotKind != tt.kind {
+			t.Errorf("Expected bot=%v kind=%q, got %+v", tt.bot, tt.kind, response.Result)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, line := range []string{"gogobotd_requests_total 2\n", `gogobotd_verdicts_total{kind="curl"} 1`, `gogobotd_verdicts_total{kind="human"} 1`} {
+		if !strings.Contains(rec.Body.String(), line) {
+			t.Errorf("Expected metrics to contain %q:\n%s", line, rec.Body.String())
+		}
+	}
+}
+
+func TestServer_DetectInvalid(t *testing.T) {
+	handler := newServer(gogobot.NewDetector(), gogobot.DefaultMiddlewareConfig()).handler()
+	for _, body := range []string{
+		`not json`,
+		`{"headers": {"User-Agent": "curl/8.4.0"}}`,
+		`{"ip": "203.0.113.7", "proto": "SPDY", "headers": {}}`,
+		`{"ip": "203.0.113.7", "headers": {"Accept": 1}}`,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("POST", "/v1/detect", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestServer_Health(t *testing.T) {
+	s := newServer(gogobot.NewDetector(), gogobot.DefaultMiddlewareConfig())
+	handler := s.handler()
+	for _, path := range []string{"/healthz", "/readyz"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected 200 from %s, got %d", path, rec.Code)
+		}
+	}
+
+	s.ready.Store(false)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while shutting down, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,116 @@
+package gogobot
+
+import (
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConnectionPolicy sets which hop-by-hop connection options clients may request. Browsers only
+// send "Connection: Upgrade" to open a WebSocket, and gRPC clients send "TE: trailers"; other
+// upgrades and TE codings are sent by tools probing servers and proxies.
+type ConnectionPolicy struct {
+	// Upgrades are the protocols clients may upgrade to, e.g. "websocket" or "h2c"
+	Upgrades []string
+	// Trailers allows "TE: trailers"
+	Trailers bool
+}
+
+// DefaultConnectionPolicy allows WebSocket handshakes and "TE: trailers"
+func DefaultConnectionPolicy() *ConnectionPolicy {
+	return &ConnectionPolicy{
+		Upgrades: []string{"websocket"},
+		Trailers: true,
+	}
+}
+
+// ConnectionOptions are the connection options a request asks for, with the policy judging them
+type ConnectionOptions struct {
+	// Tokens are the lower-case options listed in the Connection header
+	Tokens []string `json:"tokens,omitempty"`
+	// Upgrade is the lower-case name of the first protocol in the Upgrade header, without version
+	Upgrade string `json:"upgrade,omitempty"`
+	TE      string `json:"te,omitempty"`
+	// WebSocket is true for complete WebSocket opening handshakes
+	WebSocket bool             `json:"webSocket"`
+	Policy    ConnectionPolicy `json:"policy"`
+}
+
+// connectionTokens returns the lower-case options of a request's Connection headers
+func connectionTokens(req *http.Request) []string {
+	var tokens []string
+	for _, value := range req.Header.Values("Connection") {
+		tokens = append(tokens, parseAcceptList(value)...)
+	}
+	return tokens
+}
+
+// isUpgradeRequest reports whether the request asks to switch protocols
+func isUpgradeRequest(req *http.Request) bool {
+	return req.Header.Get("Upgrade") != "" && slices.Contains(connectionTokens(req), "upgrade")
+}
+
+// isWebSocketHandshake reports whether the request is a complete WebSocket opening handshake
+// (RFC 6455 section 4.1)
+func isWebSocketHandshake(req *http.Request) bool {
+	if req.Method != http.MethodGet || !isUpgradeRequest(req) || !containsFold(parseAcceptList(req.Header.Get("Upgrade")), "websocket") {
+		return false
+	}
+	key, err := base64.StdEncoding.DecodeString(req.Header.Get("Sec-WebSocket-Key"))
+	return err == nil && len(key) == 16 && req.Header.Get("Sec-WebSocket-Version") == "13"
+}
+
+func getConnectionOptions(req *http.Request, policy *ConnectionPolicy) Component[ConnectionOptions] {
+	if policy == nil {
+		policy = DefaultConnectionPolicy()
+	}
+	options := ConnectionOptions{
+		Tokens:    connectionTokens(req),
+		TE:        strings.ToLower(strings.TrimSpace(req.Header.Get("TE"))),
+		WebSocket: isWebSocketHandshake(req),
+		Policy:    *policy,
+	}
+	if upgrades := parseAcceptList(req.Header.Get("Upgrade")); len(upgrades) > 0 {
+		options.Upgrade, _, _ = strings.Cut(upgrades[0], "/")
+	}
+	return SuccessComponent[ConnectionOptions]{
+		State: StateSuccess,
+		Value: options,
+	}
+}
+
+// connectionViolation returns a description of connection options the policy does not allow,
+// or ""
+func connectionViolation(options ConnectionOptions) string {
+	if slices.Contains(options.Tokens, "upgrade") {
+		switch {
+		case options.Upgrade == "":
+			return "Connection upgrade without Upgrade header"
+		case !containsFold(options.Policy.Upgrades, options.Upgrade):
+			return "Connection upgrade to " + strconv.Quote(options.Upgrade)
+		case options.Upgrade == "websocket" && !options.WebSocket:
+			return "incomplete WebSocket handshake"
+		}
+	}
+	if slices.Contains(options.Tokens, "te") && (!options.Policy.Trailers || options.TE != "trailers") {
+		return "Connection TE with TE " + strconv.Quote(options.TE)
+	}
+	return ""
+}
+
+// detectConnection flags connection upgrades and TE codings the ConnectionPolicy does not allow
+func detectConnection(components *ComponentDict) *BotDetectionResult {
+	if components.ConnectionOptions.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+	if reason := connectionViolation(components.ConnectionOptions.GetValue()); reason != "" {
+		return &BotDetectionResult{
+			Bot:     true,
+			BotKind: BotKindUnknown,
+			Reasons: []string{reason},
+		}
+	}
+	return &BotDetectionResult{Bot: false}
+}
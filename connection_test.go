@@ -0,0 +1,115 @@
+package gogobot
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newWebSocketRequest(target string) *http.Request {
+	req := newBrowserRequest(target, "203.0.113.7:1234")
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	return req
+}
+
+func TestIsWebSocketHandshake(t *testing.T) {
+	req := newWebSocketRequest("/ws")
+	if !isWebSocketHandshake(req) {
+		t.Error("Expected a complete WebSocket handshake")
+	}
+	req.Header.Set("Sec-WebSocket-Key", "short")
+	if isWebSocketHandshake(req) {
+		t.Error("Expected an invalid key to fail the handshake")
+	}
+	req = newWebSocketRequest("/ws")
+	req.Method = http.MethodPost
+	if isWebSocketHandshake(req) {
+		t.Error("Expected a POST not to be a handshake")
+	}
+}
+
+func TestDetectConnection(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		policy  *ConnectionPolicy
+		reason  string
+	}{
+		{"keep-alive", map[string]string{"Connection": "keep-alive"}, nil, ""},
+		{"websocket", nil, nil, ""},
+		{"incomplete websocket", map[string]string{"Sec-WebSocket-Version": ""}, nil, "incomplete WebSocket handshake"},
+		{"upgrade without protocol", map[string]string{"Upgrade": ""}, nil, "Connection upgrade without Upgrade header"},
+		{"h2c", map[string]string{"Upgrade": "h2c"}, nil, `Connection upgrade to "h2c"`},
+		{"h2c allowed", map[string]string{"Upgrade": "h2c"}, &ConnectionPolicy{Upgrades: []string{"h2c"}}, ""},
+		{"te trailers", map[string]string{"Connection": "TE", "TE": "trailers"}, nil, ""},
+		{"te trailers disallowed", map[string]string{"Connection": "TE", "TE": "trailers"}, &ConnectionPolicy{}, `Connection TE with TE "trailers"`},
+		{"te gzip", map[string]string{"Connection": "TE", "TE": "gzip"}, nil, `Connection TE with TE "gzip"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newWebSocketRequest("/ws")
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			components := &ComponentDict{ConnectionOptions: getConnectionOptions(req, tt.policy)}
+			result := detectConnection(components)
+			if tt.reason == "" {
+				if result.Bot {
+					t.Errorf("Expected connection to be allowed, got %+v", result)
+				}
+				return
+			}
+			if !result.Bot || len(result.Reasons) != 1 || result.Reasons[0] != tt.reason {
+				t.Errorf("Expected %q, got %+v", tt.reason, result)
+			}
+		})
+	}
+}
+
+func TestMiddleware_WebSocketPassthrough(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.ErrorRateLimit = DefaultErrorRateLimit()
+	config.CookieChallenge = NewCookieChallenge([]byte("test-secret"))
+	detector := NewDetectorWithConfig(config)
+
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+		rw.Flush()
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	req := newWebSocketRequest(server.URL + "/ws")
+	req.RequestURI = ""
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("Expected the handler to hijack the connection, got %d", resp.StatusCode)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		t.Errorf("Expected websocket upgrade, got %q", resp.Header.Get("Upgrade"))
+	}
+}
@@ -0,0 +1,181 @@
+// Package fasthttpbot runs gogobot's detection and middleware in fasthttp servers. Requests
+// are collected straight from the fasthttp.RequestCtx: headers, remote address and TLS state are
+// read without copying the body or going through fasthttpadaptor. It is a separate module to
+// keep fasthttp out of gogobot's dependencies.
+package fasthttpbot
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lytics/gogobot"
+	"github.com/valyala/fasthttp"
+)
+
+// User value keys of the detection result and components
+const (
+	ResultKey     = "gogobot.result"
+	ComponentsKey = "gogobot.components"
+)
+
+// NewRequest returns the request of ctx in the form the detector collects from. The body reads
+// ctx's body in place, and the request's context is ctx.
+func NewRequest(ctx *fasthttp.RequestCtx) *http.Request {
+	header := make(http.Header, ctx.Request.Header.Len())
+	ctx.Request.Header.VisitAll(func(key, value []byte) {
+		name := string(key)
+		// net/http keeps these in fields of the request
+		if name == fasthttp.HeaderHost || name == fasthttp.HeaderTransferEncoding {
+			return
+		}
+		header.Add(name, string(value))
+	})
+
+	requestURI := string(ctx.RequestURI())
+	target, err := url.ParseRequestURI(requestURI)
+	if err != nil {
+		target = &url.URL{Path: string(ctx.Path())}
+	}
+	proto := string(ctx.Request.Header.Protocol())
+	major, minor, ok := http.ParseHTTPVersion(proto)
+	if !ok {
+		proto, major, minor = "HTTP/1.1", 1, 1
+	}
+
+	req := &http.Request{
+		Method:        string(ctx.Method()),
+		URL:           target,
+		Proto:         proto,
+		ProtoMajor:    major,
+		ProtoMinor:    minor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(ctx.Request.Body())),
+		ContentLength: int64(ctx.Request.Header.ContentLength()),
+		Host:          string(ctx.Host()),
+		RemoteAddr:    ctx.RemoteAddr().String(),
+		RequestURI:    requestURI,
+		TLS:           ctx.TLSConnectionState(),
+	}
+	return req.WithContext(ctx)
+}
+
+// Detect runs detection on the request of ctx
+func Detect(detector *gogobot.BotDetector, ctx *fasthttp.RequestCtx) (gogobot.BotDetectionResult, error) {
+	return detector.DetectFromRequest(NewRequest(ctx))
+}
+
+// Middleware returns a fasthttp handler running detector's middleware with config before next.
+// Requests the middleware passes on reach next with the result and components in the user
+// values; challenges, blocks and the other responses of the middleware are sent instead.
+func Middleware(detector *gogobot.BotDetector, config gogobot.MiddlewareConfig, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	middleware := detector.MiddlewareWithConfig(config)
+	return func(ctx *fasthttp.RequestCtx) {
+		passed := Run(ctx, middleware)
+		if passed == nil {
+			return
+		}
+		if result, ok := gogobot.GetResultFromContext(passed.Context()); ok {
+			ctx.SetUserValue(ResultKey, result)
+		}
+		if components, ok := gogobot.GetComponentsFromContext(passed.Context()); ok {
+			ctx.SetUserValue(ComponentsKey, components)
+		}
+		next(ctx)
+	}
+}
+
+// Run runs a net/http middleware on the request of ctx. It returns the request the middleware
+// passed on, with the changes it made to the headers applied to ctx's request, or nil when the
+// middleware wrote ctx's response instead. Headers the middleware set, such as cookies, go out
+// with either response. Responses are buffered, so a dripping Tarpit is sent at once, and
+// connection upgrades cannot be hijacked.
+func Run(ctx *fasthttp.RequestCtx, middleware func(http.Handler) http.Handler) *http.Request {
+	w := &responseWriter{header: http.Header{}}
+	var passed *http.Request
+	middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		passed = r
+	})).ServeHTTP(w, NewRequest(ctx))
+
+	for name, values := range w.header {
+		for _, value := range values {
+			ctx.Response.Header.Add(name, value)
+		}
+	}
+	if passed == nil {
+		ctx.SetStatusCode(w.status())
+		ctx.SetBody(w.body.Bytes())
+		return nil
+	}
+	copyRequestHeader(&ctx.Request.Header, passed.Header)
+	return passed
+}
+
+// Result returns the detection result stored by Middleware
+func Result(ctx *fasthttp.RequestCtx) (*gogobot.BotDetectionResult, bool) {
+	result, ok := ctx.UserValue(ResultKey).(*gogobot.BotDetectionResult)
+	return result, ok
+}
+
+// Components returns the components stored by Middleware, unless it served a cached verdict
+func Components(ctx *fasthttp.RequestCtx) (*gogobot.ComponentDict, bool) {
+	components, ok := ctx.UserValue(ComponentsKey).(*gogobot.ComponentDict)
+	return components, ok
+}
+
+// copyRequestHeader applies the middleware's changes to the request headers, such as the
+// ResultHeaders forwarded upstream and the removal of client-sent ones
+func copyRequestHeader(dst *fasthttp.RequestHeader, header http.Header) {
+	var removed []string
+	dst.VisitAll(func(key, _ []byte) {
+		name := string(key)
+		if name == fasthttp.HeaderHost || name == fasthttp.HeaderTransferEncoding {
+			return
+		}
+		if _, ok := header[http.CanonicalHeaderKey(name)]; !ok {
+			removed = append(removed, name)
+		}
+	})
+	for _, name := range removed {
+		dst.Del(name)
+	}
+	for name, values := range header {
+		dst.Del(name)
+		for _, value := range values {
+			dst.Add(name, value)
+		}
+	}
+}
+
+// responseWriter buffers the middleware's response
+type responseWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Flush lets a dripping Tarpit write its whole body into the buffer
+func (w *responseWriter) Flush() {}
+
+func (w *responseWriter) status() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+	return w.code
+}
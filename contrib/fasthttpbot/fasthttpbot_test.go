@@ -0,0 +1,55 @@
+package fasthttpbot
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/lytics/gogobot"
+	"github.com/valyala/fasthttp"
+)
+
+func newTestCtx(userAgent string) *fasthttp.RequestCtx {
+	var req fasthttp.Request
+	req.SetRequestURI("http://example.com/page?q=1")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Bot-Detected", "false")
+	var ctx fasthttp.RequestCtx
+	ctx.Init(&req, &net.TCPAddr{IP: net.IPv4(203, 0, 113, 7), Port: 1234}, nil)
+	return &ctx
+}
+
+func TestNewRequest(t *testing.T) {
+	req := NewRequest(newTestCtx("curl/8.4.0"))
+	if req.Method != http.MethodGet || req.Host != "example.com" || req.URL.Path != "/page" || req.URL.RawQuery != "q=1" {
+		t.Errorf("Expected the request line, got %s %s %s", req.Method, req.Host, req.URL)
+	}
+	if req.RemoteAddr != "203.0.113.7:1234" || req.UserAgent() != "curl/8.4.0" || req.Header.Get("Host") != "" {
+		t.Errorf("Expected remote address and headers, got %s %v", req.RemoteAddr, req.Header)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	config := gogobot.DefaultMiddlewareConfig()
+	config.ResultHeaders = &gogobot.ResultHeaders{Request: true}
+	var result *gogobot.BotDetectionResult
+	var forwarded string
+	handler := Middleware(gogobot.NewDetector(), config, func(ctx *fasthttp.RequestCtx) {
+		result, _ = Result(ctx)
+		forwarded = string(ctx.Request.Header.Peek("X-Bot-Detected"))
+	})
+
+	handler(newTestCtx("curl/8.4.0"))
+	if result == nil || !result.Bot || forwarded != "true" {
+		t.Errorf("Expected the curl verdict, got %+v and %q", result, forwarded)
+	}
+
+	config.BlockBots = true
+	ctx := newTestCtx("curl/8.4.0")
+	Middleware(gogobot.NewDetector(), config, func(ctx *fasthttp.RequestCtx) {
+		t.Error("Expected blocked request not to reach the handler")
+	})(ctx)
+	if ctx.Response.StatusCode() != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", ctx.Response.StatusCode())
+	}
+}
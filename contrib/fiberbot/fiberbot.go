@@ -0,0 +1,56 @@
+// Package fiberbot runs gogobot's middleware in Fiber applications. Fiber is built on fasthttp
+// and has no *http.Request, so requests are collected from the fasthttp.RequestCtx by
+// fasthttpbot, and the middleware's response, if it answers instead of the application, is
+// copied back. It is a separate module to keep Fiber and fasthttp out of gogobot's
+// dependencies.
+package fiberbot
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lytics/gogobot"
+	"github.com/lytics/gogobot/contrib/fasthttpbot"
+)
+
+// Locals keys of the detection result and components
+const (
+	ResultKey     = "gogobot.result"
+	ComponentsKey = "gogobot.components"
+)
+
+// New returns a Fiber handler running detector's middleware with the default configuration
+func New(detector *gogobot.BotDetector) fiber.Handler {
+	return NewWithConfig(detector, gogobot.DefaultMiddlewareConfig())
+}
+
+// NewWithConfig returns a Fiber handler running detector's middleware with config. Requests the
+// middleware passes on continue down the Fiber chain with the result in Locals; challenges,
+// blocks and the other responses of the middleware are sent instead (see fasthttpbot.Run).
+func NewWithConfig(detector *gogobot.BotDetector, config gogobot.MiddlewareConfig) fiber.Handler {
+	middleware := detector.MiddlewareWithConfig(config)
+	return func(c *fiber.Ctx) error {
+		passed := fasthttpbot.Run(c.Context(), middleware)
+		if passed == nil {
+			return nil
+		}
+		if result, ok := gogobot.GetResultFromContext(passed.Context()); ok {
+			c.Locals(ResultKey, result)
+		}
+		if components, ok := gogobot.GetComponentsFromContext(passed.Context()); ok {
+			c.Locals(ComponentsKey, components)
+		}
+		return c.Next()
+	}
+}
+
+// Result returns the detection result of the request, if the middleware ran
+func Result(c *fiber.Ctx) (*gogobot.BotDetectionResult, bool) {
+	result, ok := c.Locals(ResultKey).(*gogobot.BotDetectionResult)
+	return result, ok
+}
+
+// Components returns the collected components of the request, if the middleware ran and did not
+// serve a cached verdict
+func Components(c *fiber.Ctx) (*gogobot.ComponentDict, bool) {
+	components, ok := c.Locals(ComponentsKey).(*gogobot.ComponentDict)
+	return components, ok
+}
@@ -0,0 +1,64 @@
+package fiberbot
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lytics/gogobot"
+)
+
+func newTestApp(config gogobot.MiddlewareConfig) *fiber.App {
+	app := fiber.New()
+	app.Use(NewWithConfig(gogobot.NewDetector(), config))
+	app.Get("/", func(c *fiber.Ctx) error {
+		result, ok := Result(c)
+		if !ok {
+			return fiber.ErrInternalServerError
+		}
+		if result.Bot {
+			return c.SendString("bot " + string(result.BotKind) + " " + c.Get("X-Bot-Detected"))
+		}
+		return c.SendString("human " + c.Get("X-Bot-Detected"))
+	})
+	return app
+}
+
+func TestNewWithConfig_Passes(t *testing.T) {
+	config := gogobot.DefaultMiddlewareConfig()
+	config.ResultHeaders = &gogobot.ResultHeaders{Request: true, Response: true}
+	app := newTestApp(config)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "bot curl true" {
+		t.Errorf("Expected the curl verdict in Locals and forwarded headers, got %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("X-Bot-Kind") != "curl" {
+		t.Errorf("Expected result headers on the response, got %v", resp.Header)
+	}
+}
+
+func TestNewWithConfig_Blocks(t *testing.T) {
+	config := gogobot.DefaultMiddlewareConfig()
+	config.BlockBots = true
+	app := newTestApp(config)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusForbidden || string(body) != config.BlockedMessage+"\n" {
+		t.Errorf("Expected the middleware's block, got %d %q", resp.StatusCode, body)
+	}
+}
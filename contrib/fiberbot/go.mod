@@ -0,0 +1,28 @@
+module github.com/lytics/gogobot/contrib/fiberbot
+
+go 1.24.2
+
+require (
+	github.com/gofiber/fiber/v2 v2.52.5
+	github.com/lytics/gogobot v0.0.0
+	github.com/lytics/gogobot/contrib/fasthttpbot v0.0.0
+)
+
+require (
+	github.com/andybalholm/brotli v1.0.5 // indirect
+	github.com/google/uuid v1.5.0 // indirect
+	github.com/klauspost/compress v1.17.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-runewidth v0.0.15 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasthttp v1.51.0 // indirect
+	github.com/valyala/tcplisten v1.0.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+)
+
+replace (
+	github.com/lytics/gogobot => ../..
+	github.com/lytics/gogobot/contrib/fasthttpbot => ../fasthttpbot
+)
@@ -0,0 +1,152 @@
+// Package grpcbot runs gogobot's middleware in gRPC servers, including gRPC-Web behind a proxy.
+// Calls are detected from their metadata (user-agent, x-forwarded-for, :authority and the other
+// headers), peer address and TLS state, presented to the middleware as a POST to the full
+// method name, so MiddlewareConfig.Routes with MatchGlob patterns such as "/shop.Cart/*" set
+// per-method policies. It is a separate module to keep gRPC out of gogobot's dependencies.
+package grpcbot
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lytics/gogobot"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NewRequest returns the call to fullMethod in the form the detector collects from, with ctx as
+// its context
+func NewRequest(ctx context.Context, fullMethod string) *http.Request {
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := make(http.Header, len(md))
+	var host string
+	for key, values := range md {
+		switch {
+		case key == ":authority":
+			if len(values) > 0 {
+				host = values[0]
+			}
+		case strings.HasPrefix(key, ":"), strings.HasSuffix(key, "-bin"):
+			// Pseudo-headers and binary metadata are not HTTP headers
+		default:
+			header[http.CanonicalHeaderKey(key)] = values
+		}
+	}
+
+	req := &http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: fullMethod},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     header,
+		Body:       http.NoBody,
+		Host:       host,
+		RequestURI: fullMethod,
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			req.RemoteAddr = p.Addr.String()
+		}
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			req.TLS = &info.State
+		}
+	}
+	return req.WithContext(ctx)
+}
+
+// UnaryServerInterceptor returns an interceptor running detector's middleware with config on
+// every unary call. Calls the middleware passes on reach the handler with the result in the
+// context (see gogobot.GetResultFromContext); the others fail with the status matching the
+// middleware's response, PERMISSION_DENIED for blocked bots.
+func UnaryServerInterceptor(detector *gogobot.BotDetector, config gogobot.MiddlewareConfig) grpc.UnaryServerInterceptor {
+	middleware := detector.MiddlewareWithConfig(config)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := detect(ctx, middleware, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor running detector's middleware with config when
+// a stream opens, like UnaryServerInterceptor
+func StreamServerInterceptor(detector *gogobot.BotDetector, config gogobot.MiddlewareConfig) grpc.StreamServerInterceptor {
+	middleware := detector.MiddlewareWithConfig(config)
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := detect(stream.Context(), middleware, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// detect runs the middleware on the call, returning the context of the request it passed on
+func detect(ctx context.Context, middleware func(http.Handler) http.Handler, fullMethod string) (context.Context, error) {
+	w := &responseWriter{header: http.Header{}}
+	var passed *http.Request
+	middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		passed = r
+	})).ServeHTTP(w, NewRequest(ctx, fullMethod))
+
+	if passed == nil {
+		return nil, status.Error(code(w.code), strings.TrimSpace(w.body.String()))
+	}
+	return passed.Context(), nil
+}
+
+// code returns the gRPC status of a response of the middleware
+func code(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.PermissionDenied
+	}
+}
+
+// serverStream overrides the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// responseWriter keeps the middleware's response for the status of refused calls
+type responseWriter struct {
+	header http.Header
+	code   int
+	body   strings.Builder
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Flush lets a dripping Tarpit finish
+func (w *responseWriter) Flush() {}
@@ -0,0 +1,58 @@
+package grpcbot
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/lytics/gogobot"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newTestContext(userAgent string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		":authority", "api.example.com",
+		"user-agent", userAgent,
+		"content-type", "application/grpc",
+	))
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(203, 0, 113, 7), Port: 1234}})
+}
+
+func TestNewRequest(t *testing.T) {
+	req := NewRequest(newTestContext("grpc-go/1.67.1"), "/shop.Cart/Add")
+	if req.URL.Path != "/shop.Cart/Add" || req.Host != "api.example.com" || req.RemoteAddr != "203.0.113.7:1234" {
+		t.Errorf("Expected the call, got %s %s %s", req.URL, req.Host, req.RemoteAddr)
+	}
+	if req.UserAgent() != "grpc-go/1.67.1" || req.Header.Get(":authority") != "" {
+		t.Errorf("Expected metadata headers without pseudo-headers, got %v", req.Header)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	config := gogobot.DefaultMiddlewareConfig()
+	config.BlockBots = true
+	config.Routes = []gogobot.RoutePolicy{
+		{Match: gogobot.MatchGlob("/shop.Health/*"), Skip: true},
+	}
+	interceptor := UnaryServerInterceptor(gogobot.NewDetector(), config)
+
+	var detected bool
+	handler := func(ctx context.Context, req any) (any, error) {
+		_, detected = gogobot.GetResultFromContext(ctx)
+		return "ok", nil
+	}
+
+	_, err := interceptor(newTestContext("curl/8.4.0"), nil, &grpc.UnaryServerInfo{FullMethod: "/shop.Cart/Add"}, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PERMISSION_DENIED for curl, got %v", err)
+	}
+
+	resp, err := interceptor(newTestContext("curl/8.4.0"), nil, &grpc.UnaryServerInfo{FullMethod: "/shop.Health/Check"}, handler)
+	if err != nil || resp != "ok" || detected {
+		t.Errorf("Expected skipped method to be served without detection, got %v, %v", resp, err)
+	}
+}
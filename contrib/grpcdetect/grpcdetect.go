@@ -0,0 +1,203 @@
+// Package grpcdetect serves gogobot's detection as the gogobot.v1.DetectionService gRPC service
+// defined in proto/gogobot/v1/detection.proto, and calls it from Go, for sidecar deployments
+// where proxies and application servers classify their requests over a local connection. It is
+// a separate module to keep gRPC and protobuf out of gogobot's dependencies.
+package grpcdetect
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+
+	"github.com/lytics/gogobot"
+	"github.com/lytics/gogobot/contrib/grpcdetect/gogobotv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements DetectionService with a detector
+type Server struct {
+	gogobotv1.UnimplementedDetectionServiceServer
+
+	middleware func(http.Handler) http.Handler
+}
+
+// NewServer creates a service classifying requests with detector. Detection runs through the
+// detector's middleware with the default configuration, which keeps no state between
+// concurrent calls and reports every verdict without acting on it. The headers of a call are
+// rebuilt as an http.Header, so header order detection does not apply.
+func NewServer(detector *gogobot.BotDetector) *Server {
+	return &Server{middleware: detector.MiddlewareWithConfig(gogobot.DefaultMiddlewareConfig())}
+}
+
+// Register registers the service with a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	gogobotv1.RegisterDetectionServiceServer(registrar, s)
+}
+
+// Detect runs bot detection on the described request
+func (s *Server) Detect(ctx context.Context, in *gogobotv1.DetectRequest) (*gogobotv1.DetectResponse, error) {
+	req, err := newHTTPRequest(ctx, in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var result *gogobot.BotDetectionResult
+	s.middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		result, _ = gogobot.GetResultFromContext(r.Context())
+	})).ServeHTTP(discardResponse{}, req)
+	if result == nil {
+		return nil, status.Error(codes.Internal, "detection failed")
+	}
+	return newDetectResponse(result), nil
+}
+
+// newHTTPRequest builds the request to classify
+func newHTTPRequest(ctx context.Context, in *gogobotv1.DetectRequest) (*http.Request, error) {
+	addr, err := netip.ParseAddr(in.GetIp())
+	if err != nil {
+		return nil, fmt.Errorf("invalid ip %q", in.GetIp())
+	}
+	method := in.GetMethod()
+	if method == "" {
+		method = http.MethodGet
+	}
+	target := in.GetUrl()
+	if target == "" {
+		target = "/"
+	}
+	proto := in.GetProto()
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+	major, minor, ok := http.ParseHTTPVersion(proto)
+	if !ok {
+		return nil, fmt.Errorf("invalid proto %q", proto)
+	}
+	if in.GetTlsFingerprint() != "" {
+		ctx = gogobot.WithTLSFingerprint(ctx, in.GetTlsFingerprint())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Proto, req.ProtoMajor, req.ProtoMinor = proto, major, minor
+	req.RemoteAddr = net.JoinHostPort(addr.String(), "0")
+	req.RequestURI = req.URL.RequestURI()
+	if in.GetTls() {
+		req.TLS = &tls.ConnectionState{HandshakeComplete: true}
+	}
+	for _, header := range in.GetHeaders() {
+		for _, value := range header.GetValues() {
+			req.Header.Add(header.GetName(), value)
+		}
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+	return req, nil
+}
+
+// newDetectResponse converts a detection result
+func newDetectResponse(result *gogobot.BotDetectionResult) *gogobotv1.DetectResponse {
+	response := &gogobotv1.DetectResponse{
+		Bot:          result.Bot,
+		BotKind:      string(result.BotKind),
+		Confidence:   result.Score,
+		Reasons:      result.Reasons,
+		Verified:     result.Verified,
+		Country:      result.Country,
+		Asn:          result.ASN,
+		IsDatacenter: result.IsDatacenter,
+		Organization: result.Organization,
+	}
+	if result.Bot {
+		response.Category = string(result.BotKind.Category())
+		response.Confidence = 1
+	}
+	return response
+}
+
+// Client calls a DetectionService
+type Client struct {
+	client gogobotv1.DetectionServiceClient
+}
+
+// NewClient creates a client calling the service over conn
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: gogobotv1.NewDetectionServiceClient(conn)}
+}
+
+// Detect classifies req with the service. The client IP is the host of req.RemoteAddr, so the
+// service's ClientIPResolver applies to forwarded requests as it would in-process, and a TLS
+// fingerprint attached with gogobot.WithTLSFingerprint is sent along.
+func (c *Client) Detect(ctx context.Context, req *http.Request, opts ...grpc.CallOption) (gogobot.BotDetectionResult, error) {
+	response, err := c.client.Detect(ctx, NewDetectRequest(req), opts...)
+	if err != nil {
+		return gogobot.BotDetectionResult{}, err
+	}
+	return Result(response), nil
+}
+
+// NewDetectRequest describes req for the service. Go does not keep the order of header names, so
+// headers are sent sorted.
+func NewDetectRequest(req *http.Request) *gogobotv1.DetectRequest {
+	ip := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	in := &gogobotv1.DetectRequest{
+		Ip:     ip,
+		Method: req.Method,
+		Url:    req.URL.RequestURI(),
+		Proto:  req.Proto,
+		Tls:    req.TLS != nil,
+	}
+	if fingerprint, ok := req.Context().Value(gogobot.TLSFingerprintKey).(string); ok {
+		in.TlsFingerprint = fingerprint
+	}
+	if req.Host != "" {
+		in.Headers = append(in.Headers, &gogobotv1.Header{Name: "Host", Values: []string{req.Host}})
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		in.Headers = append(in.Headers, &gogobotv1.Header{Name: name, Values: req.Header[name]})
+	}
+	return in
+}
+
+// Result converts a response of the service to a detection result
+func Result(response *gogobotv1.DetectResponse) gogobot.BotDetectionResult {
+	result := gogobot.BotDetectionResult{
+		Bot:          response.GetBot(),
+		BotKind:      gogobot.BotKind(response.GetBotKind()),
+		Reasons:      response.GetReasons(),
+		Verified:     response.GetVerified(),
+		Country:      response.GetCountry(),
+		ASN:          response.GetAsn(),
+		IsDatacenter: response.GetIsDatacenter(),
+		Organization: response.GetOrganization(),
+	}
+	if !result.Bot {
+		result.Score = response.GetConfidence()
+	}
+	return result
+}
+
+// discardResponse drops what the middleware writes; the verdict is taken from the context
+type discardResponse struct{}
+
+func (discardResponse) Header() http.Header         { return http.Header{} }
+func (discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (discardResponse) WriteHeader(int)             {}
@@ -0,0 +1,98 @@
+package grpcdetect
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lytics/gogobot"
+	"github.com/lytics/gogobot/contrib/grpcdetect/gogobotv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	NewServer(gogobot.NewDetector()).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+func TestClient_Detect(t *testing.T) {
+	client := newTestClient(t)
+
+	req := httptest.NewRequest("GET", "/products?page=2", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	req.Header.Set("Accept", "*/*")
+	result, err := client.Detect(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Bot || result.BotKind != gogobot.BotKindCurl {
+		t.Errorf("Expected curl to be detected, got %+v", result)
+	}
+}
+
+func TestServer_Detect(t *testing.T) {
+	server := NewServer(gogobot.NewDetector())
+	response, err := server.Detect(context.Background(), &gogobotv1.DetectRequest{
+		Ip:  "203.0.113.7",
+		Url: "/",
+		Headers: []*gogobotv1.Header{
+			{Name: "Host", Values: []string{"example.com"}},
+			{Name: "User-Agent", Values: []string{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !response.GetBot() || response.GetCategory() != string(gogobot.BotCategoryAI) || response.GetConfidence() != 1 {
+		t.Errorf("Expected an AI bot, got %v", response)
+	}
+
+	_, err = server.Detect(context.Background(), &gogobotv1.DetectRequest{Ip: "not an ip"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestNewDetectRequest(t *testing.T) {
+	req := httptest.NewRequest("POST", "https://example.com/login?next=%2F", nil)
+	req.RemoteAddr = "[2001:db8::1]:443"
+	req.Header.Set("User-Agent", "test")
+	req.Header.Set("Accept", "*/*")
+	req = req.WithContext(gogobot.WithTLSFingerprint(req.Context(), "t13d1516h2_8daaf6152771_e5627efa2ab1"))
+
+	in := NewDetectRequest(req)
+	if in.GetIp() != "2001:db8::1" || in.GetMethod() != http.MethodPost || in.GetUrl() != "/login?next=%2F" || !in.GetTls() {
+		t.Errorf("Unexpected request %v", in)
+	}
+	if in.GetTlsFingerprint() != "t13d1516h2_8daaf6152771_e5627efa2ab1" {
+		t.Errorf("Expected the TLS fingerprint, got %q", in.GetTlsFingerprint())
+	}
+	var names []string
+	for _, header := range in.GetHeaders() {
+		names = append(names, header.GetName())
+	}
+	if len(names) != 3 || names[0] != "Host" || names[1] != "Accept" || names[2] != "User-Agent" {
+		t.Errorf("Expected Host then sorted headers, got %v", names)
+	}
+}
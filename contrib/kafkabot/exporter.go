@@ -0,0 +1,91 @@
+// Package kafkabot publishes gogobot's detection events to Kafka, for teams feeding bot
+// telemetry into stream processing. It is a separate module to keep the Kafka client out of
+// gogobot's dependencies.
+package kafkabot
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/lytics/gogobot"
+	"github.com/segmentio/kafka-go"
+)
+
+// Writer writes messages to Kafka; *kafka.Writer implements it
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Exporter writes detection events as Kafka messages. Use its Export method as the handler of a
+// detector subscription:
+//
+//	exporter := kafkabot.New(&kafka.Writer{Addr: kafka.TCP("kafka:9092"), Topic: "bot-events", Async: true}, nil)
+//	subscription := detector.Subscribe(exporter.Export)
+//
+// Writes run on the subscription's goroutine, so a synchronous writer makes the subscription
+// drop events while Kafka is slow rather than delaying requests.
+type Exporter struct {
+	writer  Writer
+	encoder gogobot.EventEncoder
+
+	// Timeout bounds each write; zero leaves it to the writer's WriteTimeout
+	Timeout time.Duration
+	// OnError is called with the events that could not be encoded or written; nil only counts
+	// them
+	OnError func(gogobot.Event, error)
+
+	failed atomic.Uint64
+}
+
+// New creates an exporter writing events encoded with encoder, gogobot.JSONEventEncoder when
+// nil, to writer
+func New(writer Writer, encoder gogobot.EventEncoder) *Exporter {
+	if encoder == nil {
+		encoder = gogobot.JSONEventEncoder{}
+	}
+	return &Exporter{writer: writer, encoder: encoder}
+}
+
+// Export writes the event
+func (e *Exporter) Export(event gogobot.Event) {
+	msg, err := Message(event, e.encoder)
+	if err == nil {
+		ctx := context.Background()
+		if e.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+			defer cancel()
+		}
+		err = e.writer.WriteMessages(ctx, msg)
+	}
+	if err != nil {
+		e.failed.Add(1)
+		if e.OnError != nil {
+			e.OnError(event, err)
+		}
+	}
+}
+
+// Failed returns the number of events that could not be encoded or written
+func (e *Exporter) Failed() uint64 {
+	return e.failed.Load()
+}
+
+// Message encodes the event as a message keyed by client IP, so the events of a client land in
+// one partition in order, with the encoding in a Content-Type header
+func Message(event gogobot.Event, encoder gogobot.EventEncoder) (kafka.Message, error) {
+	value, err := encoder.Encode(event)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	msg := kafka.Message{
+		Value:   value,
+		Time:    event.Time,
+		Headers: []kafka.Header{{Key: "Content-Type", Value: []byte(encoder.ContentType())}},
+	}
+	if event.ClientIP.IsValid() {
+		msg.Key = []byte(event.ClientIP.String())
+	}
+	return msg, nil
+}
@@ -0,0 +1,84 @@
+package kafkabot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/lytics/gogobot"
+	"github.com/segmentio/kafka-go"
+)
+
+var _ Writer = (*kafka.Writer)(nil)
+
+// recordingWriter keeps the messages written, or fails with err
+type recordingWriter struct {
+	msgs []kafka.Message
+	err  error
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func testEvent() gogobot.Event {
+	return gogobot.Event{
+		Time:      time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		ClientIP:  netip.MustParseAddr("203.0.113.7"),
+		Method:    "GET",
+		Path:      "/products",
+		UserAgent: "curl/8.4.0",
+		Result:    gogobot.BotDetectionResult{Bot: true, BotKind: gogobot.BotKindCurl},
+		Action:    gogobot.ActionBlock,
+	}
+}
+
+func TestExporter(t *testing.T) {
+	writer := &recordingWriter{}
+	exporter := New(writer, nil)
+	exporter.Export(testEvent())
+
+	if len(writer.msgs) != 1 {
+		t.Fatalf("Expected one message, got %d", len(writer.msgs))
+	}
+	msg := writer.msgs[0]
+	if string(msg.Key) != "203.0.113.7" || !msg.Time.Equal(testEvent().Time) {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+	if len(msg.Headers) != 1 || string(msg.Headers[0].Value) != "application/json" {
+		t.Errorf("Expected a JSON content type, got %v", msg.Headers)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(msg.Value, &record); err != nil || record["botKind"] != "curl" {
+		t.Errorf("Unexpected value %s, %v", msg.Value, err)
+	}
+}
+
+func TestExporter_Avro(t *testing.T) {
+	msg, err := Message(testEvent(), gogobot.AvroEventEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := gogobot.AvroEventEncoder{}.Encode(testEvent())
+	if string(msg.Value) != string(expected) || string(msg.Headers[0].Value) != "avro/binary" {
+		t.Errorf("Expected the Avro record, got %q %v", msg.Value, msg.Headers)
+	}
+}
+
+func TestExporter_Error(t *testing.T) {
+	exporter := New(&recordingWriter{err: errors.New("broker unavailable")}, nil)
+	var failed []gogobot.Event
+	exporter.OnError = func(event gogobot.Event, err error) { failed = append(failed, event) }
+	exporter.Export(testEvent())
+	exporter.Export(testEvent())
+	if exporter.Failed() != 2 || len(failed) != 2 {
+		t.Errorf("Expected two failures, got %d and %d", exporter.Failed(), len(failed))
+	}
+}
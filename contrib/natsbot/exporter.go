@@ -0,0 +1,94 @@
+// Package natsbot publishes gogobot's detection events to NATS subjects, for teams feeding bot
+// telemetry into stream processing. It is a separate module to keep the NATS client out of
+// gogobot's dependencies.
+package natsbot
+
+import (
+	"sync/atomic"
+
+	"github.com/lytics/gogobot"
+	"github.com/nats-io/nats.go"
+)
+
+// Publisher publishes messages; *nats.Conn implements it
+type Publisher interface {
+	PublishMsg(msg *nats.Msg) error
+}
+
+// Exporter publishes detection events as NATS messages. Use its Export method as the handler of
+// a detector subscription:
+//
+//	exporter := natsbot.New(conn, "bots.events", nil)
+//	exporter.Subject = natsbot.CategorySubject("bots.events")
+//	subscription := detector.Subscribe(exporter.Export)
+type Exporter struct {
+	conn    Publisher
+	subject string
+	encoder gogobot.EventEncoder
+
+	// Subject returns the subject of an event; nil publishes every event on the subject given
+	// to New
+	Subject func(gogobot.Event) string
+	// OnError is called with the events that could not be encoded or published; nil only counts
+	// them
+	OnError func(gogobot.Event, error)
+
+	failed atomic.Uint64
+}
+
+// New creates an exporter publishing events encoded with encoder, gogobot.JSONEventEncoder
+// when nil, on subject
+func New(conn Publisher, subject string, encoder gogobot.EventEncoder) *Exporter {
+	if encoder == nil {
+		encoder = gogobot.JSONEventEncoder{}
+	}
+	return &Exporter{conn: conn, subject: subject, encoder: encoder}
+}
+
+// Export publishes the event. NATS buffers published messages, so this does not wait for the
+// server.
+func (e *Exporter) Export(event gogobot.Event) {
+	subject := e.subject
+	if e.Subject != nil {
+		subject = e.Subject(event)
+	}
+	msg, err := Message(subject, event, e.encoder)
+	if err == nil {
+		err = e.conn.PublishMsg(msg)
+	}
+	if err != nil {
+		e.failed.Add(1)
+		if e.OnError != nil {
+			e.OnError(event, err)
+		}
+	}
+}
+
+// Failed returns the number of events that could not be encoded or published
+func (e *Exporter) Failed() uint64 {
+	return e.failed.Load()
+}
+
+// Message encodes the event as a message on subject, with the encoding in a Content-Type header
+func Message(subject string, event gogobot.Event, encoder gogobot.EventEncoder) (*nats.Msg, error) {
+	data, err := encoder.Encode(event)
+	if err != nil {
+		return nil, err
+	}
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Header.Set("Content-Type", encoder.ContentType())
+	return msg, nil
+}
+
+// CategorySubject returns a Subject function publishing events on prefix.<category>, with
+// "human" for clients not flagged, so consumers subscribe to the categories they act on, e.g.
+// "bots.events.ai", or to all of them with "bots.events.>"
+func CategorySubject(prefix string) func(gogobot.Event) string {
+	return func(event gogobot.Event) string {
+		if !event.Result.Bot {
+			return prefix + ".human"
+		}
+		return prefix + "." + string(event.Result.BotKind.Category())
+	}
+}
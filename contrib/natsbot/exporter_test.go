@@ -0,0 +1,83 @@
+package natsbot
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/lytics/gogobot"
+	"github.com/nats-io/nats.go"
+)
+
+var _ Publisher = (*nats.Conn)(nil)
+
+// recordingPublisher keeps the messages published, or fails with err
+type recordingPublisher struct {
+	msgs []*nats.Msg
+	err  error
+}
+
+func (p *recordingPublisher) PublishMsg(msg *nats.Msg) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func testEvent(bot bool) gogobot.Event {
+	event := gogobot.Event{
+		Time:     time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		ClientIP: netip.MustParseAddr("203.0.113.7"),
+		Path:     "/products",
+		Action:   gogobot.ActionAllow,
+	}
+	if bot {
+		event.Result = gogobot.BotDetectionResult{Bot: true, BotKind: gogobot.BotKindGPTBot}
+	}
+	return event
+}
+
+func TestExporter(t *testing.T) {
+	publisher := &recordingPublisher{}
+	exporter := New(publisher, "bots.events", gogobot.AvroEventEncoder{})
+	exporter.Export(testEvent(true))
+
+	if len(publisher.msgs) != 1 {
+		t.Fatalf("Expected one message, got %d", len(publisher.msgs))
+	}
+	msg := publisher.msgs[0]
+	expected, _ := gogobot.AvroEventEncoder{}.Encode(testEvent(true))
+	if msg.Subject != "bots.events" || string(msg.Data) != string(expected) {
+		t.Errorf("Unexpected message %s %q", msg.Subject, msg.Data)
+	}
+	if msg.Header.Get("Content-Type") != "avro/binary" {
+		t.Errorf("Expected the Avro content type, got %v", msg.Header)
+	}
+}
+
+func TestExporter_CategorySubject(t *testing.T) {
+	publisher := &recordingPublisher{}
+	exporter := New(publisher, "unused", nil)
+	exporter.Subject = CategorySubject("bots.events")
+	exporter.Export(testEvent(true))
+	exporter.Export(testEvent(false))
+
+	if len(publisher.msgs) != 2 || publisher.msgs[0].Subject != "bots.events.ai" || publisher.msgs[1].Subject != "bots.events.human" {
+		t.Errorf("Unexpected subjects %v", publisher.msgs)
+	}
+	if publisher.msgs[0].Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON by default, got %v", publisher.msgs[0].Header)
+	}
+}
+
+func TestExporter_Error(t *testing.T) {
+	exporter := New(&recordingPublisher{err: nats.ErrConnectionClosed}, "bots.events", nil)
+	var failed error
+	exporter.OnError = func(event gogobot.Event, err error) { failed = err }
+	exporter.Export(testEvent(true))
+	if exporter.Failed() != 1 || !errors.Is(failed, nats.ErrConnectionClosed) {
+		t.Errorf("Expected the failure to be reported, got %d, %v", exporter.Failed(), failed)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/lytics/gogobot/contrib/otelbot
+
+go 1.24.2
+
+require (
+	github.com/lytics/gogobot v0.0.0
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/metric v1.31.0
+	go.opentelemetry.io/otel/sdk v1.31.0
+	go.opentelemetry.io/otel/sdk/metric v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+)
+
+replace github.com/lytics/gogobot => ../..
@@ -0,0 +1,129 @@
+package otelbot
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/lytics/gogobot"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ScopeName is the instrumentation scope of the meter
+const ScopeName = "github.com/lytics/gogobot/contrib/otelbot"
+
+// humanLabel is the kind and category attribute of requests not flagged as bots
+const humanLabel = "human"
+
+// AttrCache names the cache of the cache metrics
+const AttrCache = attribute.Key("bot.cache")
+
+// MetricsObserver is a gogobot.Observer emitting the middleware's metrics through the
+// OpenTelemetry metric API:
+//
+//   - bot.requests{bot.kind,bot.category,bot.action}: requests detection ran on, "human" for
+//     clients not flagged
+//   - bot.detection.errors: requests detection failed on
+//   - bot.detection.duration{bot.cached}: detection latency in seconds
+//   - bot.detector.duration{bot.detector}: run time of the individual detectors in seconds
+//   - bot.cache.hits, bot.cache.misses, bot.cache.entries{bot.cache}: the result, user agent
+//     and verification caches, read at each collection
+type MetricsObserver struct {
+	requests  metric.Int64Counter
+	errors    metric.Int64Counter
+	duration  metric.Float64Histogram
+	detectors metric.Float64Histogram
+}
+
+// NewMetricsObserver creates the instruments on a meter of provider, e.g. the global
+// otel.GetMeterProvider(), and registers the cache callback for the detector. Set the observer
+// as MiddlewareConfig.Observer.
+func NewMetricsObserver(detector *gogobot.BotDetector, provider metric.MeterProvider) (*MetricsObserver, error) {
+	meter := provider.Meter(ScopeName)
+	// Detection takes microseconds to milliseconds, well below the default HTTP buckets
+	buckets := metric.WithExplicitBucketBoundaries(0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25)
+
+	var o MetricsObserver
+	var err error
+	if o.requests, err = meter.Int64Counter("bot.requests",
+		metric.WithDescription("Requests bot detection ran on, by bot kind, category and action."),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	if o.errors, err = meter.Int64Counter("bot.detection.errors",
+		metric.WithDescription("Requests bot detection failed on."),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	if o.duration, err = meter.Float64Histogram("bot.detection.duration",
+		metric.WithDescription("Time spent detecting bots, or looking up cached verdicts."),
+		metric.WithUnit("s"), buckets); err != nil {
+		return nil, err
+	}
+	if o.detectors, err = meter.Float64Histogram("bot.detector.duration",
+		metric.WithDescription("Run time of the individual detectors."),
+		metric.WithUnit("s"), buckets); err != nil {
+		return nil, err
+	}
+
+	hits, err := meter.Int64ObservableCounter("bot.cache.hits",
+		metric.WithDescription("Cache lookups served from the cache."),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64ObservableCounter("bot.cache.misses",
+		metric.WithDescription("Cache lookups not served from the cache."),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := meter.Int64ObservableGauge("bot.cache.entries",
+		metric.WithDescription("Entries held by the cache."),
+		metric.WithUnit("{entry}"))
+	if err != nil {
+		return nil, err
+	}
+
+	admin := gogobot.NewAdmin(detector)
+	_, err = meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		for name, stats := range admin.Status().Caches {
+			cache := metric.WithAttributes(AttrCache.String(name))
+			observer.ObserveInt64(hits, int64(stats.Hits), cache)
+			observer.ObserveInt64(misses, int64(stats.Misses), cache)
+			observer.ObserveInt64(entries, int64(stats.Size), cache)
+		}
+		return nil
+	}, hits, misses, entries)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// ObserveRequest implements gogobot.Observer. Measurements carry the request's context, so a
+// sampled span there can be attached as an exemplar.
+func (o *MetricsObserver) ObserveRequest(req *http.Request, observation gogobot.Observation) {
+	ctx := req.Context()
+	if observation.Err != nil {
+		o.errors.Add(ctx, 1)
+	}
+	result := observation.Result
+	if result == nil {
+		return
+	}
+
+	kind, category := humanLabel, humanLabel
+	if result.Bot {
+		kind, category = string(result.BotKind), string(result.BotKind.Category())
+	}
+	o.requests.Add(ctx, 1, metric.WithAttributes(
+		AttrKind.String(kind),
+		AttrCategory.String(category),
+		AttrAction.String(string(observation.Action)),
+	))
+	o.duration.Record(ctx, observation.Duration.Seconds(), metric.WithAttributes(AttrCached.Bool(observation.Cached)))
+	for name, duration := range observation.Detectors {
+		o.detectors.Record(ctx, duration.Seconds(), metric.WithAttributes(attribute.String("bot.detector", name)))
+	}
+}
@@ -0,0 +1,97 @@
+package otelbot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lytics/gogobot"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+var _ gogobot.Observer = (*MetricsObserver)(nil)
+
+func serve(handler http.Handler, userAgent string) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Language", "en-US")
+	req.Header.Set("Accept-Encoding", "gzip")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+// collect returns the metrics read by reader by name
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string]metricdata.Metrics{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+// sumOf returns the value of the data point of an int64 sum with the attributes
+func sumOf(t *testing.T, m metricdata.Metrics, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("%s: expected an int64 sum, got %T", m.Name, m.Data)
+	}
+	set := attribute.NewSet(attrs...)
+	for _, point := range sum.DataPoints {
+		if point.Attributes.Equals(&set) {
+			return point.Value
+		}
+	}
+	return 0
+}
+
+func TestMetricsObserver(t *testing.T) {
+	detector := gogobot.NewDetectorWithConfig(gogobot.DetectorConfig{ResultCache: gogobot.NewResultCache(10, time.Minute)})
+	reader := sdkmetric.NewManualReader()
+	observer, err := NewMetricsObserver(detector, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := gogobot.DefaultMiddlewareConfig()
+	config.BlockBots = true
+	config.Observer = observer
+	handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve(handler, browserUA)
+	serve(handler, "curl/8.4.0")
+	serve(handler, "curl/8.4.0")
+
+	metrics := collect(t, reader)
+	if got := sumOf(t, metrics["bot.requests"], AttrKind.String("human"), AttrCategory.String("human"), AttrAction.String("allow")); got != 1 {
+		t.Errorf("Expected one human request, got %d", got)
+	}
+	if got := sumOf(t, metrics["bot.requests"], AttrKind.String("curl"), AttrCategory.String("automation"), AttrAction.String("block")); got != 2 {
+		t.Errorf("Expected two blocked curl requests, got %d", got)
+	}
+	duration, ok := metrics["bot.detection.duration"].Data.(metricdata.Histogram[float64])
+	if !ok || len(duration.DataPoints) != 2 {
+		t.Errorf("Expected cached and uncached latency histograms, got %+v", metrics["bot.detection.duration"].Data)
+	}
+	if _, ok := metrics["bot.detector.duration"]; !ok {
+		t.Error("Expected detector latency histograms")
+	}
+	if got := sumOf(t, metrics["bot.cache.hits"], AttrCache.String("result")); got != 1 {
+		t.Errorf("Expected one result cache hit, got %d", got)
+	}
+	entries, ok := metrics["bot.cache.entries"].Data.(metricdata.Gauge[int64])
+	if !ok || len(entries.DataPoints) == 0 {
+		t.Errorf("Expected cache entry gauges, got %+v", metrics["bot.cache.entries"].Data)
+	}
+}
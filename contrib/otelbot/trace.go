@@ -0,0 +1,87 @@
+// Package otelbot reports gogobot's verdicts to OpenTelemetry: on spans, so traces can be
+// filtered by bot verdict, and as metrics through the metric API, for deployments exporting
+// over OTLP. It is a separate module to keep OpenTelemetry out of gogobot's dependencies.
+package otelbot
+
+import (
+	"net/http"
+
+	"github.com/lytics/gogobot"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys
+const (
+	AttrDetected   = attribute.Key("bot.detected")
+	AttrKind       = attribute.Key("bot.kind")
+	AttrCategory   = attribute.Key("bot.category")
+	AttrConfidence = attribute.Key("bot.confidence")
+	AttrVerified   = attribute.Key("bot.verified")
+	AttrAction     = attribute.Key("bot.action")
+	AttrCached     = attribute.Key("bot.cached")
+	// AttrDuration is the detection time in seconds, on the detection event
+	AttrDuration = attribute.Key("bot.detection.duration")
+)
+
+// DetectionEvent is the name of the span event carrying the detection and detector times
+const DetectionEvent = "bot.detection"
+
+// TraceObserver is a gogobot.Observer recording the verdict on the span of the request's
+// context, typically started by otelhttp in front of the middleware. Requests without a
+// recording span are left alone.
+type TraceObserver struct{}
+
+// NewTraceObserver creates an observer recording verdicts on spans; set it as
+// MiddlewareConfig.Observer
+func NewTraceObserver() *TraceObserver {
+	return &TraceObserver{}
+}
+
+// ObserveRequest implements gogobot.Observer. The span gets the bot.* attributes, and an event
+// with the detection time and the run time of each detector as bot.detector.<name>.duration, in
+// seconds. Detection errors are recorded as exception events without changing the span status.
+func (o *TraceObserver) ObserveRequest(req *http.Request, observation gogobot.Observation) {
+	span := trace.SpanFromContext(req.Context())
+	if !span.IsRecording() {
+		return
+	}
+	if observation.Err != nil {
+		span.RecordError(observation.Err)
+	}
+	result := observation.Result
+	if result == nil {
+		return
+	}
+
+	span.SetAttributes(Attributes(result)...)
+	span.SetAttributes(
+		AttrAction.String(string(observation.Action)),
+		AttrCached.Bool(observation.Cached),
+	)
+	attrs := make([]attribute.KeyValue, 0, len(observation.Detectors)+1)
+	attrs = append(attrs, AttrDuration.Float64(observation.Duration.Seconds()))
+	for name, duration := range observation.Detectors {
+		attrs = append(attrs, attribute.Float64("bot.detector."+name+".duration", duration.Seconds()))
+	}
+	span.AddEvent(DetectionEvent, trace.WithAttributes(attrs...))
+}
+
+// Attributes describes a verdict as span attributes; bot.kind and bot.category are only set for
+// bots. Confidence is 1 for bots, and the combined score of the soft signals otherwise.
+func Attributes(result *gogobot.BotDetectionResult) []attribute.KeyValue {
+	if !result.Bot {
+		return []attribute.KeyValue{
+			AttrDetected.Bool(false),
+			AttrConfidence.Float64(result.Score),
+			AttrVerified.Bool(result.Verified),
+		}
+	}
+	return []attribute.KeyValue{
+		AttrDetected.Bool(true),
+		AttrKind.String(string(result.BotKind)),
+		AttrCategory.String(string(result.BotKind.Category())),
+		AttrConfidence.Float64(1),
+		AttrVerified.Bool(result.Verified),
+	}
+}
@@ -0,0 +1,106 @@
+package otelbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lytics/gogobot"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const browserUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+var _ gogobot.Observer = (*TraceObserver)(nil)
+
+// serveTraced serves a request within a span and returns the ended span
+func serveTraced(t *testing.T, handler http.Handler, userAgent string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "GET /")
+
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Language", "en-US")
+	req.Header.Set("Accept-Encoding", "gzip")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected one span, got %d", len(spans))
+	}
+	return spans[0]
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTraceObserver(t *testing.T) {
+	config := gogobot.DefaultMiddlewareConfig()
+	config.BlockBots = true
+	config.Observer = NewTraceObserver()
+	handler := gogobot.NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	span := serveTraced(t, handler, "curl/8.4.0")
+	attrs := spanAttributes(span)
+	if !attrs[AttrDetected].AsBool() || attrs[AttrKind].AsString() != "curl" || attrs[AttrCategory].AsString() != "automation" {
+		t.Errorf("Unexpected verdict attributes %v", attrs)
+	}
+	if attrs[AttrConfidence].AsFloat64() != 1 || attrs[AttrAction].AsString() != "block" || attrs[AttrCached].AsBool() {
+		t.Errorf("Unexpected attributes %v", attrs)
+	}
+	events := span.Events()
+	if len(events) != 1 || events[0].Name != DetectionEvent {
+		t.Fatalf("Expected a detection event, got %v", events)
+	}
+	var detectorTimings int
+	for _, kv := range events[0].Attributes {
+		if kv.Key != AttrDuration {
+			detectorTimings++
+		}
+	}
+	if detectorTimings == 0 {
+		t.Error("Expected detector timings on the detection event")
+	}
+
+	attrs = spanAttributes(serveTraced(t, handler, browserUA))
+	if attrs[AttrDetected].AsBool() || attrs[AttrAction].AsString() != "allow" {
+		t.Errorf("Unexpected human attributes %v", attrs)
+	}
+	if _, ok := attrs[AttrKind]; ok {
+		t.Error("Expected no bot kind for a human")
+	}
+}
+
+func TestTraceObserver_Error(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "GET /")
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	NewTraceObserver().ObserveRequest(req, gogobot.Observation{Err: errors.New("store unreachable")})
+	span.End()
+
+	ended := recorder.Ended()[0]
+	if len(ended.Events()) != 1 || ended.Events()[0].Name != "exception" {
+		t.Errorf("Expected the error to be recorded, got %v", ended.Events())
+	}
+	if len(ended.Attributes()) != 0 {
+		t.Errorf("Expected no verdict attributes, got %v", ended.Attributes())
+	}
+
+	// Requests without a span are left alone
+	NewTraceObserver().ObserveRequest(httptest.NewRequest("GET", "/", nil), gogobot.Observation{Result: &gogobot.BotDetectionResult{}})
+}
@@ -0,0 +1,181 @@
+// Package prometheusbot exports gogobot's verdicts and detection latency as Prometheus metrics.
+// A Collector observes the middleware's requests and reads the detector's cache statistics and
+// rules version when scraped, so services register one collector instead of hand-rolling the
+// same counters. It is a separate module to keep the Prometheus client out of gogobot's
+// dependencies.
+package prometheusbot
+
+import (
+	"net/http"
+
+	"github.com/lytics/gogobot"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// humanLabel is the kind and category label of requests not flagged as bots
+const humanLabel = "human"
+
+// Options tunes a Collector
+type Options struct {
+	// Namespace prefixes the metric names; "gogobot" when empty
+	Namespace string
+	// RulesVersion is reported by the rules info metric, as Admin.RulesVersion
+	RulesVersion string
+	// Buckets are the detection latency histogram buckets in seconds; from 50µs to about 0.4s
+	// when nil
+	Buckets []float64
+}
+
+// Collector is a prometheus.Collector and a gogobot.Observer exposing:
+//
+//   - <namespace>_requests_total{kind,category,action}: requests detection ran on, "human"
+//     for clients not flagged
+//   - <namespace>_detection_errors_total: requests detection failed on
+//   - <namespace>_detection_duration_seconds{cached}: detection latency
+//   - <namespace>_detector_duration_seconds{detector}: run time of the individual detectors
+//   - <namespace>_cache_hits_total{cache}, <namespace>_cache_misses_total{cache},
+//     <namespace>_cache_entries{cache}: the result, user agent and verification caches
+//   - <namespace>_rules_info{rules_version,eol_version}: always 1
+type Collector struct {
+	admin *gogobot.Admin
+
+	requests  *prometheus.CounterVec
+	errors    prometheus.Counter
+	duration  *prometheus.HistogramVec
+	detectors *prometheus.HistogramVec
+
+	cacheHits    *prometheus.Desc
+	cacheMisses  *prometheus.Desc
+	cacheEntries *prometheus.Desc
+	rulesInfo    *prometheus.Desc
+}
+
+// New creates a collector for the detector's middleware. Register it with a prometheus.Registerer
+// and set it as the Observer of the middleware configuration, e.g. with Instrument.
+func New(detector *gogobot.BotDetector, opts Options) *Collector {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "gogobot"
+	}
+	buckets := opts.Buckets
+	if buckets == nil {
+		buckets = prometheus.ExponentialBuckets(0.00005, 2, 14)
+	}
+	admin := gogobot.NewAdmin(detector)
+	admin.RulesVersion = opts.RulesVersion
+
+	return &Collector{
+		admin: admin,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Requests bot detection ran on, by bot kind, category and action.",
+		}, []string{"kind", "category", "action"}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "detection_errors_total",
+			Help:      "Requests bot detection failed on.",
+		}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "detection_duration_seconds",
+			Help:      "Time spent detecting bots, or looking up cached verdicts.",
+			Buckets:   buckets,
+		}, []string{"cached"}),
+		detectors: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "detector_duration_seconds",
+			Help:      "Run time of the individual detectors.",
+			Buckets:   buckets,
+		}, []string{"detector"}),
+		cacheHits: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "cache_hits_total"),
+			"Cache lookups served from the cache.", []string{"cache"}, nil),
+		cacheMisses: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "cache_misses_total"),
+			"Cache lookups not served from the cache.", []string{"cache"}, nil),
+		cacheEntries: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "cache_entries"),
+			"Entries held by the cache.", []string{"cache"}, nil),
+		rulesInfo: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "rules_info"),
+			"Versions of the detection rules and browser end-of-life database.", []string{"rules_version", "eol_version"}, nil),
+	}
+}
+
+// ObserveRequest implements gogobot.Observer
+func (c *Collector) ObserveRequest(_ *http.Request, observation gogobot.Observation) {
+	if observation.Err != nil {
+		c.errors.Inc()
+	}
+	result := observation.Result
+	if result == nil {
+		return
+	}
+
+	kind, category := humanLabel, humanLabel
+	if result.Bot {
+		kind, category = string(result.BotKind), string(result.BotKind.Category())
+	}
+	cached := "false"
+	if observation.Cached {
+		cached = "true"
+	}
+	c.requests.WithLabelValues(kind, category, string(observation.Action)).Inc()
+	c.duration.WithLabelValues(cached).Observe(observation.Duration.Seconds())
+	for name, duration := range observation.Detectors {
+		c.detectors.WithLabelValues(name).Observe(duration.Seconds())
+	}
+}
+
+// Instrument returns config with the collector observing its requests, including those of its
+// Routes, after the Observer already set
+func (c *Collector) Instrument(config gogobot.MiddlewareConfig) gogobot.MiddlewareConfig {
+	config.Observer = c.chain(config.Observer)
+	if config.Routes != nil {
+		routes := make([]gogobot.RoutePolicy, len(config.Routes))
+		for i, route := range config.Routes {
+			if !route.Skip {
+				route.Config = c.Instrument(route.Config)
+			}
+			routes[i] = route
+		}
+		config.Routes = routes
+	}
+	return config
+}
+
+// chain returns an observer notifying observer, then the collector
+func (c *Collector) chain(observer gogobot.Observer) gogobot.Observer {
+	if observer == nil || observer == gogobot.Observer(c) {
+		return c
+	}
+	return gogobot.ObserverFunc(func(req *http.Request, observation gogobot.Observation) {
+		observer.ObserveRequest(req, observation)
+		c.ObserveRequest(req, observation)
+	})
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+	c.detectors.Describe(ch)
+	ch <- c.cacheHits
+	ch <- c.cacheMisses
+	ch <- c.cacheEntries
+	ch <- c.rulesInfo
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+	c.detectors.Collect(ch)
+
+	status := c.admin.Status()
+	for name, stats := range status.Caches {
+		ch <- prometheus.MustNewConstMetric(c.cacheHits, prometheus.CounterValue, float64(stats.Hits), name)
+		ch <- prometheus.MustNewConstMetric(c.cacheMisses, prometheus.CounterValue, float64(stats.Misses), name)
+		ch <- prometheus.MustNewConstMetric(c.cacheEntries, prometheus.GaugeValue, float64(stats.Size), name)
+	}
+	ch <- prometheus.MustNewConstMetric(c.rulesInfo, prometheus.GaugeValue, 1, status.RulesVersion, status.EOLVersion)
+}
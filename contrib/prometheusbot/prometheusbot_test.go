@@ -0,0 +1,97 @@
+package prometheusbot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lytics/gogobot"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const browserUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+func serve(handler http.Handler, userAgent string) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Language", "en-US")
+	req.Header.Set("Accept-Encoding", "gzip")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+var (
+	_ prometheus.Collector = (*Collector)(nil)
+	_ gogobot.Observer     = (*Collector)(nil)
+)
+
+func TestCollector(t *testing.T) {
+	detector := gogobot.NewDetectorWithConfig(gogobot.DetectorConfig{ResultCache: gogobot.NewResultCache(10, time.Minute)})
+	collector := New(detector, Options{RulesVersion: "2026-10-01"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	config := gogobot.DefaultMiddlewareConfig()
+	config.BlockBots = true
+	handler := detector.MiddlewareWithConfig(collector.Instrument(config))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve(handler, browserUA)
+	serve(handler, "curl/8.4.0")
+	serve(handler, "curl/8.4.0")
+
+	if got := testutil.ToFloat64(collector.requests.WithLabelValues("human", "human", "allow")); got != 1 {
+		t.Errorf("Expected one human request, got %v", got)
+	}
+	if got := testutil.ToFloat64(collector.requests.WithLabelValues("curl", "automation", "block")); got != 2 {
+		t.Errorf("Expected two blocked curl requests, got %v", got)
+	}
+	if got := testutil.CollectAndCount(collector.duration); got != 2 {
+		t.Errorf("Expected cached and uncached latency series, got %d", got)
+	}
+	if got := testutil.CollectAndCount(collector.detectors); got == 0 {
+		t.Error("Expected detector latency series")
+	}
+
+	expected := `
+# HELP gogobot_cache_hits_total Cache lookups served from the cache.
+# TYPE gogobot_cache_hits_total counter
+gogobot_cache_hits_total{cache="result"} 1
+gogobot_cache_hits_total{cache="verification"} 0
+# HELP gogobot_cache_entries Entries held by the cache.
+# TYPE gogobot_cache_entries gauge
+gogobot_cache_entries{cache="result"} 2
+gogobot_cache_entries{cache="verification"} 0
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "gogobot_cache_hits_total", "gogobot_cache_entries"); err != nil {
+		t.Error(err)
+	}
+	if got := testutil.CollectAndCount(collector, "gogobot_rules_info"); got != 1 {
+		t.Errorf("Expected the rules info metric, got %d", got)
+	}
+}
+
+func TestCollector_Instrument(t *testing.T) {
+	collector := New(gogobot.NewDetector(), Options{Namespace: "edge"})
+	var observed int
+	config := gogobot.DefaultMiddlewareConfig()
+	config.Observer = gogobot.ObserverFunc(func(*http.Request, gogobot.Observation) { observed++ })
+	config.Routes = []gogobot.RoutePolicy{
+		{Match: gogobot.MatchGlob("/static/**"), Skip: true},
+		{Match: gogobot.MatchGlob("/api/**"), Config: gogobot.DefaultMiddlewareConfig()},
+	}
+	instrumented := collector.Instrument(config)
+
+	if config.Routes[1].Config.Observer != nil {
+		t.Error("Expected the original routes to be left unchanged")
+	}
+	if instrumented.Routes[1].Config.Observer != collector {
+		t.Error("Expected the route to be observed by the collector")
+	}
+	instrumented.Observer.ObserveRequest(nil, gogobot.Observation{Err: gogobot.NewBotdError(gogobot.StateUnexpectedBehaviour, "down")})
+	if observed != 1 || testutil.ToFloat64(collector.errors) != 1 {
+		t.Errorf("Expected both observers to be notified, got %d and %v", observed, testutil.ToFloat64(collector.errors))
+	}
+}
@@ -0,0 +1,96 @@
+// Package redisstore implements gogobot.Store on Redis, so session and rate state is shared
+// between replicas behind a load balancer. It is a separate module to keep go-redis out of
+// gogobot's dependencies.
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lytics/gogobot"
+	"github.com/redis/go-redis/v9"
+)
+
+// Store is a gogobot.Store backed by a Redis server, cluster or sentinel setup
+type Store struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// New creates a store issuing commands through client, prepending prefix to every key so
+// several applications can share a database
+func New(client redis.UniversalClient, prefix string) *Store {
+	return &Store{client: client, prefix: prefix}
+}
+
+// Incr implements gogobot.Store. INCRBY and PTTL are pipelined in one round trip; the expiry
+// is only set by a second command when the counter has none, i.e. when it was just created.
+func (s *Store) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	key = s.prefix + key
+	pipe := s.client.Pipeline()
+	incr := pipe.IncrBy(ctx, key, delta)
+	pttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, convertError(err)
+	}
+	if err := s.expireNew(ctx, key, pttl.Val(), ttl); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// Get implements gogobot.Store
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, convertError(err)
+	}
+	return value, true, nil
+}
+
+// Set implements gogobot.Store
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return convertError(s.client.Set(ctx, s.prefix+key, value, max(ttl, 0)).Err())
+}
+
+// AddToSet implements gogobot.Store. SADD, SCARD and PTTL are pipelined in one round trip.
+func (s *Store) AddToSet(ctx context.Context, key, member string, ttl time.Duration) (int64, error) {
+	key = s.prefix + key
+	pipe := s.client.Pipeline()
+	pipe.SAdd(ctx, key, member)
+	card := pipe.SCard(ctx, key)
+	pttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, convertError(err)
+	}
+	if err := s.expireNew(ctx, key, pttl.Val(), ttl); err != nil {
+		return 0, err
+	}
+	return card.Val(), nil
+}
+
+// Delete implements gogobot.Store
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return convertError(s.client.Del(ctx, s.prefix+key).Err())
+}
+
+// expireNew sets ttl on a key without an expiry. PTTL reports -1 for keys without one.
+func (s *Store) expireNew(ctx context.Context, key string, current, ttl time.Duration) error {
+	if ttl <= 0 || current != -1 {
+		return nil
+	}
+	return convertError(s.client.PExpire(ctx, key, ttl).Err())
+}
+
+// convertError maps Redis WRONGTYPE replies to gogobot.ErrWrongType
+func convertError(err error) error {
+	if err != nil && strings.HasPrefix(err.Error(), "WRONGTYPE") {
+		return gogobot.ErrWrongType
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lytics/gogobot"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestStore connects to the Redis server at REDIS_ADDR, skipping the test if unset
+func newTestStore(t *testing.T) *Store {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+	return New(client, "gogobot-test:"+strconv.FormatInt(time.Now().UnixNano(), 36)+":")
+}
+
+var _ gogobot.Store = (*Store)(nil)
+
+func TestStore_Incr(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := store.Incr(ctx, "counter", 1, time.Minute)
+		if err != nil || n != i {
+			t.Fatalf("Expected %d, got %d, %v", i, n, err)
+		}
+	}
+	ttl, err := store.client.PTTL(ctx, store.prefix+"counter").Result()
+	if err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected counter to expire within a minute, got %v, %v", ttl, err)
+	}
+	store.Delete(ctx, "counter")
+}
+
+func TestStore_GetSet(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if _, ok, err := store.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Expected missing key, got %t, %v", ok, err)
+	}
+	if err := store.Set(ctx, "key", []byte("hello"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok, err := store.Get(ctx, "key"); !ok || err != nil || string(value) != "hello" {
+		t.Errorf("Expected stored value, got %q, %t, %v", value, ok, err)
+	}
+	store.Delete(ctx, "key")
+	if _, ok, _ := store.Get(ctx, "key"); ok {
+		t.Error("Expected deleted key to be gone")
+	}
+}
+
+func TestStore_AddToSet(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	defer store.Delete(ctx, "set")
+
+	for i, member := range []string{"a", "b", "a", "c"} {
+		n, err := store.AddToSet(ctx, "set", member, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []int64{1, 2, 2, 3}[i]; n != expected {
+			t.Errorf("After adding %q expected %d members, got %d", member, expected, n)
+		}
+	}
+}
+
+func TestStore_WrongType(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	defer store.Delete(ctx, "value")
+
+	store.AddToSet(ctx, "value", "a", time.Minute)
+	if _, err := store.Incr(ctx, "value", 1, time.Minute); !errors.Is(err, gogobot.ErrWrongType) {
+		t.Errorf("Expected ErrWrongType, got %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package gogobot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default cookie challenge parameters
+const (
+	DefaultChallengeCookieName   = "gogobot_challenge"
+	DefaultChallengeCookieMaxAge = 24 * time.Hour
+	DefaultChallengeMaxAttempts  = 3
+)
+
+// CookieChallenge redirects visitors without a signed cookie once, setting the cookie on the
+// redirect. Browsers return it on the following request; simple HTTP-library scrapers do not
+// keep cookies, so their requests keep arriving without one and are flagged after
+// MaxAttempts. Cookies are bound to the visitor's session key, so they cannot be shared
+// between the addresses of a scraper fleet.
+type CookieChallenge struct {
+	secret []byte
+	// CookieName is the name of the challenge cookie
+	CookieName string
+	// MaxAge is how long a cookie stays valid
+	MaxAge time.Duration
+	// MaxAttempts is the number of requests without a valid cookie tolerated per MaxAge, to
+	// allow for the first request and parallel requests before the cookie is set
+	MaxAttempts int64
+
+	now func() time.Time
+}
+
+// NewCookieChallenge creates a challenge signing cookies with secret, which should be at least
+// 32 random bytes shared by all replicas
+func NewCookieChallenge(secret []byte) *CookieChallenge {
+	return &CookieChallenge{
+		secret:      secret,
+		CookieName:  DefaultChallengeCookieName,
+		MaxAge:      DefaultChallengeCookieMaxAge,
+		MaxAttempts: DefaultChallengeMaxAttempts,
+		now:         time.Now,
+	}
+}
+
+// CookieChallengeState is a visitor's progress through the cookie challenge
+type CookieChallengeState struct {
+	// Passed reports whether the request carried a valid cookie
+	Passed bool `json:"passed"`
+	// Attempts is the number of requests without a valid cookie within MaxAge
+	Attempts int64 `json:"attempts"`
+	// MaxAttempts is the number of such requests tolerated
+	MaxAttempts int64 `json:"maxAttempts"`
+}
+
+func (c *CookieChallenge) maxAge() time.Duration {
+	if c.MaxAge <= 0 {
+		return DefaultChallengeCookieMaxAge
+	}
+	return c.MaxAge
+}
+
+func (c *CookieChallenge) cookieName() string {
+	if c.CookieName == "" {
+		return DefaultChallengeCookieName
+	}
+	return c.CookieName
+}
+
+// signToken returns the MAC of the expiry for the purpose and visitor key
+func signToken(secret []byte, purpose, key, expires string) string {
+	mac := hmac.New(sha256.New, secret)
+	for _, part := range []string{purpose, key, expires} {
+		mac.Write([]byte(part))
+		mac.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// issueToken returns a token for the purpose and visitor key valid until expires, formatted
+// as "<expiry>.<mac>"
+func issueToken(secret []byte, purpose, key string, expires time.Time) string {
+	unix := strconv.FormatInt(expires.Unix(), 10)
+	return unix + "." + signToken(secret, purpose, key, unix)
+}
+
+// verifyToken reports whether token was issued for the purpose and visitor key and is unexpired
+func verifyToken(secret []byte, purpose, key, token string, now time.Time) bool {
+	expires, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !now.Before(time.Unix(unix, 0)) {
+		return false
+	}
+	return hmac.Equal([]byte(mac), []byte(signToken(secret, purpose, key, expires)))
+}
+
+// token returns a cookie value for the visitor key
+func (c *CookieChallenge) token(key string) string {
+	return issueToken(c.secret, "cookie", key, c.now().Add(c.maxAge()))
+}
+
+// verify reports whether the request carries a valid, unexpired cookie for the visitor key
+func (c *CookieChallenge) verify(req *http.Request, key string) bool {
+	cookie, err := req.Cookie(c.cookieName())
+	if err != nil {
+		return false
+	}
+	return verifyToken(c.secret, "cookie", key, cookie.Value, c.now())
+}
+
+// redirect sets the challenge cookie and redirects the client back to the requested URL
+func (c *CookieChallenge) redirect(w http.ResponseWriter, req *http.Request, key string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.cookieName(),
+		Value:    c.token(key),
+		Path:     "/",
+		MaxAge:   int(c.maxAge().Seconds()),
+		Secure:   req.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, req, sameOriginURI(req.URL), http.StatusFound)
+}
+
+// sameOriginURI returns the path and query of u as a redirect target on the requested host.
+// Browsers read a Location of "//host/x" or "/\host/x" as another host's URL, so leading
+// slashes and backslashes are collapsed into one.
+func sameOriginURI(u *url.URL) string {
+	target := "/" + strings.TrimLeft(u.EscapedPath(), `/\`)
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	return target
+}
+
+// getCookieChallenge checks the challenge cookie, counting requests without a valid one.
+// Verified crawlers do not keep cookies and are not challenged.
+func getCookieChallenge(req *http.Request, components *ComponentDict, tracker *SessionTracker, challenge *CookieChallenge) Component[CookieChallengeState] {
+	if tracker == nil || challenge == nil {
+		return ErrorComponent[CookieChallengeState]{
+			State: StateUndefined,
+			Error: "cookie challenge requires a session tracker",
+		}
+	}
+	if isVerifiedCrawler(components) {
+		return ErrorComponent[CookieChallengeState]{
+			State: StateUndefined,
+			Error: "verified crawlers are not challenged",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[CookieChallengeState]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	state := CookieChallengeState{MaxAttempts: challenge.MaxAttempts}
+	if challenge.verify(req, key) {
+		state.Passed = true
+		return SuccessComponent[CookieChallengeState]{State: StateSuccess, Value: state}
+	}
+
+	attempts, err := tracker.store.Incr(req.Context(), tracker.Prefix+"challenge:"+key, 1, challenge.maxAge())
+	if err != nil {
+		return ErrorComponent[CookieChallengeState]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	state.Attempts = attempts
+	return SuccessComponent[CookieChallengeState]{State: StateSuccess, Value: state}
+}
+
+// isVerifiedCrawler reports whether the client was verified as a crawler by DNS, published
+// ranges or the CDN
+func isVerifiedCrawler(components *ComponentDict) bool {
+	if components.CrawlerVerification != nil && components.CrawlerVerification.GetState() == StateSuccess &&
+		components.CrawlerVerification.GetValue().Verified {
+		return true
+	}
+	return isCDNVerifiedBot(components)
+}
+
+// detectCookieChallenge flags visitors that kept arriving without the challenge cookie
+func detectCookieChallenge(components *ComponentDict) *BotDetectionResult {
+	if components.CookieChallenge.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	state := components.CookieChallenge.GetValue()
+	if state.Passed || state.MaxAttempts <= 0 || state.Attempts <= state.MaxAttempts {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     true,
+		BotKind: BotKindNoCookies,
+	}
+}
+
+// challengeCookie redirects GET and HEAD requests from unflagged visitors that have not yet
+// passed the cookie challenge. Other methods are not redirected since the body would be lost.
+func (d *BotDetector) challengeCookie(w http.ResponseWriter, req *http.Request, components *ComponentDict, result *BotDetectionResult) bool {
+	challenge := d.config.CookieChallenge
+	if challenge == nil || components == nil || result.Bot || components.CookieChallenge.GetState() != StateSuccess {
+		return false
+	}
+	if components.CookieChallenge.GetValue().Passed || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return false
+	}
+	challenge.redirect(w, req, d.config.SessionTracker.key(req, components))
+	return true
+}
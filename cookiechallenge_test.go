@@ -0,0 +1,179 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCookieChallenge_Verify(t *testing.T) {
+	challenge := NewCookieChallenge([]byte("secret"))
+	now := time.Unix(1700000000, 0)
+	challenge.now = func() time.Time { return now }
+
+	withCookie := func(value string) *http.Request {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: DefaultChallengeCookieName, Value: value})
+		return req
+	}
+
+	token := challenge.token("ip:203.0.113.7/32")
+	if !challenge.verify(withCookie(token), "ip:203.0.113.7/32") {
+		t.Error("Expected issued token to verify")
+	}
+	if challenge.verify(withCookie(token), "ip:203.0.113.8/32") {
+		t.Error("Expected token to be bound to the visitor key")
+	}
+	if challenge.verify(withCookie("9999999999."+token[len("1700086400."):]), "ip:203.0.113.7/32") {
+		t.Error("Expected tampered expiry to fail")
+	}
+	if challenge.verify(withCookie("garbage"), "ip:203.0.113.7/32") {
+		t.Error("Expected malformed token to fail")
+	}
+	if challenge.verify(httptest.NewRequest("GET", "/", nil), "ip:203.0.113.7/32") {
+		t.Error("Expected missing cookie to fail")
+	}
+
+	other := NewCookieChallenge([]byte("other secret"))
+	other.now = challenge.now
+	if other.verify(withCookie(token), "ip:203.0.113.7/32") {
+		t.Error("Expected token signed with another secret to fail")
+	}
+
+	now = now.Add(DefaultChallengeCookieMaxAge)
+	if challenge.verify(withCookie(token), "ip:203.0.113.7/32") {
+		t.Error("Expected expired token to fail")
+	}
+}
+
+func TestDetectCookieChallenge(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    CookieChallengeState
+		expected bool
+	}{
+		{"passed", CookieChallengeState{Passed: true, MaxAttempts: 3}, false},
+		{"first attempts", CookieChallengeState{Attempts: 3, MaxAttempts: 3}, false},
+		{"never returns cookie", CookieChallengeState{Attempts: 4, MaxAttempts: 3}, true},
+		{"flagging disabled", CookieChallengeState{Attempts: 100}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := &ComponentDict{CookieChallenge: SuccessComponent[CookieChallengeState]{State: StateSuccess, Value: tt.state}}
+			result := detectCookieChallenge(components)
+			if result.Bot != tt.expected {
+				t.Errorf("Expected bot=%t, got %+v", tt.expected, result)
+			}
+			if result.Bot && result.BotKind != BotKindNoCookies {
+				t.Errorf("Expected no_cookies, got %q", result.BotKind)
+			}
+		})
+	}
+}
+
+func TestMiddleware_CookieChallenge(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.CookieChallenge = NewCookieChallenge([]byte("secret"))
+	config.ResultCache = NewResultCache(100, time.Minute)
+	detector := NewDetectorWithConfig(config)
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(remoteAddr string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/page?x=1", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Accept-Language", "en-US")
+		req.Header.Set("Accept-Encoding", "gzip")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A browser is redirected once and then served with the cookie
+	rec := serve("203.0.113.7:1234", nil)
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/page?x=1" {
+		t.Fatalf("Expected redirect to the same URL, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultChallengeCookieName || !cookies[0].HttpOnly {
+		t.Fatalf("Expected challenge cookie, got %+v", cookies)
+	}
+	for range 10 {
+		if rec := serve("203.0.113.7:1234", cookies); rec.Code != http.StatusOK {
+			t.Fatalf("Expected browser with cookie to be served, got %d", rec.Code)
+		}
+	}
+
+	// A client ignoring cookies is redirected until it is flagged
+	for i := range DefaultChallengeMaxAttempts {
+		if rec := serve("198.51.100.9:1234", nil); rec.Code != http.StatusFound {
+			t.Fatalf("Attempt %d: expected redirect, got %d", i+1, rec.Code)
+		}
+	}
+	if rec := serve("198.51.100.9:1234", nil); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected cookie-less client to be blocked, got %d", rec.Code)
+	}
+
+	// Cookies are bound to the visitor
+	if rec := serve("192.0.2.50:1234", cookies); rec.Code != http.StatusFound {
+		t.Errorf("Expected cookie from another visitor to be challenged, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware_CookieChallengeSkipsPost(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.CookieChallenge = NewCookieChallenge([]byte("secret"))
+	detector := NewDetectorWithConfig(config)
+	handler := detector.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest("POST", "/form", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected POST not to be redirected, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware_CookieChallengeSameOriginRedirect(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.CookieChallenge = NewCookieChallenge([]byte("secret"))
+	handler := NewDetectorWithConfig(config).MiddlewareWithConfig(MiddlewareConfig{BlockBots: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		target   string
+		location string
+	}{
+		{"//evil.example/x", "/evil.example/x"},
+		{`/\evil.example/x`, "/%5Cevil.example/x"},
+		{"///evil.example/x?a=1", "/evil.example/x?a=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+			req.Header.Set("Accept", "text/html")
+			req.Header.Set("Accept-Language", "en-US")
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusFound || rec.Header().Get("Location") != tt.location {
+				t.Errorf("Expected redirect to %q, got %d %q", tt.location, rec.Code, rec.Header().Get("Location"))
+			}
+		})
+	}
+}
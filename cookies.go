@@ -0,0 +1,185 @@
+package gogobot
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CookieInfo describes the cookies a request carries
+type CookieInfo struct {
+	// Count is the number of cookies
+	Count int `json:"count"`
+	// NameEntropy is the Shannon entropy in bits per character of the cookie names, which is
+	// high for randomly generated names
+	NameEntropy float64 `json:"nameEntropy,omitempty"`
+	// SessionCookie reports whether the CookiePolicy's session cookie is present
+	SessionCookie bool `json:"sessionCookie"`
+}
+
+// Present reports whether the request carries any cookie
+func (c CookieInfo) Present() bool {
+	return c.Count > 0
+}
+
+// CookiePolicy flags visitors that keep using flows requiring the application's session cookie
+// without ever sending it. Browsers store the cookie set on the first response of a flow and
+// return it, while simple HTTP-library scrapers do not keep cookies. Unlike a CookieChallenge,
+// no redirects are added: the application's own cookie is observed.
+type CookiePolicy struct {
+	// SessionCookie is the name of the cookie the application sets on the flows
+	SessionCookie string
+	// Paths are the paths of the flows requiring the session cookie, each covering the paths
+	// below it: "/cart" covers "/cart/items" but not "/cartoons"
+	Paths []string
+	// MinRequests is the number of requests to the flows without the session cookie in a
+	// window after which a visitor that never sent it is flagged
+	MinRequests int64
+	// Window is the length of the fixed counting window; zero uses DefaultSessionWindow
+	Window time.Duration
+}
+
+// DefaultCookiePolicy flags visitors making 3 requests in 30 minutes to the given flows without
+// ever sending the session cookie
+func DefaultCookiePolicy(sessionCookie string, paths ...string) *CookiePolicy {
+	return &CookiePolicy{
+		SessionCookie: sessionCookie,
+		Paths:         paths,
+		MinRequests:   3,
+		Window:        30 * time.Minute,
+	}
+}
+
+// RequiresCookie reports whether path belongs to a flow requiring the session cookie
+func (p *CookiePolicy) RequiresCookie(path string) bool {
+	for _, flow := range p.Paths {
+		if path == flow || strings.HasPrefix(path, strings.TrimSuffix(flow, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// window returns the counting window
+func (p *CookiePolicy) window() time.Duration {
+	if p.Window <= 0 {
+		return DefaultSessionWindow
+	}
+	return p.Window
+}
+
+// CookieSession is the number of requests a visitor made to cookie-requiring flows in the
+// current window, with and without the session cookie, including the current one
+type CookieSession struct {
+	WithCookie    int64 `json:"withCookie"`
+	WithoutCookie int64 `json:"withoutCookie"`
+	MinRequests   int64 `json:"minRequests"`
+}
+
+// cookieSessionKeys returns the store keys counting the visitor's requests to the flows with
+// and without the session cookie in the current window
+func cookieSessionKeys(tracker *SessionTracker, key string, window time.Duration) (with, without string) {
+	bucket := windowBucket(tracker.now(), window)
+	return tracker.Prefix + "cookied:" + key + ":" + bucket, tracker.Prefix + "cookieless:" + key + ":" + bucket
+}
+
+// nameEntropy returns the Shannon entropy in bits per character of the concatenated names
+func nameEntropy(names []string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, name := range names {
+		for _, r := range name {
+			counts[r]++
+			total++
+		}
+	}
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// getCookies describes the request's cookies, looking for the policy's session cookie
+func getCookies(req *http.Request, policy *CookiePolicy) Component[CookieInfo] {
+	cookies := req.Cookies()
+	names := make([]string, len(cookies))
+	info := CookieInfo{Count: len(cookies)}
+	for i, cookie := range cookies {
+		names[i] = cookie.Name
+		if policy != nil && cookie.Name == policy.SessionCookie && cookie.Value != "" {
+			info.SessionCookie = true
+		}
+	}
+	info.NameEntropy = nameEntropy(names)
+	return SuccessComponent[CookieInfo]{
+		State: StateSuccess,
+		Value: info,
+	}
+}
+
+// getCookieSession counts a request to a cookie-requiring flow and reads the visitor's counts in
+// the current window
+func getCookieSession(req *http.Request, components *ComponentDict, tracker *SessionTracker, policy *CookiePolicy) Component[CookieSession] {
+	if tracker == nil || policy == nil {
+		return ErrorComponent[CookieSession]{
+			State: StateUndefined,
+			Error: "cookie policy requires a session tracker",
+		}
+	}
+	if !policy.RequiresCookie(req.URL.Path) {
+		return ErrorComponent[CookieSession]{
+			State: StateNull,
+			Error: "not a cookie-requiring flow",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[CookieSession]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	var withDelta, withoutDelta int64 = 0, 1
+	if components.Cookies.GetValue().SessionCookie {
+		withDelta, withoutDelta = 1, 0
+	}
+	window := policy.window()
+	withKey, withoutKey := cookieSessionKeys(tracker, key, window)
+	session := CookieSession{MinRequests: policy.MinRequests}
+	var err error
+	if session.WithCookie, err = tracker.store.Incr(req.Context(), withKey, withDelta, 2*window); err == nil {
+		session.WithoutCookie, err = tracker.store.Incr(req.Context(), withoutKey, withoutDelta, 2*window)
+	}
+	if err != nil {
+		return ErrorComponent[CookieSession]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	return SuccessComponent[CookieSession]{
+		State: StateSuccess,
+		Value: session,
+	}
+}
+
+// detectCookielessSession flags visitors that made MinRequests requests to cookie-requiring
+// flows in a window without ever sending the session cookie
+func detectCookielessSession(components *ComponentDict) *BotDetectionResult {
+	if components.CookieSession.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	session := components.CookieSession.GetValue()
+	if session.MinRequests <= 0 || session.WithCookie > 0 || session.WithoutCookie < session.MinRequests {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     true,
+		BotKind: BotKindNoCookies,
+		Reasons: []string{"never sent the session cookie"},
+	}
+}
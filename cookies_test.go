@@ -0,0 +1,83 @@
+package gogobot
+
+import (
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestGetCookies(t *testing.T) {
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	if info := getCookies(req, nil).GetValue(); info.Present() || info.NameEntropy != 0 {
+		t.Errorf("Expected no cookies, got %+v", info)
+	}
+
+	req.AddCookie(&http.Cookie{Name: "sid", Value: "abc"})
+	req.AddCookie(&http.Cookie{Name: "ab", Value: "1"})
+	info := getCookies(req, DefaultCookiePolicy("sid", "/cart")).GetValue()
+	if info.Count != 2 || !info.SessionCookie {
+		t.Errorf("Expected two cookies including the session cookie, got %+v", info)
+	}
+	// "sidab" has five distinct characters
+	if math.Abs(info.NameEntropy-math.Log2(5)) > 1e-9 {
+		t.Errorf("Expected entropy %f, got %f", math.Log2(5), info.NameEntropy)
+	}
+	if info := getCookies(req, DefaultCookiePolicy("session", "/cart")).GetValue(); info.SessionCookie {
+		t.Errorf("Expected other session cookie to be missing, got %+v", info)
+	}
+}
+
+func TestCookiePolicy_RequiresCookie(t *testing.T) {
+	policy := DefaultCookiePolicy("sid", "/cart", "/checkout")
+	for path, expected := range map[string]bool{
+		"/cart":             true,
+		"/cart/items":       true,
+		"/checkout/pay":     true,
+		"/products/1":       false,
+		"/cartoons":         false,
+		"/account/checkout": false,
+	} {
+		if got := policy.RequiresCookie(path); got != expected {
+			t.Errorf("Expected %t for %s, got %t", expected, path, got)
+		}
+	}
+}
+
+func TestDetector_CookielessSession(t *testing.T) {
+	newDetector := func() *BotDetector {
+		config := DefaultDetectorConfig()
+		config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+		config.CookiePolicy = DefaultCookiePolicy("sid", "/cart")
+		return NewDetectorWithConfig(config)
+	}
+
+	// A scraper never keeping the cookie set on its first request
+	detector := newDetector()
+	for i := 1; i <= 3; i++ {
+		result, err := detector.DetectFromRequest(newBrowserRequest("/cart", "203.0.113.7:1234"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if flagged := result.Bot && result.BotKind == BotKindNoCookies; flagged != (i == 3) {
+			t.Errorf("Request %d: expected flagged %t, got %+v", i, i == 3, result)
+		}
+	}
+	// Other paths are not counted
+	if result, _ := detector.DetectFromRequest(newBrowserRequest("/products", "203.0.113.8:1234")); result.Bot {
+		t.Errorf("Expected other paths not to be flagged, got %+v", result)
+	}
+
+	// A browser returning the cookie after the first response
+	detector = newDetector()
+	detector.DetectFromRequest(newBrowserRequest("/cart", "203.0.113.7:1234"))
+	for i := 0; i < 3; i++ {
+		req := newBrowserRequest("/cart", "203.0.113.7:1234")
+		req.AddCookie(&http.Cookie{Name: "sid", Value: "abc"})
+		if result, _ := detector.DetectFromRequest(req); result.Bot {
+			t.Errorf("Expected browser with session cookie not to be flagged, got %+v", result)
+		}
+	}
+	if result, _ := detector.DetectFromRequest(newBrowserRequest("/cart", "203.0.113.7:1234")); result.Bot {
+		t.Errorf("Expected visitor that sent the cookie not to be flagged, got %+v", result)
+	}
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClassifyUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		kind      string
+		bot       bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "", false},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", KindCrawler, true},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)", KindGPTBot, true},
+		{"curl/8.4.0", KindCurl, true},
+		{"python-requests/2.31.0", KindUnknown, true},
+		{"", KindUnknown, true},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Slack/4.36.140 Chrome/120.0.6099.56 Electron/28.1.0 Safari/537.36", "", false},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.56 Electron/28.1.0 Safari/537.36", KindElectron, true},
+	}
+	for _, tt := range tests {
+		kind, bot := ClassifyUserAgent(tt.userAgent)
+		if kind != tt.kind || bot != tt.bot {
+			t.Errorf("Expected %q and %t for %q, got %q and %t", tt.kind, tt.bot, tt.userAgent, kind, bot)
+		}
+	}
+}
+
+func TestCombineScores(t *testing.T) {
+	tests := []struct {
+		scores   []float64
+		expected float64
+	}{
+		{nil, 0},
+		{[]float64{0.5}, 0.5},
+		{[]float64{0.5, 0.5}, 0.75},
+		{[]float64{0.2, 0, -1, 2}, 1},
+	}
+	for _, tt := range tests {
+		if got := CombineScores(tt.scores...); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("Expected %v for %v, got %v", tt.expected, tt.scores, got)
+		}
+	}
+}
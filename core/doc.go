@@ -0,0 +1,6 @@
+// Package core holds gogobot's request-independent matching and aggregation logic: user agent
+// classification and the combination of weighted signals. It imports neither net/http nor any
+// other heavyweight package, so it compiles with TinyGo and for WebAssembly edge runtimes such
+// as Cloudflare Workers and Fastly Compute, where the full detector cannot run. The gogobot
+// package builds on it, so both classify user agents alike.
+package core
@@ -0,0 +1,13 @@
+package core
+
+// CombineScores combines the weights (0-1) of soft signals as independent probabilities: two
+// 0.5 signals combine to 0.75. Weights above 1 count as 1.
+func CombineScores(scores ...float64) float64 {
+	unscored := 1.0
+	for _, score := range scores {
+		if score > 0 {
+			unscored *= 1 - min(score, 1)
+		}
+	}
+	return 1 - unscored
+}
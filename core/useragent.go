@@ -0,0 +1,152 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kinds reported by ClassifyUserAgent; they are the values of the gogobot.BotKind constants of
+// the same names
+const (
+	KindPhantomJS      = "phantomjs"
+	KindSlimerJS       = "slimerjs"
+	KindSelenium       = "selenium"
+	KindElectron       = "electron"
+	KindHeadlessChrome = "headless_chrome"
+	KindPlaywright     = "playwright"
+	KindPuppeteer      = "puppeteer"
+	KindCurl           = "curl"
+	KindWget           = "wget"
+	KindBot            = "bot"
+	KindCrawler        = "crawler"
+	KindGPTBot         = "gptbot"
+	KindChatGPT        = "chatgpt"
+	KindOpenAI         = "openai"
+	KindClaude         = "claude"
+	KindAIAgent        = "ai_agent"
+	KindUnknown        = "unknown"
+)
+
+// suspiciousUserAgentPatterns flag generic HTTP libraries and truncated user agents
+var suspiciousUserAgentPatterns = compilePatterns(
+	"^$",
+	"^\\s*$",
+	"mozilla/5.0$",
+	"mozilla/4.0$",
+	"python",
+	"java",
+	"go-http-client",
+	"libwww-perl",
+	"httpclient",
+	"okhttp",
+	"requests",
+	"urllib",
+)
+
+// compilePatterns compiles a list of regular expressions, panicking on invalid input
+func compilePatterns(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		compiled[i] = regexp.MustCompile(pattern)
+	}
+	return compiled
+}
+
+// specificBots lists bot user agent tokens in order of specificity (most specific first)
+var specificBots = []struct {
+	kind     string
+	patterns []string
+}{
+	// AI Agents (check first as they're highly specific)
+	{KindGPTBot, []string{"gptbot", "gpt-bot"}},
+	{KindChatGPT, []string{"chatgpt-user", "chatgpt", "openai-chatgpt"}},
+	{KindOpenAI, []string{"openai", "openai-bot", "openai-crawler"}},
+	{KindClaude, []string{"claude-web", "claude", "anthropic"}},
+	{KindAIAgent, []string{"ai-agent", "aiagent", "ai_agent", "artificial intelligence", "language model", "llm", "gpt-", "claude-", "bard", "gemini-pro"}},
+
+	// Automation Tools
+	{KindPhantomJS, []string{"phantomjs"}},
+	{KindSlimerJS, []string{"slimerjs"}},
+	{KindSelenium, []string{"selenium", "webdriver"}},
+	{KindElectron, []string{"electron"}},
+	{KindHeadlessChrome, []string{"headlesschrome", "headless"}},
+	{KindPlaywright, []string{"playwright"}},
+	{KindPuppeteer, []string{"puppeteer"}},
+
+	// Command Line Tools
+	{KindCurl, []string{"curl/"}},
+	{KindWget, []string{"wget/"}},
+
+	// Search Engine Crawlers
+	{KindCrawler, []string{"googlebot", "bingbot", "slurp", "duckduckbot", "baiduspider"}}, // Check crawlers before generic "bot"
+
+	// Generic Bots (last to avoid false positives)
+	{KindBot, []string{"bot", "crawler", "spider", "scraper"}},
+}
+
+// ClassifyUserAgent returns the bot kind of a user agent, and false for user agents of browsers
+// and other clients not known to be automated
+func ClassifyUserAgent(userAgent string) (string, bool) {
+	userAgent = strings.ToLower(userAgent)
+
+	for _, botType := range specificBots {
+		// Electron runtimes hosting a named application are embedded clients, not automation
+		if botType.kind == KindElectron {
+			if _, embedded := EmbeddedApp(userAgent); embedded {
+				continue
+			}
+		}
+
+		for _, pattern := range botType.patterns {
+			if strings.Contains(userAgent, pattern) {
+				return botType.kind, true
+			}
+		}
+	}
+
+	// Check for suspicious user agent patterns
+	for _, pattern := range suspiciousUserAgentPatterns {
+		if pattern.MatchString(userAgent) {
+			return KindUnknown, true
+		}
+	}
+
+	return "", false
+}
+
+// electronPattern matches the Electron runtime token
+var electronPattern = regexp.MustCompile(`(?i)\belectron/[0-9]`)
+
+// parenthesizedPattern matches comment sections of a user agent, e.g. "(Windows NT 10.0; Win64; x64)"
+var parenthesizedPattern = regexp.MustCompile(`\([^)]*\)`)
+
+// standardProductTokens are product tokens shared by all Chromium user agents
+var standardProductTokens = map[string]bool{
+	"mozilla":     true,
+	"applewebkit": true,
+	"chrome":      true,
+	"safari":      true,
+	"electron":    true,
+	"gecko":       true,
+	"version":     true,
+	"mobile":      true,
+}
+
+// EmbeddedApp extracts the host application name from Electron-embedded user agents.
+// Electron inserts "AppName/version" ahead of the Chrome token, e.g.
+// "... (KHTML, like Gecko) Slack/4.36.140 Chrome/120.0.6099.56 Electron/28.1.0 Safari/537.36".
+func EmbeddedApp(ua string) (string, bool) {
+	if !electronPattern.MatchString(ua) {
+		return "", false
+	}
+
+	for _, token := range strings.Fields(parenthesizedPattern.ReplaceAllString(ua, " ")) {
+		name, _, found := strings.Cut(token, "/")
+		if !found || name == "" || standardProductTokens[strings.ToLower(name)] {
+			continue
+		}
+		return name, true
+	}
+
+	return "", false
+}
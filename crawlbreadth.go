@@ -0,0 +1,98 @@
+package gogobot
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CrawlBreadthLimit is the number of distinct URLs a visitor may request in a window before it
+// looks like a scraper walking the site
+type CrawlBreadthLimit struct {
+	// Limit is the number of distinct URLs per Window above which the visitor is suspicious
+	Limit int64
+	// Window is the length of the fixed counting window; zero uses DefaultSessionWindow
+	Window time.Duration
+	// IncludeQuery counts URLs differing only in their query string as distinct
+	IncludeQuery bool
+}
+
+// DefaultCrawlBreadthLimit flags visitors requesting more than 500 distinct paths a minute
+func DefaultCrawlBreadthLimit() *CrawlBreadthLimit {
+	return &CrawlBreadthLimit{
+		Limit:  500,
+		Window: time.Minute,
+	}
+}
+
+// CrawlBreadth is the number of distinct URLs a visitor requested in the current window
+type CrawlBreadth struct {
+	DistinctURLs int64         `json:"distinctUrls"`
+	Limit        int64         `json:"limit"`
+	Window       time.Duration `json:"window"`
+}
+
+// getCrawlBreadth adds the request's URL to the visitor's set for the current window. URLs are
+// stored as hashes to bound the memory each member takes.
+func getCrawlBreadth(req *http.Request, components *ComponentDict, tracker *SessionTracker, limit *CrawlBreadthLimit) Component[CrawlBreadth] {
+	if tracker == nil || limit == nil {
+		return ErrorComponent[CrawlBreadth]{
+			State: StateUndefined,
+			Error: "crawl breadth requires a session tracker and limit",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[CrawlBreadth]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	window := limit.Window
+	if window <= 0 {
+		window = DefaultSessionWindow
+	}
+	url := req.URL.Path
+	if limit.IncludeQuery && req.URL.RawQuery != "" {
+		url += "?" + req.URL.RawQuery
+	}
+	h := fnv.New64a()
+	h.Write([]byte(url))
+
+	storeKey := tracker.Prefix + "urls:" + key + ":" + windowBucket(tracker.now(), window)
+	count, err := tracker.store.AddToSet(req.Context(), storeKey, strconv.FormatUint(h.Sum64(), 36), 2*window)
+	if err != nil {
+		return ErrorComponent[CrawlBreadth]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	return SuccessComponent[CrawlBreadth]{
+		State: StateSuccess,
+		Value: CrawlBreadth{
+			DistinctURLs: count,
+			Limit:        limit.Limit,
+			Window:       window,
+		},
+	}
+}
+
+// detectCrawlBreadth scores visitors over the distinct URL limit like detectRequestRate: half
+// the ratio of distinct URLs to the limit
+func detectCrawlBreadth(components *ComponentDict) *BotDetectionResult {
+	if components.CrawlBreadth.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	breadth := components.CrawlBreadth.GetValue()
+	if breadth.Limit <= 0 || breadth.DistinctURLs <= breadth.Limit {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindScraper,
+		Score:   min(0.5*float64(breadth.DistinctURLs)/float64(breadth.Limit), 1),
+	}
+}
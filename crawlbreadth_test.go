@@ -0,0 +1,95 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetCrawlBreadth(t *testing.T) {
+	tracker := NewSessionTracker(NewMemoryStore(0))
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	collect := func(target string, limit *CrawlBreadthLimit) CrawlBreadth {
+		req := httptest.NewRequest("GET", target, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		breadth := getCrawlBreadth(req, collectAllSources(req), tracker, limit)
+		if breadth.GetState() != StateSuccess {
+			t.Fatalf("Expected crawl breadth, got %v", breadth)
+		}
+		return breadth.GetValue()
+	}
+
+	limit := DefaultCrawlBreadthLimit()
+	collect("/a", limit)
+	collect("/a?page=2", limit)
+	if breadth := collect("/b", limit); breadth.DistinctURLs != 2 || breadth.Limit != 500 || breadth.Window != time.Minute {
+		t.Errorf("Expected query strings to be ignored, got %+v", breadth)
+	}
+
+	// Counting restarts in a new window
+	now = now.Add(time.Minute)
+	if breadth := collect("/a", limit); breadth.DistinctURLs != 1 {
+		t.Errorf("Expected new window to restart, got %+v", breadth)
+	}
+
+	limit = &CrawlBreadthLimit{Limit: 10, IncludeQuery: true}
+	tracker.Prefix = "withquery:"
+	collect("/a", limit)
+	if breadth := collect("/a?page=2", limit); breadth.DistinctURLs != 2 || breadth.Window != DefaultSessionWindow {
+		t.Errorf("Expected query strings to count, got %+v", breadth)
+	}
+}
+
+func TestGetCrawlBreadth_Undefined(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if breadth := getCrawlBreadth(req, collectAllSources(req), nil, DefaultCrawlBreadthLimit()); breadth.GetState() != StateUndefined {
+		t.Errorf("Expected undefined breadth without a tracker, got %v", breadth.GetState())
+	}
+}
+
+func TestDetectCrawlBreadth(t *testing.T) {
+	tests := []struct {
+		name     string
+		breadth  CrawlBreadth
+		expected float64
+	}{
+		{"under limit", CrawlBreadth{DistinctURLs: 500, Limit: 500}, 0},
+		{"over limit", CrawlBreadth{DistinctURLs: 600, Limit: 500}, 0.6},
+		{"far over limit", CrawlBreadth{DistinctURLs: 2000, Limit: 500}, 1},
+		{"no limit", CrawlBreadth{DistinctURLs: 2000}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := &ComponentDict{CrawlBreadth: SuccessComponent[CrawlBreadth]{State: StateSuccess, Value: tt.breadth}}
+			result := detectCrawlBreadth(components)
+			if diff := result.Score - tt.expected; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected score %v, got %v", tt.expected, result.Score)
+			}
+			if tt.expected > 0 && result.BotKind != BotKindScraper {
+				t.Errorf("Expected scraper, got %q", result.BotKind)
+			}
+		})
+	}
+}
+
+func TestBotDetector_CrawlBreadth(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.CrawlBreadthLimit = &CrawlBreadthLimit{Limit: 20}
+	detector := NewDetectorWithConfig(config)
+
+	var result BotDetectionResult
+	for i := range 40 {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/products/%d", i), nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		result, _ = detector.DetectFromRequest(req)
+	}
+	if !result.Bot || detector.GetDetections().CrawlBreadth.BotKind != BotKindScraper {
+		t.Errorf("Expected visitor walking 40 pages to be flagged as a scraper, got %+v", result)
+	}
+}
@@ -0,0 +1,205 @@
+package gogobot
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+//go:embed data/crawler_ranges.txt
+var embeddedCrawlerRanges []byte
+
+// CrawlerRanges maps the IP ranges published by crawler operators to crawler names
+type CrawlerRanges struct {
+	trie     *prefixTrie[string]
+	crawlers map[string]int
+}
+
+// activeCrawlerRanges holds the ranges used for range-based crawler verification
+var activeCrawlerRanges atomic.Pointer[CrawlerRanges]
+
+func init() {
+	ranges, err := ParseCrawlerRanges(bytes.NewReader(embeddedCrawlerRanges))
+	if err != nil {
+		panic(fmt.Sprintf("gogobot: invalid embedded crawler ranges: %v", err))
+	}
+	activeCrawlerRanges.Store(ranges)
+}
+
+// NewCrawlerRanges creates an empty range set
+func NewCrawlerRanges() *CrawlerRanges {
+	return &CrawlerRanges{
+		trie:     newPrefixTrie[string](),
+		crawlers: make(map[string]int),
+	}
+}
+
+// Add registers prefix as belonging to crawler
+func (r *CrawlerRanges) Add(crawler string, prefix netip.Prefix) {
+	r.trie.insert(prefix, crawler)
+	r.crawlers[crawler]++
+}
+
+// Lookup returns the crawler whose published ranges contain addr
+func (r *CrawlerRanges) Lookup(addr netip.Addr) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	crawler, _, ok := r.trie.lookup(addr)
+	return crawler, ok
+}
+
+// Has reports whether any ranges are registered for crawler
+func (r *CrawlerRanges) Has(crawler string) bool {
+	if r == nil {
+		return false
+	}
+	return r.crawlers[crawler] > 0
+}
+
+// Len returns the number of prefixes in the set
+func (r *CrawlerRanges) Len() int {
+	return r.trie.len()
+}
+
+// ParseCrawlerRanges parses the text format of one "<crawler> <cidr>" pair per line
+func ParseCrawlerRanges(r io.Reader) (*CrawlerRanges, error) {
+	ranges := NewCrawlerRanges()
+	err := scanNamedPrefixes(r, func(name string, prefix netip.Prefix) {
+		ranges.Add(name, prefix)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("crawler ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+// ParseCrawlerRangesJSON parses a range file in the format published by Google and Bing
+func ParseCrawlerRangesJSON(r io.Reader) ([]netip.Prefix, error) {
+	prefixes, err := parseGooglePrefixJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse crawler ranges: %w", err)
+	}
+	return prefixes, nil
+}
+
+// LoadCrawlerRangesFile reads crawler ranges from a file in the ParseCrawlerRanges format
+func LoadCrawlerRangesFile(path string) (*CrawlerRanges, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseCrawlerRanges(f)
+}
+
+// SetCrawlerRanges installs the ranges used for range-based crawler verification.
+// Passing nil restores the embedded ranges.
+func SetCrawlerRanges(ranges *CrawlerRanges) {
+	if ranges == nil {
+		ranges, _ = ParseCrawlerRanges(bytes.NewReader(embeddedCrawlerRanges))
+	}
+	activeCrawlerRanges.Store(ranges)
+}
+
+// GetCrawlerRanges returns the installed crawler ranges
+func GetCrawlerRanges() *CrawlerRanges {
+	return activeCrawlerRanges.Load()
+}
+
+// CrawlerRangeSource is a published range file for a crawler
+type CrawlerRangeSource struct {
+	Crawler string
+	URL     string
+}
+
+// DefaultCrawlerRangeSources returns the range files published by Google and Microsoft
+func DefaultCrawlerRangeSources() []CrawlerRangeSource {
+	return []CrawlerRangeSource{
+		{Crawler: "Googlebot", URL: "https://developers.google.com/static/search/apis/ipranges/googlebot.json"},
+		{Crawler: "Googlebot", URL: "https://developers.google.com/static/search/apis/ipranges/special-crawlers.json"},
+		{Crawler: "Bingbot", URL: "https://www.bing.com/toolbox/bingbot.json"},
+	}
+}
+
+// CrawlerRangeUpdater periodically refreshes the installed crawler ranges from published sources.
+// Crawlers without a source, or with any failed fetch, keep their existing ranges.
+type CrawlerRangeUpdater struct {
+	Sources    []CrawlerRangeSource
+	HTTPClient *http.Client
+	// OnError is called with fetch or parse errors; errors are otherwise ignored
+	OnError func(error)
+}
+
+// NewCrawlerRangeUpdater creates an updater for the given sources, defaulting to DefaultCrawlerRangeSources
+func NewCrawlerRangeUpdater(sources ...CrawlerRangeSource) *CrawlerRangeUpdater {
+	if len(sources) == 0 {
+		sources = DefaultCrawlerRangeSources()
+	}
+	return &CrawlerRangeUpdater{
+		Sources:    sources,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Update fetches all sources once and installs the merged ranges
+func (u *CrawlerRangeUpdater) Update(ctx context.Context) error {
+	fetched := make(map[string][]netip.Prefix)
+	failed := make(map[string]bool)
+	var errs []error
+	for _, source := range u.Sources {
+		prefixes, err := fetchPrefixes(ctx, u.HTTPClient, source.URL, ParseCrawlerRangesJSON)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", source.Crawler, source.URL, err))
+			failed[source.Crawler] = true
+			continue
+		}
+		fetched[source.Crawler] = append(fetched[source.Crawler], prefixes...)
+	}
+	// A crawler split across several files is only replaced when all of them were fetched
+	for crawler := range failed {
+		delete(fetched, crawler)
+	}
+
+	if len(fetched) > 0 {
+		ranges := NewCrawlerRanges()
+		GetCrawlerRanges().trie.walk(func(prefix netip.Prefix, crawler string) {
+			if _, replaced := fetched[crawler]; !replaced {
+				ranges.Add(crawler, prefix)
+			}
+		})
+		for crawler, prefixes := range fetched {
+			for _, prefix := range prefixes {
+				ranges.Add(crawler, prefix)
+			}
+		}
+		SetCrawlerRanges(ranges)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run calls Update immediately and then every interval until ctx is cancelled
+func (u *CrawlerRangeUpdater) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.Update(ctx); err != nil && u.OnError != nil {
+			u.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
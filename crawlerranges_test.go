@@ -0,0 +1,126 @@
+package gogobot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestCrawlerRanges_Embedded(t *testing.T) {
+	tests := []struct {
+		addr    string
+		crawler string
+	}{
+		{"66.249.66.1", "Googlebot"},
+		{"2001:4860:4801:10::1", "Googlebot"},
+		{"157.55.39.1", "Bingbot"},
+		{"::ffff:207.46.13.5", "Bingbot"},
+		{"203.0.113.5", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			crawler, ok := GetCrawlerRanges().Lookup(netip.MustParseAddr(tt.addr))
+			if crawler != tt.crawler || ok != (tt.crawler != "") {
+				t.Errorf("Lookup(%s) = %q, %t; want %q", tt.addr, crawler, ok, tt.crawler)
+			}
+		})
+	}
+}
+
+func TestParseCrawlerRangesJSON(t *testing.T) {
+	input := `{"creationTime":"2024-01-01T00:00:00","prefixes":[{"ipv6Prefix":"2001:db8::/32"},{"ipv4Prefix":"192.0.2.0/24"}]}`
+	prefixes, err := ParseCrawlerRangesJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCrawlerRangesJSON() error = %v", err)
+	}
+	if len(prefixes) != 2 || prefixes[0].String() != "2001:db8::/32" || prefixes[1].String() != "192.0.2.0/24" {
+		t.Errorf("ParseCrawlerRangesJSON() = %v", prefixes)
+	}
+
+	if _, err := ParseCrawlerRangesJSON(strings.NewReader(`{"prefixes":[{"ipv4Prefix":"bogus"}]}`)); err == nil {
+		t.Error("Expected error for invalid prefix")
+	}
+}
+
+func TestCrawlerRangeUpdater_Update(t *testing.T) {
+	defer SetCrawlerRanges(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/googlebot.json":
+			w.Write([]byte(`{"prefixes":[{"ipv4Prefix":"198.51.100.0/24"}]}`))
+		case "/special-crawlers.json":
+			w.Write([]byte(`{"prefixes":[{"ipv4Prefix":"192.0.2.0/28"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	updater := NewCrawlerRangeUpdater(
+		CrawlerRangeSource{Crawler: "Googlebot", URL: server.URL + "/googlebot.json"},
+		CrawlerRangeSource{Crawler: "Googlebot", URL: server.URL + "/special-crawlers.json"},
+		CrawlerRangeSource{Crawler: "Bingbot", URL: server.URL + "/missing.json"},
+	)
+	if err := updater.Update(context.Background()); err == nil {
+		t.Error("Expected error for failed source")
+	}
+
+	ranges := GetCrawlerRanges()
+	for _, addr := range []string{"198.51.100.7", "192.0.2.3"} {
+		if crawler, _ := ranges.Lookup(netip.MustParseAddr(addr)); crawler != "Googlebot" {
+			t.Errorf("Expected fetched Googlebot range for %s, got %q", addr, crawler)
+		}
+	}
+	if _, ok := ranges.Lookup(netip.MustParseAddr("66.249.66.1")); ok {
+		t.Error("Expected embedded Googlebot ranges to be replaced")
+	}
+	// Bingbot failed to fetch and keeps its existing ranges
+	if crawler, _ := ranges.Lookup(netip.MustParseAddr("157.55.39.1")); crawler != "Bingbot" {
+		t.Errorf("Expected existing Bingbot range to be kept, got %q", crawler)
+	}
+}
+
+func TestCrawlerVerifier_RangesMode(t *testing.T) {
+	resolver := newFakeCrawlerDNS()
+	tests := []struct {
+		name     string
+		mode     CrawlerVerificationMode
+		addr     string
+		verified bool
+		lookups  bool
+	}{
+		{"Ranges hit", CrawlerVerificationRanges, "66.249.66.1", true, false},
+		{"Ranges miss does not use DNS", CrawlerVerificationRanges, "203.0.113.5", false, false},
+		{"Ranges hit skips DNS", CrawlerVerificationRangesOrDNS, "66.249.66.1", true, false},
+		{"Ranges miss falls back to DNS", CrawlerVerificationRangesOrDNS, "203.0.113.5", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver.lookups = 0
+			verifier := &CrawlerVerifier{Resolver: resolver, Mode: tt.mode}
+			// Uncached so DNS fallbacks are observable
+			defer SetVerificationCache(GetVerificationCache())
+			SetVerificationCache(nil)
+
+			verification, claimed := verifier.Verify(context.Background(), googlebotUA, netip.MustParseAddr(tt.addr))
+			if !claimed || verification.Verified != tt.verified {
+				t.Errorf("Verify() = %+v, claimed=%t; want verified=%t", verification, claimed, tt.verified)
+			}
+			if (resolver.lookups > 0) != tt.lookups {
+				t.Errorf("DNS lookups = %d, want lookups=%t", resolver.lookups, tt.lookups)
+			}
+		})
+	}
+
+	// A Bingbot range does not verify a Googlebot claim
+	verifier := &CrawlerVerifier{Resolver: resolver, Mode: CrawlerVerificationRanges}
+	if verification, _ := verifier.Verify(context.Background(), googlebotUA, netip.MustParseAddr("157.55.39.1")); verification.Verified {
+		t.Error("Expected Googlebot claim from Bingbot range to be unverified")
+	}
+}
@@ -0,0 +1,464 @@
+package gogobot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+//go:embed data/datacenter_ranges.txt
+var embeddedDatacenterRanges []byte
+
+// DatacenterProvider identifies the hosting or cloud provider owning an IP range
+type DatacenterProvider string
+
+const (
+	DatacenterAWS          DatacenterProvider = "aws"
+	DatacenterGCP          DatacenterProvider = "gcp"
+	DatacenterAzure        DatacenterProvider = "azure"
+	DatacenterOVH          DatacenterProvider = "ovh"
+	DatacenterHetzner      DatacenterProvider = "hetzner"
+	DatacenterDigitalOcean DatacenterProvider = "digitalocean"
+)
+
+// datacenterSignalWeight is the score contributed by a datacenter IP. Hosting ranges are also
+// used by VPNs and corporate proxies, so the signal stays below the default threshold alone and
+// together with any other signal detectors raise without configuration, such as missing Fetch
+// Metadata.
+const datacenterSignalWeight = 0.4
+
+// DatacenterRanges is a set of provider IP ranges with longest-prefix lookup
+type DatacenterRanges struct {
+	trie *prefixTrie[DatacenterProvider]
+}
+
+// activeDatacenterRanges holds the ranges used by the "datacenter" detector
+var activeDatacenterRanges atomic.Pointer[DatacenterRanges]
+
+func init() {
+	ranges, err := ParseDatacenterRanges(bytes.NewReader(embeddedDatacenterRanges))
+	if err != nil {
+		panic(fmt.Sprintf("gogobot: invalid embedded datacenter ranges: %v", err))
+	}
+	activeDatacenterRanges.Store(ranges)
+}
+
+// NewDatacenterRanges creates an empty range set
+func NewDatacenterRanges() *DatacenterRanges {
+	return &DatacenterRanges{trie: newPrefixTrie[DatacenterProvider]()}
+}
+
+// Add registers prefix as belonging to provider
+func (r *DatacenterRanges) Add(provider DatacenterProvider, prefix netip.Prefix) {
+	r.trie.insert(prefix, provider)
+}
+
+// Lookup returns the provider owning addr
+func (r *DatacenterRanges) Lookup(addr netip.Addr) (DatacenterProvider, bool) {
+	if r == nil {
+		return "", false
+	}
+	provider, _, ok := r.trie.lookup(addr)
+	return provider, ok
+}
+
+// Len returns the number of prefixes in the set
+func (r *DatacenterRanges) Len() int {
+	return r.trie.len()
+}
+
+// Providers returns the number of prefixes registered per provider
+func (r *DatacenterRanges) Providers() map[DatacenterProvider]int {
+	counts := make(map[DatacenterProvider]int)
+	r.trie.walk(func(_ netip.Prefix, provider DatacenterProvider) {
+		counts[provider]++
+	})
+	return counts
+}
+
+// WriteTo writes the set in the text format read by ParseDatacenterRanges
+func (r *DatacenterRanges) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	var err error
+	r.trie.walk(func(prefix netip.Prefix, provider DatacenterProvider) {
+		if err != nil {
+			return
+		}
+		var n int
+		n, err = fmt.Fprintf(w, "%s %s\n", provider, prefix)
+		written += int64(n)
+	})
+	return written, err
+}
+
+// ParseDatacenterRanges parses the text format of one "<provider> <cidr>" pair per line
+func ParseDatacenterRanges(r io.Reader) (*DatacenterRanges, error) {
+	ranges := NewDatacenterRanges()
+	err := scanNamedPrefixes(r, func(name string, prefix netip.Prefix) {
+		ranges.Add(DatacenterProvider(name), prefix)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("datacenter ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+// scanNamedPrefixes reads "<name> <cidr>" lines, skipping blank lines and "#" comments
+func scanNamedPrefixes(r io.Reader, fn func(name string, prefix netip.Prefix)) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %d: expected \"<name> <cidr>\"", lineNo)
+		}
+		prefix, err := netip.ParsePrefix(fields[1])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		fn(fields[0], prefix)
+	}
+	return scanner.Err()
+}
+
+// LoadDatacenterRangesFile loads a range set from a file in the text format
+func LoadDatacenterRangesFile(path string) (*DatacenterRanges, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDatacenterRanges(f)
+}
+
+// SetDatacenterRanges installs the ranges used by the "datacenter" detector.
+// Passing nil restores the embedded ranges.
+func SetDatacenterRanges(ranges *DatacenterRanges) {
+	if ranges == nil {
+		ranges, _ = ParseDatacenterRanges(bytes.NewReader(embeddedDatacenterRanges))
+	}
+	activeDatacenterRanges.Store(ranges)
+}
+
+// GetDatacenterRanges returns the currently installed ranges
+func GetDatacenterRanges() *DatacenterRanges {
+	return activeDatacenterRanges.Load()
+}
+
+// LookupDatacenter returns the provider owning addr in the installed ranges
+func LookupDatacenter(addr netip.Addr) (DatacenterProvider, bool) {
+	return activeDatacenterRanges.Load().Lookup(addr)
+}
+
+// DatacenterFormat identifies the layout of a provider's published range file
+type DatacenterFormat int
+
+const (
+	// DatacenterFormatCIDRList is a plain list of CIDRs, one per line
+	DatacenterFormatCIDRList DatacenterFormat = iota
+	// DatacenterFormatAWS is AWS ip-ranges.json
+	DatacenterFormatAWS
+	// DatacenterFormatGCP is Google Cloud cloud.json
+	DatacenterFormatGCP
+	// DatacenterFormatAzure is the Azure "Service Tags - Public" JSON download
+	DatacenterFormatAzure
+	// DatacenterFormatCSV is a geofeed-style CSV with the CIDR in the first column (DigitalOcean)
+	DatacenterFormatCSV
+)
+
+// ParseProviderRanges extracts the prefixes from a provider's published range file
+func ParseProviderRanges(r io.Reader, format DatacenterFormat) ([]netip.Prefix, error) {
+	var values []string
+
+	switch format {
+	case DatacenterFormatAWS:
+		var doc struct {
+			Prefixes []struct {
+				IPPrefix string `json:"ip_prefix"`
+			} `json:"prefixes"`
+			IPv6Prefixes []struct {
+				IPv6Prefix string `json:"ipv6_prefix"`
+			} `json:"ipv6_prefixes"`
+		}
+		if err := json.NewDecoder(r).Decode(&doc); err != nil {
+			return nil, fmt.Errorf("parse AWS ranges: %w", err)
+		}
+		for _, p := range doc.Prefixes {
+			values = append(values, p.IPPrefix)
+		}
+		for _, p := range doc.IPv6Prefixes {
+			values = append(values, p.IPv6Prefix)
+		}
+
+	case DatacenterFormatGCP:
+		prefixes, err := parseGooglePrefixJSON(r)
+		if err != nil {
+			return nil, fmt.Errorf("parse GCP ranges: %w", err)
+		}
+		return prefixes, nil
+
+	case DatacenterFormatAzure:
+		var doc struct {
+			Values []struct {
+				Name       string `json:"name"`
+				Properties struct {
+					AddressPrefixes []string `json:"addressPrefixes"`
+				} `json:"properties"`
+			} `json:"values"`
+		}
+		if err := json.NewDecoder(r).Decode(&doc); err != nil {
+			return nil, fmt.Errorf("parse Azure ranges: %w", err)
+		}
+		// The AzureCloud tag is the union of all regional public ranges
+		for _, v := range doc.Values {
+			if v.Name == "AzureCloud" {
+				values = append(values, v.Properties.AddressPrefixes...)
+			}
+		}
+
+	case DatacenterFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.Comment = '#'
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("parse CSV ranges: %w", err)
+		}
+		for _, record := range records {
+			if len(record) > 0 {
+				values = append(values, record[0])
+			}
+		}
+
+	case DatacenterFormatCIDRList:
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				values = append(values, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown datacenter range format %d", format)
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// DatacenterSource is a published range file for a provider
+type DatacenterSource struct {
+	Provider DatacenterProvider
+	URL      string
+	Format   DatacenterFormat
+}
+
+// DefaultDatacenterSources returns the providers that publish machine-readable range files
+// at stable URLs. Azure's download URL changes weekly and must be supplied by the caller.
+func DefaultDatacenterSources() []DatacenterSource {
+	return []DatacenterSource{
+		{Provider: DatacenterAWS, URL: "https://ip-ranges.amazonaws.com/ip-ranges.json", Format: DatacenterFormatAWS},
+		{Provider: DatacenterGCP, URL: "https://www.gstatic.com/ipranges/cloud.json", Format: DatacenterFormatGCP},
+		{Provider: DatacenterDigitalOcean, URL: "https://digitalocean.com/geo/google.csv", Format: DatacenterFormatCSV},
+	}
+}
+
+// DatacenterUpdater periodically refreshes the installed datacenter ranges from published sources.
+// Providers without a source, or whose fetch fails, keep their existing ranges.
+type DatacenterUpdater struct {
+	Sources    []DatacenterSource
+	HTTPClient *http.Client
+	// OnError is called with fetch or parse errors; errors are otherwise ignored
+	OnError func(error)
+}
+
+// NewDatacenterUpdater creates an updater for the given sources, defaulting to DefaultDatacenterSources
+func NewDatacenterUpdater(sources ...DatacenterSource) *DatacenterUpdater {
+	if len(sources) == 0 {
+		sources = DefaultDatacenterSources()
+	}
+	return &DatacenterUpdater{
+		Sources:    sources,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Update fetches all sources once and installs the merged ranges
+func (u *DatacenterUpdater) Update(ctx context.Context) error {
+	fetched := make(map[DatacenterProvider][]netip.Prefix)
+	var errs []error
+	for _, source := range u.Sources {
+		prefixes, err := u.fetch(ctx, source)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Provider, err))
+			continue
+		}
+		fetched[source.Provider] = append(fetched[source.Provider], prefixes...)
+	}
+
+	if len(fetched) > 0 {
+		ranges := NewDatacenterRanges()
+		GetDatacenterRanges().trie.walk(func(prefix netip.Prefix, provider DatacenterProvider) {
+			if _, replaced := fetched[provider]; !replaced {
+				ranges.Add(provider, prefix)
+			}
+		})
+		for provider, prefixes := range fetched {
+			for _, prefix := range prefixes {
+				ranges.Add(provider, prefix)
+			}
+		}
+		SetDatacenterRanges(ranges)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run calls Update immediately and then every interval until ctx is cancelled
+func (u *DatacenterUpdater) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.Update(ctx); err != nil && u.OnError != nil {
+			u.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *DatacenterUpdater) fetch(ctx context.Context, source DatacenterSource) ([]netip.Prefix, error) {
+	return fetchPrefixes(ctx, u.HTTPClient, source.URL, func(r io.Reader) ([]netip.Prefix, error) {
+		return ParseProviderRanges(r, source.Format)
+	})
+}
+
+// fetchPrefixes downloads url and parses the response body with parse
+func fetchPrefixes(ctx context.Context, client *http.Client, url string, parse func(io.Reader) ([]netip.Prefix, error)) ([]netip.Prefix, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parse(resp.Body)
+}
+
+// parseGooglePrefixJSON parses the {"prefixes": [{"ipv4Prefix": ...}, {"ipv6Prefix": ...}]}
+// layout shared by Google Cloud, Googlebot and Bingbot range files
+func parseGooglePrefixJSON(r io.Reader) ([]netip.Prefix, error) {
+	var doc struct {
+		Prefixes []struct {
+			IPv4Prefix string `json:"ipv4Prefix"`
+			IPv6Prefix string `json:"ipv6Prefix"`
+		} `json:"prefixes"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(doc.Prefixes))
+	for _, p := range doc.Prefixes {
+		for _, value := range []string{p.IPv4Prefix, p.IPv6Prefix} {
+			if value == "" {
+				continue
+			}
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid prefix %q: %w", value, err)
+			}
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes, nil
+}
+
+// getDatacenterProvider looks up the resolved client IP in the installed datacenter ranges
+func getDatacenterProvider(clientIP Component[netip.Addr]) Component[DatacenterProvider] {
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[DatacenterProvider]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+
+	provider, _ := LookupDatacenter(clientIP.GetValue())
+	return SuccessComponent[DatacenterProvider]{
+		State: StateSuccess,
+		Value: provider,
+	}
+}
+
+// isHostedClient reports whether the client IP belongs to a hosting or cloud provider
+func isHostedClient(components *ComponentDict) bool {
+	hosted := components.Datacenter.GetState() == StateSuccess && components.Datacenter.GetValue() != ""
+	if !hosted && components.ASN.GetState() == StateSuccess {
+		_, hosted = hostingASNProvider(components.ASN.GetValue())
+	}
+	if !hosted && components.Anonymity.GetState() == StateSuccess {
+		hosted = components.Anonymity.GetValue().Hosting
+	}
+	return hosted
+}
+
+// detectDatacenter contributes a weighted signal for requests from hosting and cloud provider IPs,
+// identified by the embedded ranges, a hosting ASN, or the AnonymityProvider's hosting verdict
+func detectDatacenter(components *ComponentDict) *BotDetectionResult {
+	if !isHostedClient(components) {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindDatacenter,
+		Score:   datacenterSignalWeight,
+	}
+}
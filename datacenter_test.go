@@ -0,0 +1,189 @@
+package gogobot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestLookupDatacenter_Embedded(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected DatacenterProvider
+	}{
+		{"54.239.123.45", DatacenterAWS},
+		{"35.184.1.1", DatacenterGCP},
+		{"40.76.4.15", DatacenterAzure},
+		{"51.68.1.1", DatacenterOVH},
+		{"88.198.1.1", DatacenterHetzner},
+		{"159.203.1.1", DatacenterDigitalOcean},
+		{"2a01:4f8:1::1", DatacenterHetzner},
+		{"192.168.1.100", ""},
+		{"8.8.8.8", ""},
+	}
+
+	for _, tt := range tests {
+		provider, ok := LookupDatacenter(netip.MustParseAddr(tt.addr))
+		if provider != tt.expected || ok != (tt.expected != "") {
+			t.Errorf("LookupDatacenter(%s) = %q, %t; want %q", tt.addr, provider, ok, tt.expected)
+		}
+	}
+}
+
+func TestParseDatacenterRanges(t *testing.T) {
+	ranges, err := ParseDatacenterRanges(strings.NewReader("# comment\n\naws 3.0.0.0/9\nexample 192.0.2.0/24\n"))
+	if err != nil {
+		t.Fatalf("ParseDatacenterRanges returned error: %v", err)
+	}
+	if ranges.Len() != 2 {
+		t.Errorf("Expected 2 ranges, got %d", ranges.Len())
+	}
+	if counts := ranges.Providers(); counts["example"] != 1 || counts[DatacenterAWS] != 1 {
+		t.Errorf("Unexpected provider counts %v", counts)
+	}
+
+	var out strings.Builder
+	if _, err := ranges.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "example 192.0.2.0/24\n") {
+		t.Errorf("Expected written ranges to round-trip, got %q", out.String())
+	}
+
+	for _, invalid := range []string{"aws\n", "aws not-a-cidr\n"} {
+		if _, err := ParseDatacenterRanges(strings.NewReader(invalid)); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestParseProviderRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   DatacenterFormat
+		input    string
+		expected []string
+	}{
+		{
+			name:     "AWS",
+			format:   DatacenterFormatAWS,
+			input:    `{"prefixes":[{"ip_prefix":"3.5.140.0/22","service":"AMAZON"}],"ipv6_prefixes":[{"ipv6_prefix":"2600:1f14::/35"}]}`,
+			expected: []string{"3.5.140.0/22", "2600:1f14::/35"},
+		},
+		{
+			name:     "GCP",
+			format:   DatacenterFormatGCP,
+			input:    `{"prefixes":[{"ipv4Prefix":"34.80.0.0/15"},{"ipv6Prefix":"2600:1900:4000::/44"}]}`,
+			expected: []string{"34.80.0.0/15", "2600:1900:4000::/44"},
+		},
+		{
+			name:     "Azure",
+			format:   DatacenterFormatAzure,
+			input:    `{"values":[{"name":"AzureCloud","properties":{"addressPrefixes":["13.64.0.0/16"]}},{"name":"Storage","properties":{"addressPrefixes":["20.1.0.0/16"]}}]}`,
+			expected: []string{"13.64.0.0/16"},
+		},
+		{
+			name:     "CSV",
+			format:   DatacenterFormatCSV,
+			input:    "5.101.96.0/21,NL,NL-NH,Amsterdam,1098 XG\n2a03:b0c0::/33,NL,NL-NH,Amsterdam,\n",
+			expected: []string{"5.101.96.0/21", "2a03:b0c0::/33"},
+		},
+		{
+			name:     "CIDR list",
+			format:   DatacenterFormatCIDRList,
+			input:    "# hosting\n198.51.100.0/24\n",
+			expected: []string{"198.51.100.0/24"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixes, err := ParseProviderRanges(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("ParseProviderRanges returned error: %v", err)
+			}
+			if len(prefixes) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, prefixes)
+			}
+			for i, prefix := range prefixes {
+				if prefix.String() != tt.expected[i] {
+					t.Errorf("Expected %s, got %s", tt.expected[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestDatacenterUpdater_Update(t *testing.T) {
+	defer SetDatacenterRanges(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/aws.json":
+			w.Write([]byte(`{"prefixes":[{"ip_prefix":"198.51.100.0/24"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	updater := NewDatacenterUpdater(
+		DatacenterSource{Provider: DatacenterAWS, URL: server.URL + "/aws.json", Format: DatacenterFormatAWS},
+		DatacenterSource{Provider: DatacenterGCP, URL: server.URL + "/missing.json", Format: DatacenterFormatGCP},
+	)
+	if err := updater.Update(context.Background()); err == nil {
+		t.Error("Expected error for failed source")
+	}
+
+	// AWS ranges are replaced by the fetched ones
+	if provider, _ := LookupDatacenter(netip.MustParseAddr("198.51.100.7")); provider != DatacenterAWS {
+		t.Errorf("Expected fetched AWS range, got %q", provider)
+	}
+	if _, ok := LookupDatacenter(netip.MustParseAddr("54.239.123.45")); ok {
+		t.Error("Expected embedded AWS ranges to be replaced")
+	}
+	// GCP failed to fetch and keeps its existing ranges
+	if provider, _ := LookupDatacenter(netip.MustParseAddr("35.184.1.1")); provider != DatacenterGCP {
+		t.Errorf("Expected existing GCP range to be kept, got %q", provider)
+	}
+}
+
+func TestDetectDatacenter_WeightedSignal(t *testing.T) {
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Accept":          "text/html",
+		"Accept-Language": "en-US,en;q=0.9",
+		"Accept-Encoding": "gzip, deflate, br",
+	})
+	req.RemoteAddr = "54.239.123.45:443"
+
+	detector := NewDetector()
+	result, err := detector.DetectFromRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Bot {
+		t.Errorf("Expected datacenter IP alone not to flag a bot, got %+v", result)
+	}
+	if result.Score != datacenterSignalWeight {
+		t.Errorf("Expected score %v, got %v", datacenterSignalWeight, result.Score)
+	}
+	if detections := detector.GetDetections(); detections.Datacenter.BotKind != BotKindDatacenter {
+		t.Errorf("Expected datacenter detection, got %+v", detections.Datacenter)
+	}
+	if provider := detector.GetComponents().Datacenter.GetValue(); provider != DatacenterAWS {
+		t.Errorf("Expected AWS provider component, got %q", provider)
+	}
+
+	// A lower threshold lets the signal flag the request on its own
+	config := DefaultDetectorConfig()
+	config.ScoreThreshold = datacenterSignalWeight
+	result, _ = NewDetectorWithConfig(config).DetectFromRequest(req)
+	if !result.Bot || result.BotKind != BotKindDatacenter {
+		t.Errorf("Expected datacenter bot at threshold %v, got %+v", datacenterSignalWeight, result)
+	}
+}
@@ -0,0 +1,44 @@
+package gogobot
+
+import "net/http"
+
+// Decision is what the middleware does after MiddlewareConfig.OnBotDecision saw a detected bot.
+// Unknown decisions block.
+type Decision int
+
+const (
+	// DecisionContinue serves the request, e.g. after logging the bot
+	DecisionContinue Decision = iota
+	// DecisionBlock answers as BlockBots would, with BlockedStatusCode and BlockedMessage
+	DecisionBlock
+	// DecisionHandled stops the chain; the callback wrote the response
+	DecisionHandled
+)
+
+// String returns the name of the decision
+func (d Decision) String() string {
+	switch d {
+	case DecisionContinue:
+		return "continue"
+	case DecisionBlock:
+		return "block"
+	case DecisionHandled:
+		return "handled"
+	default:
+		return "unknown"
+	}
+}
+
+// decide calls OnBotDecision and carries out its decision, returning the action taken, none
+// when the callback wrote the response, and whether the response was written
+func decide(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, result *BotDetectionResult) (Action, bool) {
+	switch config.OnBotDecision(w, req, result) {
+	case DecisionContinue:
+		return ActionAllow, false
+	case DecisionHandled:
+		return "", true
+	default:
+		blockBot(w, req, config, result)
+		return ActionBlock, true
+	}
+}
@@ -0,0 +1,96 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware_OnBotDecision(t *testing.T) {
+	var logged []BotKind
+	config := DefaultMiddlewareConfig()
+	config.OnBotDecision = func(w http.ResponseWriter, r *http.Request, result *BotDetectionResult) Decision {
+		switch result.BotKind.Category() {
+		case BotCategoryCrawler:
+			logged = append(logged, result.BotKind)
+			return DecisionContinue
+		case BotCategoryAI:
+			http.Error(w, "no AI", http.StatusPaymentRequired)
+			return DecisionHandled
+		}
+		return DecisionBlock
+	}
+	var actions []Action
+	config.Observer = ObserverFunc(func(r *http.Request, observation Observation) {
+		actions = append(actions, observation.Action)
+	})
+	served := 0
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		if result, ok := GetResultFromContext(r.Context()); !ok || !result.Bot {
+			t.Error("Expected the continued bot's result in the context")
+		}
+	}))
+
+	tests := []struct {
+		userAgent string
+		status    int
+		action    Action
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", http.StatusOK, ActionAllow},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)", http.StatusPaymentRequired, ""},
+		{"curl/8.4.0", http.StatusForbidden, ActionBlock},
+	}
+	for i, tt := range tests {
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", tt.userAgent)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("Expected %d for %q, got %d", tt.status, tt.userAgent, rec.Code)
+		}
+		if len(actions) != i+1 || actions[i] != tt.action {
+			t.Errorf("Expected action %q for %q, got %v", tt.action, tt.userAgent, actions)
+		}
+	}
+	if served != 1 || len(logged) != 1 {
+		t.Errorf("Expected only the crawler to be logged and served, got %d served and %v logged", served, logged)
+	}
+}
+
+func TestMiddleware_OnBotDecisionUnknown(t *testing.T) {
+	counters, _ := publishTestExpvar(t)
+	config := DefaultMiddlewareConfig()
+	config.Expvar = counters
+	config.OnBotDecision = func(w http.ResponseWriter, r *http.Request, result *BotDetectionResult) Decision {
+		return Decision(42)
+	}
+	served := false
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	}))
+
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden || served {
+		t.Errorf("Expected an unknown decision to block, got %d and served %t", rec.Code, served)
+	}
+	if blocks := counters.blocks.Value(); blocks != 1 {
+		t.Errorf("Expected the block to be counted, got %d", blocks)
+	}
+}
+
+func TestDecision_String(t *testing.T) {
+	for decision, expected := range map[Decision]string{
+		DecisionContinue: "continue",
+		DecisionBlock:    "block",
+		DecisionHandled:  "handled",
+		Decision(42):     "unknown",
+	} {
+		if got := decision.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package gogobot
 
 import (
 	"net/http"
-	"regexp"
-	"strings"
+	"net/netip"
+	"slices"
+	"time"
+
+	"github.com/lytics/gogobot/core"
 )
 
 // BotDetector is the main struct for bot detection
@@ -11,6 +14,132 @@ type BotDetector struct {
 	components    *ComponentDict
 	detections    *DetectionDict
 	detectorFuncs map[string]DetectorFunc
+	config        DetectorConfig
+	controls      runtimeControls
+	events        eventBus
+}
+
+// DetectorConfig holds optional subsystems used during collection and detection
+type DetectorConfig struct {
+	// ClientIPResolver resolves the client IP behind trusted proxies; nil uses RemoteAddr
+	ClientIPResolver *ClientIPResolver
+	// ASNProvider looks up the autonomous system of the client IP; nil disables ASN lookups
+	ASNProvider ASNProvider
+	// GeoProvider looks up the country and region of the client IP; nil disables geo lookups
+	GeoProvider GeoProvider
+	// CrawlerVerifier verifies user agents claiming to be a known crawler; nil disables verification
+	CrawlerVerifier *CrawlerVerifier
+	// AnonymityProvider classifies the client IP as VPN, proxy or hosting; nil uses NoopAnonymityProvider
+	AnonymityProvider AnonymityProvider
+	// ReputationChecker looks up the client IP with a reputation feed; nil disables reputation checks
+	ReputationChecker *ReputationChecker
+	// IPAccessList holds allow CIDRs that bypass detection and deny CIDRs that are always
+	// flagged, enforced before detectors run; nil disables access lists
+	IPAccessList *IPAccessList
+	// CDNTrust holds the edge ranges of CDNs whose client IP and bot verdict headers are
+	// honored; nil ignores CDN headers
+	CDNTrust *CDNTrust
+	// OrganizationProvider names the organization owning the client IP; nil falls back to the
+	// ASN's registered name and the embedded datacenter ranges
+	OrganizationProvider OrganizationProvider
+	// ResultCache reuses verdicts for the same client IP and user agent; nil disables caching
+	ResultCache *ResultCache
+	// SessionTracker records each visitor's requests for behavioral detectors; nil disables
+	// session tracking
+	SessionTracker *SessionTracker
+	// RateLimits are request-rate thresholds per visitor, counted in the SessionTracker's store;
+	// nil disables request-rate detection
+	RateLimits []RateLimit
+	// CrawlBreadthLimit flags visitors requesting too many distinct URLs per window, counted in
+	// the SessionTracker's store; nil disables crawl-breadth detection
+	CrawlBreadthLimit *CrawlBreadthLimit
+	// TimingAnalysis flags inhuman request cadence, recorded in the SessionTracker's store; nil
+	// disables timing analysis
+	TimingAnalysis *TimingAnalysis
+	// RobotsPolicy is the site's robots.txt; crawlers fetching paths it disallows are recorded
+	// in the SessionTracker's store, and nil disables robots.txt tracking
+	RobotsPolicy *RobotsPolicy
+	// EnumerationLimit flags visitors walking ID sequences or paginating too deep, tracked in
+	// the SessionTracker's store; nil disables enumeration detection
+	EnumerationLimit *EnumerationLimit
+	// ErrorRateLimit flags visitors whose requests mostly end in error responses, counted in the
+	// SessionTracker's store by the middleware; nil disables error-rate detection
+	ErrorRateLimit *ErrorRateLimit
+	// LoginProtection flags credential stuffing on authentication endpoints from the failed
+	// logins recorded in the SessionTracker's store; nil disables login protection
+	LoginProtection *LoginProtection
+	// H3Adoption advertises HTTP/3 and scores visitors that never switch to it, counted in the
+	// SessionTracker's store; nil disables HTTP/3 adoption detection
+	H3Adoption *H3Adoption
+	// Honeypot flags visitors fetching its trap URLs, remembered in the SessionTracker's store;
+	// nil disables honeypot detection
+	Honeypot *Honeypot
+	// CookieChallenge makes the middleware redirect visitors once to set a signed cookie and
+	// flags visitors that never return it, counted in the SessionTracker's store; nil disables
+	// the challenge
+	CookieChallenge *CookieChallenge
+	// CookiePolicy flags visitors using flows that require the application's session cookie
+	// without ever sending it, counted in the SessionTracker's store; nil disables the policy
+	CookiePolicy *CookiePolicy
+	// JSChallenge serves a script proving clients run JavaScript and issues trust cookies, which
+	// the middleware can require on sensitive routes; nil disables the challenge
+	JSChallenge *JSChallenge
+	// ProtocolPolicy enables judging the HTTP version of browser user agents, read from the
+	// request or a header set by a proxy; nil disables protocol detection
+	ProtocolPolicy *ProtocolPolicy
+	// AcceptProfiles are the Accept headers browser engines send for navigations, keyed by
+	// engine, such as DefaultAcceptProfiles; nil disables Accept plausibility detection
+	AcceptProfiles map[string]AcceptProfile
+	// EncodingProfiles are the Accept-Encoding headers browsers send, keyed by browser family,
+	// such as DefaultEncodingProfiles; nil disables Accept-Encoding plausibility detection
+	EncodingProfiles map[string]EncodingProfile
+	// ConnectionPolicy sets the connection upgrades and TE codings clients may request; nil
+	// uses DefaultConnectionPolicy
+	ConnectionPolicy *ConnectionPolicy
+	// RequestCapture dumps each request's line and headers, redacted, into the RawRequest
+	// component for later investigation; nil disables capture
+	RequestCapture *RequestCapture
+	// ScoreThreshold is the combined soft-signal score at which a request is flagged as a bot;
+	// zero uses DefaultScoreThreshold
+	ScoreThreshold float64
+}
+
+// DefaultScoreThreshold is the soft-signal score at which a request is flagged as a bot
+const DefaultScoreThreshold = 0.8
+
+// DefaultDetectorConfig returns a default detector configuration
+func DefaultDetectorConfig() DetectorConfig {
+	return DetectorConfig{
+		ClientIPResolver:     nil,
+		ASNProvider:          nil,
+		GeoProvider:          nil,
+		CrawlerVerifier:      nil,
+		AnonymityProvider:    NoopAnonymityProvider{},
+		ReputationChecker:    nil,
+		IPAccessList:         nil,
+		CDNTrust:             nil,
+		ResultCache:          nil,
+		OrganizationProvider: nil,
+		SessionTracker:       nil,
+		RateLimits:           nil,
+		CrawlBreadthLimit:    nil,
+		TimingAnalysis:       nil,
+		RobotsPolicy:         nil,
+		EnumerationLimit:     nil,
+		ErrorRateLimit:       nil,
+		LoginProtection:      nil,
+		H3Adoption:           nil,
+		Honeypot:             nil,
+		CookieChallenge:      nil,
+		CookiePolicy:         nil,
+		JSChallenge:          nil,
+		ProtocolPolicy:       nil,
+		AcceptProfiles:       nil,
+		EncodingProfiles:     nil,
+		ConnectionPolicy:     nil,
+		RequestCapture:       nil,
+		ScoreThreshold:       DefaultScoreThreshold,
+	}
 }
 
 // NewDetector creates a new BotDetector instance
@@ -34,25 +163,206 @@ func NewDetectorWithCustomDetectors(customDetectors map[string]DetectorFunc) *Bo
 	}
 }
 
+// NewDetectorWithConfig creates a new BotDetector with the default detectors and the given configuration
+func NewDetectorWithConfig(config DetectorConfig) *BotDetector {
+	return &BotDetector{
+		detectorFuncs: getDefaultDetectors(),
+		config:        config,
+	}
+}
+
 // Collect gathers data from the HTTP request
 func (d *BotDetector) Collect(req *http.Request) (*ComponentDict, error) {
-	d.components = collectAllSources(req)
+	components, err := d.collect(req)
+	if err != nil {
+		return nil, err
+	}
+
+	d.components = components
 	return d.components, nil
 }
 
+// collect gathers components without storing them on the detector
+func (d *BotDetector) collect(req *http.Request) (*ComponentDict, error) {
+	components := collectAllSources(req)
+	if d.config.ClientIPResolver != nil || d.config.CDNTrust != nil {
+		components.ResolvedClientIP, components.CDN = d.resolveClientIP(req)
+		components.Datacenter = getDatacenterProvider(components.ResolvedClientIP)
+		components.Anonymity = getAnonymity(components.ResolvedClientIP, nil)
+	}
+	if d.config.IPAccessList != nil {
+		components.IPAccess = getIPAccess(components.ResolvedClientIP, d.config.IPAccessList)
+	}
+	if d.config.ASNProvider != nil {
+		components.ASN = getASN(components.ResolvedClientIP, d.config.ASNProvider)
+	}
+	if d.config.GeoProvider != nil {
+		components.Geo = getGeo(components.ResolvedClientIP, d.config.GeoProvider)
+	}
+	if d.config.AnonymityProvider != nil {
+		components.Anonymity = getAnonymity(components.ResolvedClientIP, d.config.AnonymityProvider)
+	}
+	components.Organization = getOrganization(components.ResolvedClientIP, components.ASN, components.Datacenter, d.config.OrganizationProvider)
+	if d.config.JSChallenge != nil {
+		components.JSTrust = getJSTrust(req, components.ResolvedClientIP, d.config.JSChallenge)
+	}
+	if d.config.ProtocolPolicy != nil {
+		components.Protocol = getProtocol(req, d.config.ProtocolPolicy)
+	}
+	if d.config.AcceptProfiles != nil {
+		components.NavigationAccept = getNavigationAccept(req, d.config.AcceptProfiles)
+	}
+	if d.config.EncodingProfiles != nil {
+		components.ClaimedEncodings = getClaimedEncodings(req, d.config.EncodingProfiles)
+	}
+	if d.config.ConnectionPolicy != nil {
+		components.ConnectionOptions = getConnectionOptions(req, d.config.ConnectionPolicy)
+	}
+	if d.config.RequestCapture != nil {
+		components.RawRequest = getRawRequest(req, components, d.config.RequestCapture)
+	}
+	if d.config.CookiePolicy != nil {
+		components.Cookies = getCookies(req, d.config.CookiePolicy)
+	}
+
+	// Allowed and denied addresses are decided without network lookups
+	if components.IPAccess.GetValue() != IPAccessNone {
+		return components, nil
+	}
+	if d.config.SessionTracker != nil {
+		components.Session = getSession(req, components, d.config.SessionTracker)
+	}
+	if len(d.config.RateLimits) > 0 {
+		components.RequestRates = getRequestRates(req, components, d.config.SessionTracker, d.config.RateLimits)
+	}
+	if d.config.CrawlBreadthLimit != nil {
+		components.CrawlBreadth = getCrawlBreadth(req, components, d.config.SessionTracker, d.config.CrawlBreadthLimit)
+	}
+	if d.config.TimingAnalysis != nil {
+		components.RequestTiming = getRequestTiming(req, components, d.config.SessionTracker, d.config.TimingAnalysis)
+	}
+	if d.config.RobotsPolicy != nil {
+		components.RobotsCompliance = getRobotsCompliance(req, components, d.config.SessionTracker, d.config.RobotsPolicy)
+	}
+	if d.config.EnumerationLimit != nil {
+		components.Enumeration = getEnumeration(req, components, d.config.SessionTracker, d.config.EnumerationLimit)
+	}
+	if d.config.ErrorRateLimit != nil {
+		components.ErrorRate = getErrorRate(req, components, d.config.SessionTracker, d.config.ErrorRateLimit)
+	}
+	if d.config.LoginProtection != nil {
+		components.LoginFailures = getLoginFailures(req, components, d.config.SessionTracker, d.config.LoginProtection)
+	}
+	if d.config.H3Adoption != nil {
+		components.H3Usage = getH3Usage(req, components, d.config.SessionTracker, d.config.H3Adoption)
+	}
+	if d.config.CookiePolicy != nil {
+		components.CookieSession = getCookieSession(req, components, d.config.SessionTracker, d.config.CookiePolicy)
+	}
+	if d.config.Honeypot != nil {
+		components.Trapped = getTrapped(req, components, d.config.SessionTracker, d.config.Honeypot)
+	}
+	if d.config.ReputationChecker != nil {
+		components.Reputation = getReputation(req.Context(), components.ResolvedClientIP, d.config.ReputationChecker)
+	}
+	// A crawler the CDN already verified does not need DNS lookups
+	if d.config.CrawlerVerifier != nil && !isCDNVerifiedBot(components) {
+		components.CrawlerVerification = getCrawlerVerification(req.Context(), components.UserAgent, components.ResolvedClientIP, d.config.CrawlerVerifier)
+	}
+	if d.config.CookieChallenge != nil {
+		components.CookieChallenge = getCookieChallenge(req, components, d.config.SessionTracker, d.config.CookieChallenge)
+	}
+	return components, nil
+}
+
+// resolveClientIP resolves the client IP behind trusted proxies and CDN edges, returning it
+// with the verdict of the CDN the request arrived from
+func (d *BotDetector) resolveClientIP(req *http.Request) (Component[netip.Addr], Component[CDNVerdict]) {
+	clientIP := getResolvedClientIP(req, d.config.ClientIPResolver)
+	cdn := getCDNVerdict(req, clientIP, d.config.CDNTrust)
+
+	// The edge server is a proxy; the CDN reports the address that connected to it
+	if cdn.GetState() == StateSuccess && cdn.GetValue().ClientIP.IsValid() {
+		clientIP = SuccessComponent[netip.Addr]{
+			State: StateSuccess,
+			Value: cdn.GetValue().ClientIP,
+		}
+	}
+	return clientIP, cdn
+}
+
 // Detect performs bot detection on the collected components
 func (d *BotDetector) Detect() BotDetectionResult {
 	if d.components == nil {
 		panic("BotDetector.Detect() called before Collect()")
 	}
 
+	result, detections := d.detect(d.components, nil)
+	d.detections = detections
+	return result
+}
+
+// detect runs all detectors on components without storing the results on the detector,
+// so a single BotDetector can serve concurrent requests (e.g. from the middleware). The run time
+// of each detector is recorded in timings when it is not nil.
+func (d *BotDetector) detect(components *ComponentDict, timings map[string]time.Duration) (BotDetectionResult, *DetectionDict) {
+	result, detections := d.runDetectors(components, timings)
+	enrichNetwork(&result, components)
+	return result, detections
+}
+
+// enrichNetwork copies what the IP subsystems know about the client into the result, so
+// consumers of the report do not have to join the components themselves
+func enrichNetwork(result *BotDetectionResult, components *ComponentDict) {
+	if components.ResolvedClientIP == nil || components.ResolvedClientIP.GetState() != StateSuccess {
+		return
+	}
+
+	if components.Organization.GetState() == StateSuccess {
+		result.Organization = components.Organization.GetValue()
+	}
+	if components.Geo.GetState() == StateSuccess {
+		result.Country = components.Geo.GetValue().Country
+	}
+	if components.ASN.GetState() == StateSuccess {
+		result.ASN = components.ASN.GetValue().Number
+	}
+	if components.Anonymity.GetState() == StateSuccess {
+		result.IsTor = components.Anonymity.GetValue().Tor
+	}
+	result.IsDatacenter = isHostedClient(components)
+}
+
+// runDetectors enforces the access lists and combines the verdicts of all detectors
+func (d *BotDetector) runDetectors(components *ComponentDict, timings map[string]time.Duration) (BotDetectionResult, *DetectionDict) {
 	detections := &DetectionDict{}
+
+	switch components.IPAccess.GetValue() {
+	case IPAccessAllow:
+		return BotDetectionResult{Bot: false}, detections
+	case IPAccessDeny:
+		return BotDetectionResult{Bot: true, BotKind: BotKindDenylisted}, detections
+	}
+
 	finalResult := BotDetectionResult{Bot: false}
 	var bestResult BotDetectionResult
+	var bestSignal BotDetectionResult
+	var reasons []string
+	var scores []float64
 
 	// Run all detectors
 	for name, detectorFunc := range d.detectorFuncs {
-		result := detectorFunc(d.components)
+		if d.controls.detectorDisabled(name) {
+			continue
+		}
+		var start time.Time
+		if timings != nil {
+			start = time.Now()
+		}
+		result := detectorFunc(components)
+		if timings != nil {
+			timings[name] = time.Since(start)
+		}
 		if result == nil {
 			result = &BotDetectionResult{Bot: false}
 		}
@@ -75,6 +385,64 @@ func (d *BotDetector) Detect() BotDetectionResult {
 			detections.Connection = *result
 		case "contentLength":
 			detections.ContentLength = *result
+		case "plausibility":
+			detections.Plausibility = *result
+		case "datacenter":
+			detections.Datacenter = *result
+		case "anonymity":
+			detections.Anonymity = *result
+		case "reputation":
+			detections.Reputation = *result
+		case "impersonator":
+			detections.Impersonator = *result
+		case "cdn":
+			detections.CDN = *result
+		case "forwardedFor":
+			detections.ForwardedFor = *result
+		case "requestRate":
+			detections.RequestRate = *result
+		case "crawlBreadth":
+			detections.CrawlBreadth = *result
+		case "requestTiming":
+			detections.RequestTiming = *result
+		case "robotsViolation":
+			detections.RobotsViolation = *result
+		case "enumeration":
+			detections.Enumeration = *result
+		case "errorRate":
+			detections.ErrorRate = *result
+		case "probePath":
+			detections.ProbePath = *result
+		case "credentialStuffing":
+			detections.CredentialStuffing = *result
+		case "protocol":
+			detections.Protocol = *result
+		case "secFetch":
+			detections.SecFetch = *result
+		case "acceptPlausibility":
+			detections.AcceptPlausibility = *result
+		case "encodingPlausibility":
+			detections.EncodingPlausibility = *result
+		case "h3Adoption":
+			detections.H3Adoption = *result
+		case "cookielessSession":
+			detections.CookielessSession = *result
+		case "honeypot":
+			detections.Honeypot = *result
+		case "cookieChallenge":
+			detections.CookieChallenge = *result
+		}
+
+		if result.Bot || result.Score > 0 {
+			reasons = append(reasons, result.Reasons...)
+		}
+
+		// Weighted signals are combined as independent probabilities
+		if result.Score > 0 {
+			scores = append(scores, result.Score)
+			if result.Score > bestSignal.Score {
+				bestSignal = *result
+			}
 		}
 
 		// If any detector finds a bot, consider it for final result
@@ -93,19 +461,53 @@ func (d *BotDetector) Detect() BotDetectionResult {
 	if bestResult.Bot {
 		finalResult = bestResult
 	}
+	// A spoofed crawler user agent would otherwise be reported as the crawler it claims to be
+	if detections.Impersonator.Bot {
+		finalResult = detections.Impersonator
+	}
 
-	d.detections = detections
-	return finalResult
+	if components.CrawlerVerification.GetState() == StateSuccess {
+		finalResult.Verified = components.CrawlerVerification.GetValue().Verified
+	}
+	if isCDNVerifiedBot(components) {
+		finalResult.VerifiedByCDN = true
+		if !finalResult.Bot {
+			finalResult.Bot = true
+			finalResult.BotKind = BotKindCrawler
+		}
+	}
+
+	finalResult.Score = core.CombineScores(scores...)
+	threshold := d.config.ScoreThreshold
+	if threshold <= 0 {
+		threshold = DefaultScoreThreshold
+	}
+	if !finalResult.Bot && finalResult.Score >= threshold {
+		finalResult.Bot = true
+		finalResult.BotKind = bestSignal.BotKind
+	}
+	// Detectors run in map order
+	slices.Sort(reasons)
+	finalResult.Reasons = reasons
+
+	return finalResult, detections
 }
 
-// DetectFromRequest is a convenience method that collects and detects in one call
+// DetectFromRequest is a convenience method that collects and detects in one call. With a
+// ResultCache configured, cached verdicts are returned without collecting, leaving the
+// components and detections of the last uncached request in place.
 func (d *BotDetector) DetectFromRequest(req *http.Request) (BotDetectionResult, error) {
+	if entry, ok := d.lookupResultCache(req); ok {
+		return entry.result, nil
+	}
+
 	_, err := d.Collect(req)
 	if err != nil {
 		return BotDetectionResult{Bot: false}, err
 	}
 
 	result := d.Detect()
+	d.cacheResult(req, d.components, result)
 	return result, nil
 }
 
@@ -145,8 +547,11 @@ func (d *BotDetector) GetDetectorNames() []string {
 
 // collectAllSources collects all data sources from the HTTP request
 func collectAllSources(req *http.Request) *ComponentDict {
+	userAgent := getUserAgent(req)
+	resolvedClientIP := getResolvedClientIP(req, nil)
+	datacenter := getDatacenterProvider(resolvedClientIP)
 	return &ComponentDict{
-		UserAgent:            getUserAgent(req),
+		UserAgent:            userAgent,
 		XForwardedFor:        getXForwardedFor(req),
 		XRealIP:              getXRealIP(req),
 		AcceptLanguage:       getAcceptLanguage(req),
@@ -166,6 +571,41 @@ func collectAllSources(req *http.Request) *ComponentDict {
 		HeaderOrder:          getHeaderOrder(req),
 		HeaderCount:          getHeaderCount(req),
 		MissingCommonHeaders: getMissingCommonHeaders(req),
+		ResolvedClientIP:     resolvedClientIP,
+		ForwardedChain:       getForwardedChain(req),
+		ForwardedFor:         getForwardedFor(req),
+		Datacenter:           datacenter,
+		ASN:                  getASN(resolvedClientIP, nil),
+		Geo:                  getGeo(resolvedClientIP, nil),
+		Organization:         getOrganization(resolvedClientIP, getASN(resolvedClientIP, nil), datacenter, nil),
+		CrawlerVerification:  getCrawlerVerification(req.Context(), userAgent, resolvedClientIP, nil),
+		Anonymity:            getAnonymity(resolvedClientIP, nil),
+		Reputation:           getReputation(req.Context(), resolvedClientIP, nil),
+		IPAccess:             getIPAccess(resolvedClientIP, nil),
+		CDN:                  getCDNVerdict(req, resolvedClientIP, nil),
+		Session:              getSession(req, nil, nil),
+		RequestRates:         getRequestRates(req, nil, nil, nil),
+		CrawlBreadth:         getCrawlBreadth(req, nil, nil, nil),
+		RequestTiming:        getRequestTiming(req, nil, nil, nil),
+		RobotsCompliance:     getRobotsCompliance(req, nil, nil, nil),
+		Enumeration:          getEnumeration(req, nil, nil, nil),
+		ErrorRate:            getErrorRate(req, nil, nil, nil),
+		ProbePath:            getProbePath(req),
+		LoginFailures:        getLoginFailures(req, nil, nil, nil),
+		Trapped:              getTrapped(req, nil, nil, nil),
+		CookieChallenge:      getCookieChallenge(req, nil, nil, nil),
+		JSTrust:              getJSTrust(req, resolvedClientIP, nil),
+		TLSFingerprint:       getTLSFingerprint(req),
+		Protocol:             getProtocol(req, nil),
+		SecFetch:             getSecFetch(req),
+		NavigationAccept:     getNavigationAccept(req, nil),
+		ClaimedEncodings:     getClaimedEncodings(req, nil),
+		ConnectionOptions:    getConnectionOptions(req, nil),
+		H3Usage:              getH3Usage(req, nil, nil, nil),
+		RawRequest:           getRawRequest(req, nil, nil),
+		Cookies:              getCookies(req, nil),
+		CookieSession:        getCookieSession(req, nil, nil, nil),
+		Prefetch:             getPrefetch(req),
 	}
 }
 
@@ -311,17 +751,6 @@ func getRemoteAddr(req *http.Request) Component[string] {
 	}
 }
 
-func getHeaderOrder(req *http.Request) Component[[]string] {
-	var order []string
-	for k := range req.Header {
-		order = append(order, k)
-	}
-	return SuccessComponent[[]string]{
-		State: StateSuccess,
-		Value: order,
-	}
-}
-
 func getHeaderCount(req *http.Request) Component[int] {
 	count := len(req.Header)
 	return SuccessComponent[int]{
@@ -358,76 +787,14 @@ func detectUserAgent(components *ComponentDict) *BotDetectionResult {
 		return &BotDetectionResult{Bot: false}
 	}
 
-	userAgent := strings.ToLower(components.UserAgent.GetValue())
-
-	// Check for specific bot types in order of specificity (most specific first)
-	specificBots := []struct {
-		kind     BotKind
-		patterns []string
-	}{
-		// AI Agents (check first as they're highly specific)
-		{BotKindGPTBot, []string{"gptbot", "gpt-bot"}},
-		{BotKindChatGPT, []string{"chatgpt-user", "chatgpt", "openai-chatgpt"}},
-		{BotKindOpenAI, []string{"openai", "openai-bot", "openai-crawler"}},
-		{BotKindClaude, []string{"claude-web", "claude", "anthropic"}},
-		{BotKindAIAgent, []string{"ai-agent", "aiagent", "ai_agent", "artificial intelligence", "language model", "llm", "gpt-", "claude-", "bard", "gemini-pro"}},
-
-		// Automation Tools
-		{BotKindPhantomJS, []string{"phantomjs"}},
-		{BotKindSelenium, []string{"selenium", "webdriver"}},
-		{BotKindElectron, []string{"electron"}},
-		{BotKindHeadlessChrome, []string{"headlesschrome", "headless"}},
-		{BotKindPlaywright, []string{"playwright"}},
-		{BotKindPuppeteer, []string{"puppeteer"}},
-
-		// Command Line Tools
-		{BotKindCurl, []string{"curl/"}},
-		{BotKindWget, []string{"wget/"}},
-
-		// Search Engine Crawlers
-		{BotKindCrawler, []string{"googlebot", "bingbot", "slurp", "duckduckbot", "baiduspider"}}, // Check crawlers before generic "bot"
-
-		// Generic Bots (last to avoid false positives)
-		{BotKindBot, []string{"bot", "crawler", "spider", "scraper"}},
-	}
-
-	for _, botType := range specificBots {
-		for _, pattern := range botType.patterns {
-			if strings.Contains(userAgent, pattern) {
-				return &BotDetectionResult{
-					Bot:     true,
-					BotKind: botType.kind,
-				}
-			}
-		}
+	kind, isBot := core.ClassifyUserAgent(components.UserAgent.GetValue())
+	if !isBot {
+		return &BotDetectionResult{Bot: false}
 	}
-
-	// Check for suspicious user agent patterns
-	suspiciousPatterns := []string{
-		"^$",
-		"^\\s*$",
-		"mozilla/5.0$",
-		"mozilla/4.0$",
-		"python",
-		"java",
-		"go-http-client",
-		"libwww-perl",
-		"httpclient",
-		"okhttp",
-		"requests",
-		"urllib",
-	}
-
-	for _, pattern := range suspiciousPatterns {
-		if matched, _ := regexp.MatchString(pattern, userAgent); matched {
-			return &BotDetectionResult{
-				Bot:     true,
-				BotKind: BotKindUnknown,
-			}
-		}
+	return &BotDetectionResult{
+		Bot:     true,
+		BotKind: BotKind(kind),
 	}
-
-	return &BotDetectionResult{Bot: false}
 }
 
 func detectHeaders(components *ComponentDict) *BotDetectionResult {
@@ -437,12 +804,12 @@ func detectHeaders(components *ComponentDict) *BotDetectionResult {
 
 	headers := components.Headers.GetValue()
 
-	// Check for automation-specific headers
+	// Check for automation-specific headers. Purpose is sent by browsers prefetching pages and
+	// is classified as the Prefetch component instead.
 	automationHeaders := []string{
 		"X-Requested-With",
 		"X-DevTools-Emulate-Network-Conditions-Client-Id",
 		"Chrome-Proxy",
-		"Purpose",
 	}
 
 	for _, header := range automationHeaders {
@@ -534,26 +901,6 @@ func detectAcceptHeaders(components *ComponentDict) *BotDetectionResult {
 	return &BotDetectionResult{Bot: false}
 }
 
-func detectConnection(components *ComponentDict) *BotDetectionResult {
-	if components.Connection.GetState() != StateSuccess {
-		return &BotDetectionResult{Bot: false}
-	}
-
-	connection := strings.ToLower(components.Connection.GetValue())
-	suspiciousConnections := []string{"upgrade", "te"}
-
-	for _, suspicious := range suspiciousConnections {
-		if strings.Contains(connection, suspicious) {
-			return &BotDetectionResult{
-				Bot:     true,
-				BotKind: BotKindUnknown,
-			}
-		}
-	}
-
-	return &BotDetectionResult{Bot: false}
-}
-
 func detectContentLength(components *ComponentDict) *BotDetectionResult {
 	if components.ContentLength.GetState() != StateSuccess {
 		return &BotDetectionResult{Bot: false}
@@ -572,34 +919,39 @@ func detectContentLength(components *ComponentDict) *BotDetectionResult {
 	return &BotDetectionResult{Bot: false}
 }
 
-func detectHeaderOrder(components *ComponentDict) *BotDetectionResult {
-	if components.HeaderOrder.GetState() != StateSuccess {
-		return &BotDetectionResult{Bot: false}
-	}
-
-	order := components.HeaderOrder.GetValue()
-
-	// Very conservative - only flag if extremely few headers
-	if len(order) < 2 { // Reduced from 3
-		return &BotDetectionResult{
-			Bot:     true,
-			BotKind: BotKindUnknown,
-		}
-	}
-
-	return &BotDetectionResult{Bot: false}
-}
-
 // getDefaultDetectors returns the default set of detectors
 func getDefaultDetectors() map[string]DetectorFunc {
 	return map[string]DetectorFunc{
-		"userAgent":      detectUserAgent,
-		"headers":        detectHeaders,
-		"headerOrder":    detectHeaderOrder,
-		"headerCount":    detectHeaderCount,
-		"missingHeaders": detectMissingHeaders,
-		"acceptHeaders":  detectAcceptHeaders,
-		"connection":     detectConnection,
-		"contentLength":  detectContentLength,
+		"userAgent":            detectUserAgent,
+		"headers":              detectHeaders,
+		"headerOrder":          detectHeaderOrder,
+		"headerCount":          detectHeaderCount,
+		"missingHeaders":       detectMissingHeaders,
+		"acceptHeaders":        detectAcceptHeaders,
+		"connection":           detectConnection,
+		"contentLength":        detectContentLength,
+		"plausibility":         detectPlausibility,
+		"datacenter":           detectDatacenter,
+		"anonymity":            detectAnonymity,
+		"reputation":           detectReputation,
+		"impersonator":         detectImpersonator,
+		"cdn":                  detectCDN,
+		"forwardedFor":         detectForwardedFor,
+		"requestRate":          detectRequestRate,
+		"crawlBreadth":         detectCrawlBreadth,
+		"requestTiming":        detectRequestTiming,
+		"robotsViolation":      detectRobotsViolation,
+		"enumeration":          detectEnumeration,
+		"errorRate":            detectErrorRate,
+		"probePath":            detectProbePath,
+		"credentialStuffing":   detectCredentialStuffing,
+		"protocol":             detectProtocol,
+		"secFetch":             detectSecFetch,
+		"acceptPlausibility":   detectAcceptPlausibility,
+		"encodingPlausibility": detectEncodingPlausibility,
+		"h3Adoption":           detectH3Adoption,
+		"cookielessSession":    detectCookielessSession,
+		"honeypot":             detectHoneypot,
+		"cookieChallenge":      detectCookieChallenge,
 	}
 }
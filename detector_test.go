@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+
+	"github.com/lytics/gogobot/core"
 )
 
 func TestNewDetector(t *testing.T) {
@@ -408,3 +410,32 @@ func createTestRequest(method, path string, headers map[string]string) *http.Req
 
 	return req
 }
+
+func BenchmarkDetectUserAgent(b *testing.B) {
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	})
+	components := collectAllSources(req)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		detectUserAgent(components)
+	}
+}
+
+func TestCoreKindsAreBotKinds(t *testing.T) {
+	kinds := []string{
+		core.KindPhantomJS, core.KindSlimerJS, core.KindSelenium, core.KindElectron,
+		core.KindHeadlessChrome, core.KindPlaywright, core.KindPuppeteer, core.KindCurl,
+		core.KindWget, core.KindBot, core.KindCrawler, core.KindGPTBot, core.KindChatGPT,
+		core.KindOpenAI, core.KindClaude, core.KindAIAgent,
+	}
+	for _, kind := range kinds {
+		if _, ok := botKindCategories[BotKind(kind)]; !ok {
+			t.Errorf("Expected core kind %q to be a BotKind", kind)
+		}
+	}
+	if core.KindUnknown != string(BotKindUnknown) {
+		t.Errorf("Expected core unknown kind to be BotKindUnknown")
+	}
+}
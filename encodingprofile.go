@@ -0,0 +1,125 @@
+package gogobot
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// EncodingProfile is the Accept-Encoding header the browsers of a family send
+type EncodingProfile struct {
+	// Since maps the content codings the family always advertises to the major version that
+	// first advertised them, zero for all versions
+	Since map[string]int
+	// SecureOnly are codings only advertised to HTTPS origins
+	SecureOnly []string
+}
+
+// DefaultEncodingProfiles returns the Accept-Encoding profiles of current browsers, keyed by
+// browser family (see BrowserInfo.GetBrowserFamily). Chromium sends "gzip, deflate, br, zstd",
+// Firefox the same from version 126 and Safari "gzip, deflate, br"; Brotli and Zstandard are
+// only offered over HTTPS.
+func DefaultEncodingProfiles() map[string]EncodingProfile {
+	return map[string]EncodingProfile{
+		FamilyChromium: {
+			Since:      map[string]int{"gzip": 0, "deflate": 0, "br": 50, "zstd": 123},
+			SecureOnly: []string{"br", "zstd"},
+		},
+		FamilyGecko: {
+			Since:      map[string]int{"gzip": 0, "deflate": 0, "br": 44, "zstd": 126},
+			SecureOnly: []string{"br", "zstd"},
+		},
+		FamilyWebKit: {
+			Since:      map[string]int{"gzip": 0, "deflate": 0, "br": 11},
+			SecureOnly: []string{"br"},
+		},
+	}
+}
+
+// familyVersionTokens are the user agent tokens carrying the version of each family's engine,
+// as returned by engineVersions
+var familyVersionTokens = map[string]string{
+	FamilyChromium: "chrome",
+	FamilyGecko:    "firefox",
+	FamilyWebKit:   "version",
+}
+
+// ClaimedEncodings is the Accept-Encoding of a request with the codings the browser family its
+// user agent claims would send
+type ClaimedEncodings struct {
+	Family   string   `json:"family"`
+	Version  int      `json:"version,omitempty"`
+	Secure   bool     `json:"secure"`
+	Sent     []string `json:"sent"`
+	Expected []string `json:"expected"`
+}
+
+// getClaimedEncodings collects the Accept-Encoding of user agents whose browser family has a
+// profile, resolving the codings expected from the claimed version and the request's scheme
+func getClaimedEncodings(req *http.Request, profiles map[string]EncodingProfile) Component[ClaimedEncodings] {
+	if profiles == nil {
+		return ErrorComponent[ClaimedEncodings]{
+			State: StateUndefined,
+			Error: "Accept-Encoding profiles not configured",
+		}
+	}
+	userAgent := req.Header.Get("User-Agent")
+	name, _ := parseBrowserNameAndVersion(userAgent)
+	family := (*browserFamilies.Load())[name]
+	profile, ok := profiles[family]
+	if !ok {
+		return ErrorComponent[ClaimedEncodings]{
+			State: StateNull,
+			Error: "no Accept-Encoding profile for the user agent's browser family",
+		}
+	}
+
+	claim := ClaimedEncodings{
+		Family: family,
+		Secure: isSecureRequest(req),
+		Sent:   parseAcceptList(req.Header.Get("Accept-Encoding")),
+	}
+	if version, ok := engineVersions(userAgent)[familyVersionTokens[family]]; ok {
+		claim.Version = version[0]
+	}
+	for coding, since := range profile.Since {
+		if claim.Version < since || !claim.Secure && slices.Contains(profile.SecureOnly, coding) {
+			continue
+		}
+		claim.Expected = append(claim.Expected, coding)
+	}
+	slices.Sort(claim.Expected)
+
+	return SuccessComponent[ClaimedEncodings]{
+		State: StateSuccess,
+		Value: claim,
+	}
+}
+
+// detectEncodingPlausibility scores user agents claiming a browser whose Accept-Encoding lacks
+// codings that browser advertises, as HTTP libraries send only gzip or nothing
+func detectEncodingPlausibility(components *ComponentDict) *BotDetectionResult {
+	if components.ClaimedEncodings.GetState() != StateSuccess || components.UserAgent.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+	if isBot, _ := IsBotUserAgent(components.UserAgent.GetValue()); isBot {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	claim := components.ClaimedEncodings.GetValue()
+	var reasons []string
+	for _, coding := range claim.Expected {
+		if !slices.Contains(claim.Sent, coding) {
+			reasons = append(reasons, "Accept-Encoding of a "+claim.Family+" "+strconv.Itoa(claim.Version)+" user agent lacks "+coding)
+		}
+	}
+	if len(reasons) == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindUnknown,
+		Score:   0.5,
+		Reasons: reasons,
+	}
+}
@@ -0,0 +1,91 @@
+package gogobot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetClaimedEncodings(t *testing.T) {
+	profiles := DefaultEncodingProfiles()
+
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	if state := getClaimedEncodings(req, nil).GetState(); state != StateUndefined {
+		t.Errorf("Expected undefined without profiles, got %v", state)
+	}
+	claim := getClaimedEncodings(req, profiles).GetValue()
+	if claim.Family != FamilyChromium || claim.Version != 120 || strings.Join(claim.Expected, ",") != "deflate,gzip" {
+		t.Errorf("Expected gzip and deflate over HTTP for Chrome 120, got %+v", claim)
+	}
+
+	req.Header.Set("X-Forwarded-Proto", "https")
+	claim = getClaimedEncodings(req, profiles).GetValue()
+	if strings.Join(claim.Expected, ",") != "br,deflate,gzip" {
+		t.Errorf("Expected Brotli over HTTPS for Chrome 120, got %+v", claim)
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0")
+	claim = getClaimedEncodings(req, profiles).GetValue()
+	if claim.Version != 124 || strings.Join(claim.Expected, ",") != "br,deflate,gzip,zstd" {
+		t.Errorf("Expected Zstandard for Edge on Chromium 124, got %+v", claim)
+	}
+
+	req.Header.Set("User-Agent", "custom-client/1.0")
+	if state := getClaimedEncodings(req, profiles).GetState(); state != StateNull {
+		t.Errorf("Expected no profile for unknown browser, got %v", state)
+	}
+}
+
+func TestDetectEncodingPlausibility(t *testing.T) {
+	tests := []struct {
+		name           string
+		userAgent      string
+		acceptEncoding string
+		secure         bool
+		score          float64
+	}{
+		{"chrome over https", testBrowserUA, "gzip, deflate, br", true, 0},
+		{"chrome over http", testBrowserUA, "gzip, deflate", false, 0},
+		{"chrome with gzip only", testBrowserUA, "gzip", true, 0.5},
+		{"chrome without encodings", testBrowserUA, "", false, 0.5},
+		{"firefox", firefoxUA, "gzip, deflate, br", true, 0},
+		{"firefox without brotli", firefoxUA, "gzip, deflate", true, 0.5},
+		{"safari", safariUA, "gzip, deflate, br", true, 0},
+		{"declared crawler", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/120.0.0.0 Safari/537.36", "gzip", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newBrowserRequest("/", "203.0.113.7:1234")
+			req.Header.Set("User-Agent", tt.userAgent)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			if tt.secure {
+				req.Header.Set("X-Forwarded-Proto", "https")
+			}
+			components := &ComponentDict{
+				UserAgent:        getUserAgent(req),
+				ClaimedEncodings: getClaimedEncodings(req, DefaultEncodingProfiles()),
+			}
+			result := detectEncodingPlausibility(components)
+			if result.Bot || result.Score != tt.score {
+				t.Errorf("Expected score %v, got %+v", tt.score, result)
+			}
+		})
+	}
+}
+
+func TestDetector_EncodingPlausibility(t *testing.T) {
+	config := DefaultDetectorConfig()
+	profiles := DefaultEncodingProfiles()
+	// A site only seeing browsers over HTTPS behind a proxy not reporting the scheme
+	profiles[FamilyChromium] = EncodingProfile{Since: map[string]int{"gzip": 0, "br": 50}}
+	config.EncodingProfiles = profiles
+	detector := NewDetectorWithConfig(config)
+
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	detection := detector.GetDetections().EncodingPlausibility
+	if detection.Score != 0.5 || len(detection.Reasons) != 1 || detection.Reasons[0] != "Accept-Encoding of a chromium 120 user agent lacks br" {
+		t.Errorf("Expected missing Brotli signal, got %+v", detection)
+	}
+}
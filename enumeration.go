@@ -0,0 +1,223 @@
+package gogobot
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnumerationLimit configures detection of clients walking ID sequences or paginating far deeper
+// than people do
+type EnumerationLimit struct {
+	// MaxSequence is the number of consecutive IDs a visitor may request before it looks like
+	// enumeration
+	MaxSequence int
+	// MaxPageDepth is the deepest page number a visitor may request
+	MaxPageDepth int
+	// PageParams are the query parameters holding page numbers; "page" path segments are
+	// always recognized
+	PageParams []string
+	// Window is how long a sequence is remembered between requests; zero uses
+	// DefaultEnumerationWindow
+	Window time.Duration
+}
+
+// DefaultEnumerationWindow is how long an ID sequence is remembered between requests
+const DefaultEnumerationWindow = 10 * time.Minute
+
+// maxEnumerationStep is the largest ID increment recognized as walking a sequence
+const maxEnumerationStep = 10
+
+// DefaultEnumerationLimit flags visitors walking more than 20 consecutive IDs or requesting
+// pages deeper than 100
+func DefaultEnumerationLimit() *EnumerationLimit {
+	return &EnumerationLimit{
+		MaxSequence:  20,
+		MaxPageDepth: 100,
+		PageParams:   []string{"page", "p", "pg"},
+		Window:       DefaultEnumerationWindow,
+	}
+}
+
+// Enumeration is a visitor's walk through the ID sequence and pagination of the requested URL
+type Enumeration struct {
+	// Pattern is the requested URL with its ID replaced by "{id}", or empty without an ID
+	Pattern string `json:"pattern,omitempty"`
+	// Sequence is the number of consecutive IDs the visitor walked on Pattern
+	Sequence int `json:"sequence"`
+	// PagePattern is the requested URL with its page number replaced by "{n}", or empty
+	PagePattern string `json:"pagePattern,omitempty"`
+	// PageDepth is the requested page number
+	PageDepth int `json:"pageDepth"`
+	// MaxSequence and MaxPageDepth are the configured limits
+	MaxSequence  int `json:"maxSequence"`
+	MaxPageDepth int `json:"maxPageDepth"`
+}
+
+// parseAlphaID reads a short lowercase segment as a bijective base-26 number, so that "z" is
+// followed by "aa"
+func parseAlphaID(segment string) (int64, bool) {
+	if segment == "" || len(segment) > 3 {
+		return 0, false
+	}
+	var id int64
+	for _, c := range segment {
+		if c < 'a' || c > 'z' {
+			return 0, false
+		}
+		id = id*26 + int64(c-'a'+1)
+	}
+	return id, true
+}
+
+// pathID finds the last numeric path segment, or a trailing segment of up to three letters, and
+// returns the path with it replaced by "{id}". The segment following a "page" segment is a page
+// number instead.
+func pathID(path string) (pattern string, id int64, page int, ok bool) {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i > 0; i-- {
+		n, err := strconv.ParseInt(segments[i], 10, 64)
+		if err != nil || len(segments[i]) > 18 {
+			continue
+		}
+		if strings.EqualFold(segments[i-1], "page") {
+			return "", 0, int(n), false
+		}
+		segments[i] = "{id}"
+		return strings.Join(segments, "/"), n, 0, true
+	}
+	last := len(segments) - 1
+	if n, alpha := parseAlphaID(segments[last]); alpha && last > 1 {
+		segments[last] = "{id}"
+		return strings.Join(segments, "/"), n, 0, true
+	}
+	return "", 0, 0, false
+}
+
+// pageDepth returns the page number in a page query parameter or path segment, with the URL
+// pattern it was found in
+func pageDepth(req *http.Request, params []string) (string, int) {
+	query := req.URL.Query()
+	for _, param := range params {
+		if n, err := strconv.Atoi(query.Get(param)); err == nil && n > 0 {
+			return req.URL.Path + "?" + param + "={n}", n
+		}
+	}
+	if _, _, page, _ := pathID(req.URL.Path); page > 0 {
+		return req.URL.Path[:strings.LastIndexByte(req.URL.Path, '/')+1] + "{n}", page
+	}
+	return "", 0
+}
+
+// encodeEnumerationState records the last ID, the step between IDs and the sequence length
+func encodeEnumerationState(last, step int64, sequence int) []byte {
+	return []byte(strconv.FormatInt(last, 10) + "," + strconv.FormatInt(step, 10) + "," + strconv.Itoa(sequence))
+}
+
+func decodeEnumerationState(buf []byte) (last, step int64, sequence int, ok bool) {
+	parts := strings.Split(string(buf), ",")
+	if len(parts) != 3 {
+		return 0, 0, 0, false
+	}
+	last, err1 := strconv.ParseInt(parts[0], 10, 64)
+	step, err2 := strconv.ParseInt(parts[1], 10, 64)
+	sequence, err3 := strconv.Atoi(parts[2])
+	return last, step, sequence, err1 == nil && err2 == nil && err3 == nil
+}
+
+// getEnumeration extends the visitor's sequence for the requested URL pattern: a request for the
+// ID one step after the last, by the same step of at most maxEnumerationStep, continues it
+func getEnumeration(req *http.Request, components *ComponentDict, tracker *SessionTracker, limit *EnumerationLimit) Component[Enumeration] {
+	if tracker == nil || limit == nil {
+		return ErrorComponent[Enumeration]{
+			State: StateUndefined,
+			Error: "enumeration detection requires a session tracker",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[Enumeration]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	enumeration := Enumeration{
+		MaxSequence:  limit.MaxSequence,
+		MaxPageDepth: limit.MaxPageDepth,
+	}
+	enumeration.PagePattern, enumeration.PageDepth = pageDepth(req, limit.PageParams)
+
+	pattern, id, _, ok := pathID(req.URL.Path)
+	if !ok {
+		return SuccessComponent[Enumeration]{
+			State: StateSuccess,
+			Value: enumeration,
+		}
+	}
+	enumeration.Pattern = pattern
+
+	window := limit.Window
+	if window <= 0 {
+		window = DefaultEnumerationWindow
+	}
+	h := fnv.New64a()
+	h.Write([]byte(pattern))
+	storeKey := tracker.Prefix + "enum:" + key + ":" + strconv.FormatUint(h.Sum64(), 36)
+	state, found, err := tracker.store.Get(req.Context(), storeKey)
+	if err != nil {
+		return ErrorComponent[Enumeration]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+
+	var step int64
+	enumeration.Sequence = 1
+	if last, lastStep, sequence, valid := decodeEnumerationState(state); found && valid {
+		switch delta := id - last; {
+		case delta == 0:
+			step, enumeration.Sequence = lastStep, sequence
+		case delta == lastStep:
+			step, enumeration.Sequence = delta, sequence+1
+		case delta >= -maxEnumerationStep && delta <= maxEnumerationStep:
+			step, enumeration.Sequence = delta, 2
+		}
+	}
+	if err := tracker.store.Set(req.Context(), storeKey, encodeEnumerationState(id, step, enumeration.Sequence), window); err != nil {
+		return ErrorComponent[Enumeration]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	return SuccessComponent[Enumeration]{
+		State: StateSuccess,
+		Value: enumeration,
+	}
+}
+
+// detectEnumeration scores visitors over the sequence or page depth limits like
+// detectCrawlBreadth, naming the offending pattern in the reasons
+func detectEnumeration(components *ComponentDict) *BotDetectionResult {
+	if components.Enumeration.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	enumeration := components.Enumeration.GetValue()
+	result := &BotDetectionResult{Bot: false}
+	if enumeration.MaxSequence > 0 && enumeration.Sequence > enumeration.MaxSequence {
+		result.Score = min(0.5*float64(enumeration.Sequence)/float64(enumeration.MaxSequence), 1)
+		result.Reasons = append(result.Reasons, fmt.Sprintf("walked %d consecutive IDs of %s", enumeration.Sequence, enumeration.Pattern))
+	}
+	if enumeration.MaxPageDepth > 0 && enumeration.PageDepth > enumeration.MaxPageDepth {
+		result.Score = max(result.Score, min(0.5*float64(enumeration.PageDepth)/float64(enumeration.MaxPageDepth), 1))
+		result.Reasons = append(result.Reasons, fmt.Sprintf("requested page %d of %s", enumeration.PageDepth, enumeration.PagePattern))
+	}
+	if result.Score > 0 {
+		result.BotKind = BotKindScraper
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package gogobot
+
+import (
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestPathID(t *testing.T) {
+	tests := []struct {
+		path    string
+		pattern string
+		id      int64
+		page    int
+	}{
+		{"/item/1001", "/item/{id}", 1001, 0},
+		{"/item/1001/reviews", "/item/{id}/reviews", 1001, 0},
+		{"/users/7/posts/42", "/users/7/posts/{id}", 42, 0},
+		{"/names/ab", "/names/{id}", 28, 0},
+		{"/names/z", "/names/{id}", 26, 0},
+		{"/blog/page/7", "", 0, 7},
+		{"/en", "", 0, 0},
+		{"/about-us", "", 0, 0},
+		{"/names/abcd", "", 0, 0},
+	}
+	for _, tt := range tests {
+		pattern, id, page, _ := pathID(tt.path)
+		if pattern != tt.pattern || id != tt.id || page != tt.page {
+			t.Errorf("pathID(%q) = %q, %d, %d, expected %q, %d, %d", tt.path, pattern, id, page, tt.pattern, tt.id, tt.page)
+		}
+	}
+}
+
+func TestGetEnumeration(t *testing.T) {
+	tracker := NewSessionTracker(NewMemoryStore(0))
+	limit := DefaultEnumerationLimit()
+
+	collect := func(target string) Enumeration {
+		req := httptest.NewRequest("GET", target, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		enumeration := getEnumeration(req, collectAllSources(req), tracker, limit)
+		if enumeration.GetState() != StateSuccess {
+			t.Fatalf("Expected enumeration, got %v", enumeration)
+		}
+		return enumeration.GetValue()
+	}
+
+	var enumeration Enumeration
+	for id := 1001; id <= 1010; id++ {
+		enumeration = collect(fmt.Sprintf("/item/%d", id))
+	}
+	if enumeration.Pattern != "/item/{id}" || enumeration.Sequence != 10 || enumeration.MaxSequence != 20 {
+		t.Errorf("Expected a sequence of 10, got %+v", enumeration)
+	}
+
+	// Reloading keeps the sequence, other patterns have their own and a jump restarts it
+	if enumeration = collect("/item/1010"); enumeration.Sequence != 10 {
+		t.Errorf("Expected reload to keep the sequence, got %+v", enumeration)
+	}
+	if enumeration = collect("/user/5"); enumeration.Sequence != 1 {
+		t.Errorf("Expected a new pattern to start a sequence, got %+v", enumeration)
+	}
+	if enumeration = collect("/item/5000"); enumeration.Sequence != 1 {
+		t.Errorf("Expected a jump to restart the sequence, got %+v", enumeration)
+	}
+
+	// A constant step counts, including walking backwards
+	for id := 4990; id >= 4950; id -= 10 {
+		enumeration = collect(fmt.Sprintf("/item/%d", id))
+	}
+	if enumeration.Sequence != 6 {
+		t.Errorf("Expected a sequence of 6 with step -10, got %+v", enumeration)
+	}
+
+	if enumeration = collect("/list?page=150"); enumeration.PageDepth != 150 || enumeration.PagePattern != "/list?page={n}" || enumeration.Pattern != "" {
+		t.Errorf("Expected page depth from the query, got %+v", enumeration)
+	}
+	if enumeration = collect("/blog/page/120"); enumeration.PageDepth != 120 || enumeration.PagePattern != "/blog/page/{n}" {
+		t.Errorf("Expected page depth from the path, got %+v", enumeration)
+	}
+}
+
+func TestGetEnumeration_Undefined(t *testing.T) {
+	req := httptest.NewRequest("GET", "/item/1", nil)
+	if enumeration := getEnumeration(req, collectAllSources(req), nil, DefaultEnumerationLimit()); enumeration.GetState() != StateUndefined {
+		t.Errorf("Expected undefined enumeration without a tracker, got %v", enumeration.GetState())
+	}
+}
+
+func TestDetectEnumeration(t *testing.T) {
+	tests := []struct {
+		name        string
+		enumeration Enumeration
+		expected    float64
+		reasons     []string
+	}{
+		{"short sequence", Enumeration{Pattern: "/item/{id}", Sequence: 20, MaxSequence: 20, MaxPageDepth: 100}, 0, nil},
+		{"long sequence", Enumeration{Pattern: "/item/{id}", Sequence: 30, MaxSequence: 20, MaxPageDepth: 100}, 0.75,
+			[]string{"walked 30 consecutive IDs of /item/{id}"}},
+		{"deep pagination", Enumeration{PagePattern: "/list?page={n}", PageDepth: 400, MaxSequence: 20, MaxPageDepth: 100}, 1,
+			[]string{"requested page 400 of /list?page={n}"}},
+		{"disabled", Enumeration{Pattern: "/item/{id}", Sequence: 1000}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := &ComponentDict{Enumeration: SuccessComponent[Enumeration]{State: StateSuccess, Value: tt.enumeration}}
+			result := detectEnumeration(components)
+			if result.Bot || math.Abs(result.Score-tt.expected) > 1e-9 || !slices.Equal(result.Reasons, tt.reasons) {
+				t.Errorf("Expected score %f and reasons %q, got %+v", tt.expected, tt.reasons, result)
+			}
+			if tt.expected > 0 && result.BotKind != BotKindScraper {
+				t.Errorf("Expected scraper, got %q", result.BotKind)
+			}
+		})
+	}
+}
+
+func TestDetector_EnumerationReasons(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.EnumerationLimit = DefaultEnumerationLimit()
+	detector := NewDetectorWithConfig(config)
+
+	var result BotDetectionResult
+	for id := 1; id <= 40; id++ {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/product/%d", id), nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("User-Agent", testBrowserUA)
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Accept-Language", "en-US")
+		req.Header.Set("Accept-Encoding", "gzip")
+		var err error
+		result, err = detector.DetectFromRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !result.Bot || result.BotKind != BotKindScraper || !slices.Contains(result.Reasons, "walked 40 consecutive IDs of /product/{id}") {
+		t.Errorf("Expected enumeration to be flagged with its pattern, got %+v", result)
+	}
+}
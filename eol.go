@@ -0,0 +1,215 @@
+package gogobot
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+//go:embed data/browser_eol.json
+var embeddedEOLData []byte
+
+// BrowserEOLEntry records the end-of-life date for browser versions below a threshold
+type BrowserEOLEntry struct {
+	Browser BrowserName `json:"browser"`
+	// Below is the first version not covered by this entry
+	Below string `json:"below"`
+	// LastSupported is the last version line of the covered range that received security updates
+	LastSupported string `json:"lastSupported,omitempty"`
+	// EOL is the date (YYYY-MM-DD) after which covered versions no longer receive security updates
+	EOL string `json:"eol"`
+
+	eolTime time.Time
+}
+
+// BrowserReleaseTrain describes a browser shipping a new major on a fixed cadence, where only the
+// latest majors receive security updates. The latest major is estimated from a known release, so
+// the rule keeps flagging old versions without a dated entry per version.
+type BrowserReleaseTrain struct {
+	Browser BrowserName `json:"browser"`
+	// Major and Released are a stable release the estimate counts from
+	Major    int    `json:"major"`
+	Released string `json:"released"`
+	// CadenceDays is the average number of days between majors, including holiday gaps
+	CadenceDays int `json:"cadenceDays"`
+	// Supported is the number of latest majors considered supported
+	Supported int `json:"supported"`
+	// Extended lists older majors still supported on an extended release line, such as Firefox ESR
+	Extended []int `json:"extended,omitempty"`
+
+	releasedTime time.Time
+}
+
+// LatestMajor estimates the latest stable major at the given time
+func (t BrowserReleaseTrain) LatestMajor(at time.Time) int {
+	cadence := time.Duration(t.CadenceDays) * 24 * time.Hour
+	elapsed := at.Sub(t.releasedTime)
+	releases := int(elapsed / cadence)
+	if elapsed < 0 && elapsed%cadence != 0 {
+		releases--
+	}
+	return t.Major + releases
+}
+
+// outdated reports whether the major is older than the supported ones at the given time
+func (t BrowserReleaseTrain) outdated(major int, at time.Time) bool {
+	return major <= t.LatestMajor(at)-t.Supported && !slices.Contains(t.Extended, major)
+}
+
+// EOLDatabase is a table of browser end-of-life data used by IsOutdated: dated entries for
+// versions and browsers without a regular cadence, and release trains for rolling browsers
+type EOLDatabase struct {
+	Version string                `json:"version"`
+	Entries []BrowserEOLEntry     `json:"entries"`
+	Trains  []BrowserReleaseTrain `json:"trains,omitempty"`
+
+	byBrowser map[BrowserName][]BrowserEOLEntry
+	trains    map[BrowserName]BrowserReleaseTrain
+}
+
+// activeEOLDatabase holds the database used by IsOutdated
+var activeEOLDatabase atomic.Pointer[EOLDatabase]
+
+func init() {
+	db, err := ParseEOLDatabase(embeddedEOLData)
+	if err != nil {
+		panic(fmt.Sprintf("gogobot: invalid embedded EOL data: %v", err))
+	}
+	activeEOLDatabase.Store(db)
+}
+
+// ParseEOLDatabase parses a JSON EOL database of the form
+// {"version": ..., "entries": [...], "trains": [...]}
+func ParseEOLDatabase(data []byte) (*EOLDatabase, error) {
+	var db EOLDatabase
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("parse EOL database: %w", err)
+	}
+
+	db.byBrowser = make(map[BrowserName][]BrowserEOLEntry)
+	for i, entry := range db.Entries {
+		if entry.Browser == "" || entry.Below == "" {
+			return nil, fmt.Errorf("EOL entry %d: browser and below are required", i)
+		}
+		eol, err := time.Parse("2006-01-02", entry.EOL)
+		if err != nil {
+			return nil, fmt.Errorf("EOL entry %d: %w", i, err)
+		}
+		entry.eolTime = eol
+		db.byBrowser[entry.Browser] = append(db.byBrowser[entry.Browser], entry)
+	}
+
+	db.trains = make(map[BrowserName]BrowserReleaseTrain)
+	for i, train := range db.Trains {
+		if train.Browser == "" || train.Major <= 0 || train.CadenceDays <= 0 || train.Supported <= 0 {
+			return nil, fmt.Errorf("EOL train %d: browser, major, cadenceDays and supported are required", i)
+		}
+		released, err := time.Parse("2006-01-02", train.Released)
+		if err != nil {
+			return nil, fmt.Errorf("EOL train %d: %w", i, err)
+		}
+		train.releasedTime = released
+		db.trains[train.Browser] = train
+	}
+
+	// Narrowest ranges first so a version matches the entry closest to it
+	for _, entries := range db.byBrowser {
+		sort.Slice(entries, func(i, j int) bool {
+			return compareVersions(entries[i].Below, entries[j].Below) < 0
+		})
+	}
+
+	return &db, nil
+}
+
+// LoadEOLDatabase reads an EOL database from r and installs it for IsOutdated
+func LoadEOLDatabase(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	db, err := ParseEOLDatabase(data)
+	if err != nil {
+		return err
+	}
+	SetEOLDatabase(db)
+	return nil
+}
+
+// LoadEOLDatabaseFile reads an EOL database file and installs it for IsOutdated
+func LoadEOLDatabaseFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return LoadEOLDatabase(f)
+}
+
+// SetEOLDatabase installs the database used by IsOutdated. Passing nil restores the embedded data.
+func SetEOLDatabase(db *EOLDatabase) {
+	if db == nil {
+		db, _ = ParseEOLDatabase(embeddedEOLData)
+	}
+	activeEOLDatabase.Store(db)
+}
+
+// GetEOLDatabase returns the database currently used by IsOutdated
+func GetEOLDatabase() *EOLDatabase {
+	return activeEOLDatabase.Load()
+}
+
+// Lookup returns the EOL entry covering the browser's version, if any
+func (db *EOLDatabase) Lookup(browserInfo BrowserInfo) (BrowserEOLEntry, bool) {
+	if browserInfo.Version == "" {
+		return BrowserEOLEntry{}, false
+	}
+
+	for _, entry := range db.byBrowser[browserInfo.Name] {
+		if compareVersions(browserInfo.Version, entry.Below) < 0 {
+			return entry, true
+		}
+	}
+	return BrowserEOLEntry{}, false
+}
+
+// Train returns the release train of the browser, if any
+func (db *EOLDatabase) Train(browser BrowserName) (BrowserReleaseTrain, bool) {
+	train, ok := db.trains[browser]
+	return train, ok
+}
+
+// IsOutdated reports whether the browser version has reached end of life and no longer
+// receives security updates, according to the installed EOL database: its dated entry has
+// passed, or it is older than the supported majors of the browser's release train
+func IsOutdated(browserInfo BrowserInfo) bool {
+	return IsOutdatedAt(browserInfo, time.Now())
+}
+
+// IsOutdatedAt reports whether the browser version had reached end of life at the given time
+func IsOutdatedAt(browserInfo BrowserInfo, at time.Time) bool {
+	if browserInfo.IsBot() {
+		return false
+	}
+
+	db := GetEOLDatabase()
+	if entry, ok := db.Lookup(browserInfo); ok && !at.Before(entry.eolTime) {
+		return true
+	}
+	train, ok := db.Train(browserInfo.Name)
+	if !ok {
+		return false
+	}
+	major, err := strconv.Atoi(browserInfo.GetMajorVersion())
+	if err != nil {
+		return false
+	}
+	return train.outdated(major, at)
+}
@@ -0,0 +1,170 @@
+package gogobot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsOutdatedAt(t *testing.T) {
+	at := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		userAgent string
+		expected  bool
+	}{
+		{
+			name:      "Internet Explorer 11",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Trident/7.0; rv:11.0) like Gecko",
+			expected:  true,
+		},
+		{
+			name:      "Chrome 108",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.5359.125 Safari/537.36",
+			expected:  true,
+		},
+		{
+			name:      "Chrome 120 behind the release train",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:  true,
+		},
+		{
+			name:      "Chrome 139 within the supported majors",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36",
+			expected:  false,
+		},
+		{
+			name:      "Edge 130",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36 Edg/130.0.0.0",
+			expected:  true,
+		},
+		{
+			name:      "Firefox 137",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:137.0) Gecko/20100101 Firefox/137.0",
+			expected:  true,
+		},
+		{
+			name:      "Firefox ESR 128",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:128.0) Gecko/20100101 Firefox/128.0",
+			expected:  false,
+		},
+		{
+			name:      "Firefox ESR 115 after EOL",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:115.0) Gecko/20100101 Firefox/115.0",
+			expected:  true,
+		},
+		{
+			name:      "Safari 17",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			expected:  true,
+		},
+		{
+			name:      "Safari 26",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.0 Safari/605.1.15",
+			expected:  false,
+		},
+		{
+			name:      "Bot",
+			userAgent: "curl/7.68.0",
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			browserInfo := ParseBrowserFromUserAgent(tt.userAgent)
+			if result := IsOutdatedAt(browserInfo, at); result != tt.expected {
+				t.Errorf("Expected IsOutdatedAt=%t for %s %s, got %t", tt.expected, browserInfo.Name, browserInfo.Version, result)
+			}
+		})
+	}
+
+	firefox115 := BrowserInfo{Name: BrowserFirefox, Version: "115.0"}
+	if IsOutdatedAt(firefox115, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected Firefox 115 to be supported before its EOL date")
+	}
+}
+
+func TestLoadEOLDatabase(t *testing.T) {
+	defer SetEOLDatabase(nil)
+
+	data := `{"version": "test", "entries": [{"browser": "Chrome", "below": "130", "eol": "2020-01-01"}]}`
+	if err := LoadEOLDatabase(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadEOLDatabase returned error: %v", err)
+	}
+	if GetEOLDatabase().Version != "test" {
+		t.Errorf("Expected loaded database version test, got %s", GetEOLDatabase().Version)
+	}
+
+	chrome120 := BrowserInfo{Name: BrowserChrome, Version: "120.0.0.0"}
+	if !IsOutdated(chrome120) {
+		t.Error("Expected Chrome 120 to be outdated with the loaded database")
+	}
+
+	entry, ok := GetEOLDatabase().Lookup(chrome120)
+	if !ok || entry.Below != "130" {
+		t.Errorf("Expected lookup to return the loaded entry, got %+v", entry)
+	}
+
+	SetEOLDatabase(nil)
+	if GetEOLDatabase().Version == "test" {
+		t.Error("Expected embedded database to be restored")
+	}
+}
+
+func TestBrowserReleaseTrain(t *testing.T) {
+	db, err := ParseEOLDatabase([]byte(`{"version": "test", "entries": [], "trains": [
+		{"browser": "Chrome", "major": 140, "released": "2025-09-02", "cadenceDays": 28, "supported": 2}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseEOLDatabase returned error: %v", err)
+	}
+	train, ok := db.Train(BrowserChrome)
+	if !ok {
+		t.Fatal("Expected the Chrome release train")
+	}
+
+	tests := []struct {
+		at       time.Time
+		expected int
+	}{
+		{time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC), 140},
+		{time.Date(2025, 9, 29, 0, 0, 0, 0, time.UTC), 140},
+		{time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC), 141},
+		{time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), 153},
+		{time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), 139},
+		{time.Date(2025, 8, 6, 0, 0, 0, 0, time.UTC), 139},
+	}
+	for _, tt := range tests {
+		if got := train.LatestMajor(tt.at); got != tt.expected {
+			t.Errorf("Expected latest major %d at %s, got %d", tt.expected, tt.at.Format("2006-01-02"), got)
+		}
+	}
+
+	// Outdated versions keep being flagged as the train moves on, without new entries
+	defer SetEOLDatabase(nil)
+	SetEOLDatabase(db)
+	at := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	if !IsOutdatedAt(BrowserInfo{Name: BrowserChrome, Version: "151.0.0.0"}, at) {
+		t.Error("Expected Chrome 151 to be outdated two majors behind 153")
+	}
+	if IsOutdatedAt(BrowserInfo{Name: BrowserChrome, Version: "152.0.0.0"}, at) {
+		t.Error("Expected Chrome 152 to be supported")
+	}
+}
+
+func TestParseEOLDatabase_Invalid(t *testing.T) {
+	invalid := []string{
+		`not json`,
+		`{"entries": [{"browser": "Chrome", "eol": "2020-01-01"}]}`,
+		`{"entries": [{"browser": "Chrome", "below": "100", "eol": "January 2020"}]}`,
+		`{"entries": [], "trains": [{"browser": "Chrome", "major": 140, "released": "2025-09-02", "supported": 2}]}`,
+	}
+
+	for _, data := range invalid {
+		if _, err := ParseEOLDatabase([]byte(data)); err == nil {
+			t.Errorf("Expected error for %s", data)
+		}
+	}
+}
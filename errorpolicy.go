@@ -0,0 +1,81 @@
+package gogobot
+
+import (
+	"errors"
+	"net/http"
+)
+
+// DetectionErrorHeader marks requests served without a reliable verdict under
+// FailOpenWithHeader
+const DetectionErrorHeader = "X-Bot-Detection-Error"
+
+// ErrorPolicy selects what the middleware does with a request it could not run detection on:
+// collection failed, or a Store or reputation checker the detectors depend on could not be
+// reached. OnError, when set, handles these requests instead.
+type ErrorPolicy string
+
+const (
+	// FailOpen serves the request as usual, the default; it suits public content. Backend
+	// failures only leave the detectors depending on them without a signal.
+	FailOpen ErrorPolicy = "fail_open"
+	// FailClosed refuses the request with 503 Service Unavailable, for security-sensitive
+	// routes such as logins and checkouts
+	FailClosed ErrorPolicy = "fail_closed"
+	// FailOpenWithHeader serves the request without a verdict with DetectionErrorHeader set on
+	// it, so handlers and upstreams can tell unverified requests apart
+	FailOpenWithHeader ErrorPolicy = "fail_open_with_header"
+)
+
+// strict reports whether backend failures are detection errors under the policy
+func (p ErrorPolicy) strict() bool {
+	return p == FailClosed || p == FailOpenWithHeader
+}
+
+// backendError returns the failure of the first component collected from a Store or the
+// reputation checker. Components failing on malformed client input are not backend failures.
+func (c *ComponentDict) backendError() error {
+	backends := []struct {
+		name      string
+		component interface {
+			GetState() State
+			GetError() string
+		}
+	}{
+		{"session", c.Session},
+		{"request rates", c.RequestRates},
+		{"crawl breadth", c.CrawlBreadth},
+		{"request timing", c.RequestTiming},
+		{"robots compliance", c.RobotsCompliance},
+		{"enumeration", c.Enumeration},
+		{"error rate", c.ErrorRate},
+		{"login failures", c.LoginFailures},
+		{"h3 usage", c.H3Usage},
+		{"cookie session", c.CookieSession},
+		{"honeypot", c.Trapped},
+		{"cookie challenge", c.CookieChallenge},
+		{"reputation", c.Reputation},
+	}
+	for _, backend := range backends {
+		if backend.component != nil && backend.component.GetState() == StateUnexpectedBehaviour {
+			return errors.New(backend.name + ": " + backend.component.GetError())
+		}
+	}
+	return nil
+}
+
+// failDetection handles a request detection failed on according to the ErrorPolicy
+func failDetection(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, next http.Handler) {
+	switch config.ErrorPolicy {
+	case FailClosed:
+		refuse(w, req, config, BlockedData{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Bot detection is unavailable",
+			Result:     &BotDetectionResult{},
+		})
+	case FailOpenWithHeader:
+		req.Header.Set(DetectionErrorHeader, "1")
+		next.ServeHTTP(w, req)
+	default:
+		next.ServeHTTP(w, req)
+	}
+}
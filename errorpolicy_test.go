@@ -0,0 +1,107 @@
+package gogobot
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware_ErrorPolicy(t *testing.T) {
+	store := newMapStore()
+	store.err = errors.New("connection refused")
+	detector := NewDetectorWithConfig(DetectorConfig{SessionTracker: NewSessionTracker(store)})
+
+	tests := []struct {
+		policy ErrorPolicy
+		status int
+		header string
+	}{
+		{"", http.StatusOK, ""},
+		{FailOpen, http.StatusOK, ""},
+		{FailClosed, http.StatusServiceUnavailable, ""},
+		{FailOpenWithHeader, http.StatusOK, "1"},
+	}
+	for _, tt := range tests {
+		config := DefaultMiddlewareConfig()
+		config.ErrorPolicy = tt.policy
+		var header string
+		var verdict bool
+		handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get(DetectionErrorHeader)
+			_, verdict = GetResultFromContext(r.Context())
+		}))
+
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set(DetectionErrorHeader, "spoofed")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%q: expected %d, got %d", tt.policy, tt.status, rec.Code)
+		}
+		if tt.policy == FailOpenWithHeader && (header != tt.header || verdict) {
+			t.Errorf("%q: expected the request marked and without a verdict, got %q %v", tt.policy, header, verdict)
+		}
+		if tt.policy == FailOpen && !verdict {
+			t.Errorf("%q: expected a verdict without the failed signals", tt.policy)
+		}
+	}
+}
+
+func TestMiddleware_ErrorPolicyHealthyStore(t *testing.T) {
+	detector := NewDetectorWithConfig(DetectorConfig{SessionTracker: NewSessionTracker(newMapStore())})
+	config := DefaultMiddlewareConfig()
+	config.ErrorPolicy = FailClosed
+	handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newBrowserRequest("/", "203.0.113.7:1234"))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected requests to be served while the store works, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware_ErrorPolicyOnError(t *testing.T) {
+	store := newMapStore()
+	store.err = errors.New("connection refused")
+	detector := NewDetectorWithConfig(DetectorConfig{SessionTracker: NewSessionTracker(store)})
+	config := DefaultMiddlewareConfig()
+	config.ErrorPolicy = FailClosed
+	var reported error
+	config.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		reported = err
+		w.WriteHeader(http.StatusTeapot)
+	}
+	handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newBrowserRequest("/", "203.0.113.7:1234"))
+	if rec.Code != http.StatusTeapot || reported == nil {
+		t.Errorf("Expected OnError to handle the failure, got %d %v", rec.Code, reported)
+	}
+}
+
+func TestMiddleware_ErrorPolicyPendingReputation(t *testing.T) {
+	provider := &fakeReputationProvider{release: make(chan struct{})}
+	defer close(provider.release)
+	reputationConfig := DefaultReputationCheckerConfig()
+	reputationConfig.Timeout = time.Millisecond
+	detector := NewDetectorWithConfig(DetectorConfig{ReputationChecker: NewReputationChecker(provider, reputationConfig)})
+
+	for _, policy := range []ErrorPolicy{FailClosed, FailOpenWithHeader} {
+		config := DefaultMiddlewareConfig()
+		config.ErrorPolicy = policy
+		var header string
+		handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get(DetectionErrorHeader)
+		}))
+
+		// The lookup of a new address outlasts the timeout, which is not a feed failure
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newBrowserRequest("/", "203.0.113.7:1234"))
+		if rec.Code != http.StatusOK || header != "" {
+			t.Errorf("%q: expected a pending lookup to be served unmarked, got %d %q", policy, rec.Code, header)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// ErrorRateLimit configures flagging of clients whose requests mostly end in error responses,
+// typical of vulnerability scanners and broken scrapers guessing URLs. Response statuses are
+// recorded by the middleware, or by RecordResponse without it.
+type ErrorRateLimit struct {
+	// Statuses are the response statuses counted as errors
+	Statuses []int
+	// MinResponses is the number of responses in a window below which the rate is not judged
+	MinResponses int64
+	// MaxErrorRate is the share of error responses above which the client is suspicious
+	MaxErrorRate float64
+	// Window is the length of the fixed counting window; zero uses DefaultSessionWindow
+	Window time.Duration
+}
+
+// DefaultErrorRateLimit flags clients for which more than half of at least 20 responses in five
+// minutes were 401, 403, 404, 405 or 410
+func DefaultErrorRateLimit() *ErrorRateLimit {
+	return &ErrorRateLimit{
+		Statuses:     []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusGone},
+		MinResponses: 20,
+		MaxErrorRate: 0.5,
+		Window:       5 * time.Minute,
+	}
+}
+
+// ErrorRate is the number of responses and error responses a visitor received in the current
+// window, before the current request
+type ErrorRate struct {
+	Responses    int64         `json:"responses"`
+	Errors       int64         `json:"errors"`
+	MinResponses int64         `json:"minResponses"`
+	MaxErrorRate float64       `json:"maxErrorRate"`
+	Window       time.Duration `json:"window"`
+}
+
+// Rate returns the share of error responses, or 0 without responses
+func (e ErrorRate) Rate() float64 {
+	if e.Responses == 0 {
+		return 0
+	}
+	return float64(e.Errors) / float64(e.Responses)
+}
+
+// errorRateKeys returns the store keys counting the visitor's responses and errors in the
+// current window
+func errorRateKeys(tracker *SessionTracker, key string, window time.Duration) (responses, errors string) {
+	bucket := windowBucket(tracker.now(), window)
+	return tracker.Prefix + "responses:" + key + ":" + bucket, tracker.Prefix + "errors:" + key + ":" + bucket
+}
+
+// window returns the counting window
+func (l *ErrorRateLimit) window() time.Duration {
+	if l.Window <= 0 {
+		return DefaultSessionWindow
+	}
+	return l.Window
+}
+
+// RecordResponse counts a response status for the request's visitor, for applications not
+// using the middleware. It does nothing without an ErrorRateLimit and SessionTracker.
+func (d *BotDetector) RecordResponse(req *http.Request, status int) error {
+	return d.recordResponse(req, nil, status)
+}
+
+func (d *BotDetector) recordResponse(req *http.Request, components *ComponentDict, status int) error {
+	limit, tracker := d.config.ErrorRateLimit, d.config.SessionTracker
+	if limit == nil || tracker == nil {
+		return nil
+	}
+	key := d.visitorKey(req, components)
+	if key == "" {
+		return errNoSessionKey
+	}
+
+	window := limit.window()
+	responsesKey, errorsKey := errorRateKeys(tracker, key, window)
+	if _, err := tracker.store.Incr(req.Context(), responsesKey, 1, 2*window); err != nil {
+		return err
+	}
+	if slices.Contains(limit.Statuses, status) {
+		if _, err := tracker.store.Incr(req.Context(), errorsKey, 1, 2*window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusRecorder captures the status written by the wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher when the wrapped writer does
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// serveRecorded serves the request with next, recording the response status for error-rate
+// detection and HTTP/3 advertisements. Store errors are ignored, as they would be reported on
+// the next request anyway.
+func (d *BotDetector) serveRecorded(w http.ResponseWriter, req *http.Request, components *ComponentDict, next http.Handler) {
+	// Upgraded connections hijack the writer, and their responses are not page loads
+	if d.config.ErrorRateLimit == nil && d.config.H3Adoption == nil || d.config.SessionTracker == nil || isUpgradeRequest(req) {
+		next.ServeHTTP(w, req)
+		return
+	}
+	if d.config.H3Adoption != nil {
+		d.config.H3Adoption.advertiseH3(w)
+	}
+	recorder := &statusRecorder{ResponseWriter: w}
+	next.ServeHTTP(recorder, req)
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	d.recordResponse(req, components, recorder.status)
+	d.recordAltSvc(req, components, w.Header())
+}
+
+// getErrorRate reads the visitor's response counts in the current window
+func getErrorRate(req *http.Request, components *ComponentDict, tracker *SessionTracker, limit *ErrorRateLimit) Component[ErrorRate] {
+	if tracker == nil || limit == nil {
+		return ErrorComponent[ErrorRate]{
+			State: StateUndefined,
+			Error: "error rate requires a session tracker and limit",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[ErrorRate]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	window := limit.window()
+	responsesKey, errorsKey := errorRateKeys(tracker, key, window)
+	rate := ErrorRate{
+		MinResponses: limit.MinResponses,
+		MaxErrorRate: limit.MaxErrorRate,
+		Window:       window,
+	}
+	var err error
+	if rate.Responses, err = tracker.store.Incr(req.Context(), responsesKey, 0, 2*window); err == nil {
+		rate.Errors, err = tracker.store.Incr(req.Context(), errorsKey, 0, 2*window)
+	}
+	if err != nil {
+		return ErrorComponent[ErrorRate]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	return SuccessComponent[ErrorRate]{
+		State: StateSuccess,
+		Value: rate,
+	}
+}
+
+// detectErrorRate scores visitors whose error share exceeds the limit by the ratio of the two,
+// so that with the default limit of one half, 80% errors flag a visitor on their own
+func detectErrorRate(components *ComponentDict) *BotDetectionResult {
+	if components.ErrorRate.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	rate := components.ErrorRate.GetValue()
+	if rate.MaxErrorRate <= 0 || rate.Responses < rate.MinResponses || rate.Rate() <= rate.MaxErrorRate {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindScanner,
+		Score:   min(0.5*rate.Rate()/rate.MaxErrorRate, 1),
+		Reasons: []string{fmt.Sprintf("%d of %d responses were errors", rate.Errors, rate.Responses)},
+	}
+}
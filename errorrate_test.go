@@ -0,0 +1,124 @@
+package gogobot
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectErrorRate(t *testing.T) {
+	limit := ErrorRate{MinResponses: 20, MaxErrorRate: 0.5}
+	tests := []struct {
+		name      string
+		responses int64
+		errors    int64
+		expected  float64
+	}{
+		{"too few responses", 10, 10, 0},
+		{"at the limit", 40, 20, 0},
+		{"mostly errors", 40, 28, 0.7},
+		{"only errors", 40, 40, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := limit
+			rate.Responses, rate.Errors = tt.responses, tt.errors
+			result := detectErrorRate(&ComponentDict{ErrorRate: SuccessComponent[ErrorRate]{State: StateSuccess, Value: rate}})
+			if result.Bot || math.Abs(result.Score-tt.expected) > 1e-9 {
+				t.Errorf("Expected score %f, got %+v", tt.expected, result)
+			}
+			if tt.expected > 0 && (result.BotKind != BotKindScanner || len(result.Reasons) != 1) {
+				t.Errorf("Expected scanner with a reason, got %+v", result)
+			}
+		})
+	}
+}
+
+func newErrorRateDetector() *BotDetector {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.ErrorRateLimit = DefaultErrorRateLimit()
+	return NewDetectorWithConfig(config)
+}
+
+func newBrowserRequest(target, remoteAddr string) *http.Request {
+	req := httptest.NewRequest("GET", target, nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("User-Agent", testBrowserUA)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Language", "en-US")
+	req.Header.Set("Accept-Encoding", "gzip")
+	return req
+}
+
+func TestMiddleware_ErrorRate(t *testing.T) {
+	detector := newErrorRateDetector()
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/old-") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	serve := func(target, remoteAddr string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newBrowserRequest(target, remoteAddr))
+		return rec.Code
+	}
+
+	// A scraper with browser headers guessing URLs
+	for i := range 20 {
+		if code := serve(fmt.Sprintf("/old-%d", i), "203.0.113.7:1234"); code != http.StatusNotFound {
+			t.Fatalf("Expected guesses to reach the handler, got %d", code)
+		}
+	}
+	if code := serve("/", "203.0.113.7:1234"); code != http.StatusForbidden {
+		t.Errorf("Expected scanner to be blocked, got %d", code)
+	}
+
+	// A visitor hitting the odd missing page is fine
+	for i := range 25 {
+		target := "/"
+		if i%5 == 0 {
+			target = "/old-page"
+		}
+		if code := serve(target, "198.51.100.9:1234"); code == http.StatusForbidden {
+			t.Fatalf("Expected regular visitor to be served, got %d on request %d", code, i)
+		}
+	}
+}
+
+func TestRecordResponse(t *testing.T) {
+	detector := newErrorRateDetector()
+	for range 30 {
+		if err := detector.RecordResponse(newBrowserRequest("/x", "203.0.113.7:1234"), http.StatusForbidden); err != nil {
+			t.Fatal(err)
+		}
+	}
+	components, _ := detector.Collect(newBrowserRequest("/", "203.0.113.7:1234"))
+	rate := components.ErrorRate.GetValue()
+	if rate.Responses != 30 || rate.Errors != 30 || rate.Window != 5*time.Minute {
+		t.Errorf("Expected 30 recorded errors, got %+v", rate)
+	}
+	if err := NewDetector().RecordResponse(newBrowserRequest("/", "203.0.113.7:1234"), http.StatusOK); err != nil {
+		t.Errorf("Expected recording without a limit to do nothing, got %v", err)
+	}
+}
+
+func TestStatusRecorder(t *testing.T) {
+	rec := httptest.NewRecorder()
+	recorder := &statusRecorder{ResponseWriter: rec}
+	recorder.Flush()
+	recorder.WriteHeader(http.StatusTeapot)
+	if recorder.status != http.StatusOK || !rec.Flushed {
+		t.Errorf("Expected flush to commit 200, got %d", recorder.status)
+	}
+	if recorder.Unwrap() != rec {
+		t.Error("Expected Unwrap to return the wrapped writer")
+	}
+}
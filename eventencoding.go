@@ -0,0 +1,158 @@
+package gogobot
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// EventAvroSchema is the Avro schema of the records AvroEventEncoder writes, for registering
+// with a schema registry
+//
+//go:embed data/event.avsc
+var EventAvroSchema string
+
+// EventEncoder serializes events for exporters
+type EventEncoder interface {
+	// Encode serializes the event
+	Encode(Event) ([]byte, error)
+	// ContentType is the media type of the encoded events
+	ContentType() string
+}
+
+// eventRecord is the flat form of an Event both encoders write, with the fields of
+// EventAvroSchema
+type eventRecord struct {
+	Time           time.Time `json:"time"`
+	ClientIP       string    `json:"clientIp"`
+	Method         string    `json:"method"`
+	Host           string    `json:"host"`
+	Path           string    `json:"path"`
+	UserAgent      string    `json:"userAgent"`
+	Bot            bool      `json:"bot"`
+	BotKind        string    `json:"botKind"`
+	Category       string    `json:"category"`
+	Score          float64   `json:"score"`
+	Reasons        []string  `json:"reasons"`
+	Verified       bool      `json:"verified"`
+	Country        string    `json:"country"`
+	ASN            int64     `json:"asn"`
+	Datacenter     bool      `json:"datacenter"`
+	Action         string    `json:"action"`
+	DurationMicros int64     `json:"durationMicros"`
+	Cached         bool      `json:"cached"`
+	Error          *string   `json:"error"`
+}
+
+func newEventRecord(event Event) eventRecord {
+	record := eventRecord{
+		Time:           event.Time,
+		Method:         event.Method,
+		Host:           event.Host,
+		Path:           event.Path,
+		UserAgent:      event.UserAgent,
+		Bot:            event.Result.Bot,
+		Score:          event.Result.Score,
+		Reasons:        event.Result.Reasons,
+		Verified:       event.Result.Verified,
+		Country:        event.Result.Country,
+		ASN:            int64(event.Result.ASN),
+		Datacenter:     event.Result.IsDatacenter,
+		Action:         string(event.Action),
+		DurationMicros: event.Duration.Microseconds(),
+		Cached:         event.Cached,
+	}
+	if event.ClientIP.IsValid() {
+		record.ClientIP = event.ClientIP.String()
+	}
+	if event.Result.Bot {
+		record.BotKind = string(event.Result.BotKind)
+		record.Category = string(event.Result.BotKind.Category())
+	}
+	if record.Reasons == nil {
+		record.Reasons = []string{}
+	}
+	if event.Err != nil {
+		message := event.Err.Error()
+		record.Error = &message
+	}
+	return record
+}
+
+// JSONEventEncoder encodes events as JSON objects with the fields of EventAvroSchema, the time
+// in RFC 3339 format
+type JSONEventEncoder struct{}
+
+// Encode implements EventEncoder
+func (JSONEventEncoder) Encode(event Event) ([]byte, error) {
+	return json.Marshal(newEventRecord(event))
+}
+
+// ContentType implements EventEncoder
+func (JSONEventEncoder) ContentType() string {
+	return "application/json"
+}
+
+// AvroEventEncoder encodes events in the Avro binary encoding of EventAvroSchema, without a
+// container file header or registry framing, which the exporter or consumer agree on
+type AvroEventEncoder struct{}
+
+// Encode implements EventEncoder
+func (AvroEventEncoder) Encode(event Event) ([]byte, error) {
+	record := newEventRecord(event)
+	b := make([]byte, 0, 256)
+	b = appendAvroLong(b, record.Time.UnixMicro())
+	for _, s := range []string{record.ClientIP, record.Method, record.Host, record.Path, record.UserAgent} {
+		b = appendAvroString(b, s)
+	}
+	b = appendAvroBool(b, record.Bot)
+	b = appendAvroString(b, record.BotKind)
+	b = appendAvroString(b, record.Category)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(record.Score))
+	// Arrays are written as one block of all items, then the empty block ending them
+	if len(record.Reasons) > 0 {
+		b = appendAvroLong(b, int64(len(record.Reasons)))
+		for _, reason := range record.Reasons {
+			b = appendAvroString(b, reason)
+		}
+	}
+	b = appendAvroLong(b, 0)
+	b = appendAvroBool(b, record.Verified)
+	b = appendAvroString(b, record.Country)
+	b = appendAvroLong(b, record.ASN)
+	b = appendAvroBool(b, record.Datacenter)
+	b = appendAvroString(b, record.Action)
+	b = appendAvroLong(b, record.DurationMicros)
+	b = appendAvroBool(b, record.Cached)
+	// The error is a union of null (branch 0) and string (branch 1)
+	if record.Error == nil {
+		b = appendAvroLong(b, 0)
+	} else {
+		b = appendAvroLong(b, 1)
+		b = appendAvroString(b, *record.Error)
+	}
+	return b, nil
+}
+
+// ContentType implements EventEncoder
+func (AvroEventEncoder) ContentType() string {
+	return "avro/binary"
+}
+
+// appendAvroLong appends a zigzag-encoded variable-length long
+func appendAvroLong(b []byte, n int64) []byte {
+	return binary.AppendUvarint(b, uint64(n<<1)^uint64(n>>63))
+}
+
+func appendAvroString(b []byte, s string) []byte {
+	return append(appendAvroLong(b, int64(len(s))), s...)
+}
+
+func appendAvroBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
@@ -0,0 +1,182 @@
+package gogobot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func testEvent() Event {
+	return Event{
+		Time:      time.Date(2026, 10, 1, 12, 0, 0, 123456000, time.UTC),
+		ClientIP:  netip.MustParseAddr("203.0.113.7"),
+		Method:    "GET",
+		Host:      "shop.example",
+		Path:      "/products",
+		UserAgent: "curl/8.4.0",
+		Result:    BotDetectionResult{Bot: true, BotKind: BotKindCurl, Reasons: []string{"tool user agent", "no Accept-Language"}, ASN: 64500},
+		Action:    ActionBlock,
+		Duration:  1500 * time.Microsecond,
+	}
+}
+
+// avroSchema is the part of an Avro record schema the tests read
+type avroSchema struct {
+	Fields []struct {
+		Name string          `json:"name"`
+		Type json.RawMessage `json:"type"`
+	} `json:"fields"`
+}
+
+// avroDecoder reads the Avro binary encoding of the types EventAvroSchema uses
+type avroDecoder struct {
+	t *testing.T
+	b []byte
+}
+
+func (d *avroDecoder) long() int64 {
+	u, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.t.Fatalf("Invalid long at %v", d.b)
+	}
+	d.b = d.b[n:]
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (d *avroDecoder) string() string {
+	n := d.long()
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
+
+// value decodes a value of the schema type
+func (d *avroDecoder) value(schemaType json.RawMessage) any {
+	var name string
+	if json.Unmarshal(schemaType, &name) != nil {
+		var union []string
+		if json.Unmarshal(schemaType, &union) == nil {
+			branch := d.long()
+			if union[branch] == "null" {
+				return nil
+			}
+			name = union[branch]
+		} else {
+			var complex struct {
+				Type  string `json:"type"`
+				Items string `json:"items"`
+			}
+			json.Unmarshal(schemaType, &complex)
+			if complex.Type == "array" {
+				items := []any{}
+				for n := d.long(); n != 0; n = d.long() {
+					for range n {
+						items = append(items, d.value(json.RawMessage(`"`+complex.Items+`"`)))
+					}
+				}
+				return items
+			}
+			name = complex.Type
+		}
+	}
+	switch name {
+	case "long":
+		return d.long()
+	case "string":
+		return d.string()
+	case "boolean":
+		v := d.b[0] == 1
+		d.b = d.b[1:]
+		return v
+	case "double":
+		v := math.Float64frombits(binary.LittleEndian.Uint64(d.b))
+		d.b = d.b[8:]
+		return v
+	}
+	d.t.Fatalf("Unsupported type %s", schemaType)
+	return nil
+}
+
+func TestAvroEventEncoder(t *testing.T) {
+	var schema avroSchema
+	if err := json.Unmarshal([]byte(EventAvroSchema), &schema); err != nil {
+		t.Fatalf("Invalid schema: %v", err)
+	}
+
+	event := testEvent()
+	event.Err = errors.New("store unreachable")
+	encoded, err := AvroEventEncoder{}.Encode(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := &avroDecoder{t: t, b: encoded}
+	record := map[string]any{}
+	for _, field := range schema.Fields {
+		record[field.Name] = decoder.value(field.Type)
+	}
+	if len(decoder.b) != 0 {
+		t.Errorf("Expected the record to be fully decoded, %d bytes left", len(decoder.b))
+	}
+
+	expected := map[string]any{
+		"time":           event.Time.UnixMicro(),
+		"clientIp":       "203.0.113.7",
+		"host":           "shop.example",
+		"userAgent":      "curl/8.4.0",
+		"bot":            true,
+		"botKind":        "curl",
+		"category":       "automation",
+		"asn":            int64(64500),
+		"action":         "block",
+		"durationMicros": int64(1500),
+		"cached":         false,
+		"error":          "store unreachable",
+	}
+	for name, want := range expected {
+		if record[name] != want {
+			t.Errorf("%s: expected %v, got %v", name, want, record[name])
+		}
+	}
+	if reasons, _ := record["reasons"].([]any); len(reasons) != 2 || reasons[1] != "no Accept-Language" {
+		t.Errorf("Unexpected reasons %v", record["reasons"])
+	}
+
+	// A human without reasons or error
+	encoded, _ = AvroEventEncoder{}.Encode(Event{Time: event.Time, Action: ActionAllow})
+	decoder = &avroDecoder{t: t, b: encoded}
+	for _, field := range schema.Fields {
+		record[field.Name] = decoder.value(field.Type)
+	}
+	if record["bot"] != false || record["error"] != nil || len(record["reasons"].([]any)) != 0 || len(decoder.b) != 0 {
+		t.Errorf("Unexpected human record %v", record)
+	}
+}
+
+func TestJSONEventEncoder(t *testing.T) {
+	var schema avroSchema
+	json.Unmarshal([]byte(EventAvroSchema), &schema)
+
+	encoded, err := JSONEventEncoder{}.Encode(testEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(encoded, &record); err != nil {
+		t.Fatal(err)
+	}
+	if len(record) != len(schema.Fields) {
+		t.Errorf("Expected the %d schema fields, got %v", len(schema.Fields), record)
+	}
+	for _, field := range schema.Fields {
+		if _, ok := record[field.Name]; !ok {
+			t.Errorf("Missing field %s", field.Name)
+		}
+	}
+	if record["time"] != "2026-10-01T12:00:00.123456Z" || record["category"] != "automation" || record["error"] != nil {
+		t.Errorf("Unexpected record %v", record)
+	}
+}
@@ -0,0 +1,185 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/netip"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultEventBuffer is the number of events a subscription holds before dropping new ones
+const DefaultEventBuffer = 1024
+
+// Event describes a request the middleware ran detection on, for exporters and alerting
+type Event struct {
+	// Time is when the request was observed
+	Time time.Time
+	// ClientIP is the resolved client IP; invalid when it could not be resolved
+	ClientIP netip.Addr
+	Method   string
+	Host     string
+	Path     string
+	// UserAgent is the User-Agent header of the request
+	UserAgent string
+	// Result is the verdict, including its Reasons; zero when detection failed
+	Result BotDetectionResult
+	// Action is what the middleware did, as in Observation
+	Action Action
+	// Duration is the time spent detecting, or looking up the cached verdict
+	Duration time.Duration
+	// Cached is true when the verdict came from the ResultCache
+	Cached bool
+	// Err is the detection error, when detection failed
+	Err error
+}
+
+// Subscription delivers events to a handler on its own goroutine. Events arriving while its
+// buffer is full are dropped and counted rather than slowing requests down.
+type Subscription struct {
+	bus     *eventBus
+	events  chan Event
+	done    chan struct{}
+	stopped chan struct{}
+	dropped atomic.Uint64
+	close   sync.Once
+}
+
+// Dropped returns the number of events dropped because the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes, delivers the events already buffered and waits for the handler to return
+func (s *Subscription) Close() {
+	s.close.Do(func() {
+		s.bus.remove(s)
+		close(s.done)
+	})
+	<-s.stopped
+}
+
+// run delivers the events to handler until the subscription is closed
+func (s *Subscription) run(handler func(Event)) {
+	defer close(s.stopped)
+	for {
+		select {
+		case event := <-s.events:
+			handler(event)
+		case <-s.done:
+			for {
+				select {
+				case event := <-s.events:
+					handler(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// eventBus fans events out to the subscriptions. The subscription list is replaced on change,
+// so publishing takes no lock.
+type eventBus struct {
+	subscriptions atomic.Pointer[[]*Subscription]
+	mu            sync.Mutex
+}
+
+func (b *eventBus) add(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var subscriptions []*Subscription
+	if current := b.subscriptions.Load(); current != nil {
+		subscriptions = slices.Clone(*current)
+	}
+	subscriptions = append(subscriptions, s)
+	b.subscriptions.Store(&subscriptions)
+}
+
+func (b *eventBus) remove(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if current := b.subscriptions.Load(); current != nil {
+		subscriptions := slices.DeleteFunc(slices.Clone(*current), func(other *Subscription) bool {
+			return other == s
+		})
+		b.subscriptions.Store(&subscriptions)
+	}
+}
+
+// active reports whether there are subscriptions
+func (b *eventBus) active() bool {
+	subscriptions := b.subscriptions.Load()
+	return subscriptions != nil && len(*subscriptions) > 0
+}
+
+// publish offers the event to every subscription without blocking
+func (b *eventBus) publish(event Event) {
+	subscriptions := b.subscriptions.Load()
+	if subscriptions == nil {
+		return
+	}
+	for _, s := range *subscriptions {
+		select {
+		case s.events <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe calls handler with an Event for every request the detector's middlewares run
+// detection on, buffering up to DefaultEventBuffer events. Handlers run on the subscription's
+// goroutine, off the request path; Close the subscription to stop it.
+func (d *BotDetector) Subscribe(handler func(Event)) *Subscription {
+	return d.SubscribeBuffered(DefaultEventBuffer, handler)
+}
+
+// SubscribeBuffered is Subscribe with a buffer of size events
+func (d *BotDetector) SubscribeBuffered(size int, handler func(Event)) *Subscription {
+	s := &Subscription{
+		bus:     &d.events,
+		events:  make(chan Event, max(size, 1)),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run(handler)
+	d.events.add(s)
+	return s
+}
+
+// eventObserver returns the Observer publishing events, or nil without subscriptions
+func (d *BotDetector) eventObserver() Observer {
+	if !d.events.active() {
+		return nil
+	}
+	return ObserverFunc(d.publishEvent)
+}
+
+// publishEvent publishes the observation of a request
+func (d *BotDetector) publishEvent(req *http.Request, observation Observation) {
+	event := Event{
+		Time:      time.Now(),
+		Method:    req.Method,
+		Host:      req.Host,
+		Path:      req.URL.Path,
+		UserAgent: req.UserAgent(),
+		Action:    observation.Action,
+		Duration:  observation.Duration,
+		Cached:    observation.Cached,
+		Err:       observation.Err,
+	}
+	if observation.Result != nil {
+		event.Result = *observation.Result
+	}
+	// Components carry the IP resolved with CDN trust; cached verdicts have none
+	if components, ok := GetComponentsFromContext(req.Context()); ok {
+		event.ClientIP, _ = components.ClientIP()
+	}
+	if !event.ClientIP.IsValid() {
+		event.ClientIP, _ = d.config.ClientIPResolver.Resolve(req)
+	}
+	d.events.publish(event)
+}
@@ -0,0 +1,81 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestBotDetector_Subscribe(t *testing.T) {
+	resolver, err := NewClientIPResolver("10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	detector := NewDetectorWithConfig(DetectorConfig{ClientIPResolver: resolver})
+	var events []Event
+	subscription := detector.Subscribe(func(event Event) { events = append(events, event) })
+
+	config := DefaultMiddlewareConfig()
+	config.BlockBots = true
+	handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, userAgent := range []string{testBrowserUA, "curl/8.4.0"} {
+		req := newBrowserRequest("/products?page=2", "10.0.0.1:1234")
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		req.Header.Set("User-Agent", userAgent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	subscription.Close()
+
+	if len(events) != 2 {
+		t.Fatalf("Expected two events, got %d", len(events))
+	}
+	human, bot := events[0], events[1]
+	if human.Result.Bot || human.Action != ActionAllow || human.UserAgent != testBrowserUA {
+		t.Errorf("Unexpected human event %+v", human)
+	}
+	if !bot.Result.Bot || bot.Result.BotKind != BotKindCurl || bot.Action != ActionBlock {
+		t.Errorf("Unexpected bot event %+v", bot)
+	}
+	if bot.ClientIP != netip.MustParseAddr("203.0.113.7") || bot.Method != "GET" || bot.Path != "/products" || bot.Time.IsZero() {
+		t.Errorf("Unexpected request details %+v", bot)
+	}
+	if subscription.Dropped() != 0 {
+		t.Errorf("Expected no dropped events, got %d", subscription.Dropped())
+	}
+
+	// Closed subscriptions receive nothing more
+	handler.ServeHTTP(httptest.NewRecorder(), newBrowserRequest("/", "10.0.0.1:1234"))
+	if len(events) != 2 {
+		t.Errorf("Expected no events after Close, got %d", len(events))
+	}
+	subscription.Close()
+}
+
+func TestBotDetector_SubscribeDrops(t *testing.T) {
+	detector := NewDetector()
+	release := make(chan struct{})
+	received := make(chan Event, 10)
+	subscription := detector.SubscribeBuffered(1, func(event Event) {
+		received <- event
+		<-release
+	})
+
+	handler := detector.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func() { handler.ServeHTTP(httptest.NewRecorder(), newBrowserRequest("/", "203.0.113.7:1234")) }
+
+	// The first event blocks the handler, the second fills the buffer and the rest are dropped
+	serve()
+	<-received
+	for range 4 {
+		serve()
+	}
+	if subscription.Dropped() != 3 {
+		t.Errorf("Expected three dropped events, got %d", subscription.Dropped())
+	}
+	close(release)
+	subscription.Close()
+	if len(received) != 1 {
+		t.Errorf("Expected the buffered event to be delivered on Close, got %d", len(received))
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"net/netip"
 	"strings"
 
 	"github.com/lytics/gogobot"
@@ -125,27 +126,19 @@ func main() {
 	fmt.Printf("\nActive detectors: %v\n", detector.GetDetectorNames())
 }
 
-// Custom detector: Check if IP is from a datacenter/cloud provider
+// Custom detector: Check if IP is from a datacenter/cloud provider.
+// The built-in "datacenter" detector uses the providers' published ranges;
+// this simplified version only shows how an IP-based custom detector is written.
 func detectSuspiciousIP(components *gogobot.ComponentDict) *gogobot.BotDetectionResult {
-	if components.RemoteAddr.GetState() != gogobot.StateSuccess {
+	// ClientIP is parsed with net/netip, so IPv6 ("[2001:db8::1]:443") and IPv4-mapped
+	// addresses are handled; never split RemoteAddr on ":" yourself
+	ip, ok := components.ClientIP()
+	if !ok || !gogobot.IsPublicIP(ip) {
 		return &gogobot.BotDetectionResult{Bot: false}
 	}
 
-	remoteAddr := components.RemoteAddr.GetValue()
-	ip := strings.Split(remoteAddr, ":")[0]
-
-	// Simplified check for known datacenter IP ranges
-	datacenters := []string{
-		"54.239.",  // AWS
-		"52.0.",    // AWS
-		"104.154.", // Google Cloud
-		"35.184.",  // Google Cloud
-		"40.76.",   // Azure
-		"13.107.",  // Azure
-	}
-
-	for _, dcRange := range datacenters {
-		if strings.HasPrefix(ip, dcRange) {
+	for _, dcRange := range suspiciousRanges {
+		if dcRange.Contains(ip) {
 			return &gogobot.BotDetectionResult{
 				Bot:     true,
 				BotKind: gogobot.BotKindUnknown,
@@ -156,10 +149,32 @@ func detectSuspiciousIP(components *gogobot.ComponentDict) *gogobot.BotDetection
 	return &gogobot.BotDetectionResult{Bot: false}
 }
 
-// Custom detector: Detect rapid requests (would need state management in real implementation)
+// Simplified list of known datacenter IP ranges
+var suspiciousRanges = []netip.Prefix{
+	netip.MustParsePrefix("54.239.0.0/16"),  // AWS
+	netip.MustParsePrefix("52.0.0.0/16"),    // AWS
+	netip.MustParsePrefix("104.154.0.0/16"), // Google Cloud
+	netip.MustParsePrefix("35.184.0.0/16"),  // Google Cloud
+	netip.MustParsePrefix("40.76.0.0/16"),   // Azure
+	netip.MustParsePrefix("13.107.0.0/16"),  // Azure
+	netip.MustParsePrefix("2600:1f00::/24"), // AWS
+	netip.MustParsePrefix("2600:1900::/28"), // Google Cloud
+}
+
+// Custom detector: Detect rapid requests from the visitor's session. Sessions are only
+// tracked when the detector is configured with a SessionTracker.
 func detectRapidRequests(components *gogobot.ComponentDict) *gogobot.BotDetectionResult {
-	// This is a simplified example - real implementation would need
-	// rate limiting state management
+	if components.Session.GetState() != gogobot.StateSuccess {
+		return &gogobot.BotDetectionResult{Bot: false}
+	}
+
+	session := components.Session.GetValue()
+	if session.Requests > 120 {
+		return &gogobot.BotDetectionResult{
+			Bot:     true,
+			BotKind: gogobot.BotKindUnknown,
+		}
+	}
 	return &gogobot.BotDetectionResult{Bot: false}
 }
 
@@ -0,0 +1,24 @@
+//go:build tinygo || wasip1
+
+// Command edge classifies user agents with the net/http-free core package. Build it with
+// TinyGo or for WASI and run it in an edge runtime or any WebAssembly host:
+//
+//	tinygo build -o edge.wasm -target=wasi ./examples/edge
+//	wasmtime edge.wasm "curl/8.4.0"
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lytics/gogobot/core"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: edge <user agent>")
+		os.Exit(2)
+	}
+	kind, bot := core.ClassifyUserAgent(os.Args[1])
+	fmt.Printf("{\"bot\":%t,\"botKind\":%q}\n", bot, kind)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
 
 	"github.com/lytics/gogobot"
 )
@@ -13,6 +15,9 @@ func main() {
 	// Create a bot detector
 	detector := gogobot.Load()
 
+	// Structured detection logs: refused bots at Warn, served bots at Info, failures at Error
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 	fmt.Println("Starting server with bot detection middleware...")
 
 	// Example 1: Basic middleware that blocks bots
@@ -20,15 +25,7 @@ func main() {
 		BlockBots:         true,
 		BlockedStatusCode: http.StatusForbidden,
 		BlockedMessage:    "Bot traffic is not allowed on this endpoint",
-		OnBotDetected: func(w http.ResponseWriter, r *http.Request, result *gogobot.BotDetectionResult) {
-			log.Printf("Bot detected: %s from %s (Kind: %s)",
-				r.Header.Get("User-Agent"),
-				r.RemoteAddr,
-				result.BotKind)
-		},
-		OnError: func(w http.ResponseWriter, r *http.Request, err error) {
-			log.Printf("Bot detection error: %v", err)
-		},
+		Logger:            logger,
 	}
 
 	// Apply middleware to specific routes
@@ -44,11 +41,7 @@ func main() {
 	// Example 3: Public endpoint without bot blocking
 	publicConfig := gogobot.MiddlewareConfig{
 		BlockBots: false,
-		OnBotDetected: func(w http.ResponseWriter, r *http.Request, result *gogobot.BotDetectionResult) {
-			log.Printf("Bot detected on public endpoint: %s (Kind: %s)",
-				r.Header.Get("User-Agent"),
-				result.BotKind)
-		},
+		Logger:    logger.With("endpoint", "/public"),
 	}
 
 	http.Handle("/public", detector.MiddlewareWithConfig(publicConfig)(
@@ -61,18 +54,17 @@ func main() {
 			// Skip detection for requests with a special header
 			return r.Header.Get("X-Skip-Bot-Detection") == "true"
 		},
-		OnBotDetected: func(w http.ResponseWriter, r *http.Request, result *gogobot.BotDetectionResult) {
-			// Only block malicious bots, allow search engine crawlers
-			if result.BotKind == gogobot.BotKindCrawler {
-				log.Printf("Search engine crawler allowed: %s", r.Header.Get("User-Agent"))
-				return
-			}
-
-			log.Printf("Malicious bot blocked: %s (Kind: %s)",
-				r.Header.Get("User-Agent"),
-				result.BotKind)
-			http.Error(w, "Automated traffic blocked", http.StatusForbidden)
+		// Allow search engine crawlers, rate-limit AI crawlers and block everything else
+		Actions: map[gogobot.BotCategory]gogobot.Action{
+			gogobot.BotCategoryCrawler:    gogobot.ActionAllow,
+			gogobot.BotCategoryAI:         gogobot.ActionRateLimit,
+			gogobot.BotCategoryAutomation: gogobot.ActionBlock,
+			gogobot.BotCategoryNetwork:    gogobot.ActionBlock,
+			gogobot.BotCategoryAbuse:      gogobot.ActionBlock,
+			gogobot.BotCategoryUnknown:    gogobot.ActionBlock,
 		},
+		BlockedMessage: "Automated traffic blocked",
+		Logger:         logger,
 	}
 
 	http.Handle("/smart-protection", detector.MiddlewareWithConfig(conditionalConfig)(
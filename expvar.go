@@ -0,0 +1,56 @@
+package gogobot
+
+import (
+	"expvar"
+	"net/http"
+)
+
+// ExpvarCounters publishes the middleware's counters with expvar, served as JSON by
+// /debug/vars, for visibility without a metrics system:
+//
+//   - requests: requests detection ran on
+//   - bots: detected bots by kind
+//   - actions: detected bots by the action taken
+//   - blocks: requests refused (blocked, rate limited, challenged, tarpitted or degraded)
+//   - errors: requests detection failed on
+type ExpvarCounters struct {
+	requests expvar.Int
+	blocks   expvar.Int
+	errors   expvar.Int
+	bots     expvar.Map
+	actions  expvar.Map
+}
+
+// PublishExpvar publishes counters under name, e.g. "gogobot"; set them as
+// MiddlewareConfig.Expvar. Like expvar.Publish, it panics when the name is already in use, so
+// it is called once per process and the counters shared between middlewares.
+func PublishExpvar(name string) *ExpvarCounters {
+	c := &ExpvarCounters{}
+	c.bots.Init()
+	c.actions.Init()
+	vars := expvar.NewMap(name)
+	vars.Set("requests", &c.requests)
+	vars.Set("bots", &c.bots)
+	vars.Set("actions", &c.actions)
+	vars.Set("blocks", &c.blocks)
+	vars.Set("errors", &c.errors)
+	return c
+}
+
+// ObserveRequest implements Observer
+func (c *ExpvarCounters) ObserveRequest(_ *http.Request, observation Observation) {
+	c.requests.Add(1)
+	if observation.Err != nil {
+		c.errors.Add(1)
+	}
+	if observation.Result == nil || !observation.Result.Bot {
+		return
+	}
+	c.bots.Add(string(observation.Result.BotKind), 1)
+	if observation.Action != "" {
+		c.actions.Add(string(observation.Action), 1)
+	}
+	if observation.Action != "" && observation.Action != ActionAllow && observation.Action != ActionLog {
+		c.blocks.Add(1)
+	}
+}
@@ -0,0 +1,73 @@
+package gogobot
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// expvarNames numbers the published test counters, as names cannot be reused within a process
+var expvarNames atomic.Int64
+
+func publishTestExpvar(t *testing.T) (*ExpvarCounters, string) {
+	name := fmt.Sprintf("%s_%d", t.Name(), expvarNames.Add(1))
+	return PublishExpvar(name), name
+}
+
+func TestMiddleware_Expvar(t *testing.T) {
+	counters, name := publishTestExpvar(t)
+	config := DefaultMiddlewareConfig()
+	config.Actions = map[BotCategory]Action{BotCategoryAI: ActionLog, BotCategoryAutomation: ActionBlock}
+	config.Expvar = counters
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, userAgent := range []string{testBrowserUA, "curl/8.4.0", "curl/8.4.0", "Mozilla/5.0 (compatible; GPTBot/1.2; +https://openai.com/gptbot)"} {
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", userAgent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	counters.ObserveRequest(nil, Observation{Err: errors.New("store unreachable")})
+	// A bot answered by OnBotDecision has no action and is not counted as blocked
+	counters.ObserveRequest(nil, Observation{Result: &BotDetectionResult{Bot: true, BotKind: BotKindCurl}})
+
+	var vars struct {
+		Requests int64            `json:"requests"`
+		Bots     map[string]int64 `json:"bots"`
+		Actions  map[string]int64 `json:"actions"`
+		Blocks   int64            `json:"blocks"`
+		Errors   int64            `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if vars.Requests != 6 || vars.Blocks != 2 || vars.Errors != 1 {
+		t.Errorf("Unexpected counters %+v", vars)
+	}
+	if vars.Bots[string(BotKindCurl)] != 3 || vars.Bots[string(BotKindGPTBot)] != 1 {
+		t.Errorf("Unexpected bots %v", vars.Bots)
+	}
+	if vars.Actions["block"] != 2 || vars.Actions["log"] != 1 {
+		t.Errorf("Unexpected actions %v", vars.Actions)
+	}
+}
+
+func TestMiddlewareConfig_Observer(t *testing.T) {
+	var calls []string
+	first := ObserverFunc(func(*http.Request, Observation) { calls = append(calls, "observer") })
+
+	config := DefaultMiddlewareConfig()
+	if config.observer() != nil {
+		t.Error("Expected no observer by default")
+	}
+	config.Observer = first
+	config.Expvar, _ = publishTestExpvar(t)
+	config.observer().ObserveRequest(httptest.NewRequest("GET", "/", nil), Observation{})
+	if len(calls) != 1 || config.Expvar.requests.Value() != 1 {
+		t.Errorf("Expected both observers to be notified, got %v and %d", calls, config.Expvar.requests.Value())
+	}
+}
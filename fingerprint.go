@@ -0,0 +1,95 @@
+package gogobot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// fingerprintHeaders are the request headers a client sends identically on every request,
+// whatever the resource. Accept is left out as browsers vary it by resource type.
+var fingerprintHeaders = []string{
+	"User-Agent",
+	"Accept-Language",
+	"Accept-Encoding",
+	"Sec-CH-UA",
+	"Sec-CH-UA-Mobile",
+	"Sec-CH-UA-Platform",
+}
+
+// WithTLSFingerprint returns a context carrying the TLS fingerprint (e.g. JA3 or JA4) of the
+// client's connection, for servers terminating TLS themselves or behind a proxy reporting it.
+// The fingerprint is collected as the TLSFingerprint component and included in Fingerprint.
+func WithTLSFingerprint(ctx context.Context, fingerprint string) context.Context {
+	return context.WithValue(ctx, TLSFingerprintKey, fingerprint)
+}
+
+// getTLSFingerprint reads the TLS fingerprint attached with WithTLSFingerprint
+func getTLSFingerprint(req *http.Request) Component[string] {
+	fingerprint, ok := req.Context().Value(TLSFingerprintKey).(string)
+	if !ok || fingerprint == "" {
+		return ErrorComponent[string]{
+			State: StateUndefined,
+			Error: "no TLS fingerprint in request context",
+		}
+	}
+	return SuccessComponent[string]{
+		State: StateSuccess,
+		Value: fingerprint,
+	}
+}
+
+// Fingerprint returns a stable hash of the client's user agent, Accept-Language,
+// Accept-Encoding, client hints and TLS fingerprint when available, or "" without a user agent.
+// The same browser yields the same fingerprint from any IP address, so it correlates scrapers
+// rotating through proxies; but identically configured browsers share it too, so it should not
+// identify visitors where many people use the same browser build.
+func Fingerprint(components *ComponentDict) string {
+	if components.Headers == nil || components.Headers.GetState() != StateSuccess {
+		return ""
+	}
+	headers := http.Header(components.Headers.GetValue())
+	if headers.Get("User-Agent") == "" {
+		return ""
+	}
+
+	h := sha256.New()
+	for _, name := range fingerprintHeaders {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		for _, value := range headers.Values(name) {
+			h.Write([]byte(value))
+			h.Write([]byte{0})
+		}
+	}
+	if components.TLSFingerprint != nil && components.TLSFingerprint.GetState() == StateSuccess {
+		h.Write([]byte("TLS"))
+		h.Write([]byte{0})
+		h.Write([]byte(components.TLSFingerprint.GetValue()))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// SessionKeyByFingerprint keys visitors by Fingerprint, falling back to fallback (which may be
+// nil) for requests without a user agent. Use it as the fallback of SessionKeyByCookie to follow
+// clients without cookies across IP addresses.
+func SessionKeyByFingerprint(fallback SessionKeyFunc) SessionKeyFunc {
+	return func(req *http.Request, components *ComponentDict) string {
+		// Visitors are also keyed outside of collection, with only the client IP resolved
+		if components.Headers == nil {
+			components = &ComponentDict{
+				ResolvedClientIP: components.ResolvedClientIP,
+				Headers:          getHeaders(req),
+				TLSFingerprint:   getTLSFingerprint(req),
+			}
+		}
+		if fingerprint := Fingerprint(components); fingerprint != "" {
+			return "fp:" + fingerprint
+		}
+		if fallback != nil {
+			return fallback(req, components)
+		}
+		return ""
+	}
+}
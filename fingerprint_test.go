@@ -0,0 +1,72 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newFingerprintRequest(remoteAddr string) *http.Request {
+	req := newBrowserRequest("/", remoteAddr)
+	req.Header.Set("Sec-CH-UA", `"Chromium";v="120", "Google Chrome";v="120"`)
+	req.Header.Set("Sec-CH-UA-Platform", `"Windows"`)
+	return req
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(req *http.Request) string {
+		return Fingerprint(collectAllSources(req))
+	}
+
+	base := fingerprint(newFingerprintRequest("203.0.113.7:1234"))
+	if len(base) != 32 {
+		t.Fatalf("Expected 128-bit hex fingerprint, got %q", base)
+	}
+
+	req := newFingerprintRequest("198.51.100.9:1234")
+	req.Header.Set("Accept", "image/avif,image/webp,*/*")
+	req.URL.Path = "/logo.png"
+	if got := fingerprint(req); got != base {
+		t.Errorf("Expected fingerprint to ignore IP, path and Accept, got %q and %q", got, base)
+	}
+
+	req = newFingerprintRequest("203.0.113.7:1234")
+	req.Header.Set("Sec-CH-UA-Platform", `"Linux"`)
+	if got := fingerprint(req); got == base {
+		t.Error("Expected client hints to change the fingerprint")
+	}
+
+	req = newFingerprintRequest("203.0.113.7:1234")
+	req = req.WithContext(WithTLSFingerprint(req.Context(), "t13d1516h2_8daaf6152771_02713d6af862"))
+	if got := fingerprint(req); got == base {
+		t.Error("Expected TLS fingerprint to change the fingerprint")
+	}
+
+	if got := fingerprint(httptest.NewRequest("GET", "/", nil)); got != "" {
+		t.Errorf("Expected no fingerprint without a user agent, got %q", got)
+	}
+	if got := Fingerprint(&ComponentDict{}); got != "" {
+		t.Errorf("Expected no fingerprint without headers, got %q", got)
+	}
+}
+
+func TestSessionKeyByFingerprint(t *testing.T) {
+	keyFunc := SessionKeyByCookie("sid", SessionKeyByFingerprint(SessionKeyByIP(DefaultIPAggregation())))
+
+	first := newFingerprintRequest("203.0.113.7:1234")
+	second := newFingerprintRequest("198.51.100.9:1234")
+	key := keyFunc(first, collectAllSources(first))
+	if key != keyFunc(second, collectAllSources(second)) || key != "fp:"+Fingerprint(collectAllSources(first)) {
+		t.Errorf("Expected rotating IPs to share the fingerprint key, got %q", key)
+	}
+	// Keys computed outside of collection only have the client IP
+	if got := keyFunc(second, &ComponentDict{}); got != key {
+		t.Errorf("Expected the same key without collected headers, got %q", got)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	if got := keyFunc(req, collectAllSources(req)); got != "ip:203.0.113.7/32" {
+		t.Errorf("Expected IP fallback without a user agent, got %q", got)
+	}
+}
@@ -0,0 +1,170 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedElement is one proxy hop from an RFC 7239 Forwarded header
+type ForwardedElement struct {
+	// For is the raw node identifier of the client or previous proxy, e.g. "192.0.2.60",
+	// "[2001:db8::17]:4711", "unknown", or an obfuscated identifier such as "_hidden"
+	For string `json:"for,omitempty"`
+	// By is the raw node identifier of the proxy that added this element
+	By string `json:"by,omitempty"`
+	// Host is the original Host request header received by the proxy
+	Host string `json:"host,omitempty"`
+	// Proto is the protocol used by the incoming request, e.g. "https"
+	Proto string `json:"proto,omitempty"`
+	// ForAddr is the parsed IP address of For; invalid for "unknown" or obfuscated identifiers
+	ForAddr netip.Addr `json:"forAddr,omitempty"`
+}
+
+// IsObfuscated reports whether the For node is an obfuscated identifier or "unknown"
+func (e ForwardedElement) IsObfuscated() bool {
+	return e.For != "" && !e.ForAddr.IsValid()
+}
+
+// ParseForwarded parses all Forwarded headers into an ordered chain of elements
+func ParseForwarded(header http.Header) ([]ForwardedElement, error) {
+	var chain []ForwardedElement
+	for _, value := range header.Values("Forwarded") {
+		elements, err := parseForwardedValue(value)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, elements...)
+	}
+	return chain, nil
+}
+
+// parseForwardedValue parses a single Forwarded header value
+func parseForwardedValue(value string) ([]ForwardedElement, error) {
+	var elements []ForwardedElement
+	current := ForwardedElement{}
+	hasPairs := false
+
+	i := 0
+	for i < len(value) {
+		// Skip optional whitespace
+		for i < len(value) && (value[i] == ' ' || value[i] == '\t') {
+			i++
+		}
+		if i >= len(value) {
+			break
+		}
+
+		switch value[i] {
+		case ',':
+			if hasPairs {
+				elements = append(elements, current)
+			}
+			current = ForwardedElement{}
+			hasPairs = false
+			i++
+			continue
+		case ';':
+			i++
+			continue
+		}
+
+		eq := strings.IndexByte(value[i:], '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("forwarded: expected name=value at offset %d", i)
+		}
+		name := strings.ToLower(strings.TrimSpace(value[i : i+eq]))
+		i += eq + 1
+
+		var pairValue string
+		if i < len(value) && value[i] == '"' {
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(value) {
+				c := value[i]
+				if c == '\\' && i+1 < len(value) {
+					b.WriteByte(value[i+1])
+					i += 2
+					continue
+				}
+				i++
+				if c == '"' {
+					closed = true
+					break
+				}
+				b.WriteByte(c)
+			}
+			if !closed {
+				return nil, fmt.Errorf("forwarded: unterminated quoted string")
+			}
+			pairValue = b.String()
+		} else {
+			end := strings.IndexAny(value[i:], ";,")
+			if end < 0 {
+				end = len(value) - i
+			}
+			pairValue = strings.TrimSpace(value[i : i+end])
+			i += end
+		}
+
+		switch name {
+		case "for":
+			current.For = pairValue
+			current.ForAddr = parseForwardedNode(pairValue)
+		case "by":
+			current.By = pairValue
+		case "host":
+			current.Host = pairValue
+		case "proto":
+			current.Proto = strings.ToLower(pairValue)
+		}
+		hasPairs = true
+	}
+
+	if hasPairs {
+		elements = append(elements, current)
+	}
+	return elements, nil
+}
+
+// parseForwardedNode parses an RFC 7239 node ("192.0.2.60:8080", "[2001:db8::1]:4711"),
+// returning an invalid Addr for "unknown" and obfuscated identifiers
+func parseForwardedNode(node string) netip.Addr {
+	if node == "" || strings.EqualFold(node, "unknown") || strings.HasPrefix(node, "_") {
+		return netip.Addr{}
+	}
+
+	if strings.HasPrefix(node, "[") {
+		end := strings.IndexByte(node, ']')
+		if end < 0 {
+			return netip.Addr{}
+		}
+		node = node[1:end]
+	} else if strings.Count(node, ":") == 1 {
+		// IPv4 with port; unbracketed IPv6 is not valid RFC 7239 but is accepted as-is
+		node, _, _ = strings.Cut(node, ":")
+	}
+
+	addr, err := netip.ParseAddr(node)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return normalizeAddr(addr)
+}
+
+// getForwardedChain collects the parsed Forwarded header chain
+func getForwardedChain(req *http.Request) Component[[]ForwardedElement] {
+	chain, err := ParseForwarded(req.Header)
+	if err != nil {
+		return ErrorComponent[[]ForwardedElement]{
+			State: StateUnexpectedBehaviour,
+			Error: err.Error(),
+		}
+	}
+	return SuccessComponent[[]ForwardedElement]{
+		State: StateSuccess,
+		Value: chain,
+	}
+}
@@ -0,0 +1,82 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+)
+
+func TestParseForwarded(t *testing.T) {
+	header := http.Header{}
+	header.Add("Forwarded", `for=192.0.2.60;proto=HTTP;by=203.0.113.43, for="[2001:db8:cafe::17]:4711"`)
+	header.Add("Forwarded", `for=unknown;host="example.com", For=_hidden;by="_proxy"`)
+	header.Add("Forwarded", `for="198.51.100.1:8080";host="a\"b, c"`)
+
+	chain, err := ParseForwarded(header)
+	if err != nil {
+		t.Fatalf("ParseForwarded returned error: %v", err)
+	}
+	if len(chain) != 5 {
+		t.Fatalf("Expected 5 elements, got %d: %+v", len(chain), chain)
+	}
+
+	expected := []struct {
+		forNode    string
+		forAddr    string
+		by         string
+		host       string
+		proto      string
+		obfuscated bool
+	}{
+		{forNode: "192.0.2.60", forAddr: "192.0.2.60", by: "203.0.113.43", proto: "http"},
+		{forNode: "[2001:db8:cafe::17]:4711", forAddr: "2001:db8:cafe::17"},
+		{forNode: "unknown", host: "example.com", obfuscated: true},
+		{forNode: "_hidden", by: "_proxy", obfuscated: true},
+		{forNode: "198.51.100.1:8080", forAddr: "198.51.100.1", host: `a"b, c`},
+	}
+
+	for i, want := range expected {
+		got := chain[i]
+		if got.For != want.forNode || got.By != want.by || got.Host != want.host || got.Proto != want.proto {
+			t.Errorf("Element %d: got %+v, want %+v", i, got, want)
+		}
+		if got.IsObfuscated() != want.obfuscated {
+			t.Errorf("Element %d: expected obfuscated=%t", i, want.obfuscated)
+		}
+		if want.forAddr != "" && got.ForAddr != netip.MustParseAddr(want.forAddr) {
+			t.Errorf("Element %d: expected address %s, got %s", i, want.forAddr, got.ForAddr)
+		}
+	}
+}
+
+func TestParseForwarded_Invalid(t *testing.T) {
+	for _, value := range []string{`for="192.0.2.60`, `for`, `=192.0.2.60`} {
+		header := http.Header{}
+		header.Set("Forwarded", value)
+		if _, err := ParseForwarded(header); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestBotDetector_CollectForwardedChain(t *testing.T) {
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent": "Mozilla/5.0",
+		"Forwarded":  "for=198.51.100.10;proto=https",
+	})
+
+	components, _ := NewDetector().Collect(req)
+	if components.ForwardedChain.GetState() != StateSuccess {
+		t.Fatalf("Expected forwarded chain, got error %s", components.ForwardedChain.GetError())
+	}
+	chain := components.ForwardedChain.GetValue()
+	if len(chain) != 1 || chain[0].Proto != "https" {
+		t.Errorf("Unexpected forwarded chain %+v", chain)
+	}
+
+	req.Header.Set("Forwarded", `for="unterminated`)
+	components, _ = NewDetector().Collect(req)
+	if components.ForwardedChain.GetState() == StateSuccess {
+		t.Error("Expected error component for malformed Forwarded header")
+	}
+}
@@ -0,0 +1,127 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// GeoInfo is the location of an IP address
+type GeoInfo struct {
+	// Country is the ISO 3166-1 alpha-2 country code, e.g. "US"
+	Country string `json:"country,omitempty"`
+	// Region is the ISO 3166-2 subdivision code without the country prefix, e.g. "CA"
+	Region string `json:"region,omitempty"`
+}
+
+// GeoProvider looks up the location of an IP address
+type GeoProvider interface {
+	LookupGeo(addr netip.Addr) (GeoInfo, bool)
+}
+
+// MMDBGeoProvider is a GeoProvider backed by a MaxMind GeoLite2-Country, GeoLite2-City or
+// compatible database
+type MMDBGeoProvider struct {
+	reader *MMDBReader
+}
+
+// NewMMDBGeoProvider creates a GeoProvider from an open MaxMind DB reader
+func NewMMDBGeoProvider(reader *MMDBReader) *MMDBGeoProvider {
+	return &MMDBGeoProvider{reader: reader}
+}
+
+// OpenMMDBGeoProvider opens a GeoLite2-Country or GeoLite2-City database file
+func OpenMMDBGeoProvider(path string) (*MMDBGeoProvider, error) {
+	reader, err := OpenMMDB(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMMDBGeoProvider(reader), nil
+}
+
+// LookupGeo implements GeoProvider. The registered country is used when the
+// database has no location for the address.
+func (p *MMDBGeoProvider) LookupGeo(addr netip.Addr) (GeoInfo, bool) {
+	record, err := p.reader.Lookup(addr)
+	if err != nil {
+		return GeoInfo{}, false
+	}
+	fields, ok := record.(map[string]any)
+	if !ok {
+		return GeoInfo{}, false
+	}
+
+	var geo GeoInfo
+	for _, key := range []string{"country", "registered_country"} {
+		if country, ok := fields[key].(map[string]any); ok {
+			if code, ok := country["iso_code"].(string); ok && code != "" {
+				geo.Country = code
+				break
+			}
+		}
+	}
+	if subdivisions, ok := fields["subdivisions"].([]any); ok && len(subdivisions) > 0 {
+		if subdivision, ok := subdivisions[0].(map[string]any); ok {
+			geo.Region, _ = subdivision["iso_code"].(string)
+		}
+	}
+
+	if geo.Country == "" {
+		return GeoInfo{}, false
+	}
+	return geo, true
+}
+
+// getGeo looks up the resolved client IP with the configured GeoProvider
+func getGeo(clientIP Component[netip.Addr], provider GeoProvider) Component[GeoInfo] {
+	if provider == nil {
+		return ErrorComponent[GeoInfo]{
+			State: StateUndefined,
+			Error: "no geo provider configured",
+		}
+	}
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[GeoInfo]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+
+	geo, ok := provider.LookupGeo(clientIP.GetValue())
+	if !ok {
+		return ErrorComponent[GeoInfo]{
+			State: StateNull,
+			Error: "no location found for " + clientIP.GetValue().String(),
+		}
+	}
+	return SuccessComponent[GeoInfo]{
+		State: StateSuccess,
+		Value: geo,
+	}
+}
+
+// GeoPolicyFunc inspects the client's location and may escalate the detection result
+// before the middleware handles it
+type GeoPolicyFunc func(*http.Request, GeoInfo, *BotDetectionResult)
+
+// UnservedCountryPolicy returns a GeoPolicyFunc flagging requests from countries outside
+// served once other signals have raised the result's score to at least minScore.
+// Location alone never flags a request, so travellers and VPN users are not blocked outright.
+func UnservedCountryPolicy(served []string, minScore float64) GeoPolicyFunc {
+	servedSet := make(map[string]bool, len(served))
+	for _, country := range served {
+		servedSet[strings.ToUpper(country)] = true
+	}
+
+	return func(_ *http.Request, geo GeoInfo, result *BotDetectionResult) {
+		if result.Bot || servedSet[strings.ToUpper(geo.Country)] {
+			return
+		}
+		if result.Score > 0 && result.Score >= minScore {
+			result.Bot = true
+			if result.BotKind == "" {
+				result.BotKind = BotKindUnknown
+			}
+		}
+	}
+}
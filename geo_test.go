@@ -0,0 +1,137 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func buildTestGeoDatabase() []byte {
+	w := newMMDBTestWriter()
+
+	city := w.data.Len()
+	writeMMDBMap(&w.data, 2)
+	writeMMDBString(&w.data, "country")
+	writeMMDBMap(&w.data, 1)
+	writeMMDBString(&w.data, "iso_code")
+	writeMMDBString(&w.data, "US")
+	writeMMDBString(&w.data, "subdivisions")
+	w.data.WriteByte(1) // array of one element (extended type)
+	w.data.WriteByte(mmdbArray - 7)
+	writeMMDBMap(&w.data, 1)
+	writeMMDBString(&w.data, "iso_code")
+	writeMMDBString(&w.data, "CA")
+
+	registered := w.data.Len()
+	writeMMDBMap(&w.data, 1)
+	writeMMDBString(&w.data, "registered_country")
+	writeMMDBMap(&w.data, 1)
+	writeMMDBString(&w.data, "iso_code")
+	writeMMDBString(&w.data, "DE")
+
+	w.insert(netip.MustParsePrefix("198.51.100.0/24"), city)
+	w.insert(netip.MustParsePrefix("2001:db8::/32"), registered)
+	return w.bytes()
+}
+
+func TestMMDBGeoProvider_LookupGeo(t *testing.T) {
+	reader, err := NewMMDBReader(buildTestGeoDatabase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := NewMMDBGeoProvider(reader)
+
+	geo, ok := provider.LookupGeo(netip.MustParseAddr("198.51.100.7"))
+	if !ok || geo.Country != "US" || geo.Region != "CA" {
+		t.Errorf("Expected US/CA, got %+v, %t", geo, ok)
+	}
+
+	geo, ok = provider.LookupGeo(netip.MustParseAddr("2001:db8::1"))
+	if !ok || geo.Country != "DE" || geo.Region != "" {
+		t.Errorf("Expected registered country DE, got %+v, %t", geo, ok)
+	}
+
+	if _, ok := provider.LookupGeo(netip.MustParseAddr("203.0.113.1")); ok {
+		t.Error("Expected no location for unknown address")
+	}
+}
+
+type staticGeoProvider map[netip.Addr]GeoInfo
+
+func (p staticGeoProvider) LookupGeo(addr netip.Addr) (GeoInfo, bool) {
+	geo, ok := p[addr]
+	return geo, ok
+}
+
+func TestUnservedCountryPolicy(t *testing.T) {
+	policy := UnservedCountryPolicy([]string{"us", "CA"}, 0.5)
+
+	tests := []struct {
+		name     string
+		country  string
+		score    float64
+		expected bool
+	}{
+		{"Served country with signals", "US", 0.9, false},
+		{"Unserved country without signals", "RU", 0, false},
+		{"Unserved country with weak signals", "RU", 0.3, false},
+		{"Unserved country with enough signals", "RU", 0.5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BotDetectionResult{Score: tt.score}
+			policy(nil, GeoInfo{Country: tt.country}, &result)
+			if result.Bot != tt.expected {
+				t.Errorf("Expected bot=%t, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBotDetector_MiddlewareGeoPolicy(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.GeoProvider = staticGeoProvider{
+		netip.MustParseAddr("54.239.1.1"): {Country: "RU"},
+		netip.MustParseAddr("54.239.1.2"): {Country: "US"},
+	}
+	detector := NewDetectorWithConfig(config)
+
+	middlewareConfig := DefaultMiddlewareConfig()
+	middlewareConfig.BlockBots = true
+	middlewareConfig.GeoPolicy = UnservedCountryPolicy([]string{"US"}, datacenterSignalWeight)
+
+	var components *ComponentDict
+	handler := detector.MiddlewareWithConfig(middlewareConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		components, _ = GetComponentsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	newRequest := func(remoteAddr string) *http.Request {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Accept-Language", "en-US")
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.RemoteAddr = remoteAddr
+		return req
+	}
+
+	// Datacenter IP in an unserved country
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("54.239.1.1:1234"))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected unserved datacenter request to be blocked, got %d", w.Code)
+	}
+
+	// Same signals from a served country
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("54.239.1.2:1234"))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected served request to pass, got %d", w.Code)
+	}
+	if components == nil || components.Geo.GetValue().Country != "US" {
+		t.Errorf("Expected geo component in context, got %+v", components)
+	}
+}
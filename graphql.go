@@ -0,0 +1,599 @@
+package gogobot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Default GraphQL policy parameters
+const (
+	DefaultGraphQLMaxBodyBytes = 1 << 20
+	DefaultGraphQLMaxDepth     = 8
+	DefaultGraphQLMaxBreadth   = 50
+)
+
+// GraphQLQuery is what InspectGraphQL found in a GraphQL document
+type GraphQLQuery struct {
+	// Operation is the name of the first operation, "" for anonymous ones
+	Operation string `json:"operation,omitempty"`
+	// Depth is the deepest nesting of selection sets, with fragment spreads expanded
+	Depth int `json:"depth"`
+	// Breadth is the largest number of fields selected in one selection set
+	Breadth int `json:"breadth"`
+	// Introspection is true when the document selects __schema or __type
+	Introspection bool `json:"introspection,omitempty"`
+}
+
+// GraphQLPolicy protects a GraphQL endpoint, which scrapers increasingly target instead of the
+// HTML pages. Queries of clients the detector flagged or scored are held to tighter limits than
+// a browser application needs, and every client's operations are rate limited.
+type GraphQLPolicy struct {
+	// MaxDepth and MaxBreadth limit the queries of flagged clients (bots, and requests with a
+	// score); zero disables a limit
+	MaxDepth   int
+	MaxBreadth int
+	// DenyIntrospection refuses introspection queries from every client; those of flagged
+	// clients are always refused
+	DenyIntrospection bool
+	// OperationLimit is the number of requests per Window a client may make for one operation;
+	// zero disables operation rate limits, which require a store
+	OperationLimit int64
+	// Window is the length of the fixed rate limit window; zero uses DefaultSessionWindow
+	Window time.Duration
+	// MaxBodyBytes bounds the request bodies read for inspection; larger ones are refused
+	MaxBodyBytes int64
+	// Prefix is prepended to store keys
+	Prefix string
+	// OnError is called when the store cannot be reached; requests are then served
+	OnError func(error)
+
+	store Store
+}
+
+// NewGraphQLPolicy returns a policy with the default limits, keeping operation rate limits in
+// store; a nil store disables them
+func NewGraphQLPolicy(store Store) *GraphQLPolicy {
+	return &GraphQLPolicy{
+		MaxDepth:          DefaultGraphQLMaxDepth,
+		MaxBreadth:        DefaultGraphQLMaxBreadth,
+		DenyIntrospection: false,
+		OperationLimit:    0,
+		Window:            0,
+		MaxBodyBytes:      DefaultGraphQLMaxBodyBytes,
+		Prefix:            "gogobot:graphql:",
+		store:             store,
+	}
+}
+
+// window returns the operation rate limit window
+func (p *GraphQLPolicy) window() time.Duration {
+	if p.Window > 0 {
+		return p.Window
+	}
+	return DefaultSessionWindow
+}
+
+// GraphQLMiddleware returns the detection middleware with config followed by the inspection of
+// GraphQL requests with policy. Refused requests get a GraphQL error response and are reported
+// to config.OnAction as ActionBlock, or ActionRateLimit when over an operation limit.
+func (d *BotDetector) GraphQLMiddleware(config MiddlewareConfig, policy *GraphQLPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return d.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d.inspectGraphQL(w, r, config, policy) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+// graphQLParams are the parameters of a GraphQL over HTTP request
+type graphQLParams struct {
+	Query         string `json:"query"`
+	OperationName string `json:"operationName"`
+}
+
+// errGraphQLBodyTooLarge is returned for request bodies over MaxBodyBytes
+var errGraphQLBodyTooLarge = errors.New("request body too large")
+
+// readGraphQLParams returns the parameters of the request, from the query string of GET
+// requests and from the JSON or application/graphql body of POST requests, which is restored
+// for the next handler. Batched requests yield several parameter sets.
+func readGraphQLParams(req *http.Request, maxBytes int64) ([]graphQLParams, error) {
+	if req.Method != http.MethodPost {
+		query := req.URL.Query()
+		return []graphQLParams{{Query: query.Get("query"), OperationName: query.Get("operationName")}}, nil
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultGraphQLMaxBodyBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if int64(len(body)) > maxBytes {
+		return nil, errGraphQLBodyTooLarge
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/graphql" {
+		return []graphQLParams{{Query: string(body)}}, nil
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []graphQLParams
+		err := json.Unmarshal(body, &batch)
+		return batch, err
+	}
+	var params graphQLParams
+	err = json.Unmarshal(body, &params)
+	return []graphQLParams{params}, err
+}
+
+// inspectGraphQL applies the policy to the request, reporting whether it was refused
+func (d *BotDetector) inspectGraphQL(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, policy *GraphQLPolicy) bool {
+	if policy == nil {
+		return false
+	}
+	result, ok := GetResultFromContext(req.Context())
+	if !ok {
+		result = &BotDetectionResult{}
+	}
+	flagged := (result.Bot || result.Score > 0) && !result.ChallengePassed && !result.Trusted
+
+	batch, err := readGraphQLParams(req, policy.MaxBodyBytes)
+	if errors.Is(err, errGraphQLBodyTooLarge) {
+		refuseGraphQL(w, req, config, result, ActionBlock, http.StatusRequestEntityTooLarge, "request body too large")
+		return true
+	}
+	// Malformed requests are left for the GraphQL server to report
+	if err != nil {
+		return false
+	}
+
+	for _, params := range batch {
+		query, err := InspectGraphQL(params.Query)
+		// A document that cannot be measured cannot be held to the limits either
+		if err != nil && flagged {
+			refuseGraphQL(w, req, config, result, ActionBlock, http.StatusBadRequest, "query could not be parsed")
+			return true
+		}
+		if err != nil {
+			continue
+		}
+		switch {
+		case query.Introspection && (flagged || policy.DenyIntrospection):
+			refuseGraphQL(w, req, config, result, ActionBlock, http.StatusForbidden, "introspection is not allowed")
+			return true
+		case flagged && policy.MaxDepth > 0 && query.Depth > policy.MaxDepth:
+			refuseGraphQL(w, req, config, result, ActionBlock, http.StatusForbidden, "query is too deep")
+			return true
+		case flagged && policy.MaxBreadth > 0 && query.Breadth > policy.MaxBreadth:
+			refuseGraphQL(w, req, config, result, ActionBlock, http.StatusForbidden, "query selects too many fields")
+			return true
+		}
+
+		operation := params.OperationName
+		if operation == "" {
+			operation = query.Operation
+		}
+		if retryAfter, limited := d.limitGraphQLOperation(req, policy, operation); limited {
+			w.Header().Set("Retry-After", deltaSeconds(retryAfter))
+			refuseGraphQL(w, req, config, result, ActionRateLimit, http.StatusTooManyRequests, "too many requests for operation "+operation)
+			return true
+		}
+	}
+	return false
+}
+
+// limitGraphQLOperation counts the request against the visitor's operation limit, returning the
+// time left in the window when it is over
+func (d *BotDetector) limitGraphQLOperation(req *http.Request, policy *GraphQLPolicy, operation string) (time.Duration, bool) {
+	if policy.OperationLimit <= 0 || policy.store == nil {
+		return 0, false
+	}
+	components, _ := GetComponentsFromContext(req.Context())
+	visitor := d.visitorKey(req, components)
+	if visitor == "" {
+		return 0, false
+	}
+	if operation == "" {
+		operation = "anonymous"
+	}
+
+	window := policy.window()
+	now := time.Now()
+	key := policy.Prefix + "op:" + visitor + ":" + operation + ":" + windowBucket(now, window)
+	count, err := policy.store.Incr(req.Context(), key, 1, 2*window)
+	if err != nil {
+		if policy.OnError != nil {
+			policy.OnError(err)
+		}
+		return 0, false
+	}
+	if count <= policy.OperationLimit {
+		return 0, false
+	}
+	end := time.Unix(0, (now.UnixNano()/int64(window)+1)*int64(window))
+	return end.Sub(now), true
+}
+
+// refuseGraphQL answers with a GraphQL error response
+func refuseGraphQL(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, result *BotDetectionResult, action Action, statusCode int, message string) {
+	if config.OnAction != nil {
+		config.OnAction(req, result, action)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]any{{
+			"message":    message,
+			"extensions": map[string]any{"code": "BOT_PROTECTION"},
+		}},
+	})
+}
+
+// graphQLMaxNesting bounds the nesting the query parser follows; deeper documents are reported
+// with a depth past it
+const graphQLMaxNesting = 256
+
+// errGraphQLSyntax is returned for documents InspectGraphQL cannot parse
+var errGraphQLSyntax = errors.New("graphql: syntax error")
+
+// InspectGraphQL measures a GraphQL document without validating it against a schema
+func InspectGraphQL(document string) (GraphQLQuery, error) {
+	p := &graphQLParser{lexer: graphQLLexer{src: document}, fragments: map[string]*graphQLSet{}}
+	p.advance()
+	if err := p.parseDocument(); err != nil {
+		if p.tooDeep {
+			return GraphQLQuery{Operation: p.operation, Depth: graphQLMaxNesting + 1, Introspection: p.introspection}, nil
+		}
+		return GraphQLQuery{}, err
+	}
+
+	query := GraphQLQuery{Operation: p.operation, Introspection: p.introspection}
+	depths := map[*graphQLSet]int{}
+	for _, set := range p.operations {
+		query.Depth = max(query.Depth, p.depth(set, depths, map[*graphQLSet]bool{}))
+	}
+	for _, set := range p.sets {
+		query.Breadth = max(query.Breadth, set.fields+len(set.spreads))
+	}
+	return query, nil
+}
+
+// graphQLSet is a selection set, with the fields of its inline fragments
+type graphQLSet struct {
+	fields   int
+	children []*graphQLSet
+	spreads  []string
+}
+
+// graphQLLexer splits a GraphQL document into tokens
+type graphQLLexer struct {
+	src string
+	pos int
+}
+
+// GraphQL token kinds
+const (
+	graphQLEOF byte = iota
+	graphQLName
+	graphQLPunct
+	graphQLValue
+)
+
+// next returns the next token, skipping byte order marks, whitespace, commas and comments
+func (l *graphQLLexer) next() (byte, string) {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		case c == '#':
+			// Comments end at any line terminator: \n, \r or both
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '"':
+			start := l.pos
+			l.skipString()
+			return graphQLValue, l.src[start:l.pos]
+		case c == '.':
+			if len(l.src)-l.pos >= 3 && l.src[l.pos:l.pos+3] == "..." {
+				l.pos += 3
+				return graphQLPunct, "..."
+			}
+			l.pos++
+			return graphQLValue, "."
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := l.pos
+			for l.pos < len(l.src) && isGraphQLNameChar(l.src[l.pos]) {
+				l.pos++
+			}
+			return graphQLName, l.src[start:l.pos]
+		case c == '-' || c >= '0' && c <= '9':
+			start := l.pos
+			l.pos++
+			for l.pos < len(l.src) && (isGraphQLNameChar(l.src[l.pos]) || strings.IndexByte(".+-", l.src[l.pos]) >= 0) {
+				l.pos++
+			}
+			return graphQLValue, l.src[start:l.pos]
+		default:
+			l.pos++
+			return graphQLPunct, string(c)
+		}
+	}
+	return graphQLEOF, ""
+}
+
+// skipString moves past a string or block string starting at pos
+func (l *graphQLLexer) skipString() {
+	if len(l.src)-l.pos >= 3 && l.src[l.pos:l.pos+3] == `"""` {
+		l.pos += 3
+		for l.pos < len(l.src) {
+			if l.src[l.pos] == '\\' && len(l.src)-l.pos >= 4 && l.src[l.pos+1:l.pos+4] == `"""` {
+				l.pos += 4
+				continue
+			}
+			if len(l.src)-l.pos >= 3 && l.src[l.pos:l.pos+3] == `"""` {
+				l.pos += 3
+				return
+			}
+			l.pos++
+		}
+		return
+	}
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '"':
+			l.pos++
+			return
+		case '\n', '\r':
+			return
+		}
+	}
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// graphQLParser collects the selection sets of a document
+type graphQLParser struct {
+	lexer graphQLLexer
+	kind  byte
+	text  string
+
+	operation     string
+	operations    []*graphQLSet
+	fragments     map[string]*graphQLSet
+	sets          []*graphQLSet
+	introspection bool
+	nesting       int
+	tooDeep       bool
+}
+
+func (p *graphQLParser) advance() {
+	p.kind, p.text = p.lexer.next()
+}
+
+func (p *graphQLParser) is(kind byte, text string) bool {
+	return p.kind == kind && p.text == text
+}
+
+// expect consumes a token of kind, returning its text
+func (p *graphQLParser) expect(kind byte, text string) (string, error) {
+	if p.kind != kind || text != "" && p.text != text {
+		return "", errGraphQLSyntax
+	}
+	got := p.text
+	p.advance()
+	return got, nil
+}
+
+func (p *graphQLParser) parseDocument() error {
+	for first := true; p.kind != graphQLEOF; {
+		switch {
+		case p.is(graphQLName, "fragment"):
+			p.advance()
+			name, err := p.expect(graphQLName, "")
+			if err != nil {
+				return err
+			}
+			if _, err := p.expect(graphQLName, "on"); err != nil {
+				return err
+			}
+			if _, err := p.expect(graphQLName, ""); err != nil {
+				return err
+			}
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return err
+			}
+			p.fragments[name] = set
+		case p.is(graphQLName, "query"), p.is(graphQLName, "mutation"), p.is(graphQLName, "subscription"):
+			p.advance()
+			if p.kind == graphQLName {
+				if first {
+					p.operation = p.text
+				}
+				p.advance()
+			}
+			if p.is(graphQLPunct, "(") {
+				if err := p.skipBalanced(); err != nil {
+					return err
+				}
+			}
+			fallthrough
+		case p.is(graphQLPunct, "{"):
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return err
+			}
+			p.operations = append(p.operations, set)
+			first = false
+		default:
+			return errGraphQLSyntax
+		}
+	}
+	return nil
+}
+
+// parseSelectionSet parses directives and the selection set following them
+func (p *graphQLParser) parseSelectionSet() (*graphQLSet, error) {
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	set := &graphQLSet{}
+	p.sets = append(p.sets, set)
+	return set, p.parseSelections(set)
+}
+
+// parseSelections parses a selection set into set, merging inline fragments
+func (p *graphQLParser) parseSelections(set *graphQLSet) error {
+	if _, err := p.expect(graphQLPunct, "{"); err != nil {
+		return err
+	}
+	if p.nesting++; p.nesting > graphQLMaxNesting {
+		p.tooDeep = true
+		return errGraphQLSyntax
+	}
+	defer func() { p.nesting-- }()
+
+	for !p.is(graphQLPunct, "}") {
+		switch {
+		case p.is(graphQLPunct, "..."):
+			p.advance()
+			if p.kind == graphQLName && p.text != "on" {
+				set.spreads = append(set.spreads, p.text)
+				p.advance()
+				if err := p.skipDirectives(); err != nil {
+					return err
+				}
+				continue
+			}
+			if p.is(graphQLName, "on") {
+				p.advance()
+				if _, err := p.expect(graphQLName, ""); err != nil {
+					return err
+				}
+			}
+			if err := p.skipDirectives(); err != nil {
+				return err
+			}
+			// Inline fragments select fields of the enclosing set
+			p.nesting--
+			err := p.parseSelections(set)
+			p.nesting++
+			if err != nil {
+				return err
+			}
+		case p.kind == graphQLName:
+			field := p.text
+			p.advance()
+			if p.is(graphQLPunct, ":") {
+				p.advance()
+				var err error
+				if field, err = p.expect(graphQLName, ""); err != nil {
+					return err
+				}
+			}
+			set.fields++
+			if field == "__schema" || field == "__type" {
+				p.introspection = true
+			}
+			if p.is(graphQLPunct, "(") {
+				if err := p.skipBalanced(); err != nil {
+					return err
+				}
+			}
+			if err := p.skipDirectives(); err != nil {
+				return err
+			}
+			if p.is(graphQLPunct, "{") {
+				child, err := p.parseSelectionSet()
+				if err != nil {
+					return err
+				}
+				set.children = append(set.children, child)
+			}
+		default:
+			return errGraphQLSyntax
+		}
+	}
+	p.advance()
+	return nil
+}
+
+// skipDirectives moves past directives such as @include(if: $flag)
+func (p *graphQLParser) skipDirectives() error {
+	for p.is(graphQLPunct, "@") {
+		p.advance()
+		if _, err := p.expect(graphQLName, ""); err != nil {
+			return err
+		}
+		if p.is(graphQLPunct, "(") {
+			if err := p.skipBalanced(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipBalanced moves past arguments or variable definitions, including nested input objects
+func (p *graphQLParser) skipBalanced() error {
+	open := 0
+	for {
+		switch {
+		case p.kind == graphQLEOF:
+			return errGraphQLSyntax
+		case p.is(graphQLPunct, "("), p.is(graphQLPunct, "["), p.is(graphQLPunct, "{"):
+			open++
+		case p.is(graphQLPunct, ")"), p.is(graphQLPunct, "]"), p.is(graphQLPunct, "}"):
+			open--
+		}
+		p.advance()
+		if open == 0 {
+			return nil
+		}
+	}
+}
+
+// depth returns the nesting of a selection set with fragment spreads expanded; fragments
+// spreading themselves count once
+func (p *graphQLParser) depth(set *graphQLSet, depths map[*graphQLSet]int, visiting map[*graphQLSet]bool) int {
+	if depth, ok := depths[set]; ok {
+		return depth
+	}
+	if visiting[set] {
+		return 0
+	}
+	visiting[set] = true
+	defer delete(visiting, set)
+
+	deepest := 0
+	for _, child := range set.children {
+		deepest = max(deepest, p.depth(child, depths, visiting))
+	}
+	for _, name := range set.spreads {
+		if fragment, ok := p.fragments[name]; ok {
+			// Spread fields belong to this set
+			deepest = max(deepest, p.depth(fragment, depths, visiting)-1)
+		}
+	}
+	depths[set] = deepest + 1
+	return deepest + 1
+}
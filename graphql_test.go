@@ -0,0 +1,101 @@
+package gogobot
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInspectGraphQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected GraphQLQuery
+	}{
+		{"shorthand", `{ me { name } }`, GraphQLQuery{Depth: 2, Breadth: 1}},
+		{"named", `query Products($first: Int = 10) { products(first: $first, filter: {tags: ["a", "b"]}) { edges { node { id name price } } } }`, GraphQLQuery{Operation: "Products", Depth: 4, Breadth: 3}},
+		{"aliases and directives", `query { a: user(id: 1) @include(if: true) { id } b: user(id: 2) { id } }`, GraphQLQuery{Depth: 2, Breadth: 2}},
+		{"inline fragment", `{ node(id: "x") { id ... on User { name email } } }`, GraphQLQuery{Depth: 2, Breadth: 3}},
+		{"fragments expanded", `query Deep { a { ...F } } fragment F on A { b { c { d } } }`, GraphQLQuery{Operation: "Deep", Depth: 4, Breadth: 1}},
+		{"recursive fragment", `{ a { ...F } } fragment F on A { b { ...F } }`, GraphQLQuery{Depth: 3, Breadth: 1}},
+		{"introspection", `query IntrospectionQuery { __schema { types { name } } }`, GraphQLQuery{Operation: "IntrospectionQuery", Depth: 3, Breadth: 1, Introspection: true}},
+		{"comment ended by a carriage return", "# x\r{ __schema { types { name } } }", GraphQLQuery{Depth: 3, Breadth: 1, Introspection: true}},
+		{"byte order mark", "\ufeffquery Q { a { b } }", GraphQLQuery{Operation: "Q", Depth: 2, Breadth: 1}},
+		{"strings and comments", "{ # } {\n search(q: \"}{ \\\" \", body: \"\"\" { \"\"\") { id } }", GraphQLQuery{Depth: 2, Breadth: 1}},
+		{"too deep", strings.Repeat("{ a ", 300) + strings.Repeat("}", 300), GraphQLQuery{Depth: graphQLMaxNesting + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InspectGraphQL(tt.document)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := InspectGraphQL(`{ unclosed { id }`); err == nil {
+		t.Error("Expected a syntax error")
+	}
+}
+
+func TestGraphQLMiddleware(t *testing.T) {
+	policy := NewGraphQLPolicy(NewMemoryStore(0))
+	policy.MaxDepth = 3
+	policy.OperationLimit = 2
+	var reported []Action
+	config := DefaultMiddlewareConfig()
+	config.OnAction = func(r *http.Request, result *BotDetectionResult, action Action) {
+		reported = append(reported, action)
+	}
+	var body string
+	handler := NewDetector().GraphQLMiddleware(config, policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+
+	post := func(userAgent, remoteAddr, payload string) int {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(payload))
+		req.RemoteAddr = remoteAddr
+		req.Header = newBrowserRequest("/graphql", remoteAddr).Header
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	deep := `{"query": "query Deep { a { b { c { d } } } }"}`
+	introspection := `{"query": "{ __schema { types { name } } }"}`
+	if code := post(testBrowserUA, "203.0.113.7:1234", deep); code != http.StatusOK || body != deep {
+		t.Errorf("Expected deep query from a browser to reach the server with its body, got %d %q", code, body)
+	}
+	if code := post("curl/8.4.0", "203.0.113.8:1234", deep); code != http.StatusForbidden {
+		t.Errorf("Expected deep query from curl to be refused, got %d", code)
+	}
+	if code := post("curl/8.4.0", "203.0.113.8:1234", introspection); code != http.StatusForbidden {
+		t.Errorf("Expected introspection from curl to be refused, got %d", code)
+	}
+	if code := post(testBrowserUA, "203.0.113.9:1234", `[{"query": "query List { items { id } }"}, {"query": "query List { items { id } }"}]`); code != http.StatusOK {
+		t.Errorf("Expected batch within the operation limit, got %d", code)
+	}
+	if code := post(testBrowserUA, "203.0.113.9:1234", `{"query": "query List { items { id } }"}`); code != http.StatusTooManyRequests {
+		t.Errorf("Expected third List operation to be rate limited, got %d", code)
+	}
+	if len(reported) != 3 || reported[2] != ActionRateLimit {
+		t.Errorf("Expected refusals to be reported, got %v", reported)
+	}
+
+	// Documents the inspector cannot parse are only forwarded for clients not flagged
+	unparsable := `{"query": "{ __schema { types { name } "}`
+	if code := post("curl/8.4.0", "203.0.113.10:1234", unparsable); code != http.StatusBadRequest {
+		t.Errorf("Expected unparsable query from curl to be refused, got %d", code)
+	}
+	if code := post(testBrowserUA, "203.0.113.11:1234", unparsable); code != http.StatusOK {
+		t.Errorf("Expected unparsable query from a browser to reach the server, got %d", code)
+	}
+}
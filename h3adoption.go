@@ -0,0 +1,169 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// H3Adoption tracks whether visitors switch to HTTP/3 once it is advertised in Alt-Svc. Browsers
+// remember the advertisement and use HTTP/3 on their next connections, while most HTTP
+// libraries never do, so a visitor that keeps using HTTP/1.1 or HTTP/2 after many
+// advertisements is a weak bot signal. The server, or the proxy in front of it, must serve
+// HTTP/3.
+type H3Adoption struct {
+	// AltSvc is the Alt-Svc header the middleware adds to responses without one, e.g.
+	// `h3=":443"; ma=86400`; empty only counts responses whose handlers advertise HTTP/3
+	AltSvc string
+	// Header is the request header in which a proxy reports the client's protocol, as for
+	// ProtocolPolicy; empty uses the request's own protocol
+	Header string
+	// MinAdvertised is the number of advertising responses in a window after which visitors
+	// still not using HTTP/3 are suspicious
+	MinAdvertised int64
+	// Window is the length of the fixed counting window; zero uses DefaultSessionWindow
+	Window time.Duration
+}
+
+// DefaultH3Adoption advertises HTTP/3 on port 443 and expects visitors to use it within 20
+// responses in 30 minutes
+func DefaultH3Adoption() *H3Adoption {
+	return &H3Adoption{
+		AltSvc:        `h3=":443"; ma=86400`,
+		MinAdvertised: 20,
+		Window:        30 * time.Minute,
+	}
+}
+
+// H3Usage is the number of responses advertising HTTP/3 a visitor received in the current
+// window, and the number of its requests made over HTTP/3 including the current one
+type H3Usage struct {
+	Advertised    int64 `json:"advertised"`
+	H3            int64 `json:"h3"`
+	MinAdvertised int64 `json:"minAdvertised"`
+}
+
+// window returns the counting window
+func (a *H3Adoption) window() time.Duration {
+	if a.Window <= 0 {
+		return DefaultSessionWindow
+	}
+	return a.Window
+}
+
+// h3AdoptionKeys returns the store keys counting the visitor's advertisements and HTTP/3
+// requests in the current window
+func h3AdoptionKeys(tracker *SessionTracker, key string, window time.Duration) (advertised, h3 string) {
+	bucket := windowBucket(tracker.now(), window)
+	return tracker.Prefix + "altsvc:" + key + ":" + bucket, tracker.Prefix + "h3:" + key + ":" + bucket
+}
+
+// advertisesH3 reports whether an Alt-Svc header offers HTTP/3, final or draft
+func advertisesH3(altSvc string) bool {
+	for _, alternative := range strings.Split(altSvc, ",") {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(alternative)), "h3") {
+			return true
+		}
+	}
+	return false
+}
+
+// advertiseH3 adds the configured Alt-Svc header to a response without one
+func (a *H3Adoption) advertiseH3(w http.ResponseWriter) {
+	if a.AltSvc != "" && w.Header().Get("Alt-Svc") == "" {
+		w.Header().Set("Alt-Svc", a.AltSvc)
+	}
+}
+
+// RecordAltSvc counts a response advertising HTTP/3 in its Alt-Svc header for the request's
+// visitor, for applications not using the middleware. It does nothing without an H3Adoption
+// and SessionTracker.
+func (d *BotDetector) RecordAltSvc(req *http.Request, header http.Header) error {
+	return d.recordAltSvc(req, nil, header)
+}
+
+func (d *BotDetector) recordAltSvc(req *http.Request, components *ComponentDict, header http.Header) error {
+	adoption, tracker := d.config.H3Adoption, d.config.SessionTracker
+	if adoption == nil || tracker == nil || !advertisesH3(header.Get("Alt-Svc")) {
+		return nil
+	}
+	key := d.visitorKey(req, components)
+	if key == "" {
+		return errNoSessionKey
+	}
+
+	window := adoption.window()
+	advertisedKey, _ := h3AdoptionKeys(tracker, key, window)
+	_, err := tracker.store.Incr(req.Context(), advertisedKey, 1, 2*window)
+	return err
+}
+
+// getH3Usage counts the request when made over HTTP/3 and reads the visitor's counts in the
+// current window
+func getH3Usage(req *http.Request, components *ComponentDict, tracker *SessionTracker, adoption *H3Adoption) Component[H3Usage] {
+	if tracker == nil || adoption == nil {
+		return ErrorComponent[H3Usage]{
+			State: StateUndefined,
+			Error: "HTTP/3 adoption requires a session tracker and configuration",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[H3Usage]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	major := req.ProtoMajor
+	if adoption.Header != "" {
+		protocol, _ := parseProtocol(req.Header.Get(adoption.Header))
+		major = protocol.Major
+	}
+	var delta int64
+	if major == 3 {
+		delta = 1
+	}
+
+	window := adoption.window()
+	advertisedKey, h3Key := h3AdoptionKeys(tracker, key, window)
+	usage := H3Usage{MinAdvertised: adoption.MinAdvertised}
+	var err error
+	if usage.H3, err = tracker.store.Incr(req.Context(), h3Key, delta, 2*window); err == nil {
+		usage.Advertised, err = tracker.store.Incr(req.Context(), advertisedKey, 0, 2*window)
+	}
+	if err != nil {
+		return ErrorComponent[H3Usage]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	return SuccessComponent[H3Usage]{
+		State: StateSuccess,
+		Value: usage,
+	}
+}
+
+// detectH3Adoption weakly scores browser user agents that never used HTTP/3 in a window in which
+// they were offered it at least MinAdvertised times
+func detectH3Adoption(components *ComponentDict) *BotDetectionResult {
+	if components.H3Usage.GetState() != StateSuccess || components.UserAgent.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+	userAgent := components.UserAgent.GetValue()
+	if isBot, _ := IsBotUserAgent(userAgent); isBot || parseEngine(userAgent) == "" {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	usage := components.H3Usage.GetValue()
+	if usage.MinAdvertised <= 0 || usage.Advertised < usage.MinAdvertised || usage.H3 > 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindUnknown,
+		Score:   0.2,
+		Reasons: []string{fmt.Sprintf("no HTTP/3 request after %d Alt-Svc advertisements", usage.Advertised)},
+	}
+}
@@ -0,0 +1,115 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newH3AdoptionDetector(adoption *H3Adoption) *BotDetector {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.H3Adoption = adoption
+	return NewDetectorWithConfig(config)
+}
+
+func TestAdvertisesH3(t *testing.T) {
+	tests := []struct {
+		altSvc   string
+		expected bool
+	}{
+		{`h3=":443"; ma=86400`, true},
+		{`h2=":443", h3-29=":443"`, true},
+		{`h2="alt.example.com:443"`, false},
+		{"clear", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := advertisesH3(tt.altSvc); got != tt.expected {
+			t.Errorf("Expected %t for %q, got %t", tt.expected, tt.altSvc, got)
+		}
+	}
+}
+
+func TestMiddleware_H3Adoption(t *testing.T) {
+	adoption := DefaultH3Adoption()
+	adoption.MinAdvertised = 3
+	detector := newH3AdoptionDetector(adoption)
+	handler := detector.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newBrowserRequest("/", "203.0.113.7:1234"))
+		if altSvc := rec.Header().Get("Alt-Svc"); altSvc != adoption.AltSvc {
+			t.Fatalf("Expected Alt-Svc %q, got %q", adoption.AltSvc, altSvc)
+		}
+	}
+
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if detection := detector.GetDetections().H3Adoption; detection.Bot || detection.Score != 0.2 {
+		t.Errorf("Expected weak HTTP/3 adoption signal, got %+v", detection)
+	}
+
+	// A single HTTP/3 request clears the visitor for the window
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/3.0", 3, 0
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	req = newBrowserRequest("/", "203.0.113.7:1234")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	usage := detector.GetComponents().H3Usage.GetValue()
+	if usage.H3 != 1 || detector.GetDetections().H3Adoption.Score != 0 {
+		t.Errorf("Expected HTTP/3 visitor not to be scored, got %+v", usage)
+	}
+}
+
+func TestH3Adoption_HandlerAdvertisement(t *testing.T) {
+	detector := newH3AdoptionDetector(&H3Adoption{MinAdvertised: 1, Header: "X-Client-Proto"})
+	handler := detector.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/advertised" {
+			w.Header().Set("Alt-Svc", `h3=":8443"`)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newBrowserRequest("/plain", "203.0.113.7:1234"))
+	if altSvc := rec.Header().Get("Alt-Svc"); altSvc != "" {
+		t.Errorf("Expected no Alt-Svc without AltSvc configured, got %q", altSvc)
+	}
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if usage := detector.GetComponents().H3Usage.GetValue(); usage.Advertised != 0 {
+		t.Errorf("Expected no advertisement counted, got %+v", usage)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), newBrowserRequest("/advertised", "203.0.113.7:1234"))
+	req.Header.Set("X-Client-Proto", "h3")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if usage := detector.GetComponents().H3Usage.GetValue(); usage.Advertised != 1 || usage.H3 != 1 {
+		t.Errorf("Expected advertisement and proxied HTTP/3 request, got %+v", usage)
+	}
+}
+
+func TestDetectH3Adoption_SkipsNonBrowsers(t *testing.T) {
+	usage := SuccessComponent[H3Usage]{State: StateSuccess, Value: H3Usage{Advertised: 50, MinAdvertised: 20}}
+	for _, userAgent := range []string{"curl/8.4.0", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"} {
+		components := &ComponentDict{
+			UserAgent: SuccessComponent[string]{State: StateSuccess, Value: userAgent},
+			H3Usage:   usage,
+		}
+		if result := detectH3Adoption(components); result.Score != 0 {
+			t.Errorf("Expected %q not to be scored, got %+v", userAgent, result)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package gogobot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HeaderOrderListener wraps a net.Listener to record the order in which clients send HTTP/1.x
+// request headers, which Go's http.Header map does not keep. Browsers and HTTP libraries each
+// send headers in a characteristic order, so a user agent claiming to be a browser while
+// ordering headers like a library is spoofed.
+//
+// Serve with HeaderOrderConnContext as the server's ConnContext. The listener must see the
+// plaintext request: to serve TLS, wrap a tls.NewListener whose configuration only offers
+// "http/1.1". The server then cannot tell the connection is encrypted, so requests have no
+// TLS state, and HTTP/2 is unavailable.
+type HeaderOrderListener struct {
+	net.Listener
+}
+
+// NewHeaderOrderListener wraps l to record header orders
+func NewHeaderOrderListener(l net.Listener) *HeaderOrderListener {
+	return &HeaderOrderListener{Listener: l}
+}
+
+// Accept implements net.Listener
+func (l *HeaderOrderListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &headerOrderConn{Conn: conn}, nil
+}
+
+// HeaderOrderConnContext is an http.Server ConnContext function making the header orders
+// recorded by a HeaderOrderListener available to the detector
+func HeaderOrderConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if c, ok := conn.(*headerOrderConn); ok {
+		return context.WithValue(ctx, headerOrderConnKey, c)
+	}
+	return ctx
+}
+
+const headerOrderConnKey contextKey = "gogobot_header_order_conn"
+
+// Header order parsing limits
+const (
+	maxHeaderLineLength = 16 << 10
+	maxPendingOrders    = 8
+)
+
+// headerParseState is the part of an HTTP/1.x message a headerOrderConn is reading
+type headerParseState int
+
+const (
+	parseRequestLine headerParseState = iota
+	parseHeaders
+	parseBody
+	parseChunkSize
+	parseChunkData
+	parseTrailers
+	// parseDone stops parsing for the rest of the connection, after an upgrade or a message
+	// that could not be parsed
+	parseDone
+)
+
+// headerOrderConn parses the request heads read from the connection, skipping bodies, and
+// queues their header names in wire order until requests claim them
+type headerOrderConn struct {
+	net.Conn
+
+	mu        sync.Mutex
+	state     headerParseState
+	line      []byte
+	names     []string
+	method    string
+	length    int64
+	chunked   bool
+	upgrade   bool
+	remaining int64
+	pending   [][]string
+	last      []string
+}
+
+func (c *headerOrderConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.mu.Lock()
+		c.parse(p[:n])
+		c.mu.Unlock()
+	}
+	return n, err
+}
+
+// parse consumes data read from the connection. The connection must be locked.
+func (c *headerOrderConn) parse(data []byte) {
+	for len(data) > 0 && c.state != parseDone {
+		if c.state == parseBody || c.state == parseChunkData {
+			skip := min(c.remaining, int64(len(data)))
+			data = data[skip:]
+			c.remaining -= skip
+			if c.remaining == 0 {
+				if c.state == parseBody {
+					c.state = parseRequestLine
+				} else {
+					c.state = parseChunkSize
+				}
+			}
+			continue
+		}
+
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			c.appendLine(data)
+			return
+		}
+		c.appendLine(data[:end])
+		data = data[end+1:]
+		line := strings.TrimSuffix(string(c.line), "\r")
+		c.line = c.line[:0]
+		c.parseLine(line)
+	}
+}
+
+func (c *headerOrderConn) appendLine(data []byte) {
+	if len(c.line)+len(data) > maxHeaderLineLength {
+		c.state = parseDone
+		return
+	}
+	c.line = append(c.line, data...)
+}
+
+// parseLine handles a complete line of a request head or chunked body
+func (c *headerOrderConn) parseLine(line string) {
+	switch c.state {
+	case parseRequestLine:
+		// Clients may send empty lines between requests
+		if line == "" {
+			return
+		}
+		c.method, _, _ = strings.Cut(line, " ")
+		c.names, c.length, c.chunked, c.upgrade = nil, 0, false, false
+		c.state = parseHeaders
+	case parseHeaders:
+		if line != "" {
+			c.parseHeader(line)
+			return
+		}
+		c.enqueue(c.names)
+		switch {
+		case c.upgrade || c.method == http.MethodConnect:
+			c.state = parseDone
+		case c.chunked:
+			c.state = parseChunkSize
+		case c.length > 0:
+			c.state, c.remaining = parseBody, c.length
+		default:
+			c.state = parseRequestLine
+		}
+	case parseChunkSize:
+		sizeField, _, _ := strings.Cut(line, ";")
+		size, err := strconv.ParseInt(strings.TrimSpace(sizeField), 16, 64)
+		switch {
+		case err != nil || size < 0:
+			c.state = parseDone
+		case size == 0:
+			c.state = parseTrailers
+		default:
+			// The chunk data is followed by CRLF
+			c.state, c.remaining = parseChunkData, size+2
+		}
+	case parseTrailers:
+		if line == "" {
+			c.state = parseRequestLine
+		}
+	}
+}
+
+// parseHeader records a header line's name and the framing it implies
+func (c *headerOrderConn) parseHeader(line string) {
+	// Folded continuation lines carry no name
+	if line[0] == ' ' || line[0] == '\t' {
+		return
+	}
+	name, value, ok := strings.Cut(line, ":")
+	if !ok {
+		c.state = parseDone
+		return
+	}
+	c.names = append(c.names, name)
+	value = strings.TrimSpace(value)
+	switch textproto.CanonicalMIMEHeaderKey(name) {
+	case "Content-Length":
+		length, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || length < 0 {
+			c.state = parseDone
+			return
+		}
+		c.length = length
+	case "Transfer-Encoding":
+		c.chunked = strings.Contains(strings.ToLower(value), "chunked")
+	case "Upgrade":
+		c.upgrade = true
+	}
+}
+
+// enqueue adds a request's header order, dropping the oldest beyond maxPendingOrders
+func (c *headerOrderConn) enqueue(names []string) {
+	if len(c.pending) == maxPendingOrders {
+		c.pending = c.pending[1:]
+	}
+	c.pending = append(c.pending, names)
+}
+
+// take returns the header order of the request whose headers are header. Orders of requests
+// that were never claimed, e.g. because detection was skipped, are discarded on the way.
+func (c *headerOrderConn) take(header http.Header) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, names := range c.pending {
+		if sameHeaderNames(names, header) {
+			c.pending = c.pending[i+1:]
+			c.last = names
+			return names, true
+		}
+	}
+	// The same request may be collected more than once
+	if c.last != nil && sameHeaderNames(c.last, header) {
+		return c.last, true
+	}
+	return nil, false
+}
+
+// sameHeaderNames reports whether names are the headers of header. The server moves Host and
+// the framing headers out of the map.
+func sameHeaderNames(names []string, header http.Header) bool {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := textproto.CanonicalMIMEHeaderKey(name)
+		switch key {
+		case "Host", "Transfer-Encoding", "Trailer":
+			continue
+		}
+		if _, ok := header[key]; !ok {
+			return false
+		}
+		seen[key] = true
+	}
+	return len(seen) == len(header)
+}
+
+// getHeaderOrder returns the request's header names in the order the client sent them, as
+// recorded by a HeaderOrderListener
+func getHeaderOrder(req *http.Request) Component[[]string] {
+	conn, ok := req.Context().Value(headerOrderConnKey).(*headerOrderConn)
+	if !ok {
+		return ErrorComponent[[]string]{
+			State: StateUndefined,
+			Error: "header order requires a HeaderOrderListener",
+		}
+	}
+	order, ok := conn.take(req.Header)
+	if !ok {
+		return ErrorComponent[[]string]{
+			State: StateNull,
+			Error: "no header order recorded for request",
+		}
+	}
+	return SuccessComponent[[]string]{
+		State: StateSuccess,
+		Value: order,
+	}
+}
+
+// engineHeaderOrders are the orders in which the browsers of each rendering engine send the
+// headers they have in common on HTTP/1.1 requests; other headers may appear in between
+var engineHeaderOrders = map[string][]string{
+	EngineBlink: {
+		"host", "connection", "content-length", "cache-control", "sec-ch-ua", "sec-ch-ua-mobile",
+		"sec-ch-ua-platform", "upgrade-insecure-requests", "user-agent", "content-type", "accept",
+		"origin", "sec-fetch-site", "sec-fetch-mode", "sec-fetch-user", "sec-fetch-dest",
+		"referer", "accept-encoding", "accept-language", "cookie",
+	},
+	EngineGecko: {
+		"host", "user-agent", "accept", "accept-language", "accept-encoding", "content-type",
+		"content-length", "origin", "referer", "connection", "cookie",
+		"upgrade-insecure-requests", "sec-fetch-dest", "sec-fetch-mode", "sec-fetch-site",
+		"sec-fetch-user", "priority",
+	},
+	EngineWebKit: {
+		"host", "content-type", "origin", "accept", "sec-fetch-site", "cookie", "content-length",
+		"sec-fetch-dest", "accept-language", "sec-fetch-mode", "user-agent", "referer",
+		"accept-encoding", "connection",
+	},
+}
+
+// headerOrderInversions counts the pairs of headers known to model that order sends the other
+// way round, and the number of known headers
+func headerOrderInversions(order, model []string) (inversions, known int) {
+	rank := make(map[string]int, len(model))
+	for i, name := range model {
+		rank[name] = i
+	}
+	var ranks []int
+	for _, name := range order {
+		if r, ok := rank[strings.ToLower(name)]; ok {
+			ranks = append(ranks, r)
+		}
+	}
+	for i := range ranks {
+		for j := i + 1; j < len(ranks); j++ {
+			if ranks[i] > ranks[j] {
+				inversions++
+			}
+		}
+	}
+	return inversions, len(ranks)
+}
+
+// detectHeaderOrder scores browser user agents whose headers arrived in an order their engine
+// does not send. A single swapped pair is tolerated, as browser versions differ slightly.
+func detectHeaderOrder(components *ComponentDict) *BotDetectionResult {
+	if components.HeaderOrder.GetState() != StateSuccess || components.UserAgent.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	userAgent := components.UserAgent.GetValue()
+	if isBot, _ := IsBotUserAgent(userAgent); isBot {
+		return &BotDetectionResult{Bot: false}
+	}
+	engine := parseEngine(userAgent)
+	model, ok := engineHeaderOrders[engine]
+	if !ok {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	inversions, known := headerOrderInversions(components.HeaderOrder.GetValue(), model)
+	if known < 3 || inversions < 2 {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindUnknown,
+		Score:   0.6,
+		Reasons: []string{fmt.Sprintf("header order of a %s user agent has %d swapped pairs", engine, inversions)},
+	}
+}
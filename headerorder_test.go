@@ -0,0 +1,149 @@
+package gogobot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHeaderOrderConn_Parse(t *testing.T) {
+	stream := "GET / HTTP/1.1\r\nHost: example.com\r\nUser-Agent: test\r\nAccept: */*\r\n\r\n" +
+		"POST /form HTTP/1.1\r\nHost: example.com\r\nContent-Length: 27\r\nContent-Type: text/plain\r\n\r\n" +
+		"Fake: header\r\nNot: parsed\r\n" +
+		"\r\nPOST /upload HTTP/1.1\r\nhost: example.com\r\ntransfer-encoding: chunked\r\n\r\n" +
+		"6;ext=1\r\nA: b\r\n\r\n0\r\nTrailer-Field: x\r\n\r\n" +
+		"GET /ws HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n" +
+		"GET /after-upgrade HTTP/1.1\r\n\r\n"
+	expected := [][]string{
+		{"Host", "User-Agent", "Accept"},
+		{"Host", "Content-Length", "Content-Type"},
+		{"host", "transfer-encoding"},
+		{"Host", "Upgrade", "Connection"},
+	}
+
+	// Feed the stream in reads of every size to exercise lines split across reads
+	for size := 1; size <= len(stream); size++ {
+		conn := &headerOrderConn{}
+		for data := stream; data != ""; {
+			n := min(size, len(data))
+			conn.parse([]byte(data[:n]))
+			data = data[n:]
+		}
+		if len(conn.pending) != len(expected) {
+			t.Fatalf("Read size %d: expected %d header orders, got %v", size, len(expected), conn.pending)
+		}
+		for i, names := range conn.pending {
+			if !slices.Equal(names, expected[i]) {
+				t.Fatalf("Read size %d: expected %v, got %v", size, expected[i], names)
+			}
+		}
+	}
+}
+
+func TestHeaderOrderConn_Take(t *testing.T) {
+	conn := &headerOrderConn{}
+	conn.parse([]byte("GET / HTTP/1.1\r\nHost: a\r\nAccept: */*\r\n\r\nGET / HTTP/1.1\r\nHost: a\r\nUser-Agent: b\r\nAccept: */*\r\n\r\n"))
+
+	header := http.Header{"User-Agent": {"b"}, "Accept": {"*/*"}}
+	order, ok := conn.take(header)
+	if !ok || !slices.Equal(order, []string{"Host", "User-Agent", "Accept"}) {
+		t.Fatalf("Expected the matching order, got %v (ok=%t)", order, ok)
+	}
+	if len(conn.pending) != 0 {
+		t.Errorf("Expected unclaimed earlier orders to be discarded, got %v", conn.pending)
+	}
+	if order, ok := conn.take(header); !ok || len(order) != 3 {
+		t.Errorf("Expected a second collection of the request to get its order, got %v", order)
+	}
+	if _, ok := conn.take(http.Header{"X-Other": {"1"}}); ok {
+		t.Error("Expected no order for unknown headers")
+	}
+}
+
+func TestDetectHeaderOrder(t *testing.T) {
+	chromeUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	firefoxUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0"
+	tests := []struct {
+		name      string
+		userAgent string
+		order     []string
+		flagged   bool
+	}{
+		{"chrome", chromeUA, []string{"Host", "Connection", "sec-ch-ua", "User-Agent", "Accept", "Sec-Fetch-Site", "Accept-Encoding", "Accept-Language"}, false},
+		{"chrome with one swap", chromeUA, []string{"Host", "Connection", "Accept", "User-Agent", "Accept-Encoding", "Accept-Language"}, false},
+		{"firefox", firefoxUA, []string{"Host", "User-Agent", "Accept", "Accept-Language", "Accept-Encoding", "Connection", "Upgrade-Insecure-Requests"}, false},
+		{"python as chrome", chromeUA, []string{"Host", "User-Agent", "Accept-Encoding", "Accept", "Connection"}, true},
+		{"chrome as firefox", firefoxUA, []string{"Host", "Connection", "User-Agent", "Accept", "Accept-Encoding", "Accept-Language"}, true},
+		{"too few known headers", chromeUA, []string{"Accept", "Host", "X-Custom"}, false},
+		{"bot user agent", "curl/8.4.0", []string{"Accept", "User-Agent", "Host"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detectHeaderOrder(&ComponentDict{
+				UserAgent:   SuccessComponent[string]{State: StateSuccess, Value: tt.userAgent},
+				HeaderOrder: SuccessComponent[[]string]{State: StateSuccess, Value: tt.order},
+			})
+			if (result.Score > 0) != tt.flagged {
+				t.Errorf("Expected flagged=%t, got %+v", tt.flagged, result)
+			}
+		})
+	}
+}
+
+func TestHeaderOrderListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	detector := NewDetector()
+	server := &http.Server{
+		ConnContext: HeaderOrderConnContext,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			components, err := detector.collect(r)
+			if err != nil || components.HeaderOrder.GetState() != StateSuccess {
+				http.Error(w, "no header order", http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprint(w, strings.Join(components.HeaderOrder.GetValue(), ","))
+		}),
+	}
+	go server.Serve(NewHeaderOrderListener(ln))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for _, head := range []string{
+		"POST / HTTP/1.1\r\nHost: a\r\nUser-Agent: x\r\nContent-Length: 4\r\nAccept: */*\r\n\r\nbody",
+		"GET / HTTP/1.1\r\nAccept: */*\r\nuser-agent: x\r\nHost: a\r\n\r\n",
+	} {
+		if _, err := conn.Write([]byte(head)); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.ReadResponse(reader, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := strings.Split(head[strings.Index(head, "\n")+1:strings.Index(head, "\r\n\r\n")], "\r\n")
+		for i, line := range names {
+			names[i], _, _ = strings.Cut(line, ":")
+		}
+		if resp.StatusCode != http.StatusOK || string(body) != strings.Join(names, ",") {
+			t.Errorf("Expected order %v, got %d %q", names, resp.StatusCode, body)
+		}
+	}
+}
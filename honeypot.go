@@ -0,0 +1,121 @@
+package gogobot
+
+import (
+	"html"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultHoneypotDuration is how long a client fetching a trap URL stays flagged
+const DefaultHoneypotDuration = 30 * time.Minute
+
+// Honeypot holds trap URLs that are linked invisibly from pages and disallowed in robots.txt.
+// People never see the links and well-behaved crawlers never follow them, so any client
+// fetching one is flagged as a bot for Duration, on that and all subsequent requests.
+type Honeypot struct {
+	// Paths are the trap URL paths
+	Paths []string
+	// Duration is how long a trapped client stays flagged; zero uses DefaultHoneypotDuration
+	Duration time.Duration
+}
+
+// NewHoneypot creates a honeypot with the given trap paths
+func NewHoneypot(paths ...string) *Honeypot {
+	return &Honeypot{
+		Paths:    paths,
+		Duration: DefaultHoneypotDuration,
+	}
+}
+
+// IsTrap reports whether path is one of the trap paths
+func (h *Honeypot) IsTrap(path string) bool {
+	for _, trap := range h.Paths {
+		if path == trap {
+			return true
+		}
+	}
+	return false
+}
+
+// Link returns markup for invisible links to the trap paths, to be included in pages
+func (h *Honeypot) Link() template.HTML {
+	var b strings.Builder
+	for _, path := range h.Paths {
+		b.WriteString(`<a href="`)
+		b.WriteString(html.EscapeString(path))
+		b.WriteString(`" rel="nofollow" style="display:none" aria-hidden="true" tabindex="-1"></a>`)
+	}
+	return template.HTML(b.String())
+}
+
+// RobotsTxt returns robots.txt rules disallowing the trap paths for all crawlers
+func (h *Honeypot) RobotsTxt() string {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	for _, path := range h.Paths {
+		b.WriteString("Disallow: ")
+		b.WriteString(path)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// isTrapRequest reports whether the detector's honeypot traps the request
+func (d *BotDetector) isTrapRequest(req *http.Request) bool {
+	return d.config.Honeypot != nil && d.config.Honeypot.IsTrap(req.URL.Path)
+}
+
+// getTrapped marks the visitor when the request fetches a trap path and reports whether the
+// visitor is currently marked
+func getTrapped(req *http.Request, components *ComponentDict, tracker *SessionTracker, honeypot *Honeypot) Component[bool] {
+	if tracker == nil || honeypot == nil {
+		return ErrorComponent[bool]{
+			State: StateUndefined,
+			Error: "honeypot requires a session tracker",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[bool]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	storeKey := tracker.Prefix + "trapped:" + key
+	if honeypot.IsTrap(req.URL.Path) {
+		duration := honeypot.Duration
+		if duration <= 0 {
+			duration = DefaultHoneypotDuration
+		}
+		if err := tracker.store.Set(req.Context(), storeKey, []byte(req.URL.Path), duration); err != nil {
+			return ErrorComponent[bool]{
+				State: StateUnexpectedBehaviour,
+				Error: "session store: " + err.Error(),
+			}
+		}
+		return SuccessComponent[bool]{State: StateSuccess, Value: true}
+	}
+
+	_, trapped, err := tracker.store.Get(req.Context(), storeKey)
+	if err != nil {
+		return ErrorComponent[bool]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	return SuccessComponent[bool]{State: StateSuccess, Value: trapped}
+}
+
+// detectHoneypot flags visitors that fetched a trap URL
+func detectHoneypot(components *ComponentDict) *BotDetectionResult {
+	if components.Trapped.GetState() == StateSuccess && components.Trapped.GetValue() {
+		return &BotDetectionResult{
+			Bot:     true,
+			BotKind: BotKindHoneypot,
+		}
+	}
+	return &BotDetectionResult{Bot: false}
+}
@@ -0,0 +1,105 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHoneypot_Markup(t *testing.T) {
+	honeypot := NewHoneypot("/trap", `/a"b`)
+
+	link := string(honeypot.Link())
+	if !strings.Contains(link, `href="/trap"`) || !strings.Contains(link, `display:none`) || !strings.Contains(link, `rel="nofollow"`) {
+		t.Errorf("Unexpected trap link markup: %s", link)
+	}
+	if !strings.Contains(link, `href="/a&#34;b"`) {
+		t.Errorf("Expected trap paths to be escaped: %s", link)
+	}
+
+	expected := "User-agent: *\nDisallow: /trap\nDisallow: /a\"b\n"
+	if robots := honeypot.RobotsTxt(); robots != expected {
+		t.Errorf("Expected %q, got %q", expected, robots)
+	}
+}
+
+func TestGetTrapped(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	tracker := NewSessionTracker(store)
+	honeypot := NewHoneypot("/trap")
+	honeypot.Duration = 10 * time.Minute
+
+	trapped := func(path, remoteAddr string) bool {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = remoteAddr
+		component := getTrapped(req, collectAllSources(req), tracker, honeypot)
+		if component.GetState() != StateSuccess {
+			t.Fatalf("Expected trapped component, got %v", component)
+		}
+		return component.GetValue()
+	}
+
+	if trapped("/", "203.0.113.7:1234") {
+		t.Error("Expected visitor not to be trapped before fetching a trap")
+	}
+	if !trapped("/trap", "203.0.113.7:1234") {
+		t.Error("Expected trap fetch to be trapped")
+	}
+	if !trapped("/", "203.0.113.7:5678") {
+		t.Error("Expected trapped visitor to stay flagged")
+	}
+	if trapped("/", "203.0.113.8:1234") {
+		t.Error("Expected other visitors not to be trapped")
+	}
+
+	now = now.Add(10 * time.Minute)
+	if trapped("/", "203.0.113.7:1234") {
+		t.Error("Expected mark to expire after the honeypot duration")
+	}
+}
+
+func TestBotDetector_Honeypot(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.Honeypot = NewHoneypot("/.well-known/trap")
+	config.ResultCache = NewResultCache(100, time.Minute)
+	detector := NewDetectorWithConfig(config)
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(path string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Accept-Language", "en-US")
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("/"); code != http.StatusOK {
+		t.Fatalf("Expected browser to be served, got %d", code)
+	}
+	// The trap is fetched even though the visitor's verdict is cached
+	if code := serve("/.well-known/trap"); code != http.StatusForbidden {
+		t.Errorf("Expected trap fetch to be blocked, got %d", code)
+	}
+
+	config.ResultCache.Purge()
+	if code := serve("/"); code != http.StatusForbidden {
+		t.Errorf("Expected trapped visitor to be blocked, got %d", code)
+	}
+
+	req := httptest.NewRequest("GET", "/products", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	if result, _ := detector.DetectFromRequest(req); !result.Bot || detector.GetDetections().Honeypot.BotKind != BotKindHoneypot {
+		t.Errorf("Expected honeypot detection, got %+v", detector.GetDetections().Honeypot)
+	}
+}
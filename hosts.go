@@ -0,0 +1,101 @@
+package gogobot
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// HostRegistry selects the detector and middleware configuration of each tenant by the request
+// Host, so one middleware can serve many domains with their own thresholds, access lists and
+// actions. Patterns are host names, matching exactly, or "*." followed by a domain, matching
+// its subdomains at any depth; exact patterns win over wildcards, and longer wildcards over
+// shorter ones. Tenants can be set and removed while serving.
+type HostRegistry struct {
+	mu        sync.RWMutex
+	exact     map[string]hostPolicy
+	wildcards map[string]hostPolicy
+	fallback  hostPolicy
+}
+
+// hostPolicy is the detector and configuration of a tenant
+type hostPolicy struct {
+	detector *BotDetector
+	config   MiddlewareConfig
+}
+
+// NewHostRegistry creates a registry applying detector and config to hosts without a policy
+func NewHostRegistry(detector *BotDetector, config MiddlewareConfig) *HostRegistry {
+	return &HostRegistry{
+		exact:     map[string]hostPolicy{},
+		wildcards: map[string]hostPolicy{},
+		fallback:  hostPolicy{detector: detector, config: config},
+	}
+}
+
+// Set applies detector and config to the hosts matching pattern; a nil detector uses the
+// registry's default one
+func (r *HostRegistry) Set(pattern string, detector *BotDetector, config MiddlewareConfig) {
+	if detector == nil {
+		detector = r.fallback.detector
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if domain, ok := strings.CutPrefix(normalizeHost(pattern), "*."); ok {
+		r.wildcards[domain] = hostPolicy{detector: detector, config: config}
+		return
+	}
+	r.exact[normalizeHost(pattern)] = hostPolicy{detector: detector, config: config}
+}
+
+// Remove deletes the policy of pattern
+func (r *HostRegistry) Remove(pattern string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if domain, ok := strings.CutPrefix(normalizeHost(pattern), "*."); ok {
+		delete(r.wildcards, domain)
+		return
+	}
+	delete(r.exact, normalizeHost(pattern))
+}
+
+// Lookup returns the detector and configuration applying to host
+func (r *HostRegistry) Lookup(host string) (*BotDetector, MiddlewareConfig) {
+	host = normalizeHost(host)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if policy, ok := r.exact[host]; ok {
+		return policy.detector, policy.config
+	}
+	// The longest wildcard domain is found first by dropping labels from the left
+	for domain := host; ; {
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		if policy, ok := r.wildcards[parent]; ok {
+			return policy.detector, policy.config
+		}
+		domain = parent
+	}
+	return r.fallback.detector, r.fallback.config
+}
+
+// Middleware returns an HTTP middleware running the middleware of the request's tenant
+func (r *HostRegistry) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			detector, config := r.Lookup(req.Host)
+			detector.MiddlewareWithConfig(config)(next).ServeHTTP(w, req)
+		})
+	}
+}
+
+// normalizeHost lower-cases a host and strips its port and trailing dot
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
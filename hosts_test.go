@@ -0,0 +1,66 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostRegistry_Lookup(t *testing.T) {
+	fallback := NewDetector()
+	tenant := NewDetector()
+	registry := NewHostRegistry(fallback, DefaultMiddlewareConfig())
+
+	strict := DefaultMiddlewareConfig()
+	strict.BlockedStatusCode = http.StatusUnauthorized
+	registry.Set("shop.example.com", tenant, strict)
+	wildcard := DefaultMiddlewareConfig()
+	wildcard.BlockedStatusCode = http.StatusTeapot
+	registry.Set("*.example.com", nil, wildcard)
+	deeper := DefaultMiddlewareConfig()
+	deeper.BlockedStatusCode = http.StatusGone
+	registry.Set("*.eu.example.com", nil, deeper)
+
+	tests := []struct {
+		host     string
+		detector *BotDetector
+		status   int
+	}{
+		{"shop.example.com", tenant, http.StatusUnauthorized},
+		{"SHOP.example.com.:8443", tenant, http.StatusUnauthorized},
+		{"blog.example.com", fallback, http.StatusTeapot},
+		{"a.b.example.com", fallback, http.StatusTeapot},
+		{"fr.eu.example.com", fallback, http.StatusGone},
+		{"example.com", fallback, http.StatusForbidden},
+		{"other.org", fallback, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		detector, config := registry.Lookup(tt.host)
+		if detector != tt.detector || config.BlockedStatusCode != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.host, tt.status, config.BlockedStatusCode)
+		}
+	}
+
+	registry.Remove("shop.example.com")
+	if _, config := registry.Lookup("shop.example.com"); config.BlockedStatusCode != http.StatusTeapot {
+		t.Errorf("Expected removed tenant to fall back to the wildcard, got %d", config.BlockedStatusCode)
+	}
+}
+
+func TestHostRegistry_Middleware(t *testing.T) {
+	registry := NewHostRegistry(NewDetector(), DefaultMiddlewareConfig())
+	blocking := DefaultMiddlewareConfig()
+	blocking.BlockBots = true
+	registry.Set("api.example.com", nil, blocking)
+	handler := registry.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for host, status := range map[string]int{"api.example.com": http.StatusForbidden, "www.example.com": http.StatusOK} {
+		req := httptest.NewRequest("GET", "http://"+host+"/", nil)
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != status {
+			t.Errorf("%s: expected %d, got %d", host, status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Command gendatacenter regenerates data/datacenter_ranges.txt from the providers'
+// published range files. Providers without a published file (OVH, Hetzner) and
+// Azure, unless -azure is given, are carried over from the existing snapshot.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lytics/gogobot"
+)
+
+func main() {
+	input := flag.String("i", "data/datacenter_ranges.txt", "existing snapshot to carry unfetched providers over from")
+	output := flag.String("o", "data/datacenter_ranges.txt", "output file")
+	azure := flag.String("azure", "", "URL or path of the Azure Service Tags - Public JSON file")
+	flag.Parse()
+
+	existing, err := gogobot.LoadDatacenterRangesFile(*input)
+	if err != nil {
+		log.Fatalf("load %s: %v", *input, err)
+	}
+	gogobot.SetDatacenterRanges(existing)
+
+	sources := gogobot.DefaultDatacenterSources()
+	if *azure != "" {
+		url := *azure
+		if !strings.Contains(url, "://") {
+			path, err := filepath.Abs(url)
+			if err != nil {
+				log.Fatal(err)
+			}
+			url = "file://" + filepath.ToSlash(path)
+		}
+		sources = append(sources, gogobot.DatacenterSource{
+			Provider: gogobot.DatacenterAzure,
+			URL:      url,
+			Format:   gogobot.DatacenterFormatAzure,
+		})
+	}
+
+	updater := gogobot.NewDatacenterUpdater(sources...)
+	updater.HTTPClient.Transport = newTransport()
+	if err := updater.Update(context.Background()); err != nil {
+		log.Fatalf("update: %v", err)
+	}
+
+	byProvider := make(map[gogobot.DatacenterProvider][]netip.Prefix)
+	var buf strings.Builder
+	if _, err := gogobot.GetDatacenterRanges().WriteTo(&buf); err != nil {
+		log.Fatal(err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(buf.String()))
+	for scanner.Scan() {
+		provider, cidr, _ := strings.Cut(scanner.Text(), " ")
+		byProvider[gogobot.DatacenterProvider(provider)] = append(byProvider[gogobot.DatacenterProvider(provider)], netip.MustParsePrefix(cidr))
+	}
+
+	providers := make([]string, 0, len(byProvider))
+	for provider := range byProvider {
+		providers = append(providers, string(provider))
+	}
+	sort.Strings(providers)
+
+	f, err := os.Create(*output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "# Datacenter and cloud provider IP ranges used by the \"datacenter\" detector.")
+	fmt.Fprintln(w, "#")
+	fmt.Fprintln(w, "# Format: one \"<provider> <cidr>\" pair per line; blank lines and lines starting")
+	fmt.Fprintln(w, "# with \"#\" are ignored. AWS, GCP, Azure and DigitalOcean entries are aggregated")
+	fmt.Fprintln(w, "# from the providers' published range files; OVH and Hetzner do not publish one,")
+	fmt.Fprintln(w, "# so their entries are taken from their registered RIR allocations.")
+	fmt.Fprintln(w, "#")
+	fmt.Fprintln(w, "# Regenerate with: go run ./internal/gendatacenter -o data/datacenter_ranges.txt")
+	for _, provider := range providers {
+		fmt.Fprintln(w)
+		for _, prefix := range aggregate(byProvider[gogobot.DatacenterProvider(provider)]) {
+			fmt.Fprintf(w, "%s %s\n", provider, prefix)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// aggregate removes prefixes covered by another prefix and merges adjacent siblings
+func aggregate(prefixes []netip.Prefix) []netip.Prefix {
+	for {
+		sort.Slice(prefixes, func(i, j int) bool {
+			if c := prefixes[i].Addr().Compare(prefixes[j].Addr()); c != 0 {
+				return c < 0
+			}
+			return prefixes[i].Bits() < prefixes[j].Bits()
+		})
+
+		merged := make([]netip.Prefix, 0, len(prefixes))
+		changed := false
+		for _, prefix := range prefixes {
+			if n := len(merged); n > 0 {
+				last := merged[n-1]
+				if last.Overlaps(prefix) && last.Bits() <= prefix.Bits() {
+					changed = true
+					continue
+				}
+				if parent, ok := siblingParent(last, prefix); ok {
+					merged[n-1] = parent
+					changed = true
+					continue
+				}
+			}
+			merged = append(merged, prefix)
+		}
+
+		prefixes = merged
+		if !changed {
+			return prefixes
+		}
+	}
+}
+
+// siblingParent returns the parent prefix when a and b are the two halves of it
+func siblingParent(a, b netip.Prefix) (netip.Prefix, bool) {
+	if a.Bits() != b.Bits() || a.Bits() == 0 || a.Addr().Is4() != b.Addr().Is4() {
+		return netip.Prefix{}, false
+	}
+	parent, err := a.Addr().Prefix(a.Bits() - 1)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	if parent.Addr() != a.Addr() || !parent.Contains(b.Addr()) {
+		return netip.Prefix{}, false
+	}
+	return parent, true
+}
+
+// newTransport returns a transport that can also read file:// URLs
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	return transport
+}
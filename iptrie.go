@@ -0,0 +1,135 @@
+package gogobot
+
+import (
+	"net/netip"
+)
+
+// prefixTrie is a binary radix tree mapping IP prefixes to values with longest-prefix lookup.
+// IPv4 and IPv6 prefixes are kept in separate trees; IPv4-mapped IPv6 addresses are unmapped.
+type prefixTrie[V any] struct {
+	v4   *trieNode[V]
+	v6   *trieNode[V]
+	size int
+}
+
+type trieNode[V any] struct {
+	children [2]*trieNode[V]
+	value    V
+	prefix   netip.Prefix
+	hasValue bool
+}
+
+// newPrefixTrie creates an empty prefix trie
+func newPrefixTrie[V any]() *prefixTrie[V] {
+	return &prefixTrie[V]{
+		v4: &trieNode[V]{},
+		v6: &trieNode[V]{},
+	}
+}
+
+// insert stores value for prefix, replacing any value already stored for the same prefix
+func (t *prefixTrie[V]) insert(prefix netip.Prefix, value V) {
+	prefix = normalizePrefix(prefix)
+	addr := prefix.Addr()
+
+	b := addrBytes(addr)
+	node := t.root(addr)
+	for i := 0; i < prefix.Bits(); i++ {
+		bit := addrBit(&b, i)
+		if node.children[bit] == nil {
+			node.children[bit] = &trieNode[V]{}
+		}
+		node = node.children[bit]
+	}
+
+	if !node.hasValue {
+		t.size++
+	}
+	node.value = value
+	node.prefix = prefix
+	node.hasValue = true
+}
+
+// lookup returns the value of the longest prefix containing addr
+func (t *prefixTrie[V]) lookup(addr netip.Addr) (V, netip.Prefix, bool) {
+	var (
+		value  V
+		prefix netip.Prefix
+		found  bool
+	)
+	if !addr.IsValid() {
+		return value, prefix, false
+	}
+	addr = normalizeAddr(addr)
+
+	b := addrBytes(addr)
+	node := t.root(addr)
+	for i := 0; node != nil; i++ {
+		if node.hasValue {
+			value, prefix, found = node.value, node.prefix, true
+		}
+		if i >= addr.BitLen() {
+			break
+		}
+		node = node.children[addrBit(&b, i)]
+	}
+	return value, prefix, found
+}
+
+// len returns the number of stored prefixes
+func (t *prefixTrie[V]) len() int {
+	return t.size
+}
+
+// walk calls fn for every stored prefix
+func (t *prefixTrie[V]) walk(fn func(netip.Prefix, V)) {
+	var visit func(*trieNode[V])
+	visit = func(node *trieNode[V]) {
+		if node == nil {
+			return
+		}
+		if node.hasValue {
+			fn(node.prefix, node.value)
+		}
+		visit(node.children[0])
+		visit(node.children[1])
+	}
+	visit(t.v4)
+	visit(t.v6)
+}
+
+func (t *prefixTrie[V]) root(addr netip.Addr) *trieNode[V] {
+	if addr.Is4() {
+		return t.v4
+	}
+	return t.v6
+}
+
+// normalizePrefix unmaps IPv4-mapped prefixes and clears host bits
+func normalizePrefix(prefix netip.Prefix) netip.Prefix {
+	addr := prefix.Addr()
+	if addr.Is4In6() {
+		bits := prefix.Bits() - 96
+		if bits < 0 {
+			bits = 0
+		}
+		prefix = netip.PrefixFrom(addr.Unmap(), bits)
+	}
+	return prefix.Masked()
+}
+
+// addrBytes returns the address bytes, with IPv4 addresses in the first four bytes
+func addrBytes(addr netip.Addr) [16]byte {
+	if addr.Is4() {
+		var b [16]byte
+		a4 := addr.As4()
+		copy(b[:], a4[:])
+		return b
+	}
+	return addr.As16()
+}
+
+// addrBit returns bit i of the address bytes counting from the most significant bit
+func addrBit(b *[16]byte, i int) int {
+	return int(b[i/8]>>(7-uint(i%8))) & 1
+}
@@ -0,0 +1,71 @@
+package gogobot
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestPrefixTrie_LongestMatch(t *testing.T) {
+	trie := newPrefixTrie[string]()
+	trie.insert(netip.MustParsePrefix("10.0.0.0/8"), "wide")
+	trie.insert(netip.MustParsePrefix("10.1.0.0/16"), "narrow")
+	trie.insert(netip.MustParsePrefix("2001:db8::/32"), "v6")
+	trie.insert(netip.MustParsePrefix("10.1.0.0/16"), "replaced")
+
+	if trie.len() != 3 {
+		t.Errorf("Expected 3 prefixes, got %d", trie.len())
+	}
+
+	tests := []struct {
+		addr     string
+		expected string
+		found    bool
+	}{
+		{"10.2.3.4", "wide", true},
+		{"10.1.2.3", "replaced", true},
+		{"::ffff:10.1.2.3", "replaced", true},
+		{"2001:db8::1", "v6", true},
+		{"11.0.0.1", "", false},
+		{"2001:db9::1", "", false},
+	}
+
+	for _, tt := range tests {
+		value, _, ok := trie.lookup(netip.MustParseAddr(tt.addr))
+		if ok != tt.found || value != tt.expected {
+			t.Errorf("lookup(%s) = %q, %t; want %q, %t", tt.addr, value, ok, tt.expected, tt.found)
+		}
+	}
+
+	if _, _, ok := trie.lookup(netip.Addr{}); ok {
+		t.Error("Expected no match for invalid address")
+	}
+}
+
+func TestPrefixTrie_HostRoute(t *testing.T) {
+	trie := newPrefixTrie[int]()
+	trie.insert(netip.MustParsePrefix("192.0.2.1/32"), 1)
+	trie.insert(netip.MustParsePrefix("0.0.0.0/0"), 0)
+
+	if v, _, _ := trie.lookup(netip.MustParseAddr("192.0.2.1")); v != 1 {
+		t.Errorf("Expected host route match, got %d", v)
+	}
+	if v, _, ok := trie.lookup(netip.MustParseAddr("192.0.2.2")); !ok || v != 0 {
+		t.Errorf("Expected default route match, got %d, %t", v, ok)
+	}
+	if v, _, _ := trie.lookup(netip.MustParseAddr("::ffff:192.0.2.1")); v != 1 {
+		t.Errorf("Expected IPv4-mapped address to match host route, got %d", v)
+	}
+	if _, _, ok := trie.lookup(netip.MustParseAddr("::1")); ok {
+		t.Error("Expected IPv4 default route not to match IPv6 addresses")
+	}
+}
+
+func BenchmarkPrefixTrieLookup(b *testing.B) {
+	ranges := GetDatacenterRanges()
+	addr := netip.MustParseAddr("54.239.123.45")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ranges.Lookup(addr)
+	}
+}
@@ -0,0 +1,278 @@
+package gogobot
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+	"unicode/utf16"
+)
+
+// Default JavaScript challenge parameters
+const (
+	DefaultJSChallengeScriptPath = "/_gogobot/challenge.js"
+	DefaultJSChallengeVerifyPath = "/_gogobot/verify"
+	DefaultJSTrustCookieName     = "gogobot_trust"
+	DefaultJSTrustTTL            = time.Hour
+	DefaultJSChallengeNonceTTL   = 2 * time.Minute
+)
+
+// JSChallenge proves that a client runs JavaScript like a browser. Its script embeds a signed
+// nonce bound to the client IP and user agent, reads a few navigator properties, and posts
+// them back with a proof computed from the nonce. Clients passing verification receive a
+// signed trust cookie, which the middleware can require on sensitive routes (see
+// MiddlewareConfig.RequireJSChallenge). The middleware serves the script and verification
+// endpoint itself; JSChallengeHandler serves them without it.
+type JSChallenge struct {
+	secret []byte
+	// ScriptPath is where the challenge script is served
+	ScriptPath string
+	// VerifyPath is where the script posts its result
+	VerifyPath string
+	// CookieName is the name of the trust cookie
+	CookieName string
+	// TrustTTL is how long a trust cookie stays valid
+	TrustTTL time.Duration
+	// NonceTTL is how long the script has to post its result
+	NonceTTL time.Duration
+
+	now func() time.Time
+}
+
+// NewJSChallenge creates a challenge signing nonces and trust cookies with secret, which should
+// be at least 32 random bytes shared by all replicas
+func NewJSChallenge(secret []byte) *JSChallenge {
+	return &JSChallenge{
+		secret:     secret,
+		ScriptPath: DefaultJSChallengeScriptPath,
+		VerifyPath: DefaultJSChallengeVerifyPath,
+		CookieName: DefaultJSTrustCookieName,
+		TrustTTL:   DefaultJSTrustTTL,
+		NonceTTL:   DefaultJSChallengeNonceTTL,
+		now:        time.Now,
+	}
+}
+
+// JSChallengeProps are the navigator properties reported by the challenge script
+type JSChallengeProps struct {
+	Webdriver           bool   `json:"webdriver"`
+	Languages           int    `json:"languages"`
+	Plugins             int    `json:"plugins"`
+	HardwareConcurrency int    `json:"hardwareConcurrency"`
+	ScreenWidth         int    `json:"screenWidth"`
+	ScreenHeight        int    `json:"screenHeight"`
+	Timezone            string `json:"timezone"`
+}
+
+// automated reports whether the properties reveal an automated or headless browser
+func (p JSChallengeProps) automated() bool {
+	return p.Webdriver || p.Languages == 0 || p.ScreenWidth == 0 || p.ScreenHeight == 0
+}
+
+// ScriptTag returns markup loading the challenge script, for pages leading to routes that
+// require it, so visitors are trusted before they get there
+func (c *JSChallenge) ScriptTag() template.HTML {
+	return template.HTML(`<script src="` + html.EscapeString(c.ScriptPath) + `" async></script>`)
+}
+
+// jsChallengeScript posts the navigator properties with an FNV-1a proof over the nonce and
+// the serialized properties, reloading the page on success when loaded with data-reload
+const jsChallengeScript = `(function () {
+  var script = document.currentScript;
+  var nonce = %s;
+  function fnv(s) {
+    var h = 0x811c9dc5;
+    for (var i = 0; i < s.length; i++) {
+      h ^= s.charCodeAt(i);
+      h = Math.imul(h, 0x01000193) >>> 0;
+    }
+    return ("0000000" + h.toString(16)).slice(-8);
+  }
+  var n = navigator;
+  var props = JSON.stringify({
+    webdriver: !!n.webdriver,
+    languages: (n.languages || []).length,
+    plugins: (n.plugins || []).length,
+    hardwareConcurrency: n.hardwareConcurrency || 0,
+    screenWidth: screen.width || 0,
+    screenHeight: screen.height || 0,
+    timezone: (window.Intl && Intl.DateTimeFormat().resolvedOptions().timeZone) || ""
+  });
+  fetch(%s, {
+    method: "POST",
+    credentials: "same-origin",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({nonce: nonce, props: props, proof: fnv(nonce + "|" + props)})
+  }).then(function (res) {
+    if (res.ok && script && script.dataset.reload) {
+      location.reload();
+    }
+  });
+})();
+`
+
+// jsChallengeInterstitial is served on routes requiring the challenge to clients without trust
+const jsChallengeInterstitial = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="robots" content="noindex"><title>Checking your browser</title></head>
+<body><p>Checking your browser&hellip;</p><noscript>JavaScript is required to continue.</noscript>
+<script src="%s" data-reload="1"></script></body></html>
+`
+
+// jsChallengeRequest is the body posted by the challenge script
+type jsChallengeRequest struct {
+	Nonce string `json:"nonce"`
+	Props string `json:"props"`
+	Proof string `json:"proof"`
+}
+
+// jsChallengeProof computes the script's FNV-1a hash over UTF-16 code units, as JavaScript
+// strings are indexed
+func jsChallengeProof(nonce, props string) string {
+	h := uint32(0x811c9dc5)
+	for _, unit := range utf16.Encode([]rune(nonce + "|" + props)) {
+		h ^= uint32(unit)
+		h *= 0x01000193
+	}
+	return fmt.Sprintf("%08x", h)
+}
+
+// jsChallengeKey binds challenge tokens to the client IP, aggregated like sessions, and the
+// user agent
+func jsChallengeKey(clientIP Component[netip.Addr], userAgent string) string {
+	if clientIP == nil || clientIP.GetState() != StateSuccess {
+		return ""
+	}
+	return DefaultIPAggregation().Key(clientIP.GetValue()) + "|" + strconv.FormatUint(hashUserAgent(userAgent), 36)
+}
+
+// requestJSChallengeKey resolves the challenge key of a request without collecting components
+func (d *BotDetector) requestJSChallengeKey(req *http.Request) string {
+	clientIP, _ := d.resolveClientIP(req)
+	return jsChallengeKey(clientIP, req.Header.Get("User-Agent"))
+}
+
+// trusted reports whether the request carries a valid trust cookie for key
+func (c *JSChallenge) trusted(req *http.Request, key string) bool {
+	cookie, err := req.Cookie(c.CookieName)
+	if err != nil || key == "" {
+		return false
+	}
+	return verifyToken(c.secret, "js-trust", key, cookie.Value, c.now())
+}
+
+// serveScript serves the challenge script with a fresh nonce
+func (c *JSChallenge) serveScript(w http.ResponseWriter, key string) {
+	nonce, _ := json.Marshal(issueToken(c.secret, "js-nonce", key, c.now().Add(c.NonceTTL)))
+	verifyPath, _ := json.Marshal(c.VerifyPath)
+	w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprintf(w, jsChallengeScript, nonce, verifyPath)
+}
+
+// serveVerify checks the script's result and sets the trust cookie
+func (c *JSChallenge) serveVerify(w http.ResponseWriter, req *http.Request, key string) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body jsChallengeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 4096)).Decode(&body); err != nil {
+		http.Error(w, "invalid challenge response", http.StatusBadRequest)
+		return
+	}
+	if !verifyToken(c.secret, "js-nonce", key, body.Nonce, c.now()) || body.Proof != jsChallengeProof(body.Nonce, body.Props) {
+		http.Error(w, "challenge failed", http.StatusForbidden)
+		return
+	}
+	var props JSChallengeProps
+	if err := json.Unmarshal([]byte(body.Props), &props); err != nil || props.automated() {
+		http.Error(w, "challenge failed", http.StatusForbidden)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.CookieName,
+		Value:    issueToken(c.secret, "js-trust", key, c.now().Add(c.TrustTTL)),
+		Path:     "/",
+		MaxAge:   int(c.TrustTTL.Seconds()),
+		Secure:   req.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveInterstitial answers a request to a route requiring the challenge from an untrusted
+// client: browsers get a page running the script and reloading, other methods an error
+func (c *JSChallenge) serveInterstitial(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "JavaScript challenge required", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	fmt.Fprintf(w, jsChallengeInterstitial, html.EscapeString(c.ScriptPath))
+}
+
+// serveJSChallenge serves the challenge script and verification endpoint, reporting whether
+// the request was one of them
+func (d *BotDetector) serveJSChallenge(w http.ResponseWriter, req *http.Request) bool {
+	challenge := d.config.JSChallenge
+	if challenge == nil {
+		return false
+	}
+	switch req.URL.Path {
+	case challenge.ScriptPath:
+		key := d.requestJSChallengeKey(req)
+		if key == "" {
+			http.Error(w, "client IP is unavailable", http.StatusBadRequest)
+			return true
+		}
+		challenge.serveScript(w, key)
+		return true
+	case challenge.VerifyPath:
+		challenge.serveVerify(w, req, d.requestJSChallengeKey(req))
+		return true
+	}
+	return false
+}
+
+// JSChallengeHandler serves the JavaScript challenge script and verification endpoint, for
+// applications not using the middleware
+func (d *BotDetector) JSChallengeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !d.serveJSChallenge(w, req) {
+			http.NotFound(w, req)
+		}
+	})
+}
+
+// jsTrusted reports whether the request carries a valid trust cookie, using the collected
+// components when available
+func (d *BotDetector) jsTrusted(req *http.Request, components *ComponentDict) bool {
+	if components != nil && components.JSTrust.GetState() == StateSuccess {
+		return components.JSTrust.GetValue()
+	}
+	return d.config.JSChallenge.trusted(req, d.requestJSChallengeKey(req))
+}
+
+// getJSTrust reports whether the request carries a valid trust cookie
+func getJSTrust(req *http.Request, clientIP Component[netip.Addr], challenge *JSChallenge) Component[bool] {
+	if challenge == nil {
+		return ErrorComponent[bool]{
+			State: StateUndefined,
+			Error: "no JavaScript challenge configured",
+		}
+	}
+	return SuccessComponent[bool]{
+		State: StateSuccess,
+		Value: challenge.trusted(req, jsChallengeKey(clientIP, req.Header.Get("User-Agent"))),
+	}
+}
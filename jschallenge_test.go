@@ -0,0 +1,187 @@
+package gogobot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSChallengeProof(t *testing.T) {
+	// Reference values hashed over UTF-16 code units, as the script does
+	if proof := jsChallengeProof("n", `{"timezone":"Europe/Zürich"}`); proof != "ef2c8bc9" {
+		t.Errorf("Unexpected proof %s", proof)
+	}
+	if proof := jsChallengeProof("x", "😀"); proof != "eec5a07c" {
+		t.Errorf("Expected surrogate pairs to be hashed as two units, got %s", proof)
+	}
+}
+
+const testBrowserUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// jsChallengeClient plays the challenge script's part against a handler
+type jsChallengeClient struct {
+	t          *testing.T
+	handler    http.Handler
+	remoteAddr string
+	cookies    []*http.Cookie
+}
+
+func (c *jsChallengeClient) do(method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.RemoteAddr = c.remoteAddr
+	req.Header.Set("User-Agent", testBrowserUA)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Language", "en-US")
+	req.Header.Set("Accept-Encoding", "gzip")
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+	c.cookies = append(c.cookies, rec.Result().Cookies()...)
+	return rec
+}
+
+var jsNoncePattern = regexp.MustCompile(`var nonce = "([^"]+)";`)
+
+// solve fetches the script and posts props the way the script would
+func (c *jsChallengeClient) solve(props JSChallengeProps) int {
+	rec := c.do("GET", DefaultJSChallengeScriptPath, "")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/javascript") {
+		c.t.Fatalf("Expected challenge script, got %d", rec.Code)
+	}
+	match := jsNoncePattern.FindStringSubmatch(rec.Body.String())
+	if match == nil {
+		c.t.Fatalf("Expected nonce in script: %s", rec.Body.String())
+	}
+	encoded, _ := json.Marshal(props)
+	body, _ := json.Marshal(jsChallengeRequest{
+		Nonce: match[1],
+		Props: string(encoded),
+		Proof: jsChallengeProof(match[1], string(encoded)),
+	})
+	return c.do("POST", DefaultJSChallengeVerifyPath, string(body)).Code
+}
+
+var testBrowserProps = JSChallengeProps{Languages: 2, Plugins: 5, HardwareConcurrency: 8, ScreenWidth: 1920, ScreenHeight: 1080, Timezone: "Europe/Berlin"}
+
+func newJSChallengeHandler() (*BotDetector, http.Handler) {
+	config := DefaultDetectorConfig()
+	config.JSChallenge = NewJSChallenge([]byte("secret"))
+	detector := NewDetectorWithConfig(config)
+	middleware := detector.MiddlewareWithConfig(MiddlewareConfig{
+		RequireJSChallenge: func(r *http.Request) bool { return strings.HasPrefix(r.URL.Path, "/checkout") },
+	})
+	return detector, middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestMiddleware_JSChallenge(t *testing.T) {
+	_, handler := newJSChallengeHandler()
+	client := &jsChallengeClient{t: t, handler: handler, remoteAddr: "203.0.113.7:1234"}
+
+	if rec := client.do("GET", "/", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected unprotected route to be served, got %d", rec.Code)
+	}
+	rec := client.do("GET", "/checkout", "")
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), `<script src="/_gogobot/challenge.js" data-reload="1">`) {
+		t.Fatalf("Expected interstitial, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := client.do("POST", "/checkout", ""); rec.Code != http.StatusForbidden || strings.Contains(rec.Body.String(), "<script") {
+		t.Errorf("Expected plain rejection for POST, got %d", rec.Code)
+	}
+
+	if code := client.solve(testBrowserProps); code != http.StatusNoContent {
+		t.Fatalf("Expected challenge to pass, got %d", code)
+	}
+	if rec := client.do("GET", "/checkout", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected trusted client to be served, got %d", rec.Code)
+	}
+
+	// The trust cookie is bound to the client
+	other := &jsChallengeClient{t: t, handler: handler, remoteAddr: "198.51.100.9:1234", cookies: client.cookies}
+	if rec := other.do("GET", "/checkout", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected trust cookie from another client to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware_JSChallengeRejects(t *testing.T) {
+	_, handler := newJSChallengeHandler()
+
+	automated := testBrowserProps
+	automated.Webdriver = true
+	client := &jsChallengeClient{t: t, handler: handler, remoteAddr: "203.0.113.7:1234"}
+	if code := client.solve(automated); code != http.StatusForbidden {
+		t.Errorf("Expected webdriver to fail the challenge, got %d", code)
+	}
+
+	headless := testBrowserProps
+	headless.ScreenWidth, headless.ScreenHeight = 0, 0
+	if code := client.solve(headless); code != http.StatusForbidden {
+		t.Errorf("Expected missing screen to fail the challenge, got %d", code)
+	}
+
+	// A forged proof or nonce fails
+	body := `{"nonce":"4102444800.forged","props":"{}","proof":"00000000"}`
+	if rec := client.do("POST", DefaultJSChallengeVerifyPath, body); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected forged nonce to fail, got %d", rec.Code)
+	}
+	if rec := client.do("GET", DefaultJSChallengeVerifyPath, ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET on the verify endpoint to be rejected, got %d", rec.Code)
+	}
+	if rec := client.do("GET", "/checkout", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected failed client to stay untrusted, got %d", rec.Code)
+	}
+}
+
+func TestJSChallenge_Expiry(t *testing.T) {
+	detector, handler := newJSChallengeHandler()
+	now := time.Unix(1700000000, 0)
+	detector.config.JSChallenge.now = func() time.Time { return now }
+
+	client := &jsChallengeClient{t: t, handler: handler, remoteAddr: "203.0.113.7:1234"}
+	if code := client.solve(testBrowserProps); code != http.StatusNoContent {
+		t.Fatalf("Expected challenge to pass, got %d", code)
+	}
+
+	components, _ := detector.Collect(func() *http.Request {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = client.remoteAddr
+		req.Header.Set("User-Agent", testBrowserUA)
+		for _, cookie := range client.cookies {
+			req.AddCookie(cookie)
+		}
+		return req
+	}())
+	if components.JSTrust.GetState() != StateSuccess || !components.JSTrust.GetValue() {
+		t.Errorf("Expected JSTrust component, got %+v", components.JSTrust)
+	}
+
+	now = now.Add(DefaultJSTrustTTL)
+	if rec := client.do("GET", "/checkout", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected expired trust to be challenged again, got %d", rec.Code)
+	}
+}
+
+func TestJSChallengeHandler(t *testing.T) {
+	detector, _ := newJSChallengeHandler()
+	handler := detector.JSChallengeHandler()
+
+	req := httptest.NewRequest("GET", "/other", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 outside challenge paths, got %d", rec.Code)
+	}
+
+	client := &jsChallengeClient{t: t, handler: handler, remoteAddr: "203.0.113.7:1234"}
+	if code := client.solve(testBrowserProps); code != http.StatusNoContent {
+		t.Errorf("Expected standalone handler to verify, got %d", code)
+	}
+}
@@ -0,0 +1,100 @@
+package gogobot
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// LogValue implements slog.LogValuer, logging the verdict as a group of its set fields
+func (r BotDetectionResult) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.Bool("bot", r.Bot)}
+	if r.Bot {
+		attrs = append(attrs,
+			slog.String("kind", string(r.BotKind)),
+			slog.String("category", string(r.BotKind.Category())),
+		)
+	}
+	if r.Score > 0 {
+		attrs = append(attrs, slog.Float64("score", r.Score))
+	}
+	if len(r.Reasons) > 0 {
+		attrs = append(attrs, slog.Any("reasons", r.Reasons))
+	}
+	for _, flag := range []struct {
+		key string
+		set bool
+	}{
+		{"verified", r.Verified},
+		{"verifiedByCDN", r.VerifiedByCDN},
+		{"challengePassed", r.ChallengePassed},
+		{"trusted", r.Trusted},
+		{"shadow", r.Shadow},
+		{"unsampled", r.Unsampled},
+		{"datacenter", r.IsDatacenter},
+		{"tor", r.IsTor},
+	} {
+		if flag.set {
+			attrs = append(attrs, slog.Bool(flag.key, true))
+		}
+	}
+	if r.Organization != "" {
+		attrs = append(attrs, slog.String("organization", r.Organization))
+	}
+	if r.Country != "" {
+		attrs = append(attrs, slog.String("country", r.Country))
+	}
+	if r.ASN != 0 {
+		attrs = append(attrs, slog.Uint64("asn", uint64(r.ASN)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, logging the action, timing and verdict or error. The
+// detector timings are left out.
+func (o Observation) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.Duration("duration", o.Duration)}
+	if o.Action != "" {
+		attrs = append(attrs, slog.String("action", string(o.Action)))
+	}
+	if o.Cached {
+		attrs = append(attrs, slog.Bool("cached", true))
+	}
+	if o.Result != nil {
+		attrs = append(attrs, slog.Any("result", *o.Result))
+	}
+	if o.Err != nil {
+		attrs = append(attrs, slog.String("error", o.Err.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// logObserver logs observations to a Logger: failed detections at Error, refused bots at Warn,
+// served bots and those answered by OnBotDecision at Info, and other requests at Debug
+type logObserver struct {
+	logger *slog.Logger
+}
+
+// ObserveRequest implements Observer
+func (o logObserver) ObserveRequest(req *http.Request, observation Observation) {
+	level, message := slog.LevelDebug, "request passed bot detection"
+	switch {
+	case observation.Err != nil:
+		level, message = slog.LevelError, "bot detection failed"
+	case observation.Result == nil || !observation.Result.Bot:
+	case observation.Action == ActionAllow || observation.Action == ActionLog || observation.Action == "":
+		level, message = slog.LevelInfo, "bot detected"
+	default:
+		level, message = slog.LevelWarn, "bot refused"
+	}
+	ctx := req.Context()
+	if !o.logger.Enabled(ctx, level) {
+		return
+	}
+	o.logger.LogAttrs(ctx, level, message,
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.String("remoteAddr", req.RemoteAddr),
+		slog.String("userAgent", req.UserAgent()),
+		slog.Any("detection", observation),
+	)
+}
@@ -0,0 +1,90 @@
+package gogobot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBotDetectionResult_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	result := BotDetectionResult{Bot: true, BotKind: BotKindGPTBot, Verified: true, Country: "US", ASN: 8075}
+	logger.Info("verdict", "result", result)
+
+	line := buf.String()
+	for _, want := range []string{"result.bot=true", "result.kind=gptbot", "result.category=ai", "result.verified=true", "result.country=US", "result.asn=8075"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %q", want, line)
+		}
+	}
+	if strings.Contains(line, "result.score") || strings.Contains(line, "result.tor") {
+		t.Errorf("Expected unset fields to be left out, got %q", line)
+	}
+}
+
+func TestMiddleware_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	var observed int
+	config := DefaultMiddlewareConfig()
+	config.BlockBots = true
+	config.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	config.Observer = ObserverFunc(func(*http.Request, Observation) { observed++ })
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, userAgent := range []string{testBrowserUA, "curl/8.4.0"} {
+		req := newBrowserRequest("/products", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", userAgent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if observed != 2 {
+		t.Errorf("Expected the Observer to see both requests, got %d", observed)
+	}
+
+	// The human is logged at Debug, below the handler's level
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected one log entry, got %d: %s", len(entries), buf.String())
+	}
+	entry := entries[0]
+	if entry["level"] != "WARN" || entry["msg"] != "bot refused" || entry["path"] != "/products" || entry["userAgent"] != "curl/8.4.0" {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+	detection, _ := entry["detection"].(map[string]any)
+	result, _ := detection["result"].(map[string]any)
+	if detection["action"] != "block" || result["kind"] != "curl" || result["category"] != "automation" {
+		t.Errorf("Unexpected detection %v", detection)
+	}
+}
+
+func TestLogObserver_Levels(t *testing.T) {
+	tests := []struct {
+		observation Observation
+		level       string
+	}{
+		{Observation{Err: errors.New("store unreachable")}, "ERROR"},
+		{Observation{Result: &BotDetectionResult{Bot: true, BotKind: BotKindCurl}, Action: ActionRateLimit}, "WARN"},
+		{Observation{Result: &BotDetectionResult{Bot: true, BotKind: BotKindGPTBot}, Action: ActionLog}, "INFO"},
+		{Observation{Result: &BotDetectionResult{}, Action: ActionAllow}, "DEBUG"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		observer := logObserver{logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+		observer.ObserveRequest(httptest.NewRequest("GET", "/", nil), tt.observation)
+		if !strings.Contains(buf.String(), "level="+tt.level) {
+			t.Errorf("Expected level %s, got %q", tt.level, buf.String())
+		}
+	}
+}
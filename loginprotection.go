@@ -0,0 +1,221 @@
+package gogobot
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// LoginProtection flags credential stuffing against the site's authentication endpoints:
+// visitors failing to log in too often or as too many different users, and identifiers failing
+// too often from anywhere. Failures are reported by the application with RecordLoginFailure;
+// requests to the auth paths are then flagged as bots regardless of their user agent, so the
+// middleware can challenge or block them.
+type LoginProtection struct {
+	// Paths are the authentication endpoint paths
+	Paths []string
+	// MaxFailures is the number of failed logins per visitor and Window above which the
+	// visitor is flagged; zero disables the limit
+	MaxFailures int64
+	// MaxIdentifiers is the number of distinct identifiers a visitor may fail to log in as per
+	// Window; zero disables the limit
+	MaxIdentifiers int64
+	// MaxIdentifierFailures is the number of failed logins for one identifier from all visitors
+	// per Window above which attempts for it are flagged; zero disables the limit
+	MaxIdentifierFailures int64
+	// Identifier returns the user name or email address a login request is for, or ""; nil
+	// disables MaxIdentifierFailures. Reading a form consumes the request body, which remains
+	// available through req.Form.
+	Identifier func(*http.Request) string
+	// Window is the length of the fixed counting window; zero uses DefaultSessionWindow
+	Window time.Duration
+}
+
+// DefaultLoginProtection protects the given auth paths, flagging visitors with more than 10
+// failed logins or failures for more than 3 identifiers in 15 minutes, and identifiers failing
+// more than 20 times in 15 minutes
+func DefaultLoginProtection(paths ...string) *LoginProtection {
+	return &LoginProtection{
+		Paths:                 paths,
+		MaxFailures:           10,
+		MaxIdentifiers:        3,
+		MaxIdentifierFailures: 20,
+		Window:                15 * time.Minute,
+	}
+}
+
+// IsAuthPath reports whether path is one of the authentication endpoint paths
+func (p *LoginProtection) IsAuthPath(path string) bool {
+	return slices.Contains(p.Paths, path)
+}
+
+// window returns the counting window
+func (p *LoginProtection) window() time.Duration {
+	if p.Window <= 0 {
+		return DefaultSessionWindow
+	}
+	return p.Window
+}
+
+// LoginFailures are the failed logins recorded in the current window, before the current
+// request
+type LoginFailures struct {
+	// Failures is the number of failed logins by the visitor
+	Failures int64 `json:"failures"`
+	// Identifiers is the number of distinct identifiers the visitor failed to log in as
+	Identifiers int64 `json:"identifiers"`
+	// IdentifierFailures is the number of failed logins from all visitors for the identifier
+	// of the current request
+	IdentifierFailures    int64         `json:"identifierFailures"`
+	MaxFailures           int64         `json:"maxFailures"`
+	MaxIdentifiers        int64         `json:"maxIdentifiers"`
+	MaxIdentifierFailures int64         `json:"maxIdentifierFailures"`
+	Window                time.Duration `json:"window"`
+}
+
+// loginKeys returns the store keys counting the visitor's failures and distinct identifiers in
+// the current window
+func loginKeys(tracker *SessionTracker, key string, window time.Duration) (failures, identifiers, identifierCount string) {
+	bucket := windowBucket(tracker.now(), window)
+	return tracker.Prefix + "loginfailures:" + key + ":" + bucket,
+		tracker.Prefix + "loginids:" + key + ":" + bucket,
+		tracker.Prefix + "loginidcount:" + key + ":" + bucket
+}
+
+// identifierKey returns the store key counting failures for identifier in the current window.
+// Identifiers are stored as hashes so that the store holds no user names.
+func identifierKey(tracker *SessionTracker, identifier string, window time.Duration) string {
+	return tracker.Prefix + "loginidentifier:" + hashIdentifier(identifier) + ":" + windowBucket(tracker.now(), window)
+}
+
+func hashIdentifier(identifier string) string {
+	h := fnv.New64a()
+	h.Write([]byte(identifier))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// RecordLoginFailure counts a failed login for the request's visitor and, when identifier is
+// not empty, for the identifier. It does nothing without a LoginProtection and SessionTracker.
+func (d *BotDetector) RecordLoginFailure(req *http.Request, identifier string) error {
+	protection, tracker := d.config.LoginProtection, d.config.SessionTracker
+	if protection == nil || tracker == nil {
+		return nil
+	}
+	key := d.visitorKey(req, nil)
+	if key == "" {
+		return errNoSessionKey
+	}
+
+	ctx := req.Context()
+	window := protection.window()
+	failuresKey, identifiersKey, countKey := loginKeys(tracker, key, window)
+	if _, err := tracker.store.Incr(ctx, failuresKey, 1, 2*window); err != nil {
+		return err
+	}
+	if identifier == "" {
+		return nil
+	}
+	count, err := tracker.store.AddToSet(ctx, identifiersKey, hashIdentifier(identifier), 2*window)
+	if err != nil {
+		return err
+	}
+	// Sets cannot be read without adding to them, so their size is kept alongside
+	if err := tracker.store.Set(ctx, countKey, []byte(strconv.FormatInt(count, 10)), 2*window); err != nil {
+		return err
+	}
+	_, err = tracker.store.Incr(ctx, identifierKey(tracker, identifier, window), 1, 2*window)
+	return err
+}
+
+// getLoginFailures reads the failed logins of the visitor and the attempted identifier for
+// requests to an auth path
+func getLoginFailures(req *http.Request, components *ComponentDict, tracker *SessionTracker, protection *LoginProtection) Component[LoginFailures] {
+	if tracker == nil || protection == nil {
+		return ErrorComponent[LoginFailures]{
+			State: StateUndefined,
+			Error: "login protection requires a session tracker",
+		}
+	}
+	if !protection.IsAuthPath(req.URL.Path) {
+		return ErrorComponent[LoginFailures]{
+			State: StateNull,
+			Error: "not an auth path",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[LoginFailures]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	ctx := req.Context()
+	window := protection.window()
+	failures := LoginFailures{
+		MaxFailures:           protection.MaxFailures,
+		MaxIdentifiers:        protection.MaxIdentifiers,
+		MaxIdentifierFailures: protection.MaxIdentifierFailures,
+		Window:                window,
+	}
+	storeError := func(err error) Component[LoginFailures] {
+		return ErrorComponent[LoginFailures]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+
+	failuresKey, _, countKey := loginKeys(tracker, key, window)
+	var err error
+	if failures.Failures, err = tracker.store.Incr(ctx, failuresKey, 0, 2*window); err != nil {
+		return storeError(err)
+	}
+	count, ok, err := tracker.store.Get(ctx, countKey)
+	if err != nil {
+		return storeError(err)
+	}
+	if ok {
+		failures.Identifiers, _ = strconv.ParseInt(string(count), 10, 64)
+	}
+	if protection.Identifier != nil {
+		if identifier := protection.Identifier(req); identifier != "" {
+			if failures.IdentifierFailures, err = tracker.store.Incr(ctx, identifierKey(tracker, identifier, window), 0, 2*window); err != nil {
+				return storeError(err)
+			}
+		}
+	}
+	return SuccessComponent[LoginFailures]{
+		State: StateSuccess,
+		Value: failures,
+	}
+}
+
+// detectCredentialStuffing flags login attempts over any of the failure limits
+func detectCredentialStuffing(components *ComponentDict) *BotDetectionResult {
+	if components.LoginFailures.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	failures := components.LoginFailures.GetValue()
+	var reasons []string
+	if failures.MaxFailures > 0 && failures.Failures > failures.MaxFailures {
+		reasons = append(reasons, fmt.Sprintf("%d failed logins", failures.Failures))
+	}
+	if failures.MaxIdentifiers > 0 && failures.Identifiers > failures.MaxIdentifiers {
+		reasons = append(reasons, fmt.Sprintf("failed logins as %d identifiers", failures.Identifiers))
+	}
+	if failures.MaxIdentifierFailures > 0 && failures.IdentifierFailures > failures.MaxIdentifierFailures {
+		reasons = append(reasons, fmt.Sprintf("%d failed logins for the identifier", failures.IdentifierFailures))
+	}
+	if len(reasons) == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     true,
+		BotKind: BotKindCredentialStuffing,
+		Reasons: reasons,
+	}
+}
@@ -0,0 +1,112 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectCredentialStuffing(t *testing.T) {
+	limits := LoginFailures{MaxFailures: 10, MaxIdentifiers: 3, MaxIdentifierFailures: 20}
+	tests := []struct {
+		name     string
+		failures LoginFailures
+		reasons  int
+	}{
+		{"at the limits", LoginFailures{Failures: 10, Identifiers: 3, IdentifierFailures: 20}, 0},
+		{"too many failures", LoginFailures{Failures: 11}, 1},
+		{"too many identifiers", LoginFailures{Failures: 4, Identifiers: 4}, 1},
+		{"attacked identifier", LoginFailures{IdentifierFailures: 21}, 1},
+		{"all limits", LoginFailures{Failures: 30, Identifiers: 30, IdentifierFailures: 30}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := tt.failures
+			failures.MaxFailures, failures.MaxIdentifiers, failures.MaxIdentifierFailures = limits.MaxFailures, limits.MaxIdentifiers, limits.MaxIdentifierFailures
+			result := detectCredentialStuffing(&ComponentDict{LoginFailures: SuccessComponent[LoginFailures]{State: StateSuccess, Value: failures}})
+			if result.Bot != (tt.reasons > 0) || len(result.Reasons) != tt.reasons {
+				t.Errorf("Expected %d reasons, got %+v", tt.reasons, result)
+			}
+			if tt.reasons > 0 && result.BotKind != BotKindCredentialStuffing {
+				t.Errorf("Expected credential stuffing, got %q", result.BotKind)
+			}
+		})
+	}
+}
+
+func TestGetLoginFailures(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.LoginProtection = DefaultLoginProtection("/login")
+	config.LoginProtection.Identifier = func(r *http.Request) string { return r.FormValue("user") }
+	detector := NewDetectorWithConfig(config)
+
+	collect := func(target, remoteAddr string) Component[LoginFailures] {
+		req := httptest.NewRequest("POST", target, nil)
+		req.RemoteAddr = remoteAddr
+		return getLoginFailures(req, collectAllSources(req), config.SessionTracker, config.LoginProtection)
+	}
+
+	for i := range 5 {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		if err := detector.RecordLoginFailure(req, fmt.Sprintf("user%d", i%2)); err != nil {
+			t.Fatalf("RecordLoginFailure returned error: %v", err)
+		}
+	}
+	req := httptest.NewRequest("POST", "/login", nil)
+	req.RemoteAddr = "198.51.100.9:1234"
+	detector.RecordLoginFailure(req, "user1")
+
+	failures := collect("/login?user=user1", "203.0.113.7:1234")
+	if failures.GetState() != StateSuccess {
+		t.Fatalf("Expected login failures, got %v", failures)
+	}
+	if value := failures.GetValue(); value.Failures != 5 || value.Identifiers != 2 || value.IdentifierFailures != 3 || value.MaxFailures != 10 {
+		t.Errorf("Expected 5 failures as 2 identifiers and 3 for user1, got %+v", value)
+	}
+	if value := collect("/login", "192.0.2.1:1234").GetValue(); value.Failures != 0 || value.Identifiers != 0 || value.IdentifierFailures != 0 {
+		t.Errorf("Expected no failures for a new visitor, got %+v", value)
+	}
+	if state := collect("/", "203.0.113.7:1234").GetState(); state != StateNull {
+		t.Errorf("Expected no login failures outside the auth paths, got %v", state)
+	}
+}
+
+func TestMiddleware_LoginProtection(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.LoginProtection = DefaultLoginProtection("/login")
+	detector := NewDetectorWithConfig(config)
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			detector.RecordLoginFailure(r, r.URL.Query().Get("user"))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(target string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newBrowserRequest(target, "203.0.113.7:1234"))
+		return rec.Code
+	}
+
+	// A stuffing client with browser headers trying leaked credentials
+	for i := range 3 {
+		if code := serve(fmt.Sprintf("/login?user=user%d", i)); code != http.StatusUnauthorized {
+			t.Fatalf("Expected attempt %d to reach the handler, got %d", i, code)
+		}
+	}
+	if code := serve("/login?user=user3"); code != http.StatusUnauthorized {
+		t.Fatalf("Expected fourth identifier to reach the handler, got %d", code)
+	}
+	if code := serve("/login?user=user4"); code != http.StatusForbidden {
+		t.Errorf("Expected attempts beyond the identifier limit to be blocked, got %d", code)
+	}
+	if code := serve("/"); code != http.StatusOK {
+		t.Errorf("Expected pages outside the auth paths to be served, got %d", code)
+	}
+}
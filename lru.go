@@ -0,0 +1,79 @@
+package gogobot
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruCache is a bounded, concurrency-safe least recently used cache
+type lruCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// newLRUCache creates an LRU cache holding at most capacity entries
+func newLRUCache[K comparable, V any](capacity int) *lruCache[K, V] {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &lruCache[K, V]{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// get returns the value stored for key and marks it as recently used
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		return elem.Value.(*lruEntry[K, V]).value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// set stores value for key, evicting the least recently used entry if full
+func (c *lruCache[K, V]) set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		elem.Value.(*lruEntry[K, V]).value = value
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry[K, V]{key: key, value: value})
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// len returns the number of cached entries
+func (c *lruCache[K, V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// purge removes all entries from the cache
+func (c *lruCache[K, V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[K]*list.Element)
+}
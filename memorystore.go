@@ -0,0 +1,247 @@
+package gogobot
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// Default MemoryStore limits
+const (
+	DefaultMemoryStoreCapacity = 100_000
+	DefaultMaxSetMembers       = 10_000
+)
+
+// memoryStoreShards is the number of independently locked shards in a MemoryStore
+const memoryStoreShards = 64
+
+// ErrWrongType is returned when a Store operation targets a key holding another kind of value,
+// e.g. incrementing a key written with Set
+var ErrWrongType = errors.New("store: key holds a different kind of value")
+
+// MemoryStore is an in-process Store for single-instance deployments and tests. Keys are
+// spread over sharded locks, expired keys are dropped lazily and by Sweep, and memory is
+// bounded: each shard evicts its least recently used keys beyond its share of the capacity,
+// and set sizes saturate at MaxSetMembers.
+type MemoryStore struct {
+	shards        [memoryStoreShards]memoryShard
+	shardCapacity int
+	// MaxSetMembers limits the members kept per set; further members are not counted
+	MaxSetMembers int
+
+	now func() time.Time
+}
+
+type memoryShard struct {
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	counter int64
+	members map[string]struct{}
+	kind    memoryEntryKind
+	expires time.Time
+}
+
+type memoryEntryKind int
+
+const (
+	memoryValue memoryEntryKind = iota
+	memoryCounter
+	memorySet
+)
+
+// NewMemoryStore creates a store holding up to capacity keys; zero uses
+// DefaultMemoryStoreCapacity
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = DefaultMemoryStoreCapacity
+	}
+	s := &MemoryStore{
+		shardCapacity: max(1, capacity/memoryStoreShards),
+		MaxSetMembers: DefaultMaxSetMembers,
+		now:           time.Now,
+	}
+	for i := range s.shards {
+		s.shards[i].ll = list.New()
+		s.shards[i].items = make(map[string]*list.Element)
+	}
+	return s
+}
+
+func (s *MemoryStore) shard(key string) *memoryShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &s.shards[h.Sum32()%memoryStoreShards]
+}
+
+// lookup returns the live entry for key, dropping it if expired. The shard must be locked.
+func (sh *memoryShard) lookup(key string, now time.Time) *memoryEntry {
+	elem, ok := sh.items[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && !now.Before(entry.expires) {
+		sh.ll.Remove(elem)
+		delete(sh.items, key)
+		return nil
+	}
+	sh.ll.MoveToFront(elem)
+	return entry
+}
+
+// insert adds a new entry, evicting the least recently used ones beyond capacity. The shard
+// must be locked.
+func (sh *memoryShard) insert(entry *memoryEntry, capacity int) {
+	sh.items[entry.key] = sh.ll.PushFront(entry)
+	for sh.ll.Len() > capacity {
+		oldest := sh.ll.Back()
+		sh.ll.Remove(oldest)
+		delete(sh.items, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+func expiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// Incr implements Store
+func (s *MemoryStore) Incr(_ context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	now := s.now()
+	entry := sh.lookup(key, now)
+	if entry == nil {
+		sh.insert(&memoryEntry{key: key, kind: memoryCounter, counter: delta, expires: expiry(now, ttl)}, s.shardCapacity)
+		return delta, nil
+	}
+	if entry.kind != memoryCounter {
+		return 0, ErrWrongType
+	}
+	entry.counter += delta
+	return entry.counter, nil
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	entry := sh.lookup(key, s.now())
+	if entry == nil {
+		return nil, false, nil
+	}
+	if entry.kind != memoryValue {
+		return nil, false, ErrWrongType
+	}
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+// Set implements Store
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	now := s.now()
+	entry := &memoryEntry{key: key, kind: memoryValue, value: append([]byte(nil), value...), expires: expiry(now, ttl)}
+	if elem, ok := sh.items[key]; ok {
+		elem.Value = entry
+		sh.ll.MoveToFront(elem)
+		return nil
+	}
+	sh.insert(entry, s.shardCapacity)
+	return nil
+}
+
+// AddToSet implements Store
+func (s *MemoryStore) AddToSet(_ context.Context, key, member string, ttl time.Duration) (int64, error) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	now := s.now()
+	entry := sh.lookup(key, now)
+	if entry == nil {
+		entry = &memoryEntry{key: key, kind: memorySet, members: make(map[string]struct{}), expires: expiry(now, ttl)}
+		sh.insert(entry, s.shardCapacity)
+	}
+	if entry.kind != memorySet {
+		return 0, ErrWrongType
+	}
+	if len(entry.members) < s.MaxSetMembers {
+		entry.members[member] = struct{}{}
+	}
+	return int64(len(entry.members)), nil
+}
+
+// Delete implements Store
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if elem, ok := sh.items[key]; ok {
+		sh.ll.Remove(elem)
+		delete(sh.items, key)
+	}
+	return nil
+}
+
+// Len returns the number of keys held, including expired keys not yet swept
+func (s *MemoryStore) Len() int {
+	n := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		n += sh.ll.Len()
+		sh.mu.Unlock()
+	}
+	return n
+}
+
+// Sweep removes all expired keys
+func (s *MemoryStore) Sweep() {
+	now := s.now()
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		for key, elem := range sh.items {
+			entry := elem.Value.(*memoryEntry)
+			if !entry.expires.IsZero() && !now.Before(entry.expires) {
+				sh.ll.Remove(elem)
+				delete(sh.items, key)
+			}
+		}
+		sh.mu.Unlock()
+	}
+}
+
+// Run calls Sweep every interval until ctx is cancelled
+func (s *MemoryStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
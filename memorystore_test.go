@@ -0,0 +1,166 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Incr(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := store.Incr(ctx, "counter", 1, time.Minute)
+		if err != nil || n != i {
+			t.Fatalf("Expected %d, got %d, %v", i, n, err)
+		}
+	}
+
+	// Incrementing does not extend the TTL
+	now = now.Add(59 * time.Second)
+	store.Incr(ctx, "counter", 1, time.Minute)
+	now = now.Add(time.Second)
+	if n, _ := store.Incr(ctx, "counter", 5, time.Minute); n != 5 {
+		t.Errorf("Expected expired counter to restart, got %d", n)
+	}
+}
+
+func TestMemoryStore_GetSet(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, ok, err := store.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Expected missing key, got %t, %v", ok, err)
+	}
+
+	value := []byte("hello")
+	store.Set(ctx, "key", value, time.Minute)
+	value[0] = 'j'
+	got, ok, err := store.Get(ctx, "key")
+	if !ok || err != nil || string(got) != "hello" {
+		t.Errorf("Expected stored copy, got %q, %t, %v", got, ok, err)
+	}
+
+	// Set replaces the TTL
+	now = now.Add(50 * time.Second)
+	store.Set(ctx, "key", []byte("world"), time.Minute)
+	now = now.Add(50 * time.Second)
+	if got, ok, _ := store.Get(ctx, "key"); !ok || string(got) != "world" {
+		t.Errorf("Expected rewritten value to live on, got %q, %t", got, ok)
+	}
+	now = now.Add(10 * time.Second)
+	if _, ok, _ := store.Get(ctx, "key"); ok {
+		t.Error("Expected value to expire")
+	}
+
+	store.Set(ctx, "key", []byte("x"), 0)
+	store.Delete(ctx, "key")
+	if _, ok, _ := store.Get(ctx, "key"); ok {
+		t.Error("Expected deleted key to be gone")
+	}
+}
+
+func TestMemoryStore_AddToSet(t *testing.T) {
+	store := NewMemoryStore(0)
+	store.MaxSetMembers = 3
+	ctx := context.Background()
+
+	for i, member := range []string{"a", "b", "a", "c", "d", "e"} {
+		n, err := store.AddToSet(ctx, "set", member, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []int64{1, 2, 2, 3, 3, 3}[i]
+		if n != expected {
+			t.Errorf("After adding %q expected %d members, got %d", member, expected, n)
+		}
+	}
+}
+
+func TestMemoryStore_WrongType(t *testing.T) {
+	store := NewMemoryStore(0)
+	ctx := context.Background()
+
+	store.Set(ctx, "value", []byte("x"), time.Minute)
+	if _, err := store.Incr(ctx, "value", 1, time.Minute); !errors.Is(err, ErrWrongType) {
+		t.Errorf("Expected ErrWrongType incrementing a value, got %v", err)
+	}
+	if _, err := store.AddToSet(ctx, "value", "a", time.Minute); !errors.Is(err, ErrWrongType) {
+		t.Errorf("Expected ErrWrongType adding to a value, got %v", err)
+	}
+	store.Incr(ctx, "counter", 1, time.Minute)
+	if _, _, err := store.Get(ctx, "counter"); !errors.Is(err, ErrWrongType) {
+		t.Errorf("Expected ErrWrongType reading a counter, got %v", err)
+	}
+}
+
+func TestMemoryStore_Bounded(t *testing.T) {
+	store := NewMemoryStore(memoryStoreShards * 4)
+	ctx := context.Background()
+	for i := range 10_000 {
+		store.Incr(ctx, fmt.Sprintf("key-%d", i), 1, time.Minute)
+	}
+	if n := store.Len(); n > memoryStoreShards*4 {
+		t.Errorf("Expected at most %d keys, got %d", memoryStoreShards*4, n)
+	}
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.Incr(ctx, "short", 1, time.Second)
+	store.Incr(ctx, "long", 1, time.Hour)
+	store.Set(ctx, "forever", []byte("x"), 0)
+	now = now.Add(time.Minute)
+	store.Sweep()
+	if n := store.Len(); n != 2 {
+		t.Errorf("Expected 2 keys after sweep, got %d", n)
+	}
+}
+
+func TestMemoryStore_Concurrent(t *testing.T) {
+	store := NewMemoryStore(0)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				store.Incr(ctx, "shared", 1, time.Minute)
+			}
+		}()
+	}
+	wg.Wait()
+	if n, _ := store.Incr(ctx, "shared", 0, time.Minute); n != 8000 {
+		t.Errorf("Expected 8000, got %d", n)
+	}
+}
+
+func TestSessionTracker_MemoryStore(t *testing.T) {
+	tracker := NewSessionTracker(NewMemoryStore(0))
+	config := DefaultDetectorConfig()
+	config.SessionTracker = tracker
+	detector := NewDetectorWithConfig(config)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	detector.Collect(req)
+	components, _ := detector.Collect(req)
+	if session := components.Session.GetValue(); session.Requests != 2 || session.IsNew() {
+		t.Errorf("Expected second request in session, got %+v", session)
+	}
+}
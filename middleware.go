@@ -2,34 +2,126 @@ package gogobot
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
 // MiddlewareConfig holds configuration for the middleware
 type MiddlewareConfig struct {
+	// Mode selects whether verdicts are enforced; ModeShadow runs detection and reports what
+	// would have been done to OnAction without acting on it
+	Mode MiddlewareMode
 	// SkipFunc allows skipping detection for specific requests
 	SkipFunc func(*http.Request) bool
-	// OnBotDetected is called when a bot is detected
+	// OnBotDetected is called when a bot is detected; the request goes no further, so the
+	// callback must write the response
 	OnBotDetected func(http.ResponseWriter, *http.Request, *BotDetectionResult)
-	// OnError is called when an error occurs during detection
+	// OnBotDecision is called when a bot is detected, before OnBotDetected and BlockBots, and
+	// decides whether the request continues, is blocked or was handled by the callback
+	OnBotDecision func(http.ResponseWriter, *http.Request, *BotDetectionResult) Decision
+	// OnError is called when an error occurs during detection, and must write the response
 	OnError func(http.ResponseWriter, *http.Request, error)
+	// ErrorPolicy decides what happens to requests detection failed on when there is no
+	// OnError; with FailClosed or FailOpenWithHeader, failures of the Store and reputation
+	// checker count as detection errors
+	ErrorPolicy ErrorPolicy
 	// BlockBots determines if detected bots should be blocked
 	BlockBots bool
 	// BlockedStatusCode is the HTTP status code to return for blocked bots
 	BlockedStatusCode int
 	// BlockedMessage is the message to return for blocked bots
 	BlockedMessage string
+	// BlockedResponse renders blocked responses as JSON or HTML depending on the Accept header;
+	// nil answers with BlockedMessage as plain text
+	BlockedResponse *BlockedResponse
+	// GeoPolicy is called with the client's location before bot handling when the detector
+	// has a GeoProvider; it may escalate the result (see UnservedCountryPolicy)
+	GeoPolicy GeoPolicyFunc
+	// RequireJSChallenge selects routes that require the detector's JSChallenge; clients
+	// without a trust cookie get a page running the challenge instead
+	RequireJSChallenge func(*http.Request) bool
+	// Challenge is presented to detected bots before OnBotDecision, OnBotDetected and BlockBots
+	// apply; visitors that solve it are admitted with ChallengePassed set on the result
+	Challenge ChallengeAction
+	// TrustPass issues a signed cookie to visitors that passed detection or the challenge and
+	// admits its holders without running the detectors; nil disables trust passes
+	TrustPass *TrustPass
+	// Actions map bot categories to what the middleware does with their bots, instead of
+	// Challenge, OnBotDecision, OnBotDetected and BlockBots, which still apply to unmapped
+	// categories
+	Actions map[BotCategory]Action
+	// Tarpit configures ActionTarpit and selects bot kinds to tarpit; nil holds tarpitted
+	// requests for DefaultTarpitDelay
+	Tarpit *Tarpit
+	// DegradedHandler serves the bots ActionDegrade applies to, with the result in the request
+	// context
+	DegradedHandler http.Handler
+	// RateLimiter limits the requests served to each visitor with per bot kind rates; nil
+	// disables rate limiting
+	RateLimiter *RateLimiter
+	// RateLimitResponse configures the responses of ActionRateLimit; nil asks clients to retry
+	// after DefaultRetryAfter
+	RateLimitResponse *RateLimitResponse
+	// OnAction is called with every detected bot the Actions apply to, except allowed ones, e.g.
+	// to log verdicts; it must not write the response
+	OnAction func(*http.Request, *BotDetectionResult, Action)
+	// SampleRate is the fraction (0-1] of requests running the full detector set, bounding
+	// detection CPU on high-traffic services; the others only get their user agent and the
+	// IPAccessList checked. Trap, auth, cookie-required and probe paths are always sampled.
+	// 0 samples every request.
+	SampleRate float64
+	// SkipUnsampled serves requests outside SampleRate without any detection, except refusing
+	// addresses the IPAccessList denies
+	SkipUnsampled bool
+	// ResultHeaders annotates the response or the forwarded request with the verdict; nil
+	// disables result headers
+	ResultHeaders *ResultHeaders
+	// Observer is notified of the detection time, verdict, action and detector timings of every
+	// request; nil disables observation
+	Observer Observer
+	// Logger receives a structured log of every request detection ran on: failed detections at
+	// Error, refused bots at Warn, served bots at Info and other requests at Debug; nil
+	// disables logging
+	Logger *slog.Logger
+	// Expvar counts requests, bots by kind, actions, blocks and errors in expvar; nil disables
+	// the counters
+	Expvar *ExpvarCounters
+	// Routes are per-path policies tried in order; the first matching the request path applies
+	// instead of this configuration
+	Routes []RoutePolicy
 }
 
 // DefaultMiddlewareConfig returns a default middleware configuration
 func DefaultMiddlewareConfig() MiddlewareConfig {
 	return MiddlewareConfig{
-		SkipFunc:          nil,
-		OnBotDetected:     nil,
-		OnError:           nil,
-		BlockBots:         false,
-		BlockedStatusCode: http.StatusForbidden,
-		BlockedMessage:    "Bot traffic is not allowed",
+		Mode:               ModeEnforce,
+		SkipFunc:           nil,
+		OnBotDetected:      nil,
+		OnBotDecision:      nil,
+		OnError:            nil,
+		ErrorPolicy:        FailOpen,
+		BlockBots:          false,
+		BlockedStatusCode:  http.StatusForbidden,
+		BlockedMessage:     "Bot traffic is not allowed",
+		BlockedResponse:    nil,
+		GeoPolicy:          nil,
+		RequireJSChallenge: nil,
+		Challenge:          nil,
+		TrustPass:          nil,
+		Actions:            nil,
+		Tarpit:             nil,
+		DegradedHandler:    nil,
+		RateLimiter:        nil,
+		RateLimitResponse:  nil,
+		OnAction:           nil,
+		SampleRate:         1,
+		SkipUnsampled:      false,
+		ResultHeaders:      nil,
+		Observer:           nil,
+		Logger:             nil,
+		Expvar:             nil,
+		Routes:             nil,
 	}
 }
 
@@ -42,57 +134,181 @@ func (d *BotDetector) Middleware() func(http.Handler) http.Handler {
 func (d *BotDetector) MiddlewareWithConfig(config MiddlewareConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The challenge endpoints must stay reachable for the clients being challenged
+			if d.serveJSChallenge(w, r) {
+				return
+			}
+
+			config, skip := config.route(r.URL.Path)
+			config.Mode = d.middlewareMode(config)
+			config.ResultHeaders.strip(r)
+			if config.ErrorPolicy == FailOpenWithHeader {
+				r.Header.Del(DetectionErrorHeader)
+			}
 			// Skip detection if configured
-			if config.SkipFunc != nil && config.SkipFunc(r) {
+			if skip || config.SkipFunc != nil && config.SkipFunc(r) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Perform bot detection
-			result, err := d.DetectFromRequest(r)
-			if err != nil {
-				if config.OnError != nil {
-					config.OnError(w, r, err)
+			observer := config.observer(d.eventObserver())
+			observation := Observation{Action: ActionAllow}
+			if observer != nil {
+				defer func() {
+					if observer != nil {
+						observer.ObserveRequest(r, observation)
+					}
+				}()
+			}
+
+			// Visitors holding a trust pass skip detection, unless every verdict is measured
+			if config.Mode != ModeShadow {
+				if action, ok := d.admitTrusted(w, r, config, next); ok {
+					observation.Result = &BotDetectionResult{Trusted: true}
+					if action != "" {
+						observation.Action = action
+					}
 					return
 				}
-				// Continue processing if no error handler is configured
-				next.ServeHTTP(w, r)
-				return
 			}
 
-			// Store result in context
+			var (
+				result     BotDetectionResult
+				components *ComponentDict
+				geo        Component[GeoInfo]
+				timings    map[string]time.Duration
+			)
+			start := time.Now()
+			if entry, ok := d.lookupResultCache(r); ok {
+				result, geo = entry.result, entry.geo
+				observation.Cached = true
+			} else if !d.alwaysDetect(r) && !config.sampled() {
+				access := d.unsampledAccess(r)
+				if config.SkipUnsampled && access != IPAccessDeny {
+					observer = nil
+					next.ServeHTTP(w, r)
+					return
+				}
+				result = detectUnsampled(r, access)
+			} else {
+				// Perform bot detection without sharing state between concurrent requests
+				var err error
+				components, err = d.collect(r)
+				if err == nil && config.ErrorPolicy.strict() {
+					err = components.backendError()
+				}
+				if err != nil {
+					observation.Duration, observation.Action, observation.Err = time.Since(start), "", err
+					if config.OnError != nil {
+						config.OnError(w, r, err)
+						return
+					}
+					failDetection(w, r, config, next)
+					return
+				}
+
+				if observer != nil {
+					timings = make(map[string]time.Duration)
+				}
+				result, _ = d.detect(components, timings)
+				geo = components.Geo
+				d.cacheResult(r, components, result)
+			}
+			observation.Duration, observation.Result, observation.Detectors = time.Since(start), &result, timings
+
+			if config.GeoPolicy != nil && geo != nil && geo.GetState() == StateSuccess {
+				config.GeoPolicy(r, geo.GetValue(), &result)
+			}
+			d.controls.count(&result)
+
+			// Store result in context; cached verdicts have no components. The browser and AI
+			// classification are kept so handlers need not parse or detect again.
 			ctx := context.WithValue(r.Context(), DetectionResultKey, &result)
-			ctx = context.WithValue(ctx, ComponentsKey, d.GetComponents())
+			if components != nil {
+				ctx = context.WithValue(ctx, ComponentsKey, components)
+			}
+			browser, ai := ParseBrowserFromRequest(r), newAIInfo(&result)
+			ctx = context.WithValue(ctx, BrowserInfoKey, &browser)
+			ctx = context.WithValue(ctx, AIInfoKey, &ai)
 			r = r.WithContext(ctx)
+			config.ResultHeaders.annotate(w, r, &result)
+
+			if config.Mode == ModeShadow {
+				d.shadow(w, r, components, config, &result, next)
+				return
+			}
+
+			action, hasAction := config.action(&result)
+			challenge := config.Challenge != nil && (!hasAction || action == ActionChallenge)
+			if result.Bot && challenge && d.challengeBot(w, r, components, config.Challenge, &result) {
+				observation.Action = ActionChallenge
+				return
+			}
+			// A user agent check alone does not earn a trust pass
+			if !result.Unsampled {
+				d.updateTrustPass(w, r, components, config, result)
+			}
 
 			// Handle bot detection
-			if result.Bot {
-				if config.OnBotDetected != nil {
+			if result.Bot && !result.ChallengePassed {
+				switch {
+				case hasAction:
+					if applyAction(w, r, config, &result, action) {
+						observation.Action = action
+						return
+					}
+					if action == ActionLog {
+						observation.Action = ActionLog
+					}
+				case config.OnBotDecision != nil:
+					if action, handled := decide(w, r, config, &result); handled {
+						observation.Action = action
+						return
+					}
+				case config.OnBotDetected != nil:
+					observation.Action = ActionBlock
 					config.OnBotDetected(w, r, &result)
 					return
+				case config.BlockBots:
+					observation.Action = ActionBlock
+					blockBot(w, r, config, &result)
+					return
 				}
+			}
 
-				if config.BlockBots {
-					// Ensure we have a valid status code
-					statusCode := config.BlockedStatusCode
-					if statusCode == 0 {
-						statusCode = http.StatusForbidden
-					}
-					message := config.BlockedMessage
-					if message == "" {
-						message = "Bot traffic is not allowed"
-					}
-					http.Error(w, message, statusCode)
+			if d.limit(w, r, components, config, &result) {
+				observation.Action = ActionRateLimit
+				return
+			}
+
+			// Connection upgrades cannot follow challenge redirects or run scripts
+			if !isUpgradeRequest(r) {
+				if d.challengeCookie(w, r, components, &result) {
+					observation.Action = ActionChallenge
+					return
+				}
+				if d.requireJSChallenge(w, r, components, config) {
+					observation.Action = ActionChallenge
 					return
 				}
 			}
 
 			// Continue to next handler
-			next.ServeHTTP(w, r)
+			d.serveRecorded(w, r, components, next)
 		})
 	}
 }
 
+// requireJSChallenge serves the JavaScript challenge interstitial on routes requiring it to
+// clients without a trust cookie, reporting whether it did
+func (d *BotDetector) requireJSChallenge(w http.ResponseWriter, r *http.Request, components *ComponentDict, config MiddlewareConfig) bool {
+	if config.RequireJSChallenge == nil || d.config.JSChallenge == nil || !config.RequireJSChallenge(r) || d.jsTrusted(r, components) {
+		return false
+	}
+	d.config.JSChallenge.serveInterstitial(w, r)
+	return true
+}
+
 // HandlerFunc is a convenience function that wraps a http.HandlerFunc with bot detection
 func (d *BotDetector) HandlerFunc(handler http.HandlerFunc) http.HandlerFunc {
 	middleware := d.Middleware()
@@ -3,6 +3,7 @@ package gogobot
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 )
 
@@ -310,3 +311,35 @@ func TestBotDetector_HandlerFuncWithConfig(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
 	}
 }
+
+func TestBotDetector_MiddlewareConcurrent(t *testing.T) {
+	detector := NewDetector()
+	handler := detector.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, _ := GetResultFromContext(r.Context())
+		components, _ := GetComponentsFromContext(r.Context())
+		isCurl := components.UserAgent.GetValue() == "curl/7.68.0"
+		if result.Bot != isCurl {
+			t.Errorf("Result %+v does not belong to request with user agent %q", result, components.UserAgent.GetValue())
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/", nil)
+			if i%2 == 0 {
+				req.Header.Set("User-Agent", "curl/7.68.0")
+			} else {
+				req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+				req.Header.Set("Accept", "text/html")
+				req.Header.Set("Accept-Language", "en-US")
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,385 @@
+package gogobot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+)
+
+// mmdbMetadataMarker separates the data section from the metadata in a MaxMind DB file
+var mmdbMetadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// mmdbDataSeparatorSize is the number of zero bytes between the search tree and the data section
+const mmdbDataSeparatorSize = 16
+
+// MMDBMetadata describes a MaxMind DB file
+type MMDBMetadata struct {
+	DatabaseType string
+	BuildEpoch   uint64
+	IPVersion    uint64
+	NodeCount    uint64
+	RecordSize   uint64
+}
+
+// MMDBReader is a minimal reader for MaxMind DB (.mmdb) files such as GeoLite2-ASN and
+// GeoLite2-Country. It loads the whole file into memory and is safe for concurrent use.
+type MMDBReader struct {
+	buf        []byte
+	tree       []byte
+	data       []byte
+	metadata   MMDBMetadata
+	ipv4Start  uint64
+	nodeBytes  uint64
+	ipv4Exists bool
+}
+
+// OpenMMDB reads a MaxMind DB file from disk
+func OpenMMDB(path string) (*MMDBReader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMMDBReader(buf)
+}
+
+// NewMMDBReader parses a MaxMind DB file held in memory
+func NewMMDBReader(buf []byte) (*MMDBReader, error) {
+	markerAt := bytes.LastIndex(buf, mmdbMetadataMarker)
+	if markerAt < 0 {
+		return nil, errors.New("mmdb: metadata marker not found")
+	}
+
+	metaStart := markerAt + len(mmdbMetadataMarker)
+	metaDecoder := mmdbDecoder{buf: buf[metaStart:]}
+	raw, _, err := metaDecoder.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("mmdb: metadata: %w", err)
+	}
+	meta, ok := raw.(map[string]any)
+	if !ok {
+		return nil, errors.New("mmdb: metadata is not a map")
+	}
+
+	r := &MMDBReader{buf: buf}
+	r.metadata.DatabaseType, _ = meta["database_type"].(string)
+	r.metadata.BuildEpoch, _ = meta["build_epoch"].(uint64)
+	r.metadata.IPVersion, _ = meta["ip_version"].(uint64)
+	r.metadata.NodeCount, _ = meta["node_count"].(uint64)
+	r.metadata.RecordSize, _ = meta["record_size"].(uint64)
+
+	switch r.metadata.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("mmdb: unsupported record size %d", r.metadata.RecordSize)
+	}
+	r.nodeBytes = r.metadata.RecordSize * 2 / 8
+
+	treeSize := r.metadata.NodeCount * r.nodeBytes
+	if treeSize+mmdbDataSeparatorSize > uint64(markerAt) {
+		return nil, errors.New("mmdb: search tree exceeds file size")
+	}
+	r.tree = buf[:treeSize]
+	r.data = buf[treeSize+mmdbDataSeparatorSize : markerAt]
+
+	// IPv4 addresses live under ::/96 in IPv6 databases
+	if r.metadata.IPVersion == 6 {
+		node := uint64(0)
+		for i := 0; i < 96 && node < r.metadata.NodeCount; i++ {
+			node = r.readRecord(node, 0)
+		}
+		r.ipv4Start = node
+		r.ipv4Exists = node < r.metadata.NodeCount
+	} else {
+		r.ipv4Exists = true
+	}
+
+	return r, nil
+}
+
+// Metadata returns the database metadata
+func (r *MMDBReader) Metadata() MMDBMetadata {
+	return r.metadata
+}
+
+// Lookup returns the decoded record for addr, or nil if the address is not in the database
+func (r *MMDBReader) Lookup(addr netip.Addr) (any, error) {
+	if !addr.IsValid() {
+		return nil, nil
+	}
+	addr = normalizeAddr(addr)
+
+	var node uint64
+	var bits [16]byte
+	var bitLen int
+	if addr.Is4() {
+		if !r.ipv4Exists {
+			return nil, nil
+		}
+		node = r.ipv4Start
+		a4 := addr.As4()
+		copy(bits[:], a4[:])
+		bitLen = 32
+	} else {
+		if r.metadata.IPVersion != 6 {
+			return nil, nil
+		}
+		bits = addr.As16()
+		bitLen = 128
+	}
+
+	for i := 0; i < bitLen && node < r.metadata.NodeCount; i++ {
+		node = r.readRecord(node, addrBit(&bits, i))
+	}
+
+	switch {
+	case node == r.metadata.NodeCount:
+		return nil, nil
+	case node < r.metadata.NodeCount:
+		return nil, errors.New("mmdb: search tree is deeper than the address")
+	}
+
+	offset := node - r.metadata.NodeCount - mmdbDataSeparatorSize
+	if offset >= uint64(len(r.data)) {
+		return nil, errors.New("mmdb: invalid data pointer")
+	}
+	decoder := mmdbDecoder{buf: r.data}
+	value, _, err := decoder.decode(uint(offset))
+	return value, err
+}
+
+// readRecord returns the left (bit 0) or right (bit 1) record of a search tree node
+func (r *MMDBReader) readRecord(node uint64, bit int) uint64 {
+	b := r.tree[node*r.nodeBytes : (node+1)*r.nodeBytes]
+	switch r.metadata.RecordSize {
+	case 24:
+		if bit == 0 {
+			return uint64(b[0])<<16 | uint64(b[1])<<8 | uint64(b[2])
+		}
+		return uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
+	case 28:
+		if bit == 0 {
+			return uint64(b[3]&0xF0)<<20 | uint64(b[0])<<16 | uint64(b[1])<<8 | uint64(b[2])
+		}
+		return uint64(b[3]&0x0F)<<24 | uint64(b[4])<<16 | uint64(b[5])<<8 | uint64(b[6])
+	default:
+		if bit == 0 {
+			return uint64(binary.BigEndian.Uint32(b[0:4]))
+		}
+		return uint64(binary.BigEndian.Uint32(b[4:8]))
+	}
+}
+
+// MaxMind DB data section field types
+const (
+	mmdbExtended  = 0
+	mmdbPointer   = 1
+	mmdbString    = 2
+	mmdbDouble    = 3
+	mmdbBytes     = 4
+	mmdbUint16    = 5
+	mmdbUint32    = 6
+	mmdbMap       = 7
+	mmdbInt32     = 8
+	mmdbUint64    = 9
+	mmdbUint128   = 10
+	mmdbArray     = 11
+	mmdbContainer = 12
+	mmdbEndMarker = 13
+	mmdbBool      = 14
+	mmdbFloat     = 15
+)
+
+// mmdbDecoder decodes values from a MaxMind DB data section. Strings decode to string,
+// unsigned integers to uint64, int32 to int64, maps to map[string]any and arrays to []any.
+type mmdbDecoder struct {
+	buf []byte
+}
+
+var errMMDBTruncated = errors.New("mmdb: unexpected end of data")
+
+// mmdbMaxDepth bounds nesting so that malformed pointer cycles cannot recurse forever
+const mmdbMaxDepth = 64
+
+// decode returns the value at offset and the offset following it
+func (d *mmdbDecoder) decode(offset uint) (any, uint, error) {
+	return d.decodeAt(offset, 0)
+}
+
+func (d *mmdbDecoder) decodeAt(offset uint, depth int) (any, uint, error) {
+	if depth > mmdbMaxDepth {
+		return nil, 0, errors.New("mmdb: data nested too deeply")
+	}
+
+	typeNum, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typeNum == mmdbPointer {
+		target, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decodeAt(target, depth+1)
+		return value, next, err
+	}
+
+	return d.decodeValue(typeNum, size, offset, depth)
+}
+
+// decodeControl reads a control byte and returns the field type, payload size and payload offset
+func (d *mmdbDecoder) decodeControl(offset uint) (int, uint, uint, error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, 0, 0, errMMDBTruncated
+	}
+	ctrl := d.buf[offset]
+	offset++
+
+	typeNum := int(ctrl >> 5)
+	if typeNum == mmdbExtended {
+		if offset >= uint(len(d.buf)) {
+			return 0, 0, 0, errMMDBTruncated
+		}
+		typeNum = 7 + int(d.buf[offset])
+		offset++
+	}
+
+	size := uint(ctrl & 0x1F)
+	if typeNum == mmdbPointer {
+		return typeNum, uint(ctrl), offset, nil
+	}
+	if size >= 29 {
+		extra := size - 28
+		if offset+extra > uint(len(d.buf)) {
+			return 0, 0, 0, errMMDBTruncated
+		}
+		n := uint(0)
+		for _, b := range d.buf[offset : offset+extra] {
+			n = n<<8 | uint(b)
+		}
+		switch size {
+		case 29:
+			size = 29 + n
+		case 30:
+			size = 285 + n
+		default:
+			size = 65821 + n
+		}
+		offset += extra
+	}
+
+	return typeNum, size, offset, nil
+}
+
+// decodePointer resolves a pointer; ctrl is the full control byte
+func (d *mmdbDecoder) decodePointer(ctrl uint, offset uint) (uint, uint, error) {
+	n := (ctrl>>3)&0x3 + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, errMMDBTruncated
+	}
+	b := d.buf[offset : offset+n]
+	var target uint
+	switch n {
+	case 1:
+		target = (ctrl&0x7)<<8 | uint(b[0])
+	case 2:
+		target = ((ctrl&0x7)<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		target = ((ctrl&0x7)<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		target = uint(binary.BigEndian.Uint32(b))
+	}
+	return target, offset + n, nil
+}
+
+func (d *mmdbDecoder) decodeValue(typeNum int, size uint, offset uint, depth int) (any, uint, error) {
+	switch typeNum {
+	case mmdbMap:
+		m := make(map[string]any, min(size, 64))
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decodeAt(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("mmdb: map key is not a string")
+			}
+			value, next, err := d.decodeAt(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[keyString] = value
+			offset = next
+		}
+		return m, offset, nil
+
+	case mmdbArray:
+		a := make([]any, 0, min(size, 64))
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decodeAt(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+			offset = next
+		}
+		return a, offset, nil
+
+	case mmdbBool:
+		return size != 0, offset, nil
+
+	case mmdbContainer, mmdbEndMarker:
+		return nil, offset, nil
+	}
+
+	if offset+size > uint(len(d.buf)) {
+		return nil, 0, errMMDBTruncated
+	}
+	payload := d.buf[offset : offset+size]
+	next := offset + size
+
+	switch typeNum {
+	case mmdbString:
+		return string(payload), next, nil
+	case mmdbBytes:
+		return append([]byte(nil), payload...), next, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("mmdb: invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(payload)), next, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("mmdb: invalid float size %d", size)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(payload)), next, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("mmdb: invalid integer size %d", size)
+		}
+		var n uint64
+		for _, b := range payload {
+			n = n<<8 | uint64(b)
+		}
+		return n, next, nil
+	case mmdbInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("mmdb: invalid int32 size %d", size)
+		}
+		var n uint32
+		for _, b := range payload {
+			n = n<<8 | uint32(b)
+		}
+		return int64(int32(n)), next, nil
+	case mmdbUint128:
+		// Returned as big-endian bytes; no supported database uses uint128 values
+		return append([]byte(nil), payload...), next, nil
+	default:
+		return nil, 0, fmt.Errorf("mmdb: unknown field type %d", typeNum)
+	}
+}
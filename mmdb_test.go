@@ -0,0 +1,190 @@
+package gogobot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"testing"
+)
+
+// mmdbTestWriter builds small MaxMind DB files for tests
+type mmdbTestWriter struct {
+	nodes [][2]int
+	data  bytes.Buffer
+}
+
+const (
+	mmdbTestEmpty = -1
+	// data references are stored as -(offset + 2)
+	mmdbTestDataBase = -2
+)
+
+func newMMDBTestWriter() *mmdbTestWriter {
+	return &mmdbTestWriter{nodes: [][2]int{{mmdbTestEmpty, mmdbTestEmpty}}}
+}
+
+// insert maps prefix to a data section offset; IPv4 prefixes are placed under ::/96
+func (w *mmdbTestWriter) insert(prefix netip.Prefix, offset int) {
+	addr := prefix.Addr()
+	bits := prefix.Bits()
+	if addr.Is4() {
+		addr = netip.AddrFrom16(addr.As16())
+		a16 := addr.As16()
+		a16[10], a16[11] = 0, 0
+		addr = netip.AddrFrom16(a16)
+		bits += 96
+	}
+	b := addr.As16()
+
+	node := 0
+	for i := 0; i < bits; i++ {
+		bit := addrBit(&b, i)
+		if i == bits-1 {
+			w.nodes[node][bit] = mmdbTestDataBase - offset
+			return
+		}
+		if w.nodes[node][bit] < 0 {
+			w.nodes = append(w.nodes, [2]int{mmdbTestEmpty, mmdbTestEmpty})
+			w.nodes[node][bit] = len(w.nodes) - 1
+		}
+		node = w.nodes[node][bit]
+	}
+}
+
+func (w *mmdbTestWriter) bytes() []byte {
+	var out bytes.Buffer
+	nodeCount := len(w.nodes)
+	for _, node := range w.nodes {
+		for _, record := range node {
+			value := nodeCount
+			switch {
+			case record >= 0:
+				value = record
+			case record <= mmdbTestDataBase:
+				value = nodeCount + 16 + (mmdbTestDataBase - record)
+			}
+			out.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+		}
+	}
+	out.Write(make([]byte, 16))
+	out.Write(w.data.Bytes())
+	out.Write(mmdbMetadataMarker)
+
+	var meta bytes.Buffer
+	writeMMDBMap(&meta, 4)
+	writeMMDBString(&meta, "node_count")
+	writeMMDBUint(&meta, mmdbUint32, uint64(nodeCount))
+	writeMMDBString(&meta, "record_size")
+	writeMMDBUint(&meta, mmdbUint16, 24)
+	writeMMDBString(&meta, "ip_version")
+	writeMMDBUint(&meta, mmdbUint16, 6)
+	writeMMDBString(&meta, "database_type")
+	writeMMDBString(&meta, "Test-ASN")
+	out.Write(meta.Bytes())
+
+	return out.Bytes()
+}
+
+func writeMMDBMap(buf *bytes.Buffer, size int) {
+	buf.WriteByte(mmdbMap<<5 | byte(size))
+}
+
+func writeMMDBString(buf *bytes.Buffer, s string) {
+	if len(s) < 29 {
+		buf.WriteByte(mmdbString<<5 | byte(len(s)))
+	} else {
+		buf.WriteByte(mmdbString<<5 | 29)
+		buf.WriteByte(byte(len(s) - 29))
+	}
+	buf.WriteString(s)
+}
+
+func writeMMDBUint(buf *bytes.Buffer, typeNum int, n uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	payload := bytes.TrimLeft(b[:], "\x00")
+	if typeNum < 8 {
+		buf.WriteByte(byte(typeNum)<<5 | byte(len(payload)))
+	} else {
+		buf.WriteByte(byte(len(payload)))
+		buf.WriteByte(byte(typeNum - 7))
+	}
+	buf.Write(payload)
+}
+
+func writeMMDBPointer(buf *bytes.Buffer, offset int) {
+	buf.WriteByte(mmdbPointer<<5 | byte(offset>>8))
+	buf.WriteByte(byte(offset))
+}
+
+// buildTestASNDatabase returns a database mapping two prefixes to ASN records
+func buildTestASNDatabase() []byte {
+	w := newMMDBTestWriter()
+
+	amazon := w.data.Len()
+	writeMMDBMap(&w.data, 2)
+	numberKey := w.data.Len()
+	writeMMDBString(&w.data, "autonomous_system_number")
+	writeMMDBUint(&w.data, mmdbUint32, 16509)
+	orgKey := w.data.Len()
+	writeMMDBString(&w.data, "autonomous_system_organization")
+	writeMMDBString(&w.data, "Amazon.com, Inc.")
+
+	// The second record reuses the keys through pointers, as real databases do
+	hetzner := w.data.Len()
+	writeMMDBMap(&w.data, 2)
+	writeMMDBPointer(&w.data, numberKey)
+	writeMMDBUint(&w.data, mmdbUint64, 24940)
+	writeMMDBPointer(&w.data, orgKey)
+	writeMMDBString(&w.data, "Hetzner Online GmbH")
+
+	w.insert(netip.MustParsePrefix("3.0.0.0/9"), amazon)
+	w.insert(netip.MustParsePrefix("2a01:4f8::/29"), hetzner)
+	return w.bytes()
+}
+
+func TestMMDBReader_Lookup(t *testing.T) {
+	reader, err := NewMMDBReader(buildTestASNDatabase())
+	if err != nil {
+		t.Fatalf("NewMMDBReader returned error: %v", err)
+	}
+
+	meta := reader.Metadata()
+	if meta.DatabaseType != "Test-ASN" || meta.IPVersion != 6 || meta.RecordSize != 24 {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+
+	record, err := reader.Lookup(netip.MustParseAddr("3.5.140.1"))
+	if err != nil {
+		t.Fatalf("Lookup returned error: %v", err)
+	}
+	fields, ok := record.(map[string]any)
+	if !ok || fields["autonomous_system_number"] != uint64(16509) {
+		t.Errorf("Unexpected record %#v", record)
+	}
+
+	if record, err := reader.Lookup(netip.MustParseAddr("8.8.8.8")); err != nil || record != nil {
+		t.Errorf("Expected no record for 8.8.8.8, got %#v, %v", record, err)
+	}
+}
+
+func TestNewMMDBReader_Invalid(t *testing.T) {
+	if _, err := NewMMDBReader([]byte("not a database")); err == nil {
+		t.Error("Expected error for missing metadata marker")
+	}
+
+	truncated := append([]byte{0, 0, 0}, mmdbMetadataMarker...)
+	truncated = append(truncated, mmdbMap<<5|1)
+	if _, err := NewMMDBReader(truncated); err == nil {
+		t.Error("Expected error for truncated metadata")
+	}
+}
+
+func TestMMDBDecoder_PointerCycle(t *testing.T) {
+	// A map whose value points back at the map itself
+	buf := []byte{mmdbMap<<5 | 1, mmdbString<<5 | 1, 'k', mmdbPointer << 5, 0}
+	decoder := mmdbDecoder{buf: buf}
+	if _, _, err := decoder.decode(0); err == nil {
+		t.Error("Expected error for pointer cycle")
+	}
+}
@@ -0,0 +1,73 @@
+package gogobot
+
+import (
+	"net/http"
+	"time"
+)
+
+// Observer is notified of every request the middleware ran detection on, after the response
+// was written, e.g. to record latency and verdict metrics or log decisions. It is called
+// synchronously, so it should be fast.
+type Observer interface {
+	ObserveRequest(req *http.Request, observation Observation)
+}
+
+// ObserverFunc adapts a function to the Observer interface
+type ObserverFunc func(*http.Request, Observation)
+
+// ObserveRequest calls f
+func (f ObserverFunc) ObserveRequest(req *http.Request, observation Observation) {
+	f(req, observation)
+}
+
+// Observation describes how the middleware handled a request
+type Observation struct {
+	// Duration is the time spent collecting components and running the detectors, or looking
+	// up the verdict
+	Duration time.Duration
+	// Result is the verdict; nil when detection failed
+	Result *BotDetectionResult
+	// Action is what the middleware did: ActionAllow when the request was served, ActionLog
+	// for served bots reported to OnAction, or the refusal. Bots stopped by OnBotDetected or
+	// blocked by OnBotDecision are reported as ActionBlock; requests detection failed on and
+	// bots whose response OnBotDecision wrote (DecisionHandled) have no action.
+	Action Action
+	// Detectors are the run times of the individual detectors; nil when the verdict came from
+	// the ResultCache, sampling or a trust pass
+	Detectors map[string]time.Duration
+	// Cached is true when the verdict came from the ResultCache
+	Cached bool
+	// Err is the detection error, when detection failed
+	Err error
+}
+
+// observer returns the Observer notifying the configured Observer, logging to the Logger,
+// counting in Expvar and notifying the extra observers that are not nil
+func (c MiddlewareConfig) observer(extra ...Observer) Observer {
+	var observers []Observer
+	if c.Observer != nil {
+		observers = append(observers, c.Observer)
+	}
+	if c.Logger != nil {
+		observers = append(observers, logObserver{logger: c.Logger})
+	}
+	if c.Expvar != nil {
+		observers = append(observers, c.Expvar)
+	}
+	for _, observer := range extra {
+		if observer != nil {
+			observers = append(observers, observer)
+		}
+	}
+	switch len(observers) {
+	case 0:
+		return nil
+	case 1:
+		return observers[0]
+	}
+	return ObserverFunc(func(req *http.Request, observation Observation) {
+		for _, observer := range observers {
+			observer.ObserveRequest(req, observation)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware_Observer(t *testing.T) {
+	var observations []Observation
+	config := DefaultMiddlewareConfig()
+	config.Actions = map[BotCategory]Action{
+		BotCategoryCrawler:    ActionLog,
+		BotCategoryAutomation: ActionBlock,
+	}
+	config.Observer = ObserverFunc(func(r *http.Request, observation Observation) {
+		observations = append(observations, observation)
+	})
+	detector := NewDetectorWithConfig(DetectorConfig{ResultCache: NewResultCache(10, time.Minute)})
+	handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, userAgent := range []string{
+		testBrowserUA,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"curl/8.4.0",
+		"curl/8.4.0",
+	} {
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", userAgent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(observations) != 4 {
+		t.Fatalf("Expected 4 observations, got %d", len(observations))
+	}
+	expected := []struct {
+		action Action
+		bot    bool
+		cached bool
+	}{
+		{ActionAllow, false, false},
+		{ActionLog, true, false},
+		{ActionBlock, true, false},
+		{ActionBlock, true, true},
+	}
+	for i, tt := range expected {
+		observation := observations[i]
+		if observation.Action != tt.action || observation.Result == nil || observation.Result.Bot != tt.bot || observation.Cached != tt.cached {
+			t.Errorf("Observation %d: expected %s bot=%v cached=%v, got %+v", i, tt.action, tt.bot, tt.cached, observation)
+		}
+		if observation.Duration <= 0 {
+			t.Errorf("Observation %d: expected a detection duration", i)
+		}
+	}
+	if _, ok := observations[0].Detectors["userAgent"]; !ok || len(observations[0].Detectors) != len(detector.GetDetectorNames()) {
+		t.Errorf("Expected the timing of every detector, got %v", observations[0].Detectors)
+	}
+	if observations[3].Detectors != nil {
+		t.Errorf("Expected no detector timings for a cached verdict, got %v", observations[3].Detectors)
+	}
+}
+
+func TestMiddleware_ObserverSkipped(t *testing.T) {
+	observed := 0
+	config := DefaultMiddlewareConfig()
+	config.SkipFunc = SkipPathPrefixes("/static/")
+	config.Observer = ObserverFunc(func(r *http.Request, observation Observation) { observed++ })
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newBrowserRequest("/static/app.js", "203.0.113.7:1234"))
+	if observed != 0 {
+		t.Errorf("Expected skipped requests not to be observed, got %d", observed)
+	}
+}
@@ -0,0 +1,96 @@
+package gogobot
+
+import (
+	"net/netip"
+)
+
+// OrganizationProvider looks up the organization owning an IP address, e.g. from WHOIS data
+type OrganizationProvider interface {
+	LookupOrganization(addr netip.Addr) (string, bool)
+}
+
+// datacenterOrganizations names the operators of the embedded datacenter ranges
+var datacenterOrganizations = map[DatacenterProvider]string{
+	DatacenterAWS:          "Amazon Web Services",
+	DatacenterGCP:          "Google Cloud",
+	DatacenterAzure:        "Microsoft Azure",
+	DatacenterOVH:          "OVHcloud",
+	DatacenterHetzner:      "Hetzner Online",
+	DatacenterDigitalOcean: "DigitalOcean",
+}
+
+// Organization returns the name of the company operating the provider's ranges
+func (p DatacenterProvider) Organization() string {
+	if name, ok := datacenterOrganizations[p]; ok {
+		return name
+	}
+	return string(p)
+}
+
+// MMDBOrganizationProvider is an OrganizationProvider backed by a MaxMind GeoIP2-ISP,
+// GeoLite2-ASN or compatible database
+type MMDBOrganizationProvider struct {
+	reader *MMDBReader
+}
+
+// NewMMDBOrganizationProvider creates an OrganizationProvider from an open MaxMind DB reader
+func NewMMDBOrganizationProvider(reader *MMDBReader) *MMDBOrganizationProvider {
+	return &MMDBOrganizationProvider{reader: reader}
+}
+
+// OpenMMDBOrganizationProvider opens a GeoIP2-ISP or GeoLite2-ASN database file
+func OpenMMDBOrganizationProvider(path string) (*MMDBOrganizationProvider, error) {
+	reader, err := OpenMMDB(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMMDBOrganizationProvider(reader), nil
+}
+
+// LookupOrganization implements OrganizationProvider, preferring the registered organization
+// over the ISP and the autonomous system's name
+func (p *MMDBOrganizationProvider) LookupOrganization(addr netip.Addr) (string, bool) {
+	record, err := p.reader.Lookup(addr)
+	if err != nil {
+		return "", false
+	}
+	fields, ok := record.(map[string]any)
+	if !ok {
+		return "", false
+	}
+
+	for _, key := range []string{"organization", "isp", "autonomous_system_organization"} {
+		if name, _ := fields[key].(string); name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// getOrganization names the organization owning the client IP, using provider when configured
+// and otherwise the ASN's registered name or the embedded datacenter ranges
+func getOrganization(clientIP Component[netip.Addr], asn Component[ASNInfo], datacenter Component[DatacenterProvider], provider OrganizationProvider) Component[string] {
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[string]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+
+	if provider != nil {
+		if name, ok := provider.LookupOrganization(clientIP.GetValue()); ok {
+			return SuccessComponent[string]{State: StateSuccess, Value: name}
+		}
+	}
+	if asn.GetState() == StateSuccess && asn.GetValue().Organization != "" {
+		return SuccessComponent[string]{State: StateSuccess, Value: asn.GetValue().Organization}
+	}
+	if datacenter.GetState() == StateSuccess && datacenter.GetValue() != "" {
+		return SuccessComponent[string]{State: StateSuccess, Value: datacenter.GetValue().Organization()}
+	}
+
+	return ErrorComponent[string]{
+		State: StateNull,
+		Error: "no organization found for " + clientIP.GetValue().String(),
+	}
+}
@@ -0,0 +1,104 @@
+package gogobot
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestMMDBOrganizationProvider_LookupOrganization(t *testing.T) {
+	w := newMMDBTestWriter()
+	isp := w.data.Len()
+	writeMMDBMap(&w.data, 2)
+	writeMMDBString(&w.data, "isp")
+	writeMMDBString(&w.data, "Deutsche Telekom AG")
+	writeMMDBString(&w.data, "organization")
+	writeMMDBString(&w.data, "Example Corp")
+	w.insert(netip.MustParsePrefix("192.0.2.0/24"), isp)
+
+	reader, err := NewMMDBReader(w.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := NewMMDBOrganizationProvider(reader)
+	if name, ok := provider.LookupOrganization(netip.MustParseAddr("192.0.2.1")); !ok || name != "Example Corp" {
+		t.Errorf("Expected organization over ISP, got %q, %t", name, ok)
+	}
+
+	// ASN databases fall back to the autonomous system's name
+	reader, _ = NewMMDBReader(buildTestASNDatabase())
+	provider = NewMMDBOrganizationProvider(reader)
+	if name, ok := provider.LookupOrganization(netip.MustParseAddr("3.5.140.1")); !ok || name != "Amazon.com, Inc." {
+		t.Errorf("Expected ASN organization, got %q, %t", name, ok)
+	}
+	if _, ok := provider.LookupOrganization(netip.MustParseAddr("8.8.8.8")); ok {
+		t.Error("Expected no organization outside the database")
+	}
+}
+
+type staticOrganizationProvider map[netip.Addr]string
+
+func (p staticOrganizationProvider) LookupOrganization(addr netip.Addr) (string, bool) {
+	name, ok := p[addr]
+	return name, ok
+}
+
+func TestBotDetector_Organization(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		config     func(*DetectorConfig)
+		expected   string
+	}{
+		{
+			name:       "Embedded datacenter ranges",
+			remoteAddr: "54.239.123.45:80",
+			expected:   "Amazon Web Services",
+		},
+		{
+			name:       "ASN organization",
+			remoteAddr: "203.0.113.5:80",
+			config: func(c *DetectorConfig) {
+				c.ASNProvider = staticASNProvider{netip.MustParseAddr("203.0.113.5"): {Number: 45102, Organization: "Alibaba (US) Technology Co., Ltd."}}
+			},
+			expected: "Alibaba (US) Technology Co., Ltd.",
+		},
+		{
+			name:       "Provider takes precedence",
+			remoteAddr: "54.239.123.45:80",
+			config: func(c *DetectorConfig) {
+				c.OrganizationProvider = staticOrganizationProvider{netip.MustParseAddr("54.239.123.45"): "Amazon.com, Inc."}
+			},
+			expected: "Amazon.com, Inc.",
+		},
+		{
+			name:       "Unknown",
+			remoteAddr: "203.0.113.5:80",
+			expected:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultDetectorConfig()
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			req := createTestRequest("GET", "/", map[string]string{"User-Agent": "curl/8.0"})
+			req.RemoteAddr = tt.remoteAddr
+
+			result, _ := NewDetectorWithConfig(config).DetectFromRequest(req)
+			if result.Organization != tt.expected {
+				t.Errorf("Expected organization %q, got %q", tt.expected, result.Organization)
+			}
+		})
+	}
+}
+
+func TestDatacenterProvider_Organization(t *testing.T) {
+	if name := DatacenterHetzner.Organization(); name != "Hetzner Online" {
+		t.Errorf("Expected Hetzner Online, got %q", name)
+	}
+	if name := DatacenterProvider("linode").Organization(); name != "linode" {
+		t.Errorf("Expected provider name for unknown providers, got %q", name)
+	}
+}
@@ -0,0 +1,150 @@
+package gogobot
+
+import (
+	"net/http"
+	"strings"
+)
+
+// OS families reported by parseOSFamily
+const (
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSIOS      = "ios"
+	OSAndroid  = "android"
+	OSLinux    = "linux"
+	OSChromeOS = "chromeos"
+	OSOther    = "other"
+)
+
+// CPU architectures reported by GetArchitecture
+const (
+	ArchX86_64 = "x86_64"
+	ArchX86    = "x86"
+	ArchARM64  = "arm64"
+	ArchARM    = "arm"
+)
+
+// PlatformInfo describes the operating system and CPU architecture of a client
+type PlatformInfo struct {
+	OSFamily     string `json:"osFamily"`
+	Architecture string `json:"architecture,omitempty"`
+	Is64Bit      bool   `json:"is64Bit"`
+}
+
+// GetOSFamily returns the operating system family: windows, macos, ios, android, linux, chromeos, or other
+func (bi BrowserInfo) GetOSFamily() string {
+	return parseOSFamily(bi.RawUA)
+}
+
+// GetArchitecture returns the CPU architecture hinted by the user agent, or "" if unknown.
+// Apple Silicon Macs report "Intel Mac OS X", so macOS is always reported as x86_64.
+func (bi BrowserInfo) GetArchitecture() string {
+	return parseArchitecture(bi.RawUA)
+}
+
+// Is64Bit reports whether the user agent indicates a 64-bit operating system
+func (bi BrowserInfo) Is64Bit() bool {
+	arch := bi.GetArchitecture()
+	return arch == ArchX86_64 || arch == ArchARM64
+}
+
+// GetPlatformInfo returns OS and architecture information for a request, preferring the
+// Sec-CH-UA-Platform, Sec-CH-UA-Arch and Sec-CH-UA-Bitness client hints when present
+func GetPlatformInfo(req *http.Request) PlatformInfo {
+	ua := req.Header.Get("User-Agent")
+	info := PlatformInfo{
+		OSFamily:     parseOSFamily(ua),
+		Architecture: parseArchitecture(ua),
+	}
+
+	if hinted := platformHintOSFamily(req.Header.Get("Sec-CH-UA-Platform")); hinted != "" {
+		info.OSFamily = hinted
+	}
+
+	arch := strings.ToLower(strings.Trim(req.Header.Get("Sec-CH-UA-Arch"), `" `))
+	bitness := strings.Trim(req.Header.Get("Sec-CH-UA-Bitness"), `" `)
+	switch {
+	case arch == "x86" && bitness == "64":
+		info.Architecture = ArchX86_64
+	case arch == "x86":
+		info.Architecture = ArchX86
+	case arch == "arm" && bitness == "64":
+		info.Architecture = ArchARM64
+	case arch == "arm":
+		info.Architecture = ArchARM
+	}
+
+	info.Is64Bit = info.Architecture == ArchX86_64 || info.Architecture == ArchARM64
+	return info
+}
+
+// parseArchitecture infers the CPU architecture from a user agent string
+func parseArchitecture(ua string) string {
+	ua = strings.ToLower(ua)
+
+	switch {
+	case containsAny(ua, "aarch64", "arm64"):
+		return ArchARM64
+	case containsAny(ua, "x86_64", "x86-64", "win64", "wow64", "amd64", "x64;", "x64)"):
+		return ArchX86_64
+	case containsAny(ua, "armv", "arm;", "arm)", "armeabi"):
+		return ArchARM
+	case containsAny(ua, "i686", "i586", "i386", "x86"):
+		return ArchX86
+	case strings.Contains(ua, "intel mac os x"):
+		return ArchX86_64
+	case containsAny(ua, "iphone", "ipad", "ipod"):
+		return ArchARM64
+	default:
+		return ""
+	}
+}
+
+// parseOSFamily infers the operating system family from a user agent string
+func parseOSFamily(ua string) string {
+	ua = strings.ToLower(ua)
+
+	switch {
+	case strings.Contains(ua, "windows phone"):
+		return OSOther
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		return OSIOS
+	case strings.Contains(ua, "android"):
+		return OSAndroid
+	case strings.Contains(ua, "cros "):
+		return OSChromeOS
+	case strings.Contains(ua, "windows"):
+		return OSWindows
+	case strings.Contains(ua, "macintosh") || strings.Contains(ua, "mac os x"):
+		if isMasqueradingIPad(ua) {
+			return OSIOS
+		}
+		return OSMacOS
+	case strings.Contains(ua, "linux") || strings.Contains(ua, "x11"):
+		return OSLinux
+	default:
+		return OSOther
+	}
+}
+
+// platformHintOSFamily maps a Sec-CH-UA-Platform value to an OS family
+func platformHintOSFamily(platform string) string {
+	switch strings.ToLower(strings.Trim(platform, `" `)) {
+	case "windows":
+		return OSWindows
+	case "macos":
+		return OSMacOS
+	case "ios":
+		return OSIOS
+	case "android":
+		return OSAndroid
+	case "linux":
+		return OSLinux
+	case "chrome os", "chromeos":
+		return OSChromeOS
+	case "":
+		return ""
+	default:
+		return OSOther
+	}
+}
@@ -0,0 +1,64 @@
+package gogobot
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetOSFamily(t *testing.T) {
+	tests := []struct {
+		userAgent    string
+		expectedOS   string
+		expectedArch string
+		expected64   bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", OSWindows, ArchX86_64, true},
+		{"Mozilla/5.0 (Windows NT 6.1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36", OSWindows, "", false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", OSMacOS, ArchX86_64, true},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", OSIOS, ArchARM64, true},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", OSAndroid, "", false},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", OSLinux, ArchX86_64, true},
+		{"Mozilla/5.0 (X11; Linux aarch64; rv:121.0) Gecko/20100101 Firefox/121.0", OSLinux, ArchARM64, true},
+		{"Mozilla/5.0 (X11; Linux i686; rv:121.0) Gecko/20100101 Firefox/121.0", OSLinux, ArchX86, false},
+		{"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", OSChromeOS, ArchX86_64, true},
+		{"curl/7.68.0", OSOther, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			if os := GetOSFamily(tt.userAgent); os != tt.expectedOS {
+				t.Errorf("Expected OS family %s, got %s", tt.expectedOS, os)
+			}
+
+			browserInfo := BrowserInfo{RawUA: tt.userAgent}
+			if arch := browserInfo.GetArchitecture(); arch != tt.expectedArch {
+				t.Errorf("Expected architecture %q, got %q", tt.expectedArch, arch)
+			}
+			if browserInfo.Is64Bit() != tt.expected64 {
+				t.Errorf("Expected Is64Bit=%t, got %t", tt.expected64, browserInfo.Is64Bit())
+			}
+		})
+	}
+}
+
+func TestGetPlatformInfo_ClientHints(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+
+	info := GetPlatformInfo(req)
+	if info.OSFamily != OSMacOS || info.Architecture != ArchX86_64 {
+		t.Errorf("Unexpected platform info without hints: %+v", info)
+	}
+
+	req.Header.Set("Sec-CH-UA-Platform", `"macOS"`)
+	req.Header.Set("Sec-CH-UA-Arch", `"arm"`)
+	req.Header.Set("Sec-CH-UA-Bitness", `"64"`)
+
+	info = GetPlatformInfo(req)
+	if info.OSFamily != OSMacOS {
+		t.Errorf("Expected macOS, got %s", info.OSFamily)
+	}
+	if info.Architecture != ArchARM64 || !info.Is64Bit {
+		t.Errorf("Expected arm64 from client hints, got %+v", info)
+	}
+}
@@ -0,0 +1,311 @@
+package gogobot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// BrowserPattern describes a user-supplied pattern mapping a user agent to a browser name and version
+type BrowserPattern struct {
+	// Regex is matched against the raw user agent string
+	Regex string `json:"regex"`
+	// Name is the browser name, which may reference capture groups (e.g. "$1")
+	Name string `json:"name"`
+	// Version lists version component templates joined with "."; defaults to ["$1"]
+	Version []string `json:"version,omitempty"`
+	// CaseInsensitive makes the regex match regardless of case
+	CaseInsensitive bool `json:"caseInsensitive,omitempty"`
+}
+
+// PatternMode controls how a PatternDatabase is combined with the built-in browser patterns
+type PatternMode int
+
+const (
+	// PatternModeExtend checks loaded patterns first and falls back to the built-in patterns
+	PatternModeExtend PatternMode = iota
+	// PatternModeReplace uses only the loaded patterns
+	PatternModeReplace
+)
+
+// PatternDatabase is a compiled set of browser patterns loaded at runtime, with the
+// vulnerability-probe paths of the pattern file
+type PatternDatabase struct {
+	patterns []compiledBrowserPattern
+	probes   []string
+}
+
+type compiledBrowserPattern struct {
+	re      *regexp.Regexp
+	name    string
+	version []string
+}
+
+type activeBrowserPatterns struct {
+	db   *PatternDatabase
+	mode PatternMode
+}
+
+// loadedBrowserPatterns holds the pattern database installed with SetBrowserPatterns
+var loadedBrowserPatterns atomic.Pointer[activeBrowserPatterns]
+
+// NewPatternDatabase compiles the given browser patterns
+func NewPatternDatabase(patterns []BrowserPattern) (*PatternDatabase, error) {
+	db := &PatternDatabase{
+		patterns: make([]compiledBrowserPattern, 0, len(patterns)),
+	}
+
+	for i, p := range patterns {
+		if p.Regex == "" {
+			return nil, fmt.Errorf("browser pattern %d: regex is required", i)
+		}
+
+		expr := p.Regex
+		if p.CaseInsensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("browser pattern %d: %w", i, err)
+		}
+
+		name := p.Name
+		if name == "" {
+			name = "$1"
+		}
+		version := p.Version
+		if len(version) == 0 {
+			version = []string{"$1"}
+		}
+
+		db.patterns = append(db.patterns, compiledBrowserPattern{
+			re:      re,
+			name:    name,
+			version: version,
+		})
+	}
+
+	return db, nil
+}
+
+// Len returns the number of patterns in the database
+func (db *PatternDatabase) Len() int {
+	return len(db.patterns)
+}
+
+// Match returns the browser name and version for the first pattern matching the user agent
+func (db *PatternDatabase) Match(userAgent string) (BrowserName, string, bool) {
+	for _, p := range db.patterns {
+		match := p.re.FindStringSubmatchIndex(userAgent)
+		if match == nil {
+			continue
+		}
+
+		name := strings.TrimSpace(string(p.re.ExpandString(nil, p.name, userAgent, match)))
+		if name == "" {
+			continue
+		}
+
+		var parts []string
+		for _, tmpl := range p.version {
+			part := strings.TrimSpace(string(p.re.ExpandString(nil, tmpl, userAgent, match)))
+			if part == "" {
+				break
+			}
+			parts = append(parts, part)
+		}
+
+		return BrowserName(name), strings.Join(parts, "."), true
+	}
+
+	return "", "", false
+}
+
+// LoadPatternFile loads a package-native JSON pattern file of the form
+// {"browsers": [...], "probes": [...]}
+func LoadPatternFile(path string) (*PatternDatabase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParsePatternJSON(f)
+}
+
+// ParsePatternJSON parses a package-native JSON pattern document
+func ParsePatternJSON(r io.Reader) (*PatternDatabase, error) {
+	var doc struct {
+		Browsers []BrowserPattern `json:"browsers"`
+		Probes   []string         `json:"probes"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse pattern file: %w", err)
+	}
+	db, err := NewPatternDatabase(doc.Browsers)
+	if err != nil {
+		return nil, err
+	}
+	for i, probe := range doc.Probes {
+		if !strings.HasPrefix(probe, "/") {
+			return nil, fmt.Errorf("probe %d: path must start with /", i)
+		}
+		db.probes = append(db.probes, strings.ToLower(probe))
+	}
+	return db, nil
+}
+
+// LoadUAPCoreFile loads the user_agent_parsers section of a ua-parser/uap-core regexes.yaml file
+func LoadUAPCoreFile(path string) (*PatternDatabase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseUAPCore(f)
+}
+
+// ParseUAPCore parses the user_agent_parsers section of a uap-core regexes.yaml document.
+// Only the subset of YAML used by uap-core (a list of flat scalar maps) is supported.
+func ParseUAPCore(r io.Reader) (*PatternDatabase, error) {
+	var patterns []BrowserPattern
+	var current map[string]string
+	inSection := false
+	lineNo := 0
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		patterns = append(patterns, uapEntryToPattern(current))
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Top-level section keys are not indented
+		if line[0] != ' ' && line[0] != '-' {
+			flush()
+			inSection = strings.TrimSuffix(trimmed, ":") == "user_agent_parsers"
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			flush()
+			current = make(map[string]string)
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if trimmed == "" {
+				continue
+			}
+		}
+		if current == nil {
+			return nil, fmt.Errorf("uap-core line %d: value outside of list entry", lineNo)
+		}
+
+		key, rawValue, found := strings.Cut(trimmed, ":")
+		if !found {
+			return nil, fmt.Errorf("uap-core line %d: expected key: value", lineNo)
+		}
+		value, err := parseYAMLScalar(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("uap-core line %d: %w", lineNo, err)
+		}
+		current[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return NewPatternDatabase(patterns)
+}
+
+// uapEntryToPattern converts a uap-core user agent parser entry to a BrowserPattern
+func uapEntryToPattern(entry map[string]string) BrowserPattern {
+	pattern := BrowserPattern{
+		Regex:           entry["regex"],
+		Name:            "$1",
+		Version:         []string{"$2", "$3", "$4"},
+		CaseInsensitive: entry["regex_flag"] == "i",
+	}
+
+	if family, ok := entry["family_replacement"]; ok {
+		pattern.Name = family
+	}
+	for i, key := range []string{"v1_replacement", "v2_replacement", "v3_replacement"} {
+		if v, ok := entry[key]; ok {
+			pattern.Version[i] = v
+		}
+	}
+
+	return pattern
+}
+
+// parseYAMLScalar decodes a single-quoted, double-quoted, or plain YAML scalar
+func parseYAMLScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, "'"):
+		end := strings.LastIndex(s, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated single-quoted string")
+		}
+		return strings.ReplaceAll(s[1:end], "''", "'"), nil
+	case strings.HasPrefix(s, `"`):
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case c == '"':
+				return b.String(), nil
+			case c == '\\' && i+1 < len(s):
+				i++
+				switch s[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(s[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated double-quoted string")
+	default:
+		if idx := strings.Index(s, " #"); idx >= 0 {
+			s = s[:idx]
+		}
+		return strings.TrimSpace(s), nil
+	}
+}
+
+// SetBrowserPatterns installs a pattern database used by ParseBrowserFromUserAgent and the
+// probe path detector. Passing nil restores the built-in patterns. Any installed UACache is
+// purged.
+func SetBrowserPatterns(db *PatternDatabase, mode PatternMode) {
+	if db == nil {
+		loadedBrowserPatterns.Store(nil)
+	} else {
+		loadedBrowserPatterns.Store(&activeBrowserPatterns{db: db, mode: mode})
+	}
+
+	if cache := activeUACache.Load(); cache != nil {
+		cache.Purge()
+	}
+}
@@ -0,0 +1,122 @@
+package gogobot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testUAPCoreYAML = `# uap-core style regexes
+user_agent_parsers:
+  #### SPECIAL CASES TOP ####
+
+  # Arc browser
+  - regex: '(Arc)/(\d+)\.(\d+)(?:\.(\d+)|)'
+
+  - regex: '(Kagi(?:Browser|))/(\d+)\.(\d+)'
+    family_replacement: 'Orion'
+    v3_replacement: '0'
+
+  - regex: '(lynx)/(\d+)\.(\d+)'
+    regex_flag: 'i'
+    family_replacement: "Lynx"
+
+os_parsers:
+  - regex: '(Windows NT) (\d+)'
+`
+
+func TestParseUAPCore(t *testing.T) {
+	db, err := ParseUAPCore(strings.NewReader(testUAPCoreYAML))
+	if err != nil {
+		t.Fatalf("ParseUAPCore returned error: %v", err)
+	}
+	if db.Len() != 3 {
+		t.Fatalf("Expected 3 patterns, got %d", db.Len())
+	}
+
+	tests := []struct {
+		userAgent       string
+		expectedName    BrowserName
+		expectedVersion string
+	}{
+		{"Mozilla/5.0 (Macintosh) Arc/1.23.4", "Arc", "1.23.4"},
+		{"Mozilla/5.0 (Macintosh) Arc/1.23", "Arc", "1.23"},
+		{"Mozilla/5.0 (Macintosh) KagiBrowser/17.4", "Orion", "17.4.0"},
+		{"LYNX/2.9 libwww-FM/2.14", "Lynx", "2.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			name, version, ok := db.Match(tt.userAgent)
+			if !ok {
+				t.Fatal("Expected pattern to match")
+			}
+			if name != tt.expectedName {
+				t.Errorf("Expected name %s, got %s", tt.expectedName, name)
+			}
+			if version != tt.expectedVersion {
+				t.Errorf("Expected version %s, got %s", tt.expectedVersion, version)
+			}
+		})
+	}
+}
+
+func TestLoadPatternFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "patterns.json")
+	content := `{"browsers": [{"regex": "arc/([0-9.]+)", "name": "Arc", "caseInsensitive": true}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := LoadPatternFile(path)
+	if err != nil {
+		t.Fatalf("LoadPatternFile returned error: %v", err)
+	}
+
+	name, version, ok := db.Match("Mozilla/5.0 Arc/1.2.3")
+	if !ok || name != "Arc" || version != "1.2.3" {
+		t.Errorf("Expected Arc 1.2.3, got %s %s (ok=%t)", name, version, ok)
+	}
+}
+
+func TestNewPatternDatabase_Invalid(t *testing.T) {
+	if _, err := NewPatternDatabase([]BrowserPattern{{Regex: "("}}); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+	if _, err := NewPatternDatabase([]BrowserPattern{{Name: "Empty"}}); err == nil {
+		t.Error("Expected error for missing regex")
+	}
+}
+
+func TestSetBrowserPatterns(t *testing.T) {
+	db, err := NewPatternDatabase([]BrowserPattern{
+		{Regex: `Arc/([0-9.]+)`, Name: "Arc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arcUA := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Arc/1.23.4"
+	firefoxUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0"
+
+	SetBrowserPatterns(db, PatternModeExtend)
+	defer SetBrowserPatterns(nil, PatternModeExtend)
+
+	if result := ParseBrowserFromUserAgent(arcUA); result.Name != "Arc" || result.Version != "1.23.4" {
+		t.Errorf("Expected Arc 1.23.4, got %s %s", result.Name, result.Version)
+	}
+	if result := ParseBrowserFromUserAgent(firefoxUA); result.Name != BrowserFirefox {
+		t.Errorf("Expected built-in patterns to apply in extend mode, got %s", result.Name)
+	}
+
+	SetBrowserPatterns(db, PatternModeReplace)
+	if result := ParseBrowserFromUserAgent(firefoxUA); result.Name != BrowserUnknown {
+		t.Errorf("Expected built-in patterns to be skipped in replace mode, got %s", result.Name)
+	}
+
+	SetBrowserPatterns(nil, PatternModeExtend)
+	if result := ParseBrowserFromUserAgent(arcUA); result.Name != BrowserChrome {
+		t.Errorf("Expected built-in patterns after reset, got %s", result.Name)
+	}
+}
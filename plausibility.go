@@ -0,0 +1,97 @@
+package gogobot
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// chrome100Release is the stable release date of Chrome 100, used to estimate the newest
+// Chrome major version that can exist at a given time (Chrome ships every four weeks)
+var chrome100Release = time.Date(2022, time.March, 29, 0, 0, 0, 0, time.UTC)
+
+// chromeChannelLead is how many major versions Canary/Dev/Beta may run ahead of stable
+const chromeChannelLead = 3
+
+// maxPlausibleChromeMajor returns the highest Chrome major version that could exist at t
+func maxPlausibleChromeMajor(t time.Time) int {
+	weeks := int(t.Sub(chrome100Release).Hours() / (24 * 7))
+	return 100 + weeks/4 + chromeChannelLead
+}
+
+// detectPlausibility flags impossible OS/browser combinations that indicate a spoofed user agent
+func detectPlausibility(components *ComponentDict) *BotDetectionResult {
+	if components.UserAgent.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	userAgent := components.UserAgent.GetValue()
+	if isBot, _ := IsBotUserAgent(userAgent); isBot {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	var platformHint string
+	if components.Headers.GetState() == StateSuccess {
+		platformHint = http.Header(components.Headers.GetValue()).Get("Sec-CH-UA-Platform")
+	}
+
+	if reason := implausibleUserAgent(userAgent, platformHint, time.Now()); reason != "" {
+		return &BotDetectionResult{
+			Bot:     true,
+			BotKind: BotKindUnknown,
+		}
+	}
+
+	return &BotDetectionResult{Bot: false}
+}
+
+// implausibleUserAgent returns a description of the first impossible combination found, or ""
+func implausibleUserAgent(userAgent, platformHint string, now time.Time) string {
+	ua := strings.ToLower(userAgent)
+	name, version := parseBrowserNameAndVersion(userAgent)
+	osFamily := parseOSFamily(userAgent)
+	major, minor := versionMajorMinor(version)
+
+	switch {
+	case name == BrowserSafari && osFamily == OSWindows && (major >= 6 || strings.Contains(ua, "windows nt 10")):
+		// Safari for Windows was discontinued at 5.1.7 and never ran on Windows 10
+		return "safari on windows"
+	case name == BrowserIE && osFamily != OSWindows && osFamily != OSOther:
+		return "internet explorer on " + osFamily
+	case osFamily == OSIOS && containsAny(ua, "x86_64", "win64", "wow64", "amd64", "x11"):
+		return "ios on desktop architecture"
+	case name == BrowserChrome && major > maxPlausibleChromeMajor(now):
+		return "chrome version from the future"
+	case name == BrowserChrome && major >= 6 && minor != 0:
+		// Chrome has used MAJOR.0.BUILD.PATCH versioning since Chrome 6
+		return "malformed chrome version"
+	}
+
+	if hintFamily := platformHintOSFamily(platformHint); hintFamily != "" && osFamily != OSOther && hintFamily != osFamily {
+		return "platform hint " + hintFamily + " contradicts " + osFamily + " user agent"
+	}
+
+	return ""
+}
+
+// versionMajorMinor returns the first two numeric components of a version string
+func versionMajorMinor(version string) (int, int) {
+	parts := strings.SplitN(version, ".", 3)
+	major, _ := strconv.Atoi(parts[0])
+	var minor int
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	return major, minor
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package gogobot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImplausibleUserAgent(t *testing.T) {
+	now := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		userAgent    string
+		platformHint string
+		implausible  bool
+	}{
+		{
+			name:      "Chrome on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+		{
+			name:      "Safari on macOS",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+		},
+		{
+			name:        "Safari on Windows 10",
+			userAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			implausible: true,
+		},
+		{
+			name:        "IE on Android",
+			userAgent:   "Mozilla/5.0 (Linux; Android 10; SM-G973F) (compatible; MSIE 10.0; Trident/6.0)",
+			implausible: true,
+		},
+		{
+			name:      "Windows Phone IE",
+			userAgent: "Mozilla/5.0 (Mobile; Windows Phone 8.1; Android 4.0; ARM; Trident/7.0; Touch; rv:11.0; IEMobile/11.0; NOKIA; Lumia 635) like iPhone OS 7_0_3 Mac OS X AppleWebKit/537 (KHTML, like Gecko) Mobile Safari/537",
+		},
+		{
+			name:        "iPhone on x86_64",
+			userAgent:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X; x86_64) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			implausible: true,
+		},
+		{
+			name:        "Chrome from the future",
+			userAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/199.0.0.0 Safari/537.36",
+			implausible: true,
+		},
+		{
+			name:        "Chrome with non-zero minor version",
+			userAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.5.1.2 Safari/537.36",
+			implausible: true,
+		},
+		{
+			name:         "Matching platform hint",
+			userAgent:    "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			platformHint: `"Windows"`,
+		},
+		{
+			name:         "Contradicting platform hint",
+			userAgent:    "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			platformHint: `"Linux"`,
+			implausible:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := implausibleUserAgent(tt.userAgent, tt.platformHint, now)
+			if (reason != "") != tt.implausible {
+				t.Errorf("Expected implausible=%t, got reason %q", tt.implausible, reason)
+			}
+		})
+	}
+}
+
+func TestDetectPlausibility(t *testing.T) {
+	detector := NewDetector()
+
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+		"Accept":          "text/html,application/xhtml+xml",
+		"Accept-Language": "en-US,en;q=0.9",
+		"Accept-Encoding": "gzip, deflate, br",
+		"Connection":      "keep-alive",
+	})
+
+	result, err := detector.DetectFromRequest(req)
+	if err != nil {
+		t.Fatalf("DetectFromRequest() returned error: %v", err)
+	}
+	if !result.Bot {
+		t.Error("Expected Safari on Windows 10 to be detected as bot")
+	}
+	if !detector.GetDetections().Plausibility.Bot {
+		t.Error("Expected plausibility detector to flag the request")
+	}
+}
@@ -0,0 +1,149 @@
+package gogobot
+
+import "maps"
+
+// PolicyBuilder builds a middleware configuration with per-route actions from fluent rules:
+//
+//	policy := NewPolicy(DefaultMiddlewareConfig())
+//	policy.Default().Block(BotCategoryAutomation)
+//	policy.Route("/static/**").Skip()
+//	policy.Route("/blog/**").Allow(BotCategoryCrawler).Challenge(BotCategoryUnknown).Block(BotCategoryAbuse)
+//	config := policy.Build()
+//
+// Routes compile into RoutePolicy entries tried in the order they were added, each with the
+// default configuration and actions plus its own.
+type PolicyBuilder struct {
+	base   MiddlewareConfig
+	def    *RouteBuilder
+	routes []*RouteBuilder
+}
+
+// RouteBuilder collects the rules of a route
+type RouteBuilder struct {
+	match     func(path string) bool
+	skip      bool
+	actions   map[BotCategory]Action
+	configure []func(*MiddlewareConfig)
+}
+
+// NewPolicy starts a policy from base, whose Actions and other settings every route inherits
+func NewPolicy(base MiddlewareConfig) *PolicyBuilder {
+	return &PolicyBuilder{base: base, def: newRouteBuilder(nil)}
+}
+
+// newRouteBuilder creates the rules of a route matching with match
+func newRouteBuilder(match func(path string) bool) *RouteBuilder {
+	return &RouteBuilder{match: match, actions: map[BotCategory]Action{}}
+}
+
+// Default returns the rules applying to paths no route matches, which routes inherit
+func (p *PolicyBuilder) Default() *RouteBuilder {
+	return p.def
+}
+
+// Route adds a route for the paths matching a MatchGlob pattern
+func (p *PolicyBuilder) Route(pattern string) *RouteBuilder {
+	return p.RouteFunc(MatchGlob(pattern))
+}
+
+// RouteFunc adds a route for the paths match accepts, e.g. a MatchRegexp matcher
+func (p *PolicyBuilder) RouteFunc(match func(path string) bool) *RouteBuilder {
+	route := newRouteBuilder(match)
+	p.routes = append(p.routes, route)
+	return route
+}
+
+// Build compiles the rules into a middleware configuration
+func (p *PolicyBuilder) Build() MiddlewareConfig {
+	config := p.def.apply(p.base)
+	config.Routes = nil
+	for _, route := range p.routes {
+		policy := RoutePolicy{Match: route.match, Skip: route.skip}
+		if !route.skip {
+			policy.Config = route.apply(config)
+		}
+		config.Routes = append(config.Routes, policy)
+	}
+	return config
+}
+
+// apply returns base with the route's actions and settings
+func (r *RouteBuilder) apply(base MiddlewareConfig) MiddlewareConfig {
+	config := base
+	config.Routes = nil
+	config.Actions = maps.Clone(base.Actions)
+	if config.Actions == nil && len(r.actions) > 0 {
+		config.Actions = map[BotCategory]Action{}
+	}
+	maps.Copy(config.Actions, r.actions)
+	for _, configure := range r.configure {
+		configure(&config)
+	}
+	return config
+}
+
+// set maps the categories to the action
+func (r *RouteBuilder) set(action Action, categories []BotCategory) *RouteBuilder {
+	for _, category := range categories {
+		r.actions[category] = action
+	}
+	return r
+}
+
+// Allow serves the bots of the categories
+func (r *RouteBuilder) Allow(categories ...BotCategory) *RouteBuilder {
+	return r.set(ActionAllow, categories)
+}
+
+// Log serves the bots of the categories after reporting them to OnAction
+func (r *RouteBuilder) Log(categories ...BotCategory) *RouteBuilder {
+	return r.set(ActionLog, categories)
+}
+
+// Block blocks the bots of the categories
+func (r *RouteBuilder) Block(categories ...BotCategory) *RouteBuilder {
+	return r.set(ActionBlock, categories)
+}
+
+// RateLimit rate limits the bots of the categories
+func (r *RouteBuilder) RateLimit(categories ...BotCategory) *RouteBuilder {
+	return r.set(ActionRateLimit, categories)
+}
+
+// Challenge presents the Challenge to the bots of the categories
+func (r *RouteBuilder) Challenge(categories ...BotCategory) *RouteBuilder {
+	return r.set(ActionChallenge, categories)
+}
+
+// Tarpit tarpits the bots of the categories
+func (r *RouteBuilder) Tarpit(categories ...BotCategory) *RouteBuilder {
+	return r.set(ActionTarpit, categories)
+}
+
+// Degrade serves the bots of the categories with the DegradedHandler
+func (r *RouteBuilder) Degrade(categories ...BotCategory) *RouteBuilder {
+	return r.set(ActionDegrade, categories)
+}
+
+// Skip serves the route's requests without detection
+func (r *RouteBuilder) Skip() *RouteBuilder {
+	r.skip = true
+	return r
+}
+
+// Configure changes other settings of the route's configuration, e.g. its ErrorPolicy
+func (r *RouteBuilder) Configure(configure func(*MiddlewareConfig)) *RouteBuilder {
+	r.configure = append(r.configure, configure)
+	return r
+}
+
+// RouteAction returns the action the configuration takes on a bot of the kind requesting path,
+// and false when detection is skipped or no action is mapped, to test policies without serving
+// requests
+func (c MiddlewareConfig) RouteAction(path string, kind BotKind) (Action, bool) {
+	config, skip := c.route(path)
+	if skip {
+		return "", false
+	}
+	return config.action(&BotDetectionResult{Bot: true, BotKind: kind})
+}
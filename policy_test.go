@@ -0,0 +1,89 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestPolicy() MiddlewareConfig {
+	policy := NewPolicy(DefaultMiddlewareConfig())
+	policy.Default().Allow(BotCategoryCrawler).Block(BotCategoryAutomation)
+	policy.Route("/static/**").Skip()
+	policy.Route("/blog/**").Challenge(BotCategoryUnknown).Block(BotCategoryAbuse).Log(BotCategoryAutomation)
+	policy.Route("/login").Block(BotCategoryCrawler).Configure(func(c *MiddlewareConfig) {
+		c.ErrorPolicy = FailClosed
+	})
+	return policy.Build()
+}
+
+func TestPolicyBuilder_Build(t *testing.T) {
+	config := newTestPolicy()
+	if len(config.Routes) != 3 || !config.Routes[0].Skip {
+		t.Fatalf("Expected three routes, the first skipped, got %+v", config.Routes)
+	}
+	if config.Routes[2].Config.ErrorPolicy != FailClosed || config.ErrorPolicy == FailClosed {
+		t.Error("Expected the login route alone to fail closed")
+	}
+
+	tests := []struct {
+		path   string
+		kind   BotKind
+		action Action
+		ok     bool
+	}{
+		{"/", BotKindCrawler, ActionAllow, true},
+		{"/", BotKindCurl, ActionBlock, true},
+		{"/", BotKindScraper, "", false},
+		{"/static/app.js", BotKindCurl, "", false},
+		{"/blog/2024/post", BotKindCrawler, ActionAllow, true},
+		{"/blog/2024/post", BotKindScraper, ActionBlock, true},
+		{"/blog/2024/post", BotKindCurl, ActionLog, true},
+		{"/blog/2024/post", BotKind("custom"), ActionChallenge, true},
+		{"/login", BotKindCrawler, ActionBlock, true},
+	}
+	for _, tt := range tests {
+		action, ok := config.RouteAction(tt.path, tt.kind)
+		if action != tt.action || ok != tt.ok {
+			t.Errorf("%s %s: expected %q %v, got %q %v", tt.path, tt.kind, tt.action, tt.ok, action, ok)
+		}
+	}
+}
+
+func TestPolicyBuilder_Middleware(t *testing.T) {
+	handler := NewDetector().MiddlewareWithConfig(newTestPolicy())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/", http.StatusForbidden},
+		{"/static/app.js", http.StatusOK},
+		{"/blog/post", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := newBrowserRequest(tt.path, "203.0.113.7:1234")
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.status, rec.Code)
+		}
+	}
+}
+
+func TestPolicyBuilder_BaseUnchanged(t *testing.T) {
+	base := DefaultMiddlewareConfig()
+	base.Actions = map[BotCategory]Action{BotCategoryAI: ActionBlock}
+	policy := NewPolicy(base)
+	policy.Default().Allow(BotCategoryAI)
+	policy.Route("/api/**").RateLimit(BotCategoryCrawler)
+	config := policy.Build()
+
+	if base.Actions[BotCategoryAI] != ActionBlock || len(base.Actions) != 1 {
+		t.Errorf("Expected the base actions to be left unchanged, got %v", base.Actions)
+	}
+	if config.Actions[BotCategoryAI] != ActionAllow || config.Routes[0].Config.Actions[BotCategoryAI] != ActionAllow {
+		t.Errorf("Expected routes to inherit the default actions, got %v", config.Routes[0].Config.Actions)
+	}
+}
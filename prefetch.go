@@ -0,0 +1,78 @@
+package gogobot
+
+import (
+	"net/http"
+	"strings"
+)
+
+// PrefetchKind classifies speculative requests browsers make before the user navigates
+type PrefetchKind string
+
+const (
+	// PrefetchNone is a request the user asked for
+	PrefetchNone PrefetchKind = ""
+	// PrefetchPrefetch fetches a document or resource the user is likely to need next
+	PrefetchPrefetch PrefetchKind = "prefetch"
+	// PrefetchPrerender fetches a document to render it in the background
+	PrefetchPrerender PrefetchKind = "prerender"
+	// PrefetchPreview fetches a page to show a thumbnail, such as Safari's Top Sites
+	PrefetchPreview PrefetchKind = "preview"
+)
+
+// Prefetch is the classification of a speculative request
+type Prefetch struct {
+	Kind PrefetchKind `json:"kind,omitempty"`
+	// Anonymous is true for prefetches relayed by a private prefetch proxy, which hides the
+	// client's IP address
+	Anonymous bool `json:"anonymous,omitempty"`
+}
+
+// parsePrefetch classifies the request from Sec-Purpose, sent by Chromium and Firefox, and
+// the older Purpose, X-Purpose and X-Moz headers
+func parsePrefetch(header http.Header) Prefetch {
+	var prefetch Prefetch
+	if purpose := header.Get("Sec-Purpose"); purpose != "" {
+		// A structured list, e.g. "prefetch;prerender" or "prefetch;anonymous-client-ip"
+		item, params, _ := strings.Cut(strings.ToLower(purpose), ";")
+		if strings.TrimSpace(item) != "prefetch" {
+			return prefetch
+		}
+		prefetch.Kind = PrefetchPrefetch
+		for _, param := range strings.Split(params, ";") {
+			switch strings.TrimSpace(param) {
+			case "prerender":
+				prefetch.Kind = PrefetchPrerender
+			case "anonymous-client-ip":
+				prefetch.Anonymous = true
+			}
+		}
+		return prefetch
+	}
+	for _, name := range []string{"Purpose", "X-Purpose", "X-Moz"} {
+		switch strings.ToLower(strings.TrimSpace(header.Get(name))) {
+		case "prefetch":
+			prefetch.Kind = PrefetchPrefetch
+		case "prerender":
+			prefetch.Kind = PrefetchPrerender
+		case "preview":
+			prefetch.Kind = PrefetchPreview
+		default:
+			continue
+		}
+		break
+	}
+	return prefetch
+}
+
+// IsPrefetch reports whether the request is a speculative prefetch, prerender or preview, which
+// applications may want to leave out of analytics or answer without side effects
+func IsPrefetch(req *http.Request) bool {
+	return parsePrefetch(req.Header).Kind != PrefetchNone
+}
+
+func getPrefetch(req *http.Request) Component[Prefetch] {
+	return SuccessComponent[Prefetch]{
+		State: StateSuccess,
+		Value: parsePrefetch(req.Header),
+	}
+}
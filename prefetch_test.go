@@ -0,0 +1,57 @@
+package gogobot
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParsePrefetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected Prefetch
+	}{
+		{"navigation", nil, Prefetch{}},
+		{"chrome prefetch", map[string]string{"Sec-Purpose": "prefetch"}, Prefetch{Kind: PrefetchPrefetch}},
+		{"chrome prerender", map[string]string{"Sec-Purpose": "prefetch;prerender"}, Prefetch{Kind: PrefetchPrerender}},
+		{"private prefetch proxy", map[string]string{"Sec-Purpose": "prefetch; anonymous-client-ip"}, Prefetch{Kind: PrefetchPrefetch, Anonymous: true}},
+		{"unknown purpose", map[string]string{"Sec-Purpose": "something"}, Prefetch{}},
+		{"safari prefetch", map[string]string{"Purpose": "prefetch"}, Prefetch{Kind: PrefetchPrefetch}},
+		{"safari preview", map[string]string{"X-Purpose": "preview"}, Prefetch{Kind: PrefetchPreview}},
+		{"firefox prefetch", map[string]string{"X-Moz": "prefetch"}, Prefetch{Kind: PrefetchPrefetch}},
+		{"unknown legacy purpose", map[string]string{"Purpose": "audit"}, Prefetch{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.headers {
+				header.Set(name, value)
+			}
+			if got := parsePrefetch(header); got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDetector_PrefetchNotAutomation(t *testing.T) {
+	for _, name := range []string{"Purpose", "Sec-Purpose"} {
+		req := newBrowserRequest("/next-page", "203.0.113.7:1234")
+		req.Header.Set(name, "prefetch")
+
+		detector := NewDetector()
+		result, err := detector.DetectFromRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Bot {
+			t.Errorf("Expected %s prefetch not to be flagged, got %+v", name, result)
+		}
+		if prefetch := detector.GetComponents().Prefetch.GetValue(); prefetch.Kind != PrefetchPrefetch {
+			t.Errorf("Expected prefetch classification, got %+v", prefetch)
+		}
+		if !IsPrefetch(req) || isNavigation(req) {
+			t.Errorf("Expected %s prefetch to be classified apart from navigations", name)
+		}
+	}
+}
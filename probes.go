@@ -0,0 +1,105 @@
+package gogobot
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultProbePaths are paths requested by vulnerability scanners looking for admin consoles,
+// leaked configuration and repository metadata, and known exploitable endpoints
+var defaultProbePaths = []string{
+	"/wp-login.php",
+	"/wp-admin/",
+	"/xmlrpc.php",
+	"/.env",
+	"/.git/",
+	"/.svn/",
+	"/.hg/",
+	"/.ds_store",
+	"/.aws/",
+	"/.htaccess",
+	"/.htpasswd",
+	"/phpmyadmin",
+	"/pma/",
+	"/myadmin/",
+	"/phpinfo.php",
+	"/actuator",
+	"/cgi-bin/",
+	"/server-status",
+	"/vendor/phpunit/",
+	"/boaform/",
+	"/hnap1",
+	"/solr/admin",
+}
+
+// probePaths returns the probe paths in effect: the built-in list, extended or replaced by the
+// probes of the pattern database installed with SetBrowserPatterns
+func probePaths() []string {
+	active := loadedBrowserPatterns.Load()
+	if active == nil || len(active.db.probes) == 0 {
+		return defaultProbePaths
+	}
+	if active.mode == PatternModeReplace {
+		return active.db.probes
+	}
+	return append(active.db.probes[:len(active.db.probes):len(active.db.probes)], defaultProbePaths...)
+}
+
+// matchProbePath returns the probe path matching the request path, ignoring case. A probe path
+// matches at any depth starting at a segment boundary, followed by the end of the path, a "/"
+// or a "." (so "/.env" matches "/app/.env.bak"), or as a prefix when it ends with "/".
+func matchProbePath(path string, probes []string) (string, bool) {
+	path = strings.ToLower(path)
+	for _, probe := range probes {
+		for offset := 0; offset < len(path); {
+			index := strings.Index(path[offset:], probe)
+			if index < 0 {
+				break
+			}
+			end := offset + index + len(probe)
+			if strings.HasSuffix(probe, "/") || end == len(path) || path[end] == '/' || path[end] == '.' {
+				return probe, true
+			}
+			offset += index + 1
+		}
+	}
+	return "", false
+}
+
+// alwaysDetect reports whether the request must be detected even when a cached verdict or trust
+// pass would skip detection: honeypot traps and probe paths flag the visitor on their own, and
+// login attempts and cookie-requiring flows are judged by what was recorded since
+func (d *BotDetector) alwaysDetect(req *http.Request) bool {
+	if d.isTrapRequest(req) {
+		return true
+	}
+	if d.config.LoginProtection != nil && d.config.LoginProtection.IsAuthPath(req.URL.Path) {
+		return true
+	}
+	if d.config.CookiePolicy != nil && d.config.CookiePolicy.RequiresCookie(req.URL.Path) {
+		return true
+	}
+	_, probe := matchProbePath(req.URL.Path, probePaths())
+	return probe
+}
+
+// getProbePath returns the probe path the request matches, or an empty value
+func getProbePath(req *http.Request) Component[string] {
+	probe, _ := matchProbePath(req.URL.Path, probePaths())
+	return SuccessComponent[string]{
+		State: StateSuccess,
+		Value: probe,
+	}
+}
+
+// detectProbePath flags requests for known vulnerability-probe paths as scanners
+func detectProbePath(components *ComponentDict) *BotDetectionResult {
+	if components.ProbePath.GetState() != StateSuccess || components.ProbePath.GetValue() == "" {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     true,
+		BotKind: BotKindScanner,
+		Reasons: []string{"requested probe path " + components.ProbePath.GetValue()},
+	}
+}
@@ -0,0 +1,106 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchProbePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/wp-login.php", "/wp-login.php"},
+		{"/blog/WP-LOGIN.PHP", "/wp-login.php"},
+		{"/.env", "/.env"},
+		{"/app/.env.bak", "/.env"},
+		{"/.git/config", "/.git/"},
+		{"/phpmyadmin/index.php", "/phpmyadmin"},
+		{"/actuator/health", "/actuator"},
+		{"/cgi-bin/luci", "/cgi-bin/"},
+		{"/", ""},
+		{"/environment", ""},
+		{"/docs/.envelope", ""},
+		{"/actuators", ""},
+		{"/blog/cgi-binary", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			probe, ok := matchProbePath(tt.path, defaultProbePaths)
+			if probe != tt.expected || ok != (tt.expected != "") {
+				t.Errorf("Expected %q, got %q (ok=%t)", tt.expected, probe, ok)
+			}
+		})
+	}
+}
+
+func TestDetectProbePath(t *testing.T) {
+	result := detectProbePath(&ComponentDict{ProbePath: SuccessComponent[string]{State: StateSuccess, Value: "/.env"}})
+	if !result.Bot || result.BotKind != BotKindScanner || len(result.Reasons) != 1 {
+		t.Errorf("Expected scanner with a reason, got %+v", result)
+	}
+	result = detectProbePath(&ComponentDict{ProbePath: SuccessComponent[string]{State: StateSuccess}})
+	if result.Bot {
+		t.Errorf("Expected no detection without a probe path, got %+v", result)
+	}
+}
+
+func TestProbePaths_PatternFile(t *testing.T) {
+	db, err := ParsePatternJSON(strings.NewReader(`{"probes": ["/Internal-Admin", "/backup/"]}`))
+	if err != nil {
+		t.Fatalf("ParsePatternJSON returned error: %v", err)
+	}
+
+	SetBrowserPatterns(db, PatternModeExtend)
+	defer SetBrowserPatterns(nil, PatternModeExtend)
+
+	if probe, _ := matchProbePath("/internal-admin/users", probePaths()); probe != "/internal-admin" {
+		t.Errorf("Expected probe from the pattern file, got %q", probe)
+	}
+	if _, ok := matchProbePath("/.env", probePaths()); !ok {
+		t.Error("Expected built-in probes to apply in extend mode")
+	}
+
+	SetBrowserPatterns(db, PatternModeReplace)
+	if _, ok := matchProbePath("/.env", probePaths()); ok {
+		t.Error("Expected built-in probes to be skipped in replace mode")
+	}
+	if _, ok := matchProbePath("/backup/db.sql", probePaths()); !ok {
+		t.Error("Expected pattern file probes in replace mode")
+	}
+
+	if _, err := ParsePatternJSON(strings.NewReader(`{"probes": ["wp-login.php"]}`)); err == nil {
+		t.Error("Expected error for a relative probe path")
+	}
+}
+
+func TestBotDetector_ProbePath(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.ResultCache = NewResultCache(100, time.Minute)
+	detector := NewDetectorWithConfig(config)
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newBrowserRequest(path, "203.0.113.7:1234"))
+		return rec.Code
+	}
+
+	if code := serve("/"); code != http.StatusOK {
+		t.Fatalf("Expected browser to be served, got %d", code)
+	}
+	// The probe is detected even though the visitor's verdict is cached
+	if code := serve("/.git/config"); code != http.StatusForbidden {
+		t.Errorf("Expected probe to be blocked, got %d", code)
+	}
+
+	req := httptest.NewRequest("GET", "/wp-admin/install.php", nil)
+	if result, _ := detector.DetectFromRequest(req); !result.Bot || detector.GetDetections().ProbePath.BotKind != BotKindScanner {
+		t.Errorf("Expected scanner detection, got %+v", detector.GetDetections().ProbePath)
+	}
+}
@@ -0,0 +1,149 @@
+package gogobot
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"math/bits"
+	"net/http"
+	"time"
+)
+
+// Default proof-of-work challenge parameters
+const (
+	DefaultProofOfWorkDifficulty   = 16
+	DefaultProofOfWorkCookieName   = "gogobot_pow"
+	DefaultProofOfWorkPassTTL      = 24 * time.Hour
+	DefaultProofOfWorkChallengeTTL = 5 * time.Minute
+)
+
+// maxProofOfWorkNonce bounds the submitted nonce, a decimal counter
+const maxProofOfWorkNonce = 20
+
+// ProofOfWorkChallenge is a ChallengeAction making suspected bots solve a computational puzzle
+// instead of a CAPTCHA: the page's script searches for a nonce whose SHA-256 hash with a signed
+// challenge starts with Difficulty zero bits, which costs a browser a moment once but makes
+// scraping at scale expensive. Solutions are validated server-side and grant a signed pass
+// cookie bound to the visitor. The script uses the Web Crypto API, which browsers only expose
+// on HTTPS pages and localhost.
+type ProofOfWorkChallenge struct {
+	secret []byte
+	// Difficulty is the number of leading zero bits required; each bit doubles the work
+	Difficulty int
+	// CookieName is the name of the pass cookie
+	CookieName string
+	// PassTTL is how long a pass stays valid
+	PassTTL time.Duration
+	// ChallengeTTL is how long a puzzle may take to solve
+	ChallengeTTL time.Duration
+
+	now func() time.Time
+}
+
+// NewProofOfWorkChallenge creates a challenge signing puzzles and passes with secret, which
+// should be at least 32 random bytes shared by all replicas
+func NewProofOfWorkChallenge(secret []byte) *ProofOfWorkChallenge {
+	return &ProofOfWorkChallenge{
+		secret:       secret,
+		Difficulty:   DefaultProofOfWorkDifficulty,
+		CookieName:   DefaultProofOfWorkCookieName,
+		PassTTL:      DefaultProofOfWorkPassTTL,
+		ChallengeTTL: DefaultProofOfWorkChallengeTTL,
+		now:          time.Now,
+	}
+}
+
+// proofOfWorkPage runs the puzzle and submits the nonce found; the form posts back to the
+// requested URL
+const proofOfWorkPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="robots" content="noindex"><title>Checking your browser</title></head>
+<body><p>Checking your browser&hellip;</p><noscript>JavaScript is required to continue.</noscript>
+<form id="gogobot-pow" method="post" data-difficulty="%d">
+<input type="hidden" name="challenge" value="%s"><input type="hidden" name="nonce" value="">
+</form>
+<script>
+(async function () {
+  var form = document.getElementById("gogobot-pow");
+  var challenge = form.elements.challenge.value;
+  var difficulty = Number(form.dataset.difficulty);
+  var encoder = new TextEncoder();
+  function zeroBits(hash) {
+    var bits = 0;
+    for (var i = 0; i < hash.length; i++) {
+      if (hash[i] !== 0) {
+        return bits + Math.clz32(hash[i]) - 24;
+      }
+      bits += 8;
+    }
+    return bits;
+  }
+  for (var nonce = 0; ; nonce++) {
+    var hash = new Uint8Array(await crypto.subtle.digest("SHA-256", encoder.encode(challenge + nonce)));
+    if (zeroBits(hash) >= difficulty) {
+      form.elements.nonce.value = String(nonce);
+      form.submit();
+      return;
+    }
+  }
+})();
+</script></body></html>
+`
+
+// proofOfWorkZeroBits counts the leading zero bits of the hash of challenge and nonce
+func proofOfWorkZeroBits(challenge, nonce string) int {
+	hash := sha256.Sum256([]byte(challenge + nonce))
+	zeros := 0
+	for _, b := range hash {
+		if b != 0 {
+			return zeros + bits.LeadingZeros8(b)
+		}
+		zeros += 8
+	}
+	return zeros
+}
+
+// solved reports whether nonce solves a puzzle issued to the visitor
+func (c *ProofOfWorkChallenge) solved(visitor, challenge, nonce string) bool {
+	if nonce == "" || len(nonce) > maxProofOfWorkNonce {
+		return false
+	}
+	if !verifyToken(c.secret, "pow-challenge", visitor, challenge, c.now()) {
+		return false
+	}
+	return proofOfWorkZeroBits(challenge, nonce) >= c.Difficulty
+}
+
+// Passed implements ChallengeAction
+func (c *ProofOfWorkChallenge) Passed(req *http.Request, visitor string) bool {
+	cookie, err := req.Cookie(c.CookieName)
+	if err != nil {
+		return false
+	}
+	return verifyToken(c.secret, "pow-pass", visitor, cookie.Value, c.now())
+}
+
+// ServeChallenge implements ChallengeAction
+func (c *ProofOfWorkChallenge) ServeChallenge(w http.ResponseWriter, req *http.Request, visitor string) {
+	w.Header().Set("Cache-Control", "no-store")
+	if req.Method == http.MethodPost {
+		req.Body = http.MaxBytesReader(w, req.Body, 4096)
+		if c.solved(visitor, req.PostFormValue("challenge"), req.PostFormValue("nonce")) {
+			http.SetCookie(w, &http.Cookie{
+				Name:     c.CookieName,
+				Value:    issueToken(c.secret, "pow-pass", visitor, c.now().Add(c.PassTTL)),
+				Path:     "/",
+				MaxAge:   int(c.PassTTL.Seconds()),
+				Secure:   req.TLS != nil,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+			http.Redirect(w, req, sameOriginURI(req.URL), http.StatusSeeOther)
+			return
+		}
+	}
+
+	challenge := issueToken(c.secret, "pow-challenge", visitor, c.now().Add(c.ChallengeTTL))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	fmt.Fprintf(w, proofOfWorkPage, c.Difficulty, html.EscapeString(challenge))
+}
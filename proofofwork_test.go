@@ -0,0 +1,144 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProofOfWorkZeroBits(t *testing.T) {
+	// Reference nonce found by the page's script for 12 bits
+	if zeros := proofOfWorkZeroBits("1700000300.abc", "6010"); zeros < 12 {
+		t.Errorf("Expected at least 12 zero bits, got %d", zeros)
+	}
+	if zeros := proofOfWorkZeroBits("1700000300.abc", "6009"); zeros >= 12 {
+		t.Errorf("Expected fewer than 12 zero bits, got %d", zeros)
+	}
+}
+
+// solveProofOfWork searches for a nonce like the page's script does
+func solveProofOfWork(challenge string, difficulty int) string {
+	for nonce := 0; ; nonce++ {
+		if proofOfWorkZeroBits(challenge, strconv.Itoa(nonce)) >= difficulty {
+			return strconv.Itoa(nonce)
+		}
+	}
+}
+
+var proofOfWorkChallengePattern = regexp.MustCompile(`name="challenge" value="([^"]+)"`)
+
+func TestMiddleware_ProofOfWorkChallenge(t *testing.T) {
+	challenge := NewProofOfWorkChallenge([]byte("secret"))
+	challenge.Difficulty = 8
+	now := time.Unix(1700000000, 0)
+	challenge.now = func() time.Time { return now }
+
+	handler := NewDetector().MiddlewareWithConfig(MiddlewareConfig{BlockBots: true, Challenge: challenge})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	serve := func(method, remoteAddr string, form url.Values, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/docs", strings.NewReader(form.Encode()))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", "python-requests/2.31.0")
+		if form != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("GET", "203.0.113.7:1234", nil, nil)
+	match := proofOfWorkChallengePattern.FindStringSubmatch(rec.Body.String())
+	if rec.Code != http.StatusForbidden || match == nil || !strings.Contains(rec.Body.String(), `data-difficulty="8"`) {
+		t.Fatalf("Expected puzzle page, got %d %s", rec.Code, rec.Body.String())
+	}
+	puzzle := match[1]
+	nonce := solveProofOfWork(puzzle, 8)
+
+	// Wrong nonces, puzzles and visitors are rejected
+	if rec := serve("POST", "203.0.113.7:1234", url.Values{"challenge": {puzzle}, "nonce": {"x"}}, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected wrong nonce to fail, got %d", rec.Code)
+	}
+	forged := "4102444800." + puzzle[strings.IndexByte(puzzle, '.')+1:]
+	if rec := serve("POST", "203.0.113.7:1234", url.Values{"challenge": {forged}, "nonce": {solveProofOfWork(forged, 8)}}, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected forged puzzle to fail, got %d", rec.Code)
+	}
+	if rec := serve("POST", "198.51.100.9:1234", url.Values{"challenge": {puzzle}, "nonce": {nonce}}, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected puzzle from another visitor to fail, got %d", rec.Code)
+	}
+
+	rec = serve("POST", "203.0.113.7:1234", url.Values{"challenge": {puzzle}, "nonce": {nonce}}, nil)
+	cookies := rec.Result().Cookies()
+	if rec.Code != http.StatusSeeOther || len(cookies) != 1 || cookies[0].Name != DefaultProofOfWorkCookieName {
+		t.Fatalf("Expected pass cookie and redirect, got %d %+v", rec.Code, cookies)
+	}
+	if rec := serve("GET", "203.0.113.7:1234", nil, cookies); rec.Code != http.StatusOK {
+		t.Errorf("Expected visitor with pass to be admitted, got %d", rec.Code)
+	}
+	if rec := serve("GET", "198.51.100.9:1234", nil, cookies); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected pass to be bound to the visitor, got %d", rec.Code)
+	}
+
+	now = now.Add(DefaultProofOfWorkPassTTL)
+	if rec := serve("GET", "203.0.113.7:1234", nil, cookies); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected expired pass to be challenged again, got %d", rec.Code)
+	}
+}
+
+func TestProofOfWorkChallenge_ExpiredPuzzle(t *testing.T) {
+	challenge := NewProofOfWorkChallenge([]byte("secret"))
+	challenge.Difficulty = 4
+	now := time.Unix(1700000000, 0)
+	challenge.now = func() time.Time { return now }
+
+	puzzle := issueToken(challenge.secret, "pow-challenge", "ip:203.0.113.7/32", now.Add(challenge.ChallengeTTL))
+	nonce := solveProofOfWork(puzzle, 4)
+	if !challenge.solved("ip:203.0.113.7/32", puzzle, nonce) {
+		t.Fatal("Expected solution to be accepted")
+	}
+	if challenge.solved("ip:203.0.113.7/32", puzzle, nonce+strings.Repeat("0", maxProofOfWorkNonce)) {
+		t.Error("Expected oversized nonce to be rejected")
+	}
+	now = now.Add(challenge.ChallengeTTL)
+	if challenge.solved("ip:203.0.113.7/32", puzzle, nonce) {
+		t.Error("Expected expired puzzle to be rejected")
+	}
+}
+
+func TestMiddleware_ProofOfWorkSameOriginRedirect(t *testing.T) {
+	challenge := NewProofOfWorkChallenge([]byte("secret"))
+	challenge.Difficulty = 8
+	handler := NewDetector().MiddlewareWithConfig(MiddlewareConfig{BlockBots: true, Challenge: challenge})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(method string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "//evil.example/x", strings.NewReader(form.Encode()))
+		req.Header.Set("User-Agent", "python-requests/2.31.0")
+		if form != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	match := proofOfWorkChallengePattern.FindStringSubmatch(serve("GET", nil).Body.String())
+	if match == nil {
+		t.Fatal("Expected puzzle page")
+	}
+	rec := serve("POST", url.Values{"challenge": {match[1]}, "nonce": {solveProofOfWork(match[1], 8)}})
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/evil.example/x" {
+		t.Errorf("Expected redirect on the same host, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
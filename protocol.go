@@ -0,0 +1,105 @@
+package gogobot
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ProtocolPolicy enables judging the HTTP version clients use. Browsers have spoken HTTP/1.1
+// for decades and negotiate HTTP/2 wherever it is offered, while many HTTP libraries still
+// send HTTP/1.0 or never upgrade past HTTP/1.1.
+type ProtocolPolicy struct {
+	// Header is the request header in which a proxy reports the client's protocol, e.g.
+	// "X-Forwarded-Proto-Version" set to "HTTP/2.0" or "h2"; empty uses the request's own
+	// protocol, for servers clients connect to directly. The proxy must overwrite the header.
+	Header string
+	// MinMajor is the lowest major version browsers reach the site with: 2 for sites only
+	// offering HTTP/2 and HTTP/3 to browsers, zero to only flag HTTP/1.0
+	MinMajor int
+}
+
+// Protocol is the HTTP version of a request
+type Protocol struct {
+	Proto string `json:"proto"`
+	Major int    `json:"major"`
+	Minor int    `json:"minor"`
+	// Client is true when the version is the client's, as configured by a ProtocolPolicy, and
+	// not that of a proxy in between
+	Client   bool `json:"client"`
+	MinMajor int  `json:"minMajor,omitempty"`
+}
+
+// parseProtocol parses "HTTP/x.y", "HTTP/x" and the ALPN names "h2", "h2c" and "h3"
+func parseProtocol(value string) (Protocol, bool) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "h2", "h2c", "http/2":
+		return Protocol{Proto: "HTTP/2.0", Major: 2}, true
+	case "h3", "http/3":
+		return Protocol{Proto: "HTTP/3.0", Major: 3}, true
+	}
+	major, minor, ok := http.ParseHTTPVersion(strings.ToUpper(value))
+	if !ok {
+		return Protocol{}, false
+	}
+	return Protocol{Proto: fmt.Sprintf("HTTP/%d.%d", major, minor), Major: major, Minor: minor}, true
+}
+
+// getProtocol returns the HTTP version of the request, or of the client behind the proxy
+// reporting it in the policy's header
+func getProtocol(req *http.Request, policy *ProtocolPolicy) Component[Protocol] {
+	protocol := Protocol{Proto: req.Proto, Major: req.ProtoMajor, Minor: req.ProtoMinor}
+	if policy != nil {
+		if policy.Header != "" {
+			reported, ok := parseProtocol(req.Header.Get(policy.Header))
+			if !ok {
+				return ErrorComponent[Protocol]{
+					State: StateNull,
+					Error: "no valid protocol in " + policy.Header,
+				}
+			}
+			protocol = reported
+		}
+		protocol.Client = true
+		protocol.MinMajor = policy.MinMajor
+	}
+	return SuccessComponent[Protocol]{
+		State: StateSuccess,
+		Value: protocol,
+	}
+}
+
+// detectProtocol scores browser user agents speaking HTTP/1.0, which no browser sends, and
+// clients below the version browsers reach the site with
+func detectProtocol(components *ComponentDict) *BotDetectionResult {
+	if components.Protocol.GetState() != StateSuccess || components.UserAgent.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+	protocol := components.Protocol.GetValue()
+	if !protocol.Client {
+		return &BotDetectionResult{Bot: false}
+	}
+	userAgent := components.UserAgent.GetValue()
+	if isBot, _ := IsBotUserAgent(userAgent); isBot {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	switch {
+	case protocol.Major == 1 && protocol.Minor == 0 && parseEngine(userAgent) != "":
+		return &BotDetectionResult{
+			Bot:     false,
+			BotKind: BotKindUnknown,
+			Score:   0.7,
+			Reasons: []string{"browser user agent over HTTP/1.0"},
+		}
+	case protocol.Major < protocol.MinMajor:
+		return &BotDetectionResult{
+			Bot:     false,
+			BotKind: BotKindUnknown,
+			Score:   0.5,
+			Reasons: []string{fmt.Sprintf("%s where browsers use HTTP/%d", protocol.Proto, protocol.MinMajor)},
+		}
+	}
+	return &BotDetectionResult{Bot: false}
+}
@@ -0,0 +1,88 @@
+package gogobot
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetProtocol(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/1.0", 1, 0
+	if protocol := getProtocol(req, nil).GetValue(); protocol.Proto != "HTTP/1.0" || protocol.Client {
+		t.Errorf("Expected the request's protocol without a policy, got %+v", protocol)
+	}
+	if protocol := getProtocol(req, &ProtocolPolicy{MinMajor: 2}).GetValue(); !protocol.Client || protocol.MinMajor != 2 {
+		t.Errorf("Expected the client's protocol with a policy, got %+v", protocol)
+	}
+
+	policy := &ProtocolPolicy{Header: "X-Forwarded-Proto-Version"}
+	tests := []struct {
+		header string
+		proto  string
+		major  int
+	}{
+		{"HTTP/2.0", "HTTP/2.0", 2},
+		{"h2", "HTTP/2.0", 2},
+		{"h3", "HTTP/3.0", 3},
+		{"http/1.1", "HTTP/1.1", 1},
+	}
+	for _, tt := range tests {
+		req.Header.Set("X-Forwarded-Proto-Version", tt.header)
+		if protocol := getProtocol(req, policy).GetValue(); protocol.Proto != tt.proto || protocol.Major != tt.major || !protocol.Client {
+			t.Errorf("Expected %s for %q, got %+v", tt.proto, tt.header, protocol)
+		}
+	}
+	req.Header.Set("X-Forwarded-Proto-Version", "spdy")
+	if state := getProtocol(req, policy).GetState(); state != StateNull {
+		t.Errorf("Expected invalid header to be rejected, got %v", state)
+	}
+}
+
+func TestDetectProtocol(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		protocol  Protocol
+		expected  float64
+	}{
+		{"chrome over HTTP/1.0", testBrowserUA, Protocol{Proto: "HTTP/1.0", Major: 1, Client: true}, 0.7},
+		{"proxy's HTTP/1.0", testBrowserUA, Protocol{Proto: "HTTP/1.0", Major: 1}, 0},
+		{"library over HTTP/1.0", "custom-client", Protocol{Proto: "HTTP/1.0", Major: 1, Client: true}, 0},
+		{"declared bot", "curl/8.4.0", Protocol{Proto: "HTTP/1.0", Major: 1, Client: true, MinMajor: 2}, 0},
+		{"HTTP/1.1 on an HTTP/2 site", testBrowserUA, Protocol{Proto: "HTTP/1.1", Major: 1, Minor: 1, Client: true, MinMajor: 2}, 0.5},
+		{"HTTP/2", testBrowserUA, Protocol{Proto: "HTTP/2.0", Major: 2, Client: true, MinMajor: 2}, 0},
+		{"HTTP/1.1 without a minimum", testBrowserUA, Protocol{Proto: "HTTP/1.1", Major: 1, Minor: 1, Client: true}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detectProtocol(&ComponentDict{
+				UserAgent: SuccessComponent[string]{State: StateSuccess, Value: tt.userAgent},
+				Protocol:  SuccessComponent[Protocol]{State: StateSuccess, Value: tt.protocol},
+			})
+			if result.Score != tt.expected || (tt.expected > 0 && len(result.Reasons) != 1) {
+				t.Errorf("Expected score %v with a reason, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBotDetector_Protocol(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.ProtocolPolicy = &ProtocolPolicy{Header: "X-Forwarded-Proto-Version", MinMajor: 2}
+	detector := NewDetectorWithConfig(config)
+
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	req.Header.Set("X-Forwarded-Proto-Version", "HTTP/1.0")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if detection := detector.GetDetections().Protocol; detection.Score != 0.7 {
+		t.Errorf("Expected HTTP/1.0 signal, got %+v", detection)
+	}
+
+	req.Header.Set("X-Forwarded-Proto-Version", "h2")
+	detector.DetectFromRequest(req)
+	if detection := detector.GetDetections().Protocol; detection.Score != 0 {
+		t.Errorf("Expected no signal over HTTP/2, got %+v", detection)
+	}
+}
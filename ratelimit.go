@@ -0,0 +1,115 @@
+package gogobot
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RateLimit is a request-rate threshold for a visitor, over all requests or a group of paths
+type RateLimit struct {
+	// Group names the path group, e.g. "login"; it is part of the store key
+	Group string
+	// Paths are the path prefixes in the group; empty applies the limit to every request
+	Paths []string
+	// Limit is the number of requests per Window above which the visitor is suspicious
+	Limit int64
+	// Window is the length of the fixed counting window; zero uses DefaultSessionWindow
+	Window time.Duration
+}
+
+// matches reports whether the limit applies to path
+func (l RateLimit) matches(path string) bool {
+	if len(l.Paths) == 0 {
+		return true
+	}
+	for _, prefix := range l.Paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestRate is a visitor's request count in the current window of a RateLimit
+type RequestRate struct {
+	Group    string        `json:"group"`
+	Requests int64         `json:"requests"`
+	Limit    int64         `json:"limit"`
+	Window   time.Duration `json:"window"`
+}
+
+// getRequestRates counts the request against every limit matching its path
+func getRequestRates(req *http.Request, components *ComponentDict, tracker *SessionTracker, limits []RateLimit) Component[[]RequestRate] {
+	if tracker == nil {
+		return ErrorComponent[[]RequestRate]{
+			State: StateUndefined,
+			Error: "rate limits require a session tracker",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[[]RequestRate]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	now := tracker.now()
+	path := req.URL.Path
+	var rates []RequestRate
+	for _, limit := range limits {
+		if limit.Limit <= 0 || !limit.matches(path) {
+			continue
+		}
+		window := limit.Window
+		if window <= 0 {
+			window = DefaultSessionWindow
+		}
+
+		storeKey := tracker.Prefix + "rate:" + limit.Group + ":" + key + ":" + windowBucket(now, window)
+		count, err := tracker.store.Incr(req.Context(), storeKey, 1, 2*window)
+		if err != nil {
+			return ErrorComponent[[]RequestRate]{
+				State: StateUnexpectedBehaviour,
+				Error: "session store: " + err.Error(),
+			}
+		}
+		rates = append(rates, RequestRate{
+			Group:    limit.Group,
+			Requests: count,
+			Limit:    limit.Limit,
+			Window:   window,
+		})
+	}
+
+	return SuccessComponent[[]RequestRate]{
+		State: StateSuccess,
+		Value: rates,
+	}
+}
+
+// detectRequestRate scores the visitor by how far it exceeds its tightest rate limit: half of
+// the request-to-limit ratio, so a visitor at 1.6 times a limit is flagged with the default
+// threshold and one at twice the limit scores 1
+func detectRequestRate(components *ComponentDict) *BotDetectionResult {
+	if components.RequestRates.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	var score float64
+	for _, rate := range components.RequestRates.GetValue() {
+		if rate.Requests <= rate.Limit {
+			continue
+		}
+		score = max(score, min(0.5*float64(rate.Requests)/float64(rate.Limit), 1))
+	}
+	if score == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindRateAbuse,
+		Score:   score,
+	}
+}
@@ -0,0 +1,114 @@
+package gogobot
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetRequestRates(t *testing.T) {
+	tracker := NewSessionTracker(NewMemoryStore(0))
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+	limits := []RateLimit{
+		{Group: "all", Limit: 100},
+		{Group: "login", Paths: []string{"/login", "/api/auth/"}, Limit: 5, Window: 10 * time.Second},
+	}
+
+	collect := func(path string) []RequestRate {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		rates := getRequestRates(req, collectAllSources(req), tracker, limits)
+		if rates.GetState() != StateSuccess {
+			t.Fatalf("Expected rates, got %v", rates)
+		}
+		return rates.GetValue()
+	}
+
+	collect("/")
+	rates := collect("/login")
+	if len(rates) != 2 || rates[0].Requests != 2 || rates[1].Group != "login" || rates[1].Requests != 1 {
+		t.Errorf("Unexpected rates: %+v", rates)
+	}
+	if rates[0].Window != DefaultSessionWindow || rates[1].Window != 10*time.Second {
+		t.Errorf("Unexpected windows: %+v", rates)
+	}
+
+	// The login window restarts independently of the overall one
+	now = now.Add(10 * time.Second)
+	rates = collect("/api/auth/token")
+	if rates[0].Requests != 3 || rates[1].Requests != 1 {
+		t.Errorf("Unexpected rates in new login window: %+v", rates)
+	}
+}
+
+func TestGetRequestRates_Errors(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	limits := []RateLimit{{Limit: 10}}
+
+	if rates := getRequestRates(req, collectAllSources(req), nil, limits); rates.GetState() != StateUndefined {
+		t.Errorf("Expected undefined rates without a tracker, got %v", rates.GetState())
+	}
+
+	store := newMapStore()
+	store.err = errors.New("connection refused")
+	tracker := NewSessionTracker(store)
+	if rates := getRequestRates(req, collectAllSources(req), tracker, limits); rates.GetState() != StateUnexpectedBehaviour {
+		t.Errorf("Expected store errors to surface, got %v", rates.GetState())
+	}
+}
+
+func TestDetectRequestRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rates    []RequestRate
+		expected float64
+	}{
+		{"under limit", []RequestRate{{Requests: 10, Limit: 10}}, 0},
+		{"just over", []RequestRate{{Requests: 12, Limit: 10}}, 0.6},
+		{"double", []RequestRate{{Requests: 30, Limit: 10}}, 1},
+		{"worst group wins", []RequestRate{{Requests: 11, Limit: 10}, {Group: "login", Requests: 8, Limit: 5}}, 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := &ComponentDict{RequestRates: SuccessComponent[[]RequestRate]{State: StateSuccess, Value: tt.rates}}
+			result := detectRequestRate(components)
+			if result.Bot {
+				t.Error("Expected a soft signal")
+			}
+			if diff := result.Score - tt.expected; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected score %v, got %v", tt.expected, result.Score)
+			}
+			if tt.expected > 0 && result.BotKind != BotKindRateAbuse {
+				t.Errorf("Expected rate_abuse, got %q", result.BotKind)
+			}
+		})
+	}
+}
+
+func TestBotDetector_RateLimits(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.RateLimits = []RateLimit{{Group: "search", Paths: []string{"/search"}, Limit: 5}}
+	detector := NewDetectorWithConfig(config)
+
+	var result BotDetectionResult
+	for i := range 10 {
+		req := httptest.NewRequest("GET", "/search?q="+string(rune('a'+i)), nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		result, _ = detector.DetectFromRequest(req)
+		if i < 5 && detector.GetDetections().RequestRate.Score != 0 {
+			t.Errorf("Request %d: expected no rate signal under the limit", i+1)
+		}
+	}
+	if detection := detector.GetDetections().RequestRate; detection.Score != 1 || detection.BotKind != BotKindRateAbuse {
+		t.Errorf("Expected full rate_abuse signal, got %+v", detection)
+	}
+	if !result.Bot {
+		t.Errorf("Expected client at twice the limit to be flagged, got %+v", result)
+	}
+}
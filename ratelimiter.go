@@ -0,0 +1,177 @@
+package gogobot
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Rate is a token bucket: PerSecond tokens are added each second up to Burst, and every request
+// takes one
+type Rate struct {
+	PerSecond float64
+	// Burst is the bucket size; zero allows one second's worth of requests, at least one
+	Burst int
+}
+
+// burst returns the bucket size
+func (r Rate) burst() int64 {
+	if r.Burst > 0 {
+		return int64(r.Burst)
+	}
+	return max(1, int64(math.Ceil(r.PerSecond)))
+}
+
+// RateLimiter limits the requests the middleware serves with token buckets per visitor and bot
+// kind, kept in a Store so every replica shares them. Requests over the rate are answered as
+// ActionRateLimit. A bucket's tokens and refill time are read and written back per request:
+// requests of a visitor are serialized within a process, but ones reaching different replicas at
+// the same instant may both take its last token.
+type RateLimiter struct {
+	// Rates are the rates of bots of a kind; bots of other kinds get BotRate
+	Rates map[BotKind]Rate
+	// BotRate is the rate of bots without a kind rate; zero does not limit them
+	BotRate Rate
+	// HumanRate is the rate of requests not flagged as bots; zero does not limit them
+	HumanRate Rate
+	// KeyFunc identifies the visitor owning a bucket; nil uses the detector's session key, or the
+	// client IP without a SessionTracker. Use SessionKeyByFingerprint to share buckets across
+	// the addresses of a bot.
+	KeyFunc SessionKeyFunc
+	// Prefix is prepended to store keys
+	Prefix string
+	// OnError is called when the store cannot be reached; requests are then served
+	OnError func(error)
+
+	store Store
+	now   func() time.Time
+	locks [rateLimiterLocks]sync.Mutex
+}
+
+// rateLimiterLocks is the number of locks serializing bucket updates, shared by hashed keys
+const rateLimiterLocks = 256
+
+// NewRateLimiter creates a rate limiter keeping buckets in store, with default rates of 5
+// requests per second for crawlers, 0.5 for AI crawlers and agents, and 0.1 for other bots
+func NewRateLimiter(store Store) *RateLimiter {
+	rates := map[BotKind]Rate{}
+	for kind, category := range botKindCategories {
+		switch category {
+		case BotCategoryCrawler:
+			rates[kind] = Rate{PerSecond: 5, Burst: 10}
+		case BotCategoryAI:
+			rates[kind] = Rate{PerSecond: 0.5, Burst: 2}
+		}
+	}
+	return &RateLimiter{
+		Rates:     rates,
+		BotRate:   Rate{PerSecond: 0.1, Burst: 1},
+		HumanRate: Rate{},
+		Prefix:    "gogobot:ratelimit:",
+		store:     store,
+		now:       time.Now,
+	}
+}
+
+// rate returns the rate of the verdict's bucket and the bucket name
+func (l *RateLimiter) rate(result *BotDetectionResult) (Rate, string) {
+	if !result.Bot {
+		return l.HumanRate, "human"
+	}
+	if rate, ok := l.Rates[result.BotKind]; ok {
+		return rate, string(result.BotKind)
+	}
+	return l.BotRate, "bot"
+}
+
+// encodeBucket encodes the tokens left in a bucket and when they were counted
+func encodeBucket(tokens float64, updated time.Time) []byte {
+	buf := make([]byte, 16)
+	binary.BigEndian.PutUint64(buf, math.Float64bits(tokens))
+	binary.BigEndian.PutUint64(buf[8:], uint64(updated.UnixNano()))
+	return buf
+}
+
+func decodeBucket(buf []byte) (tokens float64, updated time.Time, ok bool) {
+	if len(buf) != 16 {
+		return 0, time.Time{}, false
+	}
+	tokens = math.Float64frombits(binary.BigEndian.Uint64(buf))
+	updated = time.Unix(0, int64(binary.BigEndian.Uint64(buf[8:])))
+	return tokens, updated, true
+}
+
+// lock returns the lock serializing updates of the bucket at key
+func (l *RateLimiter) lock(key string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &l.locks[h.Sum32()%rateLimiterLocks]
+}
+
+// take takes a token from the visitor's bucket, returning how long to wait for the next token
+// when the bucket is empty
+func (l *RateLimiter) take(req *http.Request, visitor string, result *BotDetectionResult) (time.Duration, bool) {
+	rate, bucket := l.rate(result)
+	if rate.PerSecond <= 0 || visitor == "" {
+		return 0, true
+	}
+
+	burst := float64(rate.burst())
+	// A bucket left alone until it is full again is the same as a new one, so it can expire
+	ttl := max(time.Second, time.Duration(burst/rate.PerSecond*float64(time.Second)))
+	key := l.Prefix + bucket + ":" + visitor
+	lock := l.lock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	now := l.now()
+	state, found, err := l.store.Get(req.Context(), key)
+	if err != nil {
+		if l.OnError != nil {
+			l.OnError(err)
+		}
+		return 0, true
+	}
+	tokens := burst
+	if saved, updated, ok := decodeBucket(state); found && ok {
+		refilled := max(0, now.Sub(updated).Seconds()) * rate.PerSecond
+		tokens = min(burst, saved+refilled)
+	}
+	if tokens < 1 {
+		// Denied requests take no token, so the bucket is left as stored
+		return time.Duration((1 - tokens) / rate.PerSecond * float64(time.Second)), false
+	}
+
+	if err := l.store.Set(req.Context(), key, encodeBucket(tokens-1, now), ttl); err != nil && l.OnError != nil {
+		l.OnError(err)
+	}
+	return 0, true
+}
+
+// limit answers requests over the RateLimiter's rate as ActionRateLimit, reporting whether it
+// did
+func (d *BotDetector) limit(w http.ResponseWriter, req *http.Request, components *ComponentDict, config MiddlewareConfig, result *BotDetectionResult) bool {
+	limiter := config.RateLimiter
+	if limiter == nil {
+		return false
+	}
+	var visitor string
+	if limiter.KeyFunc != nil {
+		visitor = limiter.KeyFunc(req, d.addressComponents(req, components))
+	} else {
+		visitor = d.visitorKey(req, components)
+	}
+	retryAfter, ok := limiter.take(req, visitor, result)
+	if ok {
+		return false
+	}
+
+	if config.OnAction != nil {
+		config.OnAction(req, result, ActionRateLimit)
+	}
+	config.RateLimitResponse.serve(w, req, config, result, retryAfter)
+	return true
+}
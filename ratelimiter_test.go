@@ -0,0 +1,112 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Take(t *testing.T) {
+	limiter := NewRateLimiter(NewMemoryStore(0))
+	now := time.Unix(1700000000, 0)
+	limiter.now = func() time.Time { return now }
+	req := httptest.NewRequest("GET", "/", nil)
+	bot := &BotDetectionResult{Bot: true, BotKind: BotKindGPTBot}
+
+	for i := range 2 {
+		if _, ok := limiter.take(req, "ip:203.0.113.7", bot); !ok {
+			t.Fatalf("Expected request %d within the burst", i+1)
+		}
+	}
+	wait, ok := limiter.take(req, "ip:203.0.113.7", bot)
+	if ok || wait != 2*time.Second {
+		t.Fatalf("Expected empty bucket with a 2s wait, got %t and %v", ok, wait)
+	}
+	if _, ok := limiter.take(req, "ip:203.0.113.8", bot); !ok {
+		t.Error("Expected visitors to have their own buckets")
+	}
+	if _, ok := limiter.take(req, "ip:203.0.113.7", &BotDetectionResult{}); !ok {
+		t.Error("Expected humans not to be limited by default")
+	}
+
+	now = now.Add(2 * time.Second)
+	if _, ok := limiter.take(req, "ip:203.0.113.7", bot); !ok {
+		t.Error("Expected a token after the refill interval")
+	}
+	if _, ok := limiter.take(req, "ip:203.0.113.7", bot); ok {
+		t.Error("Expected the refilled token to be taken")
+	}
+}
+
+func TestRateLimiter_SustainedRate(t *testing.T) {
+	limiter := NewRateLimiter(NewMemoryStore(0))
+	limiter.Rates = map[BotKind]Rate{BotKindCrawler: {PerSecond: 5, Burst: 10}}
+	start := time.Unix(1700000000, 0)
+	now := start
+	limiter.now = func() time.Time { return now }
+	req := httptest.NewRequest("GET", "/", nil)
+	crawler := &BotDetectionResult{Bot: true, BotKind: BotKindCrawler}
+
+	// A 10 second probe every 10ms gets the burst and then 5 requests a second
+	var allowed int
+	for ; now.Sub(start) < 10*time.Second; now = now.Add(10 * time.Millisecond) {
+		if _, ok := limiter.take(req, "ip:203.0.113.7", crawler); ok {
+			allowed++
+		}
+	}
+	if allowed < 59 || allowed > 60 {
+		t.Errorf("Expected about 60 requests in 10s at 5 rps with a burst of 10, got %d", allowed)
+	}
+
+	// Concurrent requests cannot take the same token twice
+	now = now.Add(time.Hour)
+	var wg sync.WaitGroup
+	var concurrent atomic.Int64
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := limiter.take(req, "ip:203.0.113.7", crawler); ok {
+				concurrent.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := concurrent.Load(); got != 10 {
+		t.Errorf("Expected the burst of 10 for concurrent requests, got %d", got)
+	}
+}
+
+func TestMiddleware_RateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(NewMemoryStore(0))
+	limiter.now = func() time.Time { return time.Unix(1700000000, 0) }
+	var reported []Action
+	config := DefaultMiddlewareConfig()
+	config.RateLimiter = limiter
+	config.Actions = map[BotCategory]Action{BotCategoryAutomation: ActionRateLimit}
+	config.OnAction = func(r *http.Request, result *BotDetectionResult, action Action) {
+		reported = append(reported, action)
+	}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	var codes []int
+	for range 2 {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+		if rec.Code == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "10" {
+			t.Errorf("Expected Retry-After of the next token, got %q", rec.Header().Get("Retry-After"))
+		}
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("Expected one request within the bot rate, got %v", codes)
+	}
+	if len(reported) != 1 || reported[0] != ActionRateLimit {
+		t.Errorf("Expected only the refused request to be reported, got %v", reported)
+	}
+}
@@ -0,0 +1,74 @@
+package gogobot
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryAfter is how long rate limited clients are asked to wait
+const DefaultRetryAfter = time.Minute
+
+// RateLimitResponse configures the 429 Too Many Requests of ActionRateLimit, so well-behaved
+// crawlers back off instead of treating an opaque refusal as an error
+type RateLimitResponse struct {
+	// RetryAfter is sent in the Retry-After header; zero uses DefaultRetryAfter
+	RetryAfter time.Duration
+	// Headers adds the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers of the
+	// IETF rate limit fields draft
+	Headers bool
+	// Limit is the request quota per Window advertised in RateLimit-Limit and RateLimit-Policy;
+	// zero omits them
+	Limit  int
+	Window time.Duration
+}
+
+// DefaultRateLimitResponse returns a response asking clients to retry after DefaultRetryAfter,
+// with RateLimit headers
+func DefaultRateLimitResponse() *RateLimitResponse {
+	return &RateLimitResponse{
+		RetryAfter: DefaultRetryAfter,
+		Headers:    true,
+		Limit:      0,
+		Window:     0,
+	}
+}
+
+// retryAfter returns the configured wait, DefaultRetryAfter for a nil response
+func (r *RateLimitResponse) retryAfter() time.Duration {
+	if r == nil || r.RetryAfter <= 0 {
+		return DefaultRetryAfter
+	}
+	return r.RetryAfter
+}
+
+// serve answers with 429 and the configured headers, asking to retry after wait when the
+// RateLimiter knows it and after the configured RetryAfter otherwise
+func (r *RateLimitResponse) serve(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, result *BotDetectionResult, wait time.Duration) {
+	if wait <= 0 {
+		wait = r.retryAfter()
+	}
+	retryAfter := deltaSeconds(wait)
+	w.Header().Set("Retry-After", retryAfter)
+	if r != nil && r.Headers {
+		if r.Limit > 0 {
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(r.Limit))
+			if r.Window > 0 {
+				w.Header().Set("RateLimit-Policy", strconv.Itoa(r.Limit)+";w="+deltaSeconds(r.Window))
+			}
+		}
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", retryAfter)
+	}
+	refuse(w, req, config, BlockedData{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    http.StatusText(http.StatusTooManyRequests),
+		Result:     result,
+	})
+}
+
+// deltaSeconds formats a duration as whole seconds, rounded up
+func deltaSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,44 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware_RateLimitResponse(t *testing.T) {
+	config := DefaultMiddlewareConfig()
+	config.Actions = map[BotCategory]Action{BotCategoryAutomation: ActionRateLimit}
+	config.RateLimitResponse = &RateLimitResponse{RetryAfter: 1500 * time.Millisecond, Headers: true, Limit: 10, Window: time.Minute}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d", rec.Code)
+	}
+	expected := map[string]string{
+		"Retry-After":         "2",
+		"RateLimit-Limit":     "10",
+		"RateLimit-Policy":    "10;w=60",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "2",
+	}
+	for name, value := range expected {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("Expected %s: %s, got %q", name, value, got)
+		}
+	}
+}
+
+func TestRateLimitResponse_Default(t *testing.T) {
+	rec := httptest.NewRecorder()
+	var response *RateLimitResponse
+	response.serve(rec, httptest.NewRequest("GET", "/", nil), DefaultMiddlewareConfig(), &BotDetectionResult{Bot: true}, 0)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" || rec.Header().Get("RateLimit-Remaining") != "" {
+		t.Errorf("Expected bare 429 with Retry-After, got %d %v", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,137 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// RequestCapture enables capturing a bounded, redacted dump of each request's line and headers
+// as the RawRequest component, to investigate novel bot traffic after the fact from the
+// components attached to the request context. Headers are dumped in wire order and original
+// case when the server records them with a HeaderOrderListener, and sorted in canonical case
+// otherwise.
+type RequestCapture struct {
+	// MaxBytes bounds the dump; zero uses DefaultCaptureMaxBytes
+	MaxBytes int
+	// RedactHeaders are the headers whose values are replaced, matched case-insensitively
+	RedactHeaders []string
+	// RedactParams are the query parameters whose values are replaced, matched
+	// case-insensitively
+	RedactParams []string
+}
+
+// DefaultCaptureMaxBytes is the default bound of a request dump
+const DefaultCaptureMaxBytes = 4096
+
+// redactedValue replaces redacted header and parameter values
+const redactedValue = "[REDACTED]"
+
+// DefaultRequestCapture redacts credentials, cookies and common token parameters
+func DefaultRequestCapture() *RequestCapture {
+	return &RequestCapture{
+		MaxBytes:      DefaultCaptureMaxBytes,
+		RedactHeaders: []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key", "X-Auth-Token", "X-CSRF-Token"},
+		RedactParams:  []string{"access_token", "api_key", "apikey", "key", "password", "secret", "token"},
+	}
+}
+
+// RawRequest is a redacted dump of a request's line and headers
+type RawRequest struct {
+	Dump string `json:"dump"`
+	// WireOrder is true when headers are in the order and case the client sent them
+	WireOrder bool `json:"wireOrder"`
+	// Truncated is true when the dump was cut at the capture's MaxBytes
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// redactQuery replaces the values of redacted parameters in a raw query, leaving the rest as sent
+func redactQuery(rawQuery string, params []string) string {
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		name, _, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if containsFold(params, name) {
+			pairs[i] = pair[:strings.IndexByte(pair, '=')+1] + redactedValue
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// getRawRequest dumps the request line as sent and the headers, in the wire order collected
+// as the HeaderOrder component when available
+func getRawRequest(req *http.Request, components *ComponentDict, capture *RequestCapture) Component[RawRequest] {
+	if capture == nil {
+		return ErrorComponent[RawRequest]{
+			State: StateUndefined,
+			Error: "request capture not configured",
+		}
+	}
+
+	target := req.RequestURI
+	if target == "" {
+		target = req.URL.RequestURI()
+	}
+	if path, query, ok := strings.Cut(target, "?"); ok {
+		target = path + "?" + redactQuery(query, capture.RedactParams)
+	}
+
+	var dump strings.Builder
+	dump.WriteString(req.Method + " " + target + " " + req.Proto + "\r\n")
+	writeHeader := func(name, value string) {
+		if containsFold(capture.RedactHeaders, name) {
+			value = redactedValue
+		}
+		dump.WriteString(name + ": " + value + "\r\n")
+	}
+
+	var raw RawRequest
+	if components != nil && components.HeaderOrder != nil && components.HeaderOrder.GetState() == StateSuccess {
+		raw.WireOrder = true
+		// Repeated headers take their values in turn
+		next := make(map[string]int)
+		for _, name := range components.HeaderOrder.GetValue() {
+			key := textproto.CanonicalMIMEHeaderKey(name)
+			if key == "Host" {
+				writeHeader(name, req.Host)
+				continue
+			}
+			if values := req.Header[key]; next[key] < len(values) {
+				writeHeader(name, values[next[key]])
+				next[key]++
+			}
+		}
+	} else {
+		writeHeader("Host", req.Host)
+		names := make([]string, 0, len(req.Header))
+		for name := range req.Header {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			for _, value := range req.Header[name] {
+				writeHeader(name, value)
+			}
+		}
+	}
+
+	raw.Dump = dump.String()
+	maxBytes := capture.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultCaptureMaxBytes
+	}
+	if len(raw.Dump) > maxBytes {
+		raw.Dump, raw.Truncated = raw.Dump[:maxBytes], true
+	}
+	return SuccessComponent[RawRequest]{
+		State: StateSuccess,
+		Value: raw,
+	}
+}
@@ -0,0 +1,80 @@
+package gogobot
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactQuery(t *testing.T) {
+	params := DefaultRequestCapture().RedactParams
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"q=shoes&page=2", "q=shoes&page=2"},
+		{"q=shoes&Token=abc", "q=shoes&Token=" + redactedValue},
+		{"api%5Fkey=abc&flag", "api%5Fkey=" + redactedValue + "&flag"},
+		{"password=a=b", "password=" + redactedValue},
+	}
+	for _, tt := range tests {
+		if got := redactQuery(tt.query, params); got != tt.expected {
+			t.Errorf("Expected %q for %q, got %q", tt.expected, tt.query, got)
+		}
+	}
+}
+
+func TestGetRawRequest(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://example.com/search?q=a&token=secret", nil)
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	req.Header.Set("Cookie", "session=abc")
+	req.Header.Add("Accept", "text/html")
+	req.Header.Add("Accept", "*/*")
+
+	if state := getRawRequest(req, nil, nil).GetState(); state != StateUndefined {
+		t.Errorf("Expected undefined without capture, got %v", state)
+	}
+
+	raw := getRawRequest(req, &ComponentDict{}, DefaultRequestCapture()).GetValue()
+	expected := "GET http://example.com/search?q=a&token=" + redactedValue + " HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Accept: text/html\r\n" +
+		"Accept: */*\r\n" +
+		"Cookie: " + redactedValue + "\r\n" +
+		"User-Agent: curl/8.4.0\r\n"
+	if raw.Dump != expected || raw.WireOrder || raw.Truncated {
+		t.Errorf("Expected sorted dump %q, got %+v", expected, raw)
+	}
+
+	// Headers recorded by a HeaderOrderListener keep their order and case
+	components := &ComponentDict{
+		HeaderOrder: SuccessComponent[[]string]{
+			State: StateSuccess,
+			Value: []string{"host", "accept", "user-agent", "ACCEPT", "cookie"},
+		},
+	}
+	raw = getRawRequest(req, components, DefaultRequestCapture()).GetValue()
+	if !raw.WireOrder || !strings.HasSuffix(raw.Dump, "\r\nhost: example.com\r\naccept: text/html\r\nuser-agent: curl/8.4.0\r\nACCEPT: */*\r\ncookie: "+redactedValue+"\r\n") {
+		t.Errorf("Expected wire-order dump, got %+v", raw)
+	}
+
+	raw = getRawRequest(req, nil, &RequestCapture{MaxBytes: 16}).GetValue()
+	if raw.Dump != "GET http://examp" || !raw.Truncated {
+		t.Errorf("Expected truncated dump, got %+v", raw)
+	}
+}
+
+func TestDetector_RequestCapture(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.RequestCapture = DefaultRequestCapture()
+	detector := NewDetectorWithConfig(config)
+
+	req := newBrowserRequest("/login?password=hunter2", "203.0.113.7:1234")
+	if _, err := detector.DetectFromRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	raw := detector.GetComponents().RawRequest
+	if raw.GetState() != StateSuccess || strings.Contains(raw.GetValue().Dump, "hunter2") {
+		t.Errorf("Expected redacted capture, got %+v", raw)
+	}
+}
@@ -0,0 +1,209 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// Reputation is an IP reputation verdict from a feed such as AbuseIPDB or Spamhaus
+type Reputation struct {
+	// Score is the abuse confidence from 0 (clean) to 1 (known abusive)
+	Score float64 `json:"score"`
+	// Categories lists the abuse categories reported for the IP, e.g. "brute-force"
+	Categories []string `json:"categories,omitempty"`
+}
+
+// ReputationProvider looks up the reputation of an IP address
+type ReputationProvider interface {
+	LookupReputation(ctx context.Context, addr netip.Addr) (Reputation, error)
+}
+
+// ErrReputationPending is returned when a lookup did not finish within the request timeout.
+// The lookup continues in the background and later requests are served from the cache.
+var ErrReputationPending = errors.New("reputation lookup pending")
+
+// ReputationCheckerConfig holds configuration for a ReputationChecker
+type ReputationCheckerConfig struct {
+	// Timeout is how long a request waits for an uncached lookup
+	Timeout time.Duration
+	// LookupTimeout bounds the background lookup itself
+	LookupTimeout time.Duration
+	// CacheSize is the maximum number of cached verdicts
+	CacheSize int
+	// CacheTTL is how long verdicts are cached
+	CacheTTL time.Duration
+	// ErrorTTL is how long failed lookups are cached before being retried
+	ErrorTTL time.Duration
+}
+
+// DefaultReputationCheckerConfig returns a default reputation checker configuration
+func DefaultReputationCheckerConfig() ReputationCheckerConfig {
+	return ReputationCheckerConfig{
+		Timeout:       50 * time.Millisecond,
+		LookupTimeout: 5 * time.Second,
+		CacheSize:     10000,
+		CacheTTL:      6 * time.Hour,
+		ErrorTTL:      time.Minute,
+	}
+}
+
+// ReputationChecker calls a ReputationProvider asynchronously with a timeout, caching
+// verdicts and coalescing concurrent lookups for the same IP
+type ReputationChecker struct {
+	provider ReputationProvider
+	config   ReputationCheckerConfig
+	cache    *lruCache[netip.Addr, reputationEntry]
+	mu       sync.Mutex
+	inflight map[netip.Addr]*reputationCall
+	now      func() time.Time
+}
+
+type reputationEntry struct {
+	reputation Reputation
+	err        error
+	expires    time.Time
+}
+
+type reputationCall struct {
+	done       chan struct{}
+	reputation Reputation
+	err        error
+}
+
+// NewReputationChecker creates a ReputationChecker for provider
+func NewReputationChecker(provider ReputationProvider, config ReputationCheckerConfig) *ReputationChecker {
+	return &ReputationChecker{
+		provider: provider,
+		config:   config,
+		cache:    newLRUCache[netip.Addr, reputationEntry](config.CacheSize),
+		inflight: make(map[netip.Addr]*reputationCall),
+		now:      time.Now,
+	}
+}
+
+// Check returns the reputation of addr, waiting at most the configured timeout for an
+// uncached lookup. It returns ErrReputationPending if the lookup is still running.
+func (c *ReputationChecker) Check(ctx context.Context, addr netip.Addr) (Reputation, error) {
+	addr = normalizeAddr(addr)
+	if entry, ok := c.cache.get(addr); ok && c.now().Before(entry.expires) {
+		return entry.reputation, entry.err
+	}
+
+	call := c.lookup(addr)
+
+	var timeout <-chan time.Time
+	if c.config.Timeout > 0 {
+		timer := time.NewTimer(c.config.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-call.done:
+		return call.reputation, call.err
+	case <-timeout:
+		return Reputation{}, ErrReputationPending
+	case <-ctx.Done():
+		return Reputation{}, ErrReputationPending
+	}
+}
+
+// lookup starts a background lookup for addr unless one is already running
+func (c *ReputationChecker) lookup(addr netip.Addr) *reputationCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if call, ok := c.inflight[addr]; ok {
+		return call
+	}
+
+	call := &reputationCall{done: make(chan struct{})}
+	c.inflight[addr] = call
+
+	go func() {
+		// Detached from the request so that a slow feed still warms the cache
+		ctx := context.Background()
+		if c.config.LookupTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.config.LookupTimeout)
+			defer cancel()
+		}
+
+		call.reputation, call.err = c.provider.LookupReputation(ctx, addr)
+
+		ttl := c.config.CacheTTL
+		if call.err != nil {
+			ttl = c.config.ErrorTTL
+		}
+		if ttl > 0 {
+			c.cache.set(addr, reputationEntry{
+				reputation: call.reputation,
+				err:        call.err,
+				expires:    c.now().Add(ttl),
+			})
+		}
+
+		c.mu.Lock()
+		delete(c.inflight, addr)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	return call
+}
+
+// getReputation checks the resolved client IP with the configured ReputationChecker
+func getReputation(ctx context.Context, clientIP Component[netip.Addr], checker *ReputationChecker) Component[Reputation] {
+	if checker == nil {
+		return ErrorComponent[Reputation]{
+			State: StateUndefined,
+			Error: "no reputation provider configured",
+		}
+	}
+	if clientIP.GetState() != StateSuccess {
+		return ErrorComponent[Reputation]{
+			State: StateUndefined,
+			Error: "client IP is unavailable",
+		}
+	}
+
+	reputation, err := checker.Check(ctx, clientIP.GetValue())
+	if errors.Is(err, ErrReputationPending) {
+		// The feed is slow, not failing; later requests get the verdict from the cache
+		return ErrorComponent[Reputation]{
+			State: StateNull,
+			Error: err.Error(),
+		}
+	}
+	if err != nil {
+		return ErrorComponent[Reputation]{
+			State: StateUnexpectedBehaviour,
+			Error: err.Error(),
+		}
+	}
+	return SuccessComponent[Reputation]{
+		State: StateSuccess,
+		Value: reputation,
+	}
+}
+
+// detectReputation contributes the IP reputation score as a weighted signal
+func detectReputation(components *ComponentDict) *BotDetectionResult {
+	if components.Reputation.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	score := min(max(components.Reputation.GetValue().Score, 0), 1)
+	if score == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindAbusiveIP,
+		Score:   score,
+	}
+}
@@ -0,0 +1,149 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeReputationProvider returns fixed verdicts, optionally blocking until released
+type fakeReputationProvider struct {
+	scores  map[netip.Addr]float64
+	release chan struct{}
+	calls   atomic.Int32
+	err     error
+}
+
+func (p *fakeReputationProvider) LookupReputation(ctx context.Context, addr netip.Addr) (Reputation, error) {
+	p.calls.Add(1)
+	if p.release != nil {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return Reputation{}, ctx.Err()
+		}
+	}
+	if p.err != nil {
+		return Reputation{}, p.err
+	}
+	return Reputation{Score: p.scores[addr], Categories: []string{"brute-force"}}, nil
+}
+
+func TestReputationChecker_CachesVerdicts(t *testing.T) {
+	addr := netip.MustParseAddr("198.51.100.1")
+	provider := &fakeReputationProvider{scores: map[netip.Addr]float64{addr: 0.9}}
+	checker := NewReputationChecker(provider, DefaultReputationCheckerConfig())
+
+	for i := 0; i < 3; i++ {
+		reputation, err := checker.Check(context.Background(), addr)
+		if err != nil || reputation.Score != 0.9 {
+			t.Fatalf("Expected score 0.9, got %+v, %v", reputation, err)
+		}
+	}
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 provider call, got %d", calls)
+	}
+}
+
+func TestReputationChecker_TimeoutContinuesInBackground(t *testing.T) {
+	addr := netip.MustParseAddr("198.51.100.2")
+	provider := &fakeReputationProvider{
+		scores:  map[netip.Addr]float64{addr: 0.7},
+		release: make(chan struct{}),
+	}
+	config := DefaultReputationCheckerConfig()
+	config.Timeout = 10 * time.Millisecond
+	checker := NewReputationChecker(provider, config)
+
+	// Concurrent requests coalesce into one lookup and give up after the timeout
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := checker.Check(context.Background(), addr); !errors.Is(err, ErrReputationPending) {
+				t.Errorf("Expected pending lookup, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	close(provider.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		reputation, err := checker.Check(context.Background(), addr)
+		if err == nil {
+			if reputation.Score != 0.7 {
+				t.Errorf("Expected score 0.7, got %v", reputation.Score)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Background lookup never completed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected concurrent lookups to be coalesced into 1 call, got %d", calls)
+	}
+}
+
+func TestReputationChecker_ErrorsCachedBriefly(t *testing.T) {
+	addr := netip.MustParseAddr("198.51.100.3")
+	provider := &fakeReputationProvider{err: errors.New("quota exceeded")}
+	checker := NewReputationChecker(provider, DefaultReputationCheckerConfig())
+
+	now := time.Now()
+	checker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := checker.Check(context.Background(), addr); err == nil {
+			t.Fatal("Expected provider error")
+		}
+	}
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected error to be cached, got %d calls", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	checker.Check(context.Background(), addr)
+	if calls := provider.calls.Load(); calls != 2 {
+		t.Errorf("Expected retry after error TTL, got %d calls", calls)
+	}
+}
+
+func TestDetectReputation(t *testing.T) {
+	abusive := netip.MustParseAddr("198.51.100.10")
+	provider := &fakeReputationProvider{scores: map[netip.Addr]float64{abusive: 1}}
+
+	config := DefaultDetectorConfig()
+	config.ReputationChecker = NewReputationChecker(provider, DefaultReputationCheckerConfig())
+	detector := NewDetectorWithConfig(config)
+
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Accept":          "text/html",
+		"Accept-Language": "en-US",
+		"Accept-Encoding": "gzip",
+	})
+	req.RemoteAddr = "198.51.100.10:1234"
+
+	result, _ := detector.DetectFromRequest(req)
+	if !result.Bot || result.BotKind != BotKindAbusiveIP {
+		t.Errorf("Expected abusive IP to be flagged, got %+v", result)
+	}
+	if categories := detector.GetComponents().Reputation.GetValue().Categories; len(categories) != 1 {
+		t.Errorf("Expected reputation categories in components, got %v", categories)
+	}
+
+	req.RemoteAddr = "198.51.100.11:1234"
+	result, _ = detector.DetectFromRequest(req)
+	if result.Bot {
+		t.Errorf("Expected clean IP not to be flagged, got %+v", result)
+	}
+}
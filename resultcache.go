@@ -0,0 +1,122 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/netip"
+	"sync/atomic"
+	"time"
+)
+
+// ResultCache reuses the verdict for the same client IP and user agent for a short TTL, so
+// hot endpoints do not collect and run every detector on each request. Verdicts can be up to
+// one TTL stale, and cached verdicts are served without collecting components or recording
+// sessions.
+type ResultCache struct {
+	entries *lruCache[resultCacheKey, resultCacheEntry]
+	ttl     time.Duration
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	now     func() time.Time
+}
+
+// ResultCacheStats holds hit/miss statistics for a ResultCache
+type ResultCacheStats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+}
+
+// HitRate returns the fraction of lookups served from the cache
+func (s ResultCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type resultCacheKey struct {
+	addr netip.Addr
+	ua   uint64
+}
+
+type resultCacheEntry struct {
+	ua      string
+	result  BotDetectionResult
+	geo     Component[GeoInfo]
+	expires time.Time
+}
+
+// NewResultCache creates a cache holding up to capacity verdicts for ttl each
+func NewResultCache(capacity int, ttl time.Duration) *ResultCache {
+	return &ResultCache{
+		entries: newLRUCache[resultCacheKey, resultCacheEntry](capacity),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// Stats returns the current hit/miss statistics
+func (c *ResultCache) Stats() ResultCacheStats {
+	return ResultCacheStats{
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Size:     c.entries.len(),
+		Capacity: c.entries.capacity,
+	}
+}
+
+// Purge removes all cached verdicts and resets the statistics
+func (c *ResultCache) Purge() {
+	c.entries.purge()
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+func (c *ResultCache) get(addr netip.Addr, ua string) (resultCacheEntry, bool) {
+	entry, ok := c.entries.get(resultCacheKey{addr: addr, ua: hashUserAgent(ua)})
+	if !ok || entry.ua != ua || !c.now().Before(entry.expires) {
+		c.misses.Add(1)
+		return resultCacheEntry{}, false
+	}
+	c.hits.Add(1)
+	return entry, true
+}
+
+func (c *ResultCache) set(addr netip.Addr, ua string, result BotDetectionResult, geo Component[GeoInfo]) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.entries.set(resultCacheKey{addr: addr, ua: hashUserAgent(ua)}, resultCacheEntry{
+		ua:      ua,
+		result:  result,
+		geo:     geo,
+		expires: c.now().Add(c.ttl),
+	})
+}
+
+// lookupResultCache returns the cached verdict for the request's client IP and user agent.
+// Honeypot trap requests are never served from the cache so that the visitor is marked.
+func (d *BotDetector) lookupResultCache(req *http.Request) (resultCacheEntry, bool) {
+	if d.config.ResultCache == nil || d.alwaysDetect(req) {
+		return resultCacheEntry{}, false
+	}
+	clientIP, _ := d.resolveClientIP(req)
+	if clientIP.GetState() != StateSuccess {
+		return resultCacheEntry{}, false
+	}
+	return d.config.ResultCache.get(clientIP.GetValue(), req.Header.Get("User-Agent"))
+}
+
+// cacheResult stores the verdict computed from components. Visitors still going through the
+// cookie challenge are not cached, so that their requests keep being counted.
+func (d *BotDetector) cacheResult(req *http.Request, components *ComponentDict, result BotDetectionResult) {
+	if d.config.ResultCache == nil || components.ResolvedClientIP.GetState() != StateSuccess {
+		return
+	}
+	if components.CookieChallenge.GetState() == StateSuccess && !components.CookieChallenge.GetValue().Passed {
+		return
+	}
+	d.config.ResultCache.set(components.ResolvedClientIP.GetValue(), req.Header.Get("User-Agent"), result, components.Geo)
+}
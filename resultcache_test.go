@@ -0,0 +1,98 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResultCache_DetectFromRequest(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewResultCache(100, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	config := DefaultDetectorConfig()
+	config.ResultCache = cache
+	detector := NewDetectorWithConfig(config)
+
+	var runs int
+	detector.AddDetector("counter", func(*ComponentDict) *BotDetectionResult {
+		runs++
+		return &BotDetectionResult{Bot: false}
+	})
+
+	newRequest := func(remoteAddr, ua string) *http.Request {
+		req := createTestRequest("GET", "/", map[string]string{"User-Agent": ua})
+		req.RemoteAddr = remoteAddr
+		return req
+	}
+
+	result, _ := detector.DetectFromRequest(newRequest("198.51.100.7:1000", "curl/8.0"))
+	cached, _ := detector.DetectFromRequest(newRequest("198.51.100.7:2000", "curl/8.0"))
+	if runs != 1 {
+		t.Errorf("Expected cached verdict for the same IP and user agent, got %d runs", runs)
+	}
+	if !reflect.DeepEqual(cached, result) || !cached.Bot {
+		t.Errorf("Expected cached bot verdict %+v, got %+v", result, cached)
+	}
+
+	// A different user agent or address is a different key
+	detector.DetectFromRequest(newRequest("198.51.100.7:1000", "wget/1.21"))
+	detector.DetectFromRequest(newRequest("[2001:db8::1]:1000", "curl/8.0"))
+	if runs != 3 {
+		t.Errorf("Expected misses for new keys, got %d runs", runs)
+	}
+
+	// Verdicts expire after the TTL
+	now = now.Add(2 * time.Minute)
+	detector.DetectFromRequest(newRequest("198.51.100.7:1000", "curl/8.0"))
+	if runs != 4 {
+		t.Errorf("Expected expired verdict to be recomputed, got %d runs", runs)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 4 || stats.Size != 3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	cache.Purge()
+	if stats := cache.Stats(); stats.Size != 0 || stats.Hits != 0 {
+		t.Errorf("Expected purged cache, got %+v", stats)
+	}
+}
+
+func TestResultCache_Middleware(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.ResultCache = NewResultCache(100, time.Minute)
+	detector := NewDetectorWithConfig(config)
+
+	var runs atomic.Int32
+	detector.AddDetector("counter", func(*ComponentDict) *BotDetectionResult {
+		runs.Add(1)
+		return &BotDetectionResult{Bot: false}
+	})
+
+	var withComponents []bool
+	handler := detector.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetResultFromContext(r.Context()); !ok {
+			t.Error("Expected detection result in context")
+		}
+		_, ok := GetComponentsFromContext(r.Context())
+		withComponents = append(withComponents, ok)
+	}))
+
+	for range 3 {
+		req := createTestRequest("GET", "/", map[string]string{"User-Agent": "Mozilla/5.0"})
+		req.RemoteAddr = "198.51.100.7:1000"
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if runs.Load() != 1 {
+		t.Errorf("Expected one detection run, got %d", runs.Load())
+	}
+	if len(withComponents) != 3 || !withComponents[0] || withComponents[1] || withComponents[2] {
+		t.Errorf("Expected components only for the uncached request, got %v", withComponents)
+	}
+}
@@ -0,0 +1,76 @@
+package gogobot
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// DefaultResultHeaderPrefix starts the names of the result headers
+const DefaultResultHeaderPrefix = "X-Bot-"
+
+// ResultHeaders configures how the middleware annotates requests with its verdict, so caches,
+// log pipelines and upstream services can use it without detecting again. The headers are
+// <Prefix>Detected ("true" or "false"), <Prefix>Kind and <Prefix>Score.
+type ResultHeaders struct {
+	// Prefix starts the header names; empty uses DefaultResultHeaderPrefix
+	Prefix string
+	// Response adds the headers to the response
+	Response bool
+	// Request adds the headers to the request passed to the next handler, e.g. a reverse proxy
+	// forwarding it upstream. Headers with these names sent by the client are always removed.
+	Request bool
+}
+
+// DefaultResultHeaders returns result headers on the response
+func DefaultResultHeaders() *ResultHeaders {
+	return &ResultHeaders{
+		Prefix:   DefaultResultHeaderPrefix,
+		Response: true,
+		Request:  false,
+	}
+}
+
+// names returns the detected, kind and score header names
+func (h *ResultHeaders) names() (string, string, string) {
+	prefix := h.Prefix
+	if prefix == "" {
+		prefix = DefaultResultHeaderPrefix
+	}
+	return prefix + "Detected", prefix + "Kind", prefix + "Score"
+}
+
+// strip removes result headers sent by the client, so that the next handler cannot mistake
+// them for the middleware's verdict
+func (h *ResultHeaders) strip(req *http.Request) {
+	if h == nil || !h.Request {
+		return
+	}
+	detected, kind, score := h.names()
+	req.Header.Del(detected)
+	req.Header.Del(kind)
+	req.Header.Del(score)
+}
+
+// annotate writes the verdict to the configured headers
+func (h *ResultHeaders) annotate(w http.ResponseWriter, req *http.Request, result *BotDetectionResult) {
+	if h == nil {
+		return
+	}
+	if h.Response {
+		h.set(w.Header(), result)
+	}
+	if h.Request {
+		h.set(req.Header, result)
+	}
+}
+
+func (h *ResultHeaders) set(header http.Header, result *BotDetectionResult) {
+	detected, kind, score := h.names()
+	header.Set(detected, strconv.FormatBool(result.Bot))
+	if result.BotKind != "" {
+		header.Set(kind, string(result.BotKind))
+	} else {
+		header.Del(kind)
+	}
+	header.Set(score, strconv.FormatFloat(result.Score, 'f', 2, 64))
+}
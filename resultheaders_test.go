@@ -0,0 +1,53 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware_ResultHeaders(t *testing.T) {
+	config := DefaultMiddlewareConfig()
+	config.ResultHeaders = DefaultResultHeaders()
+	config.ResultHeaders.Request = true
+	var forwarded http.Header
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Clone()
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	for _, header := range []http.Header{rec.Header(), forwarded} {
+		if header.Get("X-Bot-Detected") != "true" || header.Get("X-Bot-Kind") != string(BotKindCurl) || header.Get("X-Bot-Score") == "" {
+			t.Errorf("Expected curl verdict headers, got %v", header)
+		}
+	}
+
+	req = newBrowserRequest("/", "203.0.113.7:1234")
+	req.Header.Set("X-Bot-Kind", "spoofed")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if forwarded.Get("X-Bot-Detected") != "false" || forwarded.Get("X-Bot-Kind") != "" {
+		t.Errorf("Expected browser verdict without client-sent kind, got %v", forwarded)
+	}
+}
+
+func TestMiddleware_ResultHeadersStrippedWhenSkipped(t *testing.T) {
+	config := DefaultMiddlewareConfig()
+	config.SkipFunc = func(*http.Request) bool { return true }
+	config.ResultHeaders = &ResultHeaders{Prefix: "X-Verdict-", Request: true}
+	var forwarded http.Header
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Clone()
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Verdict-Detected", "false")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if forwarded.Get("X-Verdict-Detected") != "" || rec.Header().Get("X-Verdict-Detected") != "" {
+		t.Errorf("Expected client-sent verdict to be removed, got %v", forwarded)
+	}
+}
@@ -0,0 +1,387 @@
+package gogobot
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRobotsViolationTTL is how long robots.txt violations count against a client
+const DefaultRobotsViolationTTL = time.Hour
+
+// robotsTxtPath is where crawlers fetch robots.txt; it is always allowed
+const robotsTxtPath = "/robots.txt"
+
+// RobotsPolicy builds the site's robots.txt and tracks clients fetching paths it disallows for
+// them. Only clients behaving as crawlers are held to it, those that fetched robots.txt or whose
+// user agent is named in a group, since people browsing disallowed pages do nothing wrong.
+type RobotsPolicy struct {
+	// ViolationTTL is how long violations count against a client
+	ViolationTTL time.Duration
+
+	groups   []*RobotsGroup
+	sitemaps []string
+}
+
+// RobotsGroup is a robots.txt group: rules for the crawlers named by its user agent tokens
+type RobotsGroup struct {
+	userAgents []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// robotsRule is an Allow or Disallow line
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// NewRobotsPolicy creates an empty policy, allowing everything
+func NewRobotsPolicy() *RobotsPolicy {
+	return &RobotsPolicy{ViolationTTL: DefaultRobotsViolationTTL}
+}
+
+// Group adds a group for the user agent tokens, "*" for all crawlers
+func (p *RobotsPolicy) Group(userAgents ...string) *RobotsGroup {
+	group := &RobotsGroup{userAgents: userAgents}
+	p.groups = append(p.groups, group)
+	return group
+}
+
+// Allow adds Allow rules for the path patterns
+func (g *RobotsGroup) Allow(patterns ...string) *RobotsGroup {
+	for _, pattern := range patterns {
+		g.rules = append(g.rules, robotsRule{allow: true, pattern: pattern})
+	}
+	return g
+}
+
+// Disallow adds Disallow rules for the path patterns
+func (g *RobotsGroup) Disallow(patterns ...string) *RobotsGroup {
+	for _, pattern := range patterns {
+		g.rules = append(g.rules, robotsRule{allow: false, pattern: pattern})
+	}
+	return g
+}
+
+// CrawlDelay asks the group's crawlers to wait between requests. Crawl-delay is not part of RFC
+// 9309 and some crawlers, Googlebot among them, ignore it.
+func (g *RobotsGroup) CrawlDelay(delay time.Duration) *RobotsGroup {
+	g.crawlDelay = delay
+	return g
+}
+
+// Sitemap adds Sitemap lines for the absolute sitemap URLs
+func (p *RobotsPolicy) Sitemap(urls ...string) *RobotsPolicy {
+	p.sitemaps = append(p.sitemaps, urls...)
+	return p
+}
+
+// String renders the policy as robots.txt
+func (p *RobotsPolicy) String() string {
+	var b strings.Builder
+	for i, group := range p.groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, userAgent := range group.userAgents {
+			b.WriteString("User-agent: " + userAgent + "\n")
+		}
+		if group.crawlDelay > 0 {
+			b.WriteString("Crawl-delay: " + strconv.FormatFloat(group.crawlDelay.Seconds(), 'f', -1, 64) + "\n")
+		}
+		for _, rule := range group.rules {
+			if rule.allow {
+				b.WriteString("Allow:")
+			} else {
+				b.WriteString("Disallow:")
+			}
+			if rule.pattern != "" {
+				b.WriteString(" " + rule.pattern)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(p.sitemaps) > 0 && len(p.groups) > 0 {
+		b.WriteString("\n")
+	}
+	for _, sitemap := range p.sitemaps {
+		b.WriteString("Sitemap: " + sitemap + "\n")
+	}
+	return b.String()
+}
+
+// RobotsUserAgents are the robots.txt user agent tokens of the crawlers in a bot category, used
+// by RobotsPolicy.ForConfig. Other categories do not read robots.txt.
+var RobotsUserAgents = map[BotCategory][]string{
+	BotCategoryCrawler: {"Googlebot", "Bingbot", "Slurp", "DuckDuckBot", "Baiduspider", "YandexBot", "Applebot"},
+	BotCategoryAI:      {"GPTBot", "ChatGPT-User", "OAI-SearchBot", "ClaudeBot", "Claude-User", "anthropic-ai", "Google-Extended", "CCBot", "PerplexityBot", "Bytespider"},
+}
+
+// ForConfig returns a copy of the policy telling crawlers what the middleware configuration
+// does with their category, so that the crawl policy follows the enforcement policy: categories
+// the Actions block, challenge or tarpit are disallowed everywhere, and those held to a rate by
+// the RateLimiter, or refused by ActionRateLimit without one, get a Crawl-delay on top of the
+// "*" rules. Tokens already named in the policy keep their groups.
+func (p *RobotsPolicy) ForConfig(config MiddlewareConfig) *RobotsPolicy {
+	policy := &RobotsPolicy{
+		ViolationTTL: p.ViolationTTL,
+		groups:       slices.Clone(p.groups),
+		sitemaps:     slices.Clone(p.sitemaps),
+	}
+	var named []string
+	var wildcard []robotsRule
+	for _, group := range p.groups {
+		for _, token := range group.userAgents {
+			if token == "*" {
+				wildcard = append(wildcard, group.rules...)
+			} else {
+				named = append(named, strings.ToLower(token))
+			}
+		}
+	}
+
+	categories := make([]BotCategory, 0, len(RobotsUserAgents))
+	for category := range RobotsUserAgents {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+	for _, category := range categories {
+		var userAgents []string
+		for _, token := range RobotsUserAgents[category] {
+			if !slices.Contains(named, strings.ToLower(token)) {
+				userAgents = append(userAgents, token)
+			}
+		}
+		if len(userAgents) == 0 {
+			continue
+		}
+
+		action := config.Actions[category]
+		if action == ActionBlock || action == ActionChallenge || action == ActionTarpit {
+			policy.Group(userAgents...).Disallow("/")
+			continue
+		}
+		delay := config.crawlDelay(category)
+		if action == ActionRateLimit && config.RateLimiter == nil {
+			delay = config.RateLimitResponse.retryAfter()
+		}
+		if delay > 0 {
+			group := policy.Group(userAgents...).CrawlDelay(delay)
+			group.rules = slices.Clone(wildcard)
+		}
+	}
+	return policy
+}
+
+// crawlDelay returns the interval between requests the RateLimiter allows the slowest bot kind
+// of the category, zero when they are not limited
+func (c MiddlewareConfig) crawlDelay(category BotCategory) time.Duration {
+	if c.RateLimiter == nil {
+		return 0
+	}
+	var perSecond float64
+	for kind, cat := range botKindCategories {
+		if cat != category {
+			continue
+		}
+		rate, _ := c.RateLimiter.rate(&BotDetectionResult{Bot: true, BotKind: kind})
+		if rate.PerSecond > 0 && (perSecond == 0 || rate.PerSecond < perSecond) {
+			perSecond = rate.PerSecond
+		}
+	}
+	if perSecond == 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / perSecond)
+}
+
+// ServeHTTP serves the policy as robots.txt
+func (p *RobotsPolicy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(p.String()))
+}
+
+// matchGroups returns the groups applying to the user agent: those naming the longest token it
+// contains, otherwise the "*" groups. The second result reports whether a token matched.
+func (p *RobotsPolicy) matchGroups(userAgent string) ([]*RobotsGroup, bool) {
+	userAgent = strings.ToLower(userAgent)
+	var (
+		named    []*RobotsGroup
+		wildcard []*RobotsGroup
+		longest  int
+	)
+	for _, group := range p.groups {
+		for _, token := range group.userAgents {
+			if token == "*" {
+				wildcard = append(wildcard, group)
+				break
+			}
+			if token == "" || !strings.Contains(userAgent, strings.ToLower(token)) {
+				continue
+			}
+			if len(token) > longest {
+				named, longest = nil, len(token)
+			}
+			if len(token) == longest {
+				named = append(named, group)
+			}
+			break
+		}
+	}
+	if len(named) > 0 {
+		return named, true
+	}
+	return wildcard, false
+}
+
+// Allowed reports whether the policy lets the user agent fetch the path, which includes the
+// query string. As in RFC 9309, the longest matching rule wins and Allow wins ties.
+func (p *RobotsPolicy) Allowed(userAgent, path string) bool {
+	if path == robotsTxtPath {
+		return true
+	}
+	groups, _ := p.matchGroups(userAgent)
+	allowed, longest := true, -1
+	for _, group := range groups {
+		for _, rule := range group.rules {
+			if rule.pattern == "" || !matchRobotsPattern(rule.pattern, path) {
+				continue
+			}
+			if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+				allowed, longest = rule.allow, len(rule.pattern)
+			}
+		}
+	}
+	return allowed
+}
+
+// matchRobotsPattern matches a path against a rule pattern, a prefix where "*" matches any
+// characters and a trailing "$" anchors the end
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// RobotsCompliance is a client's record of fetching paths robots.txt disallows for it
+type RobotsCompliance struct {
+	// Crawler is true when the client fetched robots.txt or its user agent is named in a group
+	Crawler bool `json:"crawler"`
+	// Violations is the number of disallowed paths the client fetched recently
+	Violations int64 `json:"violations"`
+}
+
+// getRobotsCompliance records robots.txt fetches and violations by crawlers in the store
+func getRobotsCompliance(req *http.Request, components *ComponentDict, tracker *SessionTracker, policy *RobotsPolicy) Component[RobotsCompliance] {
+	if tracker == nil || policy == nil {
+		return ErrorComponent[RobotsCompliance]{
+			State: StateUndefined,
+			Error: "robots.txt tracking requires a session tracker",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[RobotsCompliance]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	ttl := policy.ViolationTTL
+	if ttl <= 0 {
+		ttl = DefaultRobotsViolationTTL
+	}
+	ctx := req.Context()
+	readKey := tracker.Prefix + "robots:read:" + key
+	violationsKey := tracker.Prefix + "robots:violations:" + key
+	storeError := func(err error) Component[RobotsCompliance] {
+		return ErrorComponent[RobotsCompliance]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+
+	if req.URL.Path == robotsTxtPath {
+		if err := tracker.store.Set(ctx, readKey, []byte{1}, ttl); err != nil {
+			return storeError(err)
+		}
+		count, err := tracker.store.Incr(ctx, violationsKey, 0, ttl)
+		if err != nil {
+			return storeError(err)
+		}
+		return SuccessComponent[RobotsCompliance]{
+			State: StateSuccess,
+			Value: RobotsCompliance{Crawler: true, Violations: count},
+		}
+	}
+
+	userAgent := req.Header.Get("User-Agent")
+	_, named := policy.matchGroups(userAgent)
+	compliance := RobotsCompliance{Crawler: named}
+	if !named {
+		_, read, err := tracker.store.Get(ctx, readKey)
+		if err != nil {
+			return storeError(err)
+		}
+		compliance.Crawler = read
+	}
+	if !compliance.Crawler {
+		return SuccessComponent[RobotsCompliance]{
+			State: StateSuccess,
+			Value: compliance,
+		}
+	}
+
+	var delta int64
+	if !policy.Allowed(userAgent, req.URL.RequestURI()) {
+		delta = 1
+	}
+	count, err := tracker.store.Incr(ctx, violationsKey, delta, ttl)
+	if err != nil {
+		return storeError(err)
+	}
+	compliance.Violations = count
+	return SuccessComponent[RobotsCompliance]{
+		State: StateSuccess,
+		Value: compliance,
+	}
+}
+
+// detectRobotsViolation escalates the score of crawlers ignoring robots.txt: each violation
+// halves the remaining distance to certainty, so three flag the client on their own
+func detectRobotsViolation(components *ComponentDict) *BotDetectionResult {
+	if components.RobotsCompliance.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	compliance := components.RobotsCompliance.GetValue()
+	if !compliance.Crawler || compliance.Violations == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindRobotsViolation,
+		Score:   1 - math.Pow(0.5, float64(compliance.Violations)),
+	}
+}
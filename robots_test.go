@@ -0,0 +1,264 @@
+package gogobot
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRobotsPolicy() *RobotsPolicy {
+	policy := NewRobotsPolicy()
+	policy.Group("*").Disallow("/search", "/private/").Allow("/private/press")
+	policy.Group("GPTBot", "CCBot").Disallow("/")
+	policy.Group("Googlebot").Disallow("/*.json$").Allow("")
+	return policy
+}
+
+func TestRobotsPolicy_String(t *testing.T) {
+	expected := `User-agent: *
+Disallow: /search
+Disallow: /private/
+Allow: /private/press
+
+User-agent: GPTBot
+User-agent: CCBot
+Disallow: /
+
+User-agent: Googlebot
+Disallow: /*.json$
+Allow:
+`
+	if got := newTestRobotsPolicy().String(); got != expected {
+		t.Errorf("Unexpected robots.txt:\n%s", got)
+	}
+
+	rec := httptest.NewRecorder()
+	newTestRobotsPolicy().ServeHTTP(rec, httptest.NewRequest("GET", "/robots.txt", nil))
+	if rec.Header().Get("Content-Type") != "text/plain; charset=utf-8" || rec.Body.String() != expected {
+		t.Errorf("Unexpected robots.txt response %q", rec.Body.String())
+	}
+}
+
+func TestRobotsPolicy_CrawlDelayAndSitemap(t *testing.T) {
+	policy := NewRobotsPolicy().Sitemap("https://example.com/sitemap.xml")
+	policy.Group("Bingbot").CrawlDelay(1500 * time.Millisecond).Disallow("/search")
+
+	expected := `User-agent: Bingbot
+Crawl-delay: 1.5
+Disallow: /search
+
+Sitemap: https://example.com/sitemap.xml
+`
+	if got := policy.String(); got != expected {
+		t.Errorf("Unexpected robots.txt:\n%s", got)
+	}
+}
+
+func TestRobotsPolicy_ForConfig(t *testing.T) {
+	base := NewRobotsPolicy().Sitemap("https://example.com/sitemap.xml")
+	base.Group("*").Disallow("/search")
+	base.Group("CCBot").Disallow("/")
+
+	config := DefaultMiddlewareConfig()
+	config.Actions = map[BotCategory]Action{
+		BotCategoryCrawler: ActionAllow,
+		BotCategoryAI:      ActionBlock,
+	}
+	config.RateLimiter = NewRateLimiter(NewMemoryStore(0))
+	policy := base.ForConfig(config)
+
+	tests := []struct {
+		userAgent string
+		path      string
+		allowed   bool
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "/articles", true},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "/search?q=go", false},
+		{"Mozilla/5.0 (compatible; GPTBot/1.0; +https://openai.com/gptbot)", "/articles", false},
+		{"CCBot/2.0 (https://commoncrawl.org/faq/)", "/articles", false},
+		{"SomeCrawler/1.0", "/articles", true},
+	}
+	for _, tt := range tests {
+		if got := policy.Allowed(tt.userAgent, tt.path); got != tt.allowed {
+			t.Errorf("Expected Allowed(%q, %q) to be %v", tt.userAgent, tt.path, tt.allowed)
+		}
+	}
+
+	robots := policy.String()
+	for _, line := range []string{"User-agent: Googlebot\nUser-agent: Bingbot", "Crawl-delay: 0.2\nDisallow: /search\n", "User-agent: GPTBot\n", "Sitemap: https://example.com/sitemap.xml\n"} {
+		if !strings.Contains(robots, line) {
+			t.Errorf("Expected robots.txt to contain %q:\n%s", line, robots)
+		}
+	}
+	if strings.Count(robots, "User-agent: CCBot") != 1 {
+		t.Errorf("Expected the CCBot group to be kept:\n%s", robots)
+	}
+	if len(base.groups) != 2 {
+		t.Errorf("Expected the base policy to be left unchanged, got %d groups", len(base.groups))
+	}
+
+	// Without a rate limiter, rate limited crawlers are asked to wait as long as Retry-After
+	config = DefaultMiddlewareConfig()
+	config.Actions = map[BotCategory]Action{BotCategoryAI: ActionRateLimit}
+	robots = base.ForConfig(config).String()
+	if !strings.Contains(robots, "User-agent: GPTBot") || !strings.Contains(robots, "Crawl-delay: 60\n") {
+		t.Errorf("Expected AI crawlers to get the Retry-After as crawl delay:\n%s", robots)
+	}
+	if strings.Contains(robots, "User-agent: Googlebot") {
+		t.Errorf("Expected crawlers without a policy to follow the * group:\n%s", robots)
+	}
+}
+
+func TestRobotsPolicy_Allowed(t *testing.T) {
+	policy := newTestRobotsPolicy()
+	tests := []struct {
+		userAgent string
+		path      string
+		expected  bool
+	}{
+		{"SomeCrawler/1.0", "/", true},
+		{"SomeCrawler/1.0", "/search?q=x", false},
+		{"SomeCrawler/1.0", "/private/report", false},
+		{"SomeCrawler/1.0", "/private/press/2024", true},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.1; +https://openai.com/gptbot)", "/", false},
+		{"CCBot/2.0 (https://commoncrawl.org/faq/)", "/about", false},
+		{"GPTBot/1.1", "/robots.txt", true},
+		// A named group replaces the "*" group
+		{"Googlebot/2.1", "/search", true},
+		{"Googlebot/2.1", "/data/feed.json", false},
+		{"Googlebot/2.1", "/data/feed.json?v=2", true},
+	}
+	for _, tt := range tests {
+		if got := policy.Allowed(tt.userAgent, tt.path); got != tt.expected {
+			t.Errorf("Allowed(%q, %q) = %t, expected %t", tt.userAgent, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestMatchRobotsPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"/fish", "/fish.html", true},
+		{"/fish", "/Fish.asp", false},
+		{"/fish*", "/fishheads", true},
+		{"/*.php", "/index.php?x=1", true},
+		{"/*.php$", "/index.php?x=1", false},
+		{"/*.php$", "/folder/filename.php", true},
+		{"/fish*.php", "/fishheads/catfish.php?parameters", true},
+		{"/fish*.php", "/Fish.PHP", false},
+		{"/a*b*c$", "/axxbyyc", true},
+		{"/a*b*c$", "/axxbyycd", false},
+		{"/$", "/", true},
+		{"/$", "/page", false},
+	}
+	for _, tt := range tests {
+		if got := matchRobotsPattern(tt.pattern, tt.path); got != tt.expected {
+			t.Errorf("matchRobotsPattern(%q, %q) = %t, expected %t", tt.pattern, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestGetRobotsCompliance(t *testing.T) {
+	tracker := NewSessionTracker(NewMemoryStore(0))
+	policy := newTestRobotsPolicy()
+
+	collect := func(remoteAddr, userAgent, target string) RobotsCompliance {
+		req := httptest.NewRequest("GET", target, nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", userAgent)
+		compliance := getRobotsCompliance(req, collectAllSources(req), tracker, policy)
+		if compliance.GetState() != StateSuccess {
+			t.Fatalf("Expected robots compliance, got %v", compliance)
+		}
+		return compliance.GetValue()
+	}
+
+	// People browsing disallowed pages are not held to robots.txt
+	if compliance := collect("203.0.113.7:1234", testBrowserUA, "/search?q=shoes"); compliance.Crawler || compliance.Violations != 0 {
+		t.Errorf("Expected browser not to be held to robots.txt, got %+v", compliance)
+	}
+
+	// A client reading robots.txt is, and violations persist over later requests
+	if compliance := collect("198.51.100.9:1234", "SomeCrawler/1.0", "/robots.txt"); !compliance.Crawler || compliance.Violations != 0 {
+		t.Errorf("Expected robots.txt fetch to mark a crawler, got %+v", compliance)
+	}
+	collect("198.51.100.9:1234", "SomeCrawler/1.0", "/search?q=1")
+	collect("198.51.100.9:1234", "SomeCrawler/1.0", "/private/report")
+	if compliance := collect("198.51.100.9:1234", "SomeCrawler/1.0", "/"); compliance.Violations != 2 {
+		t.Errorf("Expected 2 violations, got %+v", compliance)
+	}
+
+	// A user agent named in a group is held to it without reading robots.txt
+	if compliance := collect("192.0.2.50:1234", "GPTBot/1.1", "/articles/1"); !compliance.Crawler || compliance.Violations != 1 {
+		t.Errorf("Expected named crawler violation, got %+v", compliance)
+	}
+}
+
+func TestGetRobotsCompliance_Undefined(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if compliance := getRobotsCompliance(req, collectAllSources(req), nil, NewRobotsPolicy()); compliance.GetState() != StateUndefined {
+		t.Errorf("Expected undefined compliance without a tracker, got %v", compliance.GetState())
+	}
+}
+
+func TestDetectRobotsViolation(t *testing.T) {
+	tests := []struct {
+		name       string
+		compliance RobotsCompliance
+		expected   float64
+	}{
+		{"compliant crawler", RobotsCompliance{Crawler: true}, 0},
+		{"not a crawler", RobotsCompliance{Violations: 3}, 0},
+		{"one violation", RobotsCompliance{Crawler: true, Violations: 1}, 0.5},
+		{"three violations", RobotsCompliance{Crawler: true, Violations: 3}, 0.875},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := &ComponentDict{RobotsCompliance: SuccessComponent[RobotsCompliance]{State: StateSuccess, Value: tt.compliance}}
+			result := detectRobotsViolation(components)
+			if result.Bot || math.Abs(result.Score-tt.expected) > 1e-9 {
+				t.Errorf("Expected score %f, got %+v", tt.expected, result)
+			}
+			if tt.expected > 0 && result.BotKind != BotKindRobotsViolation {
+				t.Errorf("Expected robots_violation, got %q", result.BotKind)
+			}
+		})
+	}
+}
+
+func TestDetector_RobotsViolation(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.RobotsPolicy = newTestRobotsPolicy()
+	detector := NewDetectorWithConfig(config)
+
+	detect := func(target string) BotDetectionResult {
+		req := httptest.NewRequest("GET", target, nil)
+		req.RemoteAddr = "198.51.100.9:1234"
+		req.Header.Set("User-Agent", testBrowserUA)
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Accept-Language", "en-US")
+		req.Header.Set("Accept-Encoding", "gzip")
+		result, err := detector.DetectFromRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	// A browser-like scraper that read robots.txt and ignores it
+	detect("/robots.txt")
+	for _, target := range []string{"/search?q=1", "/search?q=2"} {
+		if result := detect(target); result.Bot {
+			t.Fatalf("Expected %s not to be flagged yet, got %+v", target, result)
+		}
+	}
+	if result := detect("/private/x"); !result.Bot {
+		t.Errorf("Expected third violation to flag the client, got %+v", result)
+	}
+}
@@ -0,0 +1,68 @@
+package gogobot
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RoutePolicy applies its own middleware configuration to the requests whose path it matches,
+// so one middleware can be lenient on an API, strict on an admin area and skip static files
+type RoutePolicy struct {
+	// Match reports whether the policy applies to a request path; see MatchGlob and MatchRegexp
+	Match func(path string) bool
+	// Skip serves matching requests without detection
+	Skip bool
+	// Config replaces the middleware configuration for matching requests; its Routes are ignored,
+	// and the base configuration's Observer, Logger and Expvar are used when it leaves them unset
+	Config MiddlewareConfig
+}
+
+// MatchGlob returns a path matcher for a glob pattern, in which "*" matches any characters but
+// "/", "**" any characters including "/", and "?" a single character but "/". "/api/**" matches
+// every path below /api/, and "/static/*.css" the stylesheets directly in /static/.
+func MatchGlob(pattern string) func(path string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return MatchRegexp(regexp.MustCompile(expr.String()))
+}
+
+// MatchRegexp returns a path matcher for a regular expression, which matches anywhere in the
+// path unless anchored
+func MatchRegexp(re *regexp.Regexp) func(path string) bool {
+	return re.MatchString
+}
+
+// route returns the configuration applying to path, and whether detection is skipped. Routes
+// inherit the monitoring of the base configuration, so that no route goes unobserved.
+func (c MiddlewareConfig) route(path string) (MiddlewareConfig, bool) {
+	for _, policy := range c.Routes {
+		if policy.Match != nil && policy.Match(path) {
+			config := policy.Config
+			if config.Observer == nil {
+				config.Observer = c.Observer
+			}
+			if config.Logger == nil {
+				config.Logger = c.Logger
+			}
+			if config.Expvar == nil {
+				config.Expvar = c.Expvar
+			}
+			return config, policy.Skip
+		}
+	}
+	return c, false
+}
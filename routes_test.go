@@ -0,0 +1,117 @@
+package gogobot
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"/api/**", "/api/v1/users", true},
+		{"/api/**", "/api/", true},
+		{"/api/**", "/apix", false},
+		{"/static/*.css", "/static/site.css", true},
+		{"/static/*.css", "/static/css/site.css", false},
+		{"/users/?", "/users/1", true},
+		{"/users/?", "/users/12", false},
+		{"/a+b/(x)", "/a+b/(x)", true},
+		{"/a+b/(x)", "/aab/x", false},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern)(tt.path); got != tt.expected {
+			t.Errorf("Expected %s to match %s: %t, got %t", tt.pattern, tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestMiddleware_Routes(t *testing.T) {
+	strict := DefaultMiddlewareConfig()
+	strict.BlockBots = true
+	strict.BlockedStatusCode = http.StatusUnauthorized
+
+	config := DefaultMiddlewareConfig()
+	config.BlockBots = true
+	config.Routes = []RoutePolicy{
+		{Match: MatchGlob("/static/**"), Skip: true},
+		{Match: MatchRegexp(regexp.MustCompile(`^/admin(/|$)`)), Config: strict},
+		{Match: MatchGlob("/api/**"), Config: DefaultMiddlewareConfig()},
+	}
+
+	var detected bool
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, detected = GetResultFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		path     string
+		status   int
+		detected bool
+	}{
+		{"/static/app.js", http.StatusOK, false},
+		{"/admin/users", http.StatusUnauthorized, false},
+		{"/api/v1/items", http.StatusOK, true},
+		{"/page", http.StatusForbidden, false},
+	}
+	for _, tt := range tests {
+		detected = false
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status || detected != tt.detected {
+			t.Errorf("%s: expected status %d and detected %t, got %d and %t", tt.path, tt.status, tt.detected, rec.Code, detected)
+		}
+	}
+}
+
+func TestMiddleware_RoutesInheritMonitoring(t *testing.T) {
+	var observed, ownObserved []string
+	var logs bytes.Buffer
+	counters, _ := publishTestExpvar(t)
+	config := DefaultMiddlewareConfig()
+	config.Observer = ObserverFunc(func(r *http.Request, observation Observation) {
+		observed = append(observed, r.URL.Path)
+	})
+	config.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	config.Expvar = counters
+
+	strict := DefaultMiddlewareConfig()
+	strict.BlockBots = true
+	own := DefaultMiddlewareConfig()
+	own.Observer = ObserverFunc(func(r *http.Request, observation Observation) {
+		ownObserved = append(ownObserved, r.URL.Path)
+	})
+	config.Routes = []RoutePolicy{
+		{Match: MatchGlob("/admin/**"), Config: strict},
+		{Match: MatchGlob("/api/**"), Config: own},
+	}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, path := range []string{"/admin/users", "/api/items"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if len(observed) != 1 || observed[0] != "/admin/users" {
+		t.Errorf("Expected the route without an observer to use the base one, got %v", observed)
+	}
+	if len(ownObserved) != 1 || ownObserved[0] != "/api/items" {
+		t.Errorf("Expected the route's own observer, got %v", ownObserved)
+	}
+	if !strings.Contains(logs.String(), "path=/admin/users") || !strings.Contains(logs.String(), "path=/api/items") {
+		t.Errorf("Expected both routes to be logged, got %q", logs.String())
+	}
+	if got := counters.requests.Value(); got != 2 {
+		t.Errorf("Expected both routes to be counted, got %d", got)
+	}
+}
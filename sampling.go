@@ -0,0 +1,40 @@
+package gogobot
+
+import (
+	"math/rand/v2"
+	"net/http"
+)
+
+// sampleFloat returns a pseudo-random number in [0, 1); tests replace it
+var sampleFloat = rand.Float64
+
+// sampled reports whether a request falls within the configured SampleRate
+func (c MiddlewareConfig) sampled() bool {
+	if c.SampleRate <= 0 || c.SampleRate >= 1 {
+		return true
+	}
+	return sampleFloat() < c.SampleRate
+}
+
+// unsampledAccess checks the client IP of a request outside the sample against the access list
+func (d *BotDetector) unsampledAccess(req *http.Request) IPAccess {
+	if d.config.IPAccessList == nil {
+		return IPAccessNone
+	}
+	clientIP, _ := d.resolveClientIP(req)
+	return getIPAccess(clientIP, d.config.IPAccessList).GetValue()
+}
+
+// detectUnsampled is the cheap check of requests outside the sample: the access list verdict
+// applies as it does to sampled requests, and otherwise only the user agent is classified,
+// using the user agent cache when one is enabled
+func detectUnsampled(req *http.Request, access IPAccess) BotDetectionResult {
+	switch access {
+	case IPAccessAllow:
+		return BotDetectionResult{Bot: false, Unsampled: true}
+	case IPAccessDeny:
+		return BotDetectionResult{Bot: true, BotKind: BotKindDenylisted, Unsampled: true}
+	}
+	isBot, botKind := IsBotUserAgent(req.UserAgent())
+	return BotDetectionResult{Bot: isBot, BotKind: botKind, Unsampled: true}
+}
@@ -0,0 +1,138 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware_SampleRate(t *testing.T) {
+	defer func(original func() float64) { sampleFloat = original }(sampleFloat)
+
+	config := DefaultMiddlewareConfig()
+	config.SampleRate = 0.1
+	var (
+		result     *BotDetectionResult
+		components *ComponentDict
+	)
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, _ = GetResultFromContext(r.Context())
+		components, _ = GetComponentsFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name      string
+		sample    float64
+		userAgent string
+		bot       bool
+		unsampled bool
+	}{
+		{"sampled", 0.05, "curl/8.4.0", true, false},
+		{"unsampled bot", 0.5, "curl/8.4.0", true, true},
+		{"unsampled browser", 0.5, testBrowserUA, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampleFloat = func() float64 { return tt.sample }
+			result, components = nil, nil
+			req := newBrowserRequest("/", "203.0.113.7:1234")
+			req.Header.Set("User-Agent", tt.userAgent)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if result == nil || result.Bot != tt.bot || result.Unsampled != tt.unsampled {
+				t.Fatalf("Expected bot %t and unsampled %t, got %+v", tt.bot, tt.unsampled, result)
+			}
+			if (components == nil) != tt.unsampled {
+				t.Errorf("Expected components only for sampled requests")
+			}
+		})
+	}
+}
+
+func TestMiddleware_SkipUnsampled(t *testing.T) {
+	defer func(original func() float64) { sampleFloat = original }(sampleFloat)
+	sampleFloat = func() float64 { return 0.99 }
+
+	config := DefaultMiddlewareConfig()
+	config.SampleRate = 0.5
+	config.SkipUnsampled = true
+	config.BlockBots = true
+	var detected bool
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, detected = GetResultFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || detected {
+		t.Errorf("Expected unsampled request to skip detection, got %d and detected %t", rec.Code, detected)
+	}
+}
+
+func TestMiddleware_SampleRateAlwaysDetects(t *testing.T) {
+	defer func(original func() float64) { sampleFloat = original }(sampleFloat)
+	sampleFloat = func() float64 { return 0.5 }
+
+	detectorConfig := DefaultDetectorConfig()
+	detectorConfig.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	detectorConfig.Honeypot = NewHoneypot("/.well-known/trap")
+	config := DefaultMiddlewareConfig()
+	config.SampleRate = 1e-6
+	config.BlockBots = true
+	handler := NewDetectorWithConfig(detectorConfig).MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newBrowserRequest(path, "203.0.113.7:1234"))
+		return rec.Code
+	}
+
+	// The trap hit runs the full detector set although the request is outside the sample
+	if code := serve("/.well-known/trap"); code != http.StatusForbidden {
+		t.Errorf("Expected unsampled trap hit to be blocked, got %d", code)
+	}
+	sampleFloat = func() float64 { return 0 }
+	if code := serve("/"); code != http.StatusForbidden {
+		t.Errorf("Expected trapped visitor to be blocked, got %d", code)
+	}
+}
+
+func TestMiddleware_SampleRateAccessList(t *testing.T) {
+	defer func(original func() float64) { sampleFloat = original }(sampleFloat)
+	sampleFloat = func() float64 { return 0.5 }
+
+	list, _ := NewIPAccessList([]string{"192.0.2.0/24"}, []string{"198.51.100.0/24"})
+	detectorConfig := DefaultDetectorConfig()
+	detectorConfig.IPAccessList = list
+	detector := NewDetectorWithConfig(detectorConfig)
+
+	tests := []struct {
+		name          string
+		skipUnsampled bool
+		remoteAddr    string
+		userAgent     string
+		expected      int
+	}{
+		{"denied browser", false, "198.51.100.10:1234", testBrowserUA, http.StatusForbidden},
+		{"denied browser skipping unsampled", true, "198.51.100.10:1234", testBrowserUA, http.StatusForbidden},
+		{"allowed curl", false, "192.0.2.10:1234", "curl/8.4.0", http.StatusOK},
+		{"unlisted browser", false, "203.0.113.7:1234", testBrowserUA, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultMiddlewareConfig()
+			config.SampleRate = 0.1
+			config.SkipUnsampled = tt.skipUnsampled
+			config.BlockBots = true
+			handler := detector.MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			req := newBrowserRequest("/", tt.remoteAddr)
+			req.Header.Set("User-Agent", tt.userAgent)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package gogobot
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SecFetch holds the Fetch Metadata request headers, which browsers add to every request in a
+// secure context
+type SecFetch struct {
+	Site string `json:"site,omitempty"`
+	Mode string `json:"mode,omitempty"`
+	Dest string `json:"dest,omitempty"`
+	User string `json:"user,omitempty"`
+	// Secure is true when the request was made over HTTPS, directly or as reported by a proxy
+	// in X-Forwarded-Proto or Forwarded; browsers only send the headers to secure origins
+	Secure bool `json:"secure"`
+}
+
+// Present reports whether any of the headers was sent
+func (s SecFetch) Present() bool {
+	return s.Site != "" || s.Mode != "" || s.Dest != "" || s.User != ""
+}
+
+// Values browsers send in Sec-Fetch-Site and Sec-Fetch-Mode. Sec-Fetch-Dest is not checked, as
+// new destinations are added with new web platform features.
+var (
+	secFetchSites = []string{"cross-site", "same-origin", "same-site", "none"}
+	secFetchModes = []string{"cors", "navigate", "no-cors", "same-origin", "websocket"}
+)
+
+// navigationDests are the destinations of navigations, which browsers fetch in navigate mode
+var navigationDests = []string{"document", "iframe", "frame", "embed", "object", "fencedframe"}
+
+// isSecureRequest reports whether the request was made over HTTPS, directly or as reported by a
+// proxy in X-Forwarded-Proto or Forwarded
+func isSecureRequest(req *http.Request) bool {
+	return req.TLS != nil ||
+		strings.EqualFold(req.Header.Get("X-Forwarded-Proto"), "https") ||
+		strings.Contains(strings.ToLower(req.Header.Get("Forwarded")), "proto=https")
+}
+
+func getSecFetch(req *http.Request) Component[SecFetch] {
+	return SuccessComponent[SecFetch]{
+		State: StateSuccess,
+		Value: SecFetch{
+			Site:   req.Header.Get("Sec-Fetch-Site"),
+			Mode:   req.Header.Get("Sec-Fetch-Mode"),
+			Dest:   req.Header.Get("Sec-Fetch-Dest"),
+			User:   req.Header.Get("Sec-Fetch-User"),
+			Secure: isSecureRequest(req),
+		},
+	}
+}
+
+// engineVersionPattern extracts the Chrome, Firefox and Safari version tokens
+var engineVersionPattern = regexp.MustCompile(`(?i)\b(chrome|firefox|version)/([0-9]+)(?:\.([0-9]+))?`)
+
+// engineVersions returns the major and minor versions of the Chrome, Firefox and Safari
+// ("version") tokens of a user agent, keyed by lower-case token
+func engineVersions(userAgent string) map[string][2]int {
+	versions := make(map[string][2]int)
+	for _, match := range engineVersionPattern.FindAllStringSubmatch(userAgent, -1) {
+		major, _ := strconv.Atoi(match[2])
+		minor, _ := strconv.Atoi(match[3])
+		versions[strings.ToLower(match[1])] = [2]int{major, minor}
+	}
+	return versions
+}
+
+// sendsSecFetch reports whether the browser the user agent claims sends Fetch Metadata:
+// Chromium 76, Firefox 90 and Safari 16.4 onwards
+func sendsSecFetch(userAgent string) bool {
+	versions := engineVersions(userAgent)
+
+	switch parseEngine(userAgent) {
+	case EngineBlink:
+		version, ok := versions["chrome"]
+		return ok && version[0] >= 76
+	case EngineGecko:
+		version, ok := versions["firefox"]
+		return ok && version[0] >= 90
+	case EngineWebKit:
+		name, _ := parseBrowserNameAndVersion(userAgent)
+		version, ok := versions["version"]
+		return name == BrowserSafari && ok && (version[0] > 16 || version[0] == 16 && version[1] >= 4)
+	}
+	return false
+}
+
+// secFetchContradiction returns a description of a combination no browser sends, or ""
+func secFetchContradiction(fetch SecFetch) string {
+	switch {
+	case fetch.Site == "" || fetch.Mode == "":
+		// Browsers always send both, and Dest since they send any
+		return "incomplete Sec-Fetch headers"
+	case !containsFold(secFetchSites, fetch.Site):
+		return "invalid Sec-Fetch-Site " + strconv.Quote(fetch.Site)
+	case !containsFold(secFetchModes, fetch.Mode):
+		return "invalid Sec-Fetch-Mode " + strconv.Quote(fetch.Mode)
+	case fetch.User != "" && fetch.User != "?1":
+		return "invalid Sec-Fetch-User " + strconv.Quote(fetch.User)
+	case fetch.User != "" && fetch.Mode != "navigate":
+		return "Sec-Fetch-User outside a navigation"
+	case fetch.Mode == "navigate" && fetch.Dest != "" && !containsFold(navigationDests, fetch.Dest):
+		return "navigation to Sec-Fetch-Dest " + strconv.Quote(fetch.Dest)
+	case fetch.Mode != "navigate" && (fetch.Dest == "document" || fetch.Dest == "iframe" || fetch.Dest == "frame"):
+		return "Sec-Fetch-Dest " + fetch.Dest + " in " + fetch.Mode + " mode"
+	}
+	return ""
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// secFetchSignalWeight is the score of HTTPS requests from browser user agents without Fetch
+// Metadata. Privacy extensions and corporate proxies strip the headers, so together with the
+// datacenter signal of a VPN or proxy IP it stays below the default threshold.
+const secFetchSignalWeight = 0.4
+
+// detectSecFetch flags Fetch Metadata no browser sends, and scores secure requests from user
+// agents claiming a browser that sends it without any
+func detectSecFetch(components *ComponentDict) *BotDetectionResult {
+	if components.SecFetch.GetState() != StateSuccess || components.UserAgent.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+	userAgent := components.UserAgent.GetValue()
+	if isBot, _ := IsBotUserAgent(userAgent); isBot {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	fetch := components.SecFetch.GetValue()
+	if !fetch.Present() {
+		if fetch.Secure && sendsSecFetch(userAgent) {
+			return &BotDetectionResult{
+				Bot:     false,
+				BotKind: BotKindUnknown,
+				Score:   secFetchSignalWeight,
+				Reasons: []string{"browser user agent without Sec-Fetch headers"},
+			}
+		}
+		return &BotDetectionResult{Bot: false}
+	}
+	if reason := secFetchContradiction(fetch); reason != "" {
+		return &BotDetectionResult{
+			Bot:     true,
+			BotKind: BotKindUnknown,
+			Reasons: []string{reason},
+		}
+	}
+	return &BotDetectionResult{Bot: false}
+}
@@ -0,0 +1,113 @@
+package gogobot
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestSendsSecFetch(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  bool
+	}{
+		{testBrowserUA, true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3770.100 Safari/537.36", false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:89.0) Gecko/20100101 Firefox/89.0", false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15", true},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.3 Safari/605.1.15", false},
+		{"custom-client/1.0", false},
+	}
+	for _, tt := range tests {
+		if got := sendsSecFetch(tt.userAgent); got != tt.expected {
+			t.Errorf("Expected %t for %q, got %t", tt.expected, tt.userAgent, got)
+		}
+	}
+}
+
+func TestGetSecFetch(t *testing.T) {
+	req := newBrowserRequest("/", "203.0.113.7:1234")
+	req.Header.Set("Sec-Fetch-Site", "none")
+	req.Header.Set("Sec-Fetch-Mode", "navigate")
+	if fetch := getSecFetch(req).GetValue(); fetch.Site != "none" || fetch.Mode != "navigate" || fetch.Secure {
+		t.Errorf("Expected plain HTTP Sec-Fetch headers, got %+v", fetch)
+	}
+
+	req.Header.Set("X-Forwarded-Proto", "https")
+	if fetch := getSecFetch(req).GetValue(); !fetch.Secure {
+		t.Error("Expected X-Forwarded-Proto https to be secure")
+	}
+	req.Header.Del("X-Forwarded-Proto")
+	req.Header.Set("Forwarded", "for=192.0.2.60;proto=https")
+	if fetch := getSecFetch(req).GetValue(); !fetch.Secure {
+		t.Error("Expected Forwarded proto=https to be secure")
+	}
+	req.Header.Del("Forwarded")
+	req.TLS = &tls.ConnectionState{}
+	if fetch := getSecFetch(req).GetValue(); !fetch.Secure {
+		t.Error("Expected TLS request to be secure")
+	}
+}
+
+func TestDetectSecFetch(t *testing.T) {
+	navigation := SecFetch{Site: "none", Mode: "navigate", Dest: "document", User: "?1", Secure: true}
+	tests := []struct {
+		name      string
+		userAgent string
+		fetch     SecFetch
+		bot       bool
+		score     float64
+	}{
+		{"navigation", testBrowserUA, navigation, false, 0},
+		{"subresource", testBrowserUA, SecFetch{Site: "same-origin", Mode: "no-cors", Dest: "image", Secure: true}, false, 0},
+		{"missing over HTTPS", testBrowserUA, SecFetch{Secure: true}, false, secFetchSignalWeight},
+		{"missing over HTTP", testBrowserUA, SecFetch{}, false, 0},
+		{"missing from an old browser", "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:89.0) Gecko/20100101 Firefox/89.0", SecFetch{Secure: true}, false, 0},
+		{"document in cors mode", testBrowserUA, SecFetch{Site: "same-origin", Mode: "cors", Dest: "document"}, true, 0},
+		{"navigation to a script", testBrowserUA, SecFetch{Site: "same-origin", Mode: "navigate", Dest: "script"}, true, 0},
+		{"user activation outside a navigation", testBrowserUA, SecFetch{Site: "same-origin", Mode: "cors", Dest: "empty", User: "?1"}, true, 0},
+		{"invalid site", testBrowserUA, SecFetch{Site: "anywhere", Mode: "navigate", Dest: "document"}, true, 0},
+		{"mode only", testBrowserUA, SecFetch{Mode: "navigate"}, true, 0},
+		{"declared bot", "curl/8.4.0", SecFetch{Mode: "navigate"}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detectSecFetch(&ComponentDict{
+				UserAgent: SuccessComponent[string]{State: StateSuccess, Value: tt.userAgent},
+				SecFetch:  SuccessComponent[SecFetch]{State: StateSuccess, Value: tt.fetch},
+			})
+			if result.Bot != tt.bot || result.Score != tt.score {
+				t.Errorf("Expected bot=%t score=%v, got %+v", tt.bot, tt.score, result)
+			}
+			if (tt.bot || tt.score > 0) && len(result.Reasons) != 1 {
+				t.Errorf("Expected a reason, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestDetectSecFetch_DatacenterProxy(t *testing.T) {
+	// A browser behind a cloud VPN with an extension stripping Fetch Metadata raises both default
+	// soft signals, which must not flag it without configuration
+	req := createTestRequest("GET", "/", map[string]string{
+		"User-Agent":        testBrowserUA,
+		"Accept":            "text/html",
+		"Accept-Language":   "en-US,en;q=0.9",
+		"Accept-Encoding":   "gzip, deflate, br",
+		"X-Forwarded-Proto": "https",
+	})
+	req.RemoteAddr = "54.239.123.45:443"
+
+	detector := NewDetector()
+	result, err := detector.DetectFromRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	detections := detector.GetDetections()
+	if detections.SecFetch.Score == 0 || detections.Datacenter.Score == 0 {
+		t.Fatalf("Expected both signals, got %+v and %+v", detections.SecFetch, detections.Datacenter)
+	}
+	if result.Bot || result.Score >= DefaultScoreThreshold {
+		t.Errorf("Expected the combined signals to stay below the threshold, got %+v", result)
+	}
+}
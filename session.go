@@ -0,0 +1,209 @@
+package gogobot
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SessionKeyFunc returns the key identifying the visitor making a request, or "" if the
+// request cannot be attributed to a visitor
+type SessionKeyFunc func(req *http.Request, components *ComponentDict) string
+
+// SessionKeyByIP keys visitors by client IP, grouped with aggregation (see IPAggregation)
+func SessionKeyByIP(aggregation IPAggregation) SessionKeyFunc {
+	return func(_ *http.Request, components *ComponentDict) string {
+		addr, ok := components.ClientIP()
+		if !ok {
+			return ""
+		}
+		return "ip:" + aggregation.Key(addr)
+	}
+}
+
+// SessionKeyByCookie keys visitors by the value of a cookie, falling back to fallback (which
+// may be nil) for requests without it. Cookies are chosen by the client, so a scraper can
+// shed its history by dropping them; pair cookie keys with IP-keyed detectors.
+func SessionKeyByCookie(name string, fallback SessionKeyFunc) SessionKeyFunc {
+	return func(req *http.Request, components *ComponentDict) string {
+		if cookie, err := req.Cookie(name); err == nil && cookie.Value != "" {
+			return "cookie:" + cookie.Value
+		}
+		if fallback != nil {
+			return fallback(req, components)
+		}
+		return ""
+	}
+}
+
+// Session summarizes a visitor's activity across requests, including the current one
+type Session struct {
+	// Key identifies the visitor (see SessionKeyFunc)
+	Key string `json:"key"`
+	// Requests is the number of requests in the current window
+	Requests int64 `json:"requests"`
+	// TotalRequests is the number of requests since the session started
+	TotalRequests int64 `json:"totalRequests"`
+	// FirstSeen is the time of the session's first request
+	FirstSeen time.Time `json:"firstSeen"`
+	// PreviousSeen is the time of the visitor's previous request, zero for the first one
+	PreviousSeen time.Time `json:"previousSeen,omitzero"`
+	// Window is the length of the window Requests is counted over
+	Window time.Duration `json:"window"`
+}
+
+// IsNew reports whether the current request is the visitor's first
+func (s Session) IsNew() bool {
+	return s.PreviousSeen.IsZero()
+}
+
+// Default session tracking parameters
+const (
+	DefaultSessionWindow = time.Minute
+	DefaultSessionTTL    = 30 * time.Minute
+)
+
+// SessionTracker records each visitor's requests in a Store and exposes the accumulated
+// activity to detectors as the Session component
+type SessionTracker struct {
+	store Store
+	// KeyFunc identifies visitors; nil keys by client IP with DefaultIPAggregation
+	KeyFunc SessionKeyFunc
+	// Window is the length of the fixed window requests are counted over
+	Window time.Duration
+	// TTL is how long a session is kept after its last request
+	TTL time.Duration
+	// Prefix is prepended to all store keys
+	Prefix string
+
+	now func() time.Time
+}
+
+// NewSessionTracker creates a tracker storing sessions in store, keyed by client IP
+func NewSessionTracker(store Store) *SessionTracker {
+	return &SessionTracker{
+		store:   store,
+		KeyFunc: SessionKeyByIP(DefaultIPAggregation()),
+		Window:  DefaultSessionWindow,
+		TTL:     DefaultSessionTTL,
+		Prefix:  "gogobot:session:",
+		now:     time.Now,
+	}
+}
+
+// Store returns the store sessions are kept in, for detectors keeping their own state
+func (t *SessionTracker) Store() Store {
+	return t.store
+}
+
+// key returns the visitor key for the request
+func (t *SessionTracker) key(req *http.Request, components *ComponentDict) string {
+	if t.KeyFunc == nil {
+		return SessionKeyByIP(DefaultIPAggregation())(req, components)
+	}
+	return t.KeyFunc(req, components)
+}
+
+// windowBucket numbers the fixed window of the given length containing now
+func windowBucket(now time.Time, window time.Duration) string {
+	return strconv.FormatInt(now.UnixNano()/int64(window), 10)
+}
+
+// errNoSessionKey is returned when a request cannot be attributed to a visitor
+var errNoSessionKey = errors.New("request has no session key")
+
+// Track records the request and returns the visitor's session
+func (t *SessionTracker) Track(ctx context.Context, req *http.Request, components *ComponentDict) (Session, error) {
+	key := t.key(req, components)
+	if key == "" {
+		return Session{}, errNoSessionKey
+	}
+
+	window := t.Window
+	if window <= 0 {
+		window = DefaultSessionWindow
+	}
+	ttl := t.TTL
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	now := t.now()
+	session := Session{Key: key, Window: window}
+
+	var err error
+	session.Requests, err = t.store.Incr(ctx, t.Prefix+"requests:"+key+":"+windowBucket(now, window), 1, 2*window)
+	if err != nil {
+		return Session{}, err
+	}
+
+	// The session state holds the first and last request times; it is rewritten on every
+	// request so that it expires ttl after the last one
+	stateKey := t.Prefix + "state:" + key
+	state, ok, err := t.store.Get(ctx, stateKey)
+	if err != nil {
+		return Session{}, err
+	}
+	session.FirstSeen = now
+	if ok {
+		first, last, total, valid := decodeSessionState(state)
+		if valid {
+			session.FirstSeen, session.PreviousSeen, session.TotalRequests = first, last, total
+		}
+	}
+	session.TotalRequests++
+
+	if err := t.store.Set(ctx, stateKey, encodeSessionState(session.FirstSeen, now, session.TotalRequests), ttl); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
+
+// encodeSessionState packs the session timestamps and request count into 24 bytes
+func encodeSessionState(first, last time.Time, total int64) []byte {
+	buf := make([]byte, 24)
+	binary.BigEndian.PutUint64(buf[0:], uint64(first.UnixNano()))
+	binary.BigEndian.PutUint64(buf[8:], uint64(last.UnixNano()))
+	binary.BigEndian.PutUint64(buf[16:], uint64(total))
+	return buf
+}
+
+func decodeSessionState(buf []byte) (first, last time.Time, total int64, ok bool) {
+	if len(buf) != 24 {
+		return time.Time{}, time.Time{}, 0, false
+	}
+	first = time.Unix(0, int64(binary.BigEndian.Uint64(buf[0:])))
+	last = time.Unix(0, int64(binary.BigEndian.Uint64(buf[8:])))
+	total = int64(binary.BigEndian.Uint64(buf[16:]))
+	return first, last, total, true
+}
+
+// getSession records the request with tracker and returns the visitor's session
+func getSession(req *http.Request, components *ComponentDict, tracker *SessionTracker) Component[Session] {
+	if tracker == nil {
+		return ErrorComponent[Session]{
+			State: StateUndefined,
+			Error: "no session tracker configured",
+		}
+	}
+
+	session, err := tracker.Track(req.Context(), req, components)
+	if errors.Is(err, errNoSessionKey) {
+		return ErrorComponent[Session]{
+			State: StateNull,
+			Error: err.Error(),
+		}
+	}
+	if err != nil {
+		return ErrorComponent[Session]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	return SuccessComponent[Session]{
+		State: StateSuccess,
+		Value: session,
+	}
+}
@@ -0,0 +1,183 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapStore is a minimal Store for tests; it ignores TTLs
+type mapStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	counts map[string]int64
+	sets   map[string]map[string]bool
+	err    error
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{
+		values: make(map[string][]byte),
+		counts: make(map[string]int64),
+		sets:   make(map[string]map[string]bool),
+	}
+}
+
+func (s *mapStore) Incr(_ context.Context, key string, delta int64, _ time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.counts[key] += delta
+	return s.counts[key], nil
+}
+
+func (s *mapStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok, s.err
+}
+
+func (s *mapStore) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	return s.err
+}
+
+func (s *mapStore) AddToSet(_ context.Context, key, member string, _ time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sets[key] == nil {
+		s.sets[key] = make(map[string]bool)
+	}
+	s.sets[key][member] = true
+	return int64(len(s.sets[key])), s.err
+}
+
+func (s *mapStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	delete(s.counts, key)
+	delete(s.sets, key)
+	return s.err
+}
+
+func TestSessionTracker_Track(t *testing.T) {
+	tracker := NewSessionTracker(newMapStore())
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	components := collectAllSources(req)
+
+	session, err := tracker.Track(context.Background(), req, components)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !session.IsNew() || session.Requests != 1 || session.TotalRequests != 1 || !session.FirstSeen.Equal(now) {
+		t.Errorf("Unexpected first session: %+v", session)
+	}
+	if session.Key != "ip:203.0.113.7/32" {
+		t.Errorf("Expected IP session key, got %q", session.Key)
+	}
+
+	now = now.Add(10 * time.Second)
+	session, _ = tracker.Track(context.Background(), req, components)
+	if session.IsNew() || session.Requests != 2 || session.TotalRequests != 2 {
+		t.Errorf("Unexpected second session: %+v", session)
+	}
+	if !session.PreviousSeen.Equal(now.Add(-10*time.Second)) || !session.FirstSeen.Equal(now.Add(-10*time.Second)) {
+		t.Errorf("Unexpected session timestamps: %+v", session)
+	}
+
+	// A new window restarts the request count but keeps the session
+	now = now.Add(time.Minute)
+	session, _ = tracker.Track(context.Background(), req, components)
+	if session.Requests != 1 || session.TotalRequests != 3 {
+		t.Errorf("Expected count to restart in a new window, got %+v", session)
+	}
+}
+
+func TestSessionTracker_IPv6Aggregation(t *testing.T) {
+	tracker := NewSessionTracker(newMapStore())
+	for _, addr := range []string{"[2001:db8:1:2::1]:1234", "[2001:db8:1:2::ffff]:1234"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = addr
+		session, err := tracker.Track(context.Background(), req, collectAllSources(req))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if session.Key != "ip:2001:db8:1:2::/64" {
+			t.Errorf("Expected /64 session key, got %q", session.Key)
+		}
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "[2001:db8:1:2::2]:1234"
+	session, _ := tracker.Track(context.Background(), req, collectAllSources(req))
+	if session.Requests != 3 {
+		t.Errorf("Expected requests from one /64 to share a session, got %d", session.Requests)
+	}
+}
+
+func TestSessionKeyByCookie(t *testing.T) {
+	keyFunc := SessionKeyByCookie("sid", SessionKeyByIP(DefaultIPAggregation()))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.AddCookie(&http.Cookie{Name: "sid", Value: "abc"})
+	if key := keyFunc(req, collectAllSources(req)); key != "cookie:abc" {
+		t.Errorf("Expected cookie key, got %q", key)
+	}
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	if key := keyFunc(req, collectAllSources(req)); key != "ip:203.0.113.7/32" {
+		t.Errorf("Expected IP fallback key, got %q", key)
+	}
+
+	if key := SessionKeyByCookie("sid", nil)(req, collectAllSources(req)); key != "" {
+		t.Errorf("Expected no key without a fallback, got %q", key)
+	}
+}
+
+func TestBotDetector_Session(t *testing.T) {
+	store := newMapStore()
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(store)
+	detector := NewDetectorWithConfig(config)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	var components *ComponentDict
+	for range 3 {
+		var err error
+		components, err = detector.Collect(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if components.Session.GetState() != StateSuccess || components.Session.GetValue().Requests != 3 {
+		t.Errorf("Expected third request in session, got %+v", components.Session)
+	}
+
+	store.err = errors.New("connection refused")
+	components, _ = detector.Collect(req)
+	if components.Session.GetState() != StateUnexpectedBehaviour {
+		t.Errorf("Expected store errors to surface as unexpected behaviour, got %v", components.Session.GetState())
+	}
+
+	// Without a tracker the component is undefined
+	components, _ = NewDetector().Collect(req)
+	if components.Session.GetState() != StateUndefined {
+		t.Errorf("Expected undefined session without a tracker, got %v", components.Session.GetState())
+	}
+}
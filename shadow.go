@@ -0,0 +1,45 @@
+package gogobot
+
+import "net/http"
+
+// MiddlewareMode selects whether the middleware acts on its verdicts
+type MiddlewareMode string
+
+const (
+	// ModeEnforce challenges, blocks and otherwise handles detected bots as configured
+	ModeEnforce MiddlewareMode = "enforce"
+	// ModeShadow runs full detection and serves every request, reporting the action that would
+	// have applied to OnAction, to measure false positives before enforcing
+	ModeShadow MiddlewareMode = "shadow"
+)
+
+// enforcedAction returns what an enforcing middleware would do with a detected bot: the mapped
+// action, the challenge, or blocking when OnBotDetected or BlockBots would handle it.
+// OnBotDecision is not called in shadow mode, so its decisions are not reported.
+func (c MiddlewareConfig) enforcedAction(result *BotDetectionResult) (Action, bool) {
+	if action, ok := c.action(result); ok {
+		return action, true
+	}
+	switch {
+	case !result.Bot:
+		return "", false
+	case c.Challenge != nil:
+		return ActionChallenge, true
+	case c.OnBotDecision != nil:
+		return "", false
+	case c.OnBotDetected != nil || c.BlockBots:
+		return ActionBlock, true
+	}
+	return "", false
+}
+
+// shadow serves a request in ModeShadow, reporting the action an enforcing middleware would
+// have taken. Challenges, trust passes and the cookie challenge are left alone, since they
+// write to the response and would change later verdicts.
+func (d *BotDetector) shadow(w http.ResponseWriter, r *http.Request, components *ComponentDict, config MiddlewareConfig, result *BotDetectionResult, next http.Handler) {
+	result.Shadow = true
+	if action, ok := config.enforcedAction(result); ok && action != ActionAllow && config.OnAction != nil {
+		config.OnAction(r, result, action)
+	}
+	d.serveRecorded(w, r, components, next)
+}
@@ -0,0 +1,60 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware_ShadowMode(t *testing.T) {
+	var (
+		reported []Action
+		result   *BotDetectionResult
+	)
+	config := DefaultMiddlewareConfig()
+	config.Mode = ModeShadow
+	config.BlockBots = true
+	config.Actions = map[BotCategory]Action{BotCategoryAI: ActionTarpit}
+	config.OnAction = func(r *http.Request, result *BotDetectionResult, action Action) {
+		reported = append(reported, action)
+	}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, _ = GetResultFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, userAgent := range []string{
+		"curl/8.4.0",
+		"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)",
+	} {
+		result = nil
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", userAgent)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected %q to be served in shadow mode, got %d", userAgent, rec.Code)
+		}
+		if result == nil || !result.Bot || !result.Shadow {
+			t.Errorf("Expected a shadow bot verdict for %q, got %+v", userAgent, result)
+		}
+	}
+	if len(reported) != 2 || reported[0] != ActionBlock || reported[1] != ActionTarpit {
+		t.Errorf("Expected block and tarpit to be reported, got %v", reported)
+	}
+}
+
+func TestMiddlewareConfig_EnforcedAction(t *testing.T) {
+	bot := &BotDetectionResult{Bot: true, BotKind: BotKindCurl}
+	config := DefaultMiddlewareConfig()
+	if _, ok := config.enforcedAction(bot); ok {
+		t.Error("Expected no action when bots are only detected")
+	}
+	config.Challenge = NewProofOfWorkChallenge([]byte("secret"))
+	if action, _ := config.enforcedAction(bot); action != ActionChallenge {
+		t.Errorf("Expected challenge, got %s", action)
+	}
+	if _, ok := config.enforcedAction(&BotDetectionResult{}); ok {
+		t.Error("Expected no action for humans")
+	}
+}
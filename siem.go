@@ -0,0 +1,359 @@
+package gogobot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DatadogLogsURL is the log intake of the Datadog US1 site
+const DatadogLogsURL = "https://http-intake.logs.datadoghq.com/api/v2/logs"
+
+// SIEMFormat builds the intake request for a batch of events
+type SIEMFormat interface {
+	NewRequest(ctx context.Context, events []Event) (*http.Request, error)
+}
+
+// SIEMExporter posts detection events in batches to a SIEM's HTTP intake. Use its Export method
+// as the handler of a detector subscription, and Run it to send partial batches:
+//
+//	exporter := gogobot.NewSIEMExporter(&gogobot.SplunkHEC{URL: url, Token: token})
+//	go exporter.Run(ctx)
+//	subscription := detector.Subscribe(exporter.Export)
+type SIEMExporter struct {
+	format SIEMFormat
+
+	HTTPClient *http.Client
+	// BatchSize is the number of events sent per request
+	BatchSize int
+	// FlushInterval is how often Run sends the events of a partial batch
+	FlushInterval time.Duration
+	// OnError is called with delivery errors; failed batches are otherwise only counted
+	OnError func(error)
+
+	mu      sync.Mutex
+	pending []Event
+	failed  atomic.Uint64
+}
+
+// NewSIEMExporter creates an exporter sending batches of 100 events in the format, or the
+// events pending every 5 seconds
+func NewSIEMExporter(format SIEMFormat) *SIEMExporter {
+	return &SIEMExporter{
+		format:        format,
+		HTTPClient:    &http.Client{Timeout: 10 * time.Second},
+		BatchSize:     100,
+		FlushInterval: 5 * time.Second,
+	}
+}
+
+// Export queues the event, sending the batch once it is full
+func (e *SIEMExporter) Export(event Event) {
+	e.mu.Lock()
+	e.pending = append(e.pending, event)
+	full := len(e.pending) >= e.BatchSize
+	e.mu.Unlock()
+	if full {
+		e.Flush(context.Background())
+	}
+}
+
+// Flush sends the pending events
+func (e *SIEMExporter) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	events := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+
+	err := e.send(ctx, events)
+	if err != nil {
+		e.failed.Add(uint64(len(events)))
+		if e.OnError != nil {
+			e.OnError(err)
+		}
+	}
+	return err
+}
+
+// send posts a batch
+func (e *SIEMExporter) send(ctx context.Context, events []Event) error {
+	req, err := e.format.NewRequest(ctx, events)
+	if err != nil {
+		return err
+	}
+	resp, err := e.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("siem intake %s: status %d", req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+// Run flushes the pending events every FlushInterval until ctx is done, then sends the last
+// ones
+func (e *SIEMExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// The context is done, but the last events should still be delivered
+			e.Flush(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			e.Flush(ctx)
+		}
+	}
+}
+
+// Failed returns the number of events whose batch could not be delivered
+func (e *SIEMExporter) Failed() uint64 {
+	return e.failed.Load()
+}
+
+// cefField is a CEF extension field
+type cefField struct {
+	key   string
+	value any
+}
+
+// cefFields maps an event to CEF extension fields, leaving out empty ones. Fields without a
+// standard CEF key use the custom string and number keys with their labels.
+func cefFields(event Event) []cefField {
+	result := event.Result
+	category := "human"
+	if result.Bot {
+		category = string(result.BotKind.Category())
+	}
+	fields := []cefField{{"rt", event.Time.UnixMilli()}}
+	if event.ClientIP.IsValid() {
+		fields = append(fields, cefField{"src", event.ClientIP.String()})
+	}
+	for _, field := range []cefField{
+		{"dhost", event.Host},
+		{"requestMethod", event.Method},
+		{"request", event.Path},
+		{"requestClientApplication", event.UserAgent},
+		{"act", string(event.Action)},
+	} {
+		if field.value != "" {
+			fields = append(fields, field)
+		}
+	}
+	fields = append(fields, cefField{"cat", category})
+	if result.Bot {
+		fields = append(fields, cefField{"cs1Label", "botKind"}, cefField{"cs1", string(result.BotKind)})
+	}
+	if len(result.Reasons) > 0 {
+		fields = append(fields, cefField{"cs2Label", "reasons"}, cefField{"cs2", strings.Join(result.Reasons, "; ")})
+	}
+	if result.Score > 0 {
+		fields = append(fields, cefField{"cfp1Label", "score"}, cefField{"cfp1", result.Score})
+	}
+	if result.Country != "" {
+		fields = append(fields, cefField{"cs3Label", "country"}, cefField{"cs3", result.Country})
+	}
+	if result.ASN != 0 {
+		fields = append(fields, cefField{"cn1Label", "asn"}, cefField{"cn1", int64(result.ASN)})
+	}
+	if result.Verified {
+		fields = append(fields, cefField{"cs4Label", "verified"}, cefField{"cs4", "true"})
+	}
+	if event.Err != nil {
+		fields = append(fields, cefField{"reason", event.Err.Error()})
+	}
+	return fields
+}
+
+// CEFExtension maps an event to ArcSight Common Event Format extension fields: rt, src, dhost,
+// requestMethod, request, requestClientApplication, act, cat (the bot category, "human" for
+// clients not flagged), reason (the detection error), and labeled custom fields for the bot
+// kind (cs1), reasons (cs2), score (cfp1), country (cs3), ASN (cn1) and verification (cs4)
+func CEFExtension(event Event) map[string]any {
+	fields := cefFields(event)
+	extension := make(map[string]any, len(fields))
+	for _, field := range fields {
+		extension[field.key] = field.value
+	}
+	return extension
+}
+
+// cefSummary returns the signature ID, name and CEF severity (0-10) of an event
+func cefSummary(event Event) (string, string, int) {
+	switch {
+	case event.Err != nil:
+		return "detection-error", "Bot detection failed", 7
+	case !event.Result.Bot:
+		return "human", "Request passed bot detection", 1
+	case event.Action == ActionAllow || event.Action == ActionLog || event.Action == "":
+		return "bot:" + string(event.Result.BotKind), "Bot detected", 3
+	default:
+		return "bot:" + string(event.Result.BotKind), "Bot refused", 6
+	}
+}
+
+// FormatCEF formats an event as a CEF line, e.g.
+//
+//	CEF:0|Lytics|gogobot|1.0|bot:curl|Bot refused|6|rt=1790856000000 src=203.0.113.7 ...
+func FormatCEF(event Event) string {
+	signature, name, severity := cefSummary(event)
+	var b strings.Builder
+	b.WriteString("CEF:0|Lytics|gogobot|1.0|")
+	b.WriteString(cefHeaderEscaper.Replace(signature))
+	b.WriteByte('|')
+	b.WriteString(cefHeaderEscaper.Replace(name))
+	b.WriteByte('|')
+	b.WriteString(strconv.Itoa(severity))
+	b.WriteByte('|')
+	for i, field := range cefFields(event) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(field.key)
+		b.WriteByte('=')
+		b.WriteString(cefValueEscaper.Replace(fmt.Sprint(field.value)))
+	}
+	return b.String()
+}
+
+var (
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+	cefValueEscaper  = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// SplunkHEC formats batches for a Splunk HTTP Event Collector, one event per detection with
+// the CEFExtension fields
+type SplunkHEC struct {
+	// URL is the event endpoint, e.g. "https://splunk.example:8088/services/collector/event"
+	URL string
+	// Token is the HEC token
+	Token string
+	// Index, Source and Host set the event metadata; empty uses the token's defaults
+	Index  string
+	Source string
+	Host   string
+	// SourceType is the event sourcetype; empty uses "gogobot:detection"
+	SourceType string
+}
+
+// NewRequest implements SIEMFormat
+func (s *SplunkHEC) NewRequest(ctx context.Context, events []Event) (*http.Request, error) {
+	sourceType := s.SourceType
+	if sourceType == "" {
+		sourceType = "gogobot:detection"
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		err := encoder.Encode(struct {
+			Time       float64        `json:"time"`
+			Host       string         `json:"host,omitempty"`
+			Source     string         `json:"source,omitempty"`
+			SourceType string         `json:"sourcetype"`
+			Index      string         `json:"index,omitempty"`
+			Event      map[string]any `json:"event"`
+		}{
+			Time:       float64(event.Time.UnixMicro()) / 1e6,
+			Host:       s.Host,
+			Source:     s.Source,
+			SourceType: sourceType,
+			Index:      s.Index,
+			Event:      CEFExtension(event),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.Token)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// DatadogLogs formats batches for the Datadog log intake: the message is the FormatCEF line,
+// the CEFExtension fields are under "cef", and the client and request are also set as the
+// network and http standard attributes
+type DatadogLogs struct {
+	// URL is the intake of the organization's site; empty uses DatadogLogsURL
+	URL string
+	// APIKey is the Datadog API key
+	APIKey string
+	// Service, Hostname and Tags (comma-separated key:value pairs) set the log metadata
+	Service  string
+	Hostname string
+	Tags     string
+	// Source is the log source; empty uses "gogobot"
+	Source string
+}
+
+// NewRequest implements SIEMFormat
+func (d *DatadogLogs) NewRequest(ctx context.Context, events []Event) (*http.Request, error) {
+	source := d.Source
+	if source == "" {
+		source = "gogobot"
+	}
+	entries := make([]map[string]any, len(events))
+	for i, event := range events {
+		status := "info"
+		if _, _, severity := cefSummary(event); severity >= 7 {
+			status = "error"
+		} else if severity >= 5 {
+			status = "warning"
+		}
+		entry := map[string]any{
+			"ddsource": source,
+			"status":   status,
+			"message":  FormatCEF(event),
+			"date":     event.Time.UnixMilli(),
+			"cef":      CEFExtension(event),
+			"http": map[string]any{
+				"method":      event.Method,
+				"useragent":   event.UserAgent,
+				"url_details": map[string]string{"host": event.Host, "path": event.Path},
+			},
+		}
+		if event.ClientIP.IsValid() {
+			entry["network"] = map[string]any{"client": map[string]string{"ip": event.ClientIP.String()}}
+		}
+		for key, value := range map[string]string{"service": d.Service, "hostname": d.Hostname, "ddtags": d.Tags} {
+			if value != "" {
+				entry[key] = value
+			}
+		}
+		entries[i] = entry
+	}
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	url := d.URL
+	if url == "" {
+		url = DatadogLogsURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("DD-API-KEY", d.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
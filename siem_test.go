@@ -0,0 +1,172 @@
+package gogobot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// siemIntake records the requests it receives, answering with status
+type siemIntake struct {
+	mu       sync.Mutex
+	status   int
+	headers  []http.Header
+	bodies   []string
+	received chan struct{}
+}
+
+func newSIEMIntake(t *testing.T, status int) (*siemIntake, *httptest.Server) {
+	intake := &siemIntake{status: status, received: make(chan struct{}, 10)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body strings.Builder
+		bufio.NewReader(r.Body).WriteTo(&body)
+		intake.mu.Lock()
+		intake.headers = append(intake.headers, r.Header)
+		intake.bodies = append(intake.bodies, body.String())
+		intake.mu.Unlock()
+		w.WriteHeader(intake.status)
+		intake.received <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+	return intake, server
+}
+
+func TestFormatCEF(t *testing.T) {
+	event := testEvent()
+	event.Path = "/search?q=a=b"
+	line := FormatCEF(event)
+
+	prefix := "CEF:0|Lytics|gogobot|1.0|bot:curl|Bot refused|6|rt=1790856000123 src=203.0.113.7 dhost=shop.example requestMethod=GET request=/search?q\\=a\\=b "
+	if !strings.HasPrefix(line, prefix) {
+		t.Errorf("Expected prefix %q, got %q", prefix, line)
+	}
+	for _, want := range []string{"act=block", "cat=automation", "cs1Label=botKind cs1=curl", "cs2=tool user agent; no Accept-Language", "cn1Label=asn cn1=64500"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %q", want, line)
+		}
+	}
+
+	human := FormatCEF(Event{Time: event.Time, Action: ActionAllow})
+	if !strings.HasPrefix(human, "CEF:0|Lytics|gogobot|1.0|human|Request passed bot detection|1|") || strings.Contains(human, "cs1") {
+		t.Errorf("Unexpected human line %q", human)
+	}
+	failed := FormatCEF(Event{Time: event.Time, Err: errors.New("store\nunreachable")})
+	if !strings.Contains(failed, "|7|") || !strings.HasSuffix(failed, `reason=store\nunreachable`) {
+		t.Errorf("Unexpected error line %q", failed)
+	}
+}
+
+func TestSIEMExporter_SplunkHEC(t *testing.T) {
+	intake, server := newSIEMIntake(t, http.StatusOK)
+	exporter := NewSIEMExporter(&SplunkHEC{URL: server.URL + "/services/collector/event", Token: "hec-token", Index: "bots"})
+	exporter.BatchSize = 2
+	exporter.Export(testEvent())
+	if len(intake.bodies) != 0 {
+		t.Fatal("Expected the partial batch to wait")
+	}
+	exporter.Export(testEvent())
+
+	if len(intake.bodies) != 1 || intake.headers[0].Get("Authorization") != "Splunk hec-token" {
+		t.Fatalf("Expected one authenticated batch, got %v", intake.headers)
+	}
+	lines := strings.Split(strings.TrimSpace(intake.bodies[0]), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two events, got %q", intake.bodies[0])
+	}
+	var event struct {
+		Time       float64        `json:"time"`
+		SourceType string         `json:"sourcetype"`
+		Index      string         `json:"index"`
+		Event      map[string]any `json:"event"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Time != 1790856000.123456 || event.SourceType != "gogobot:detection" || event.Index != "bots" {
+		t.Errorf("Unexpected metadata %+v", event)
+	}
+	if event.Event["src"] != "203.0.113.7" || event.Event["cs1"] != "curl" || event.Event["act"] != "block" {
+		t.Errorf("Unexpected fields %v", event.Event)
+	}
+}
+
+func TestSIEMExporter_DatadogLogs(t *testing.T) {
+	intake, server := newSIEMIntake(t, http.StatusAccepted)
+	exporter := NewSIEMExporter(&DatadogLogs{URL: server.URL, APIKey: "dd-key", Service: "shop", Tags: "env:prod"})
+	exporter.Export(testEvent())
+	exporter.Export(Event{Time: testEvent().Time, Action: ActionAllow})
+	if err := exporter.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(intake.bodies) != 1 || intake.headers[0].Get("DD-API-KEY") != "dd-key" {
+		t.Fatalf("Expected one authenticated batch, got %v", intake.headers)
+	}
+	var entries []map[string]any
+	if err := json.Unmarshal([]byte(intake.bodies[0]), &entries); err != nil || len(entries) != 2 {
+		t.Fatalf("Expected two entries, got %s, %v", intake.bodies[0], err)
+	}
+	bot, human := entries[0], entries[1]
+	if bot["status"] != "warning" || bot["service"] != "shop" || bot["ddtags"] != "env:prod" || bot["ddsource"] != "gogobot" {
+		t.Errorf("Unexpected bot entry %v", bot)
+	}
+	if message, _ := bot["message"].(string); !strings.HasPrefix(message, "CEF:0|Lytics|gogobot|") {
+		t.Errorf("Expected a CEF message, got %q", message)
+	}
+	if client := bot["network"].(map[string]any)["client"].(map[string]any); client["ip"] != "203.0.113.7" {
+		t.Errorf("Unexpected network attributes %v", bot["network"])
+	}
+	if human["status"] != "info" || human["network"] != nil {
+		t.Errorf("Unexpected human entry %v", human)
+	}
+}
+
+func TestSIEMExporter_Run(t *testing.T) {
+	intake, server := newSIEMIntake(t, http.StatusOK)
+	exporter := NewSIEMExporter(&SplunkHEC{URL: server.URL, Token: "hec-token"})
+	exporter.FlushInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		exporter.Run(ctx)
+		close(done)
+	}()
+
+	exporter.Export(testEvent())
+	select {
+	case <-intake.received:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the partial batch to be flushed")
+	}
+	exporter.Export(testEvent())
+	cancel()
+	<-done
+	intake.mu.Lock()
+	defer intake.mu.Unlock()
+	if len(intake.bodies) != 2 {
+		t.Errorf("Expected the last event to be sent on shutdown, got %d batches", len(intake.bodies))
+	}
+}
+
+func TestSIEMExporter_Error(t *testing.T) {
+	_, server := newSIEMIntake(t, http.StatusForbidden)
+	exporter := NewSIEMExporter(&DatadogLogs{URL: server.URL, APIKey: "wrong"})
+	var reported error
+	exporter.OnError = func(err error) { reported = err }
+	exporter.Export(testEvent())
+	exporter.Export(testEvent())
+
+	if err := exporter.Flush(context.Background()); err == nil || reported == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the rejection to be reported, got %v", err)
+	}
+	if exporter.Failed() != 2 {
+		t.Errorf("Expected two failed events, got %d", exporter.Failed())
+	}
+}
@@ -0,0 +1,136 @@
+package gogobot
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// SkipMethods returns a SkipFunc skipping requests with one of the methods, e.g. OPTIONS
+// preflights and HEAD
+func SkipMethods(methods ...string) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		return slices.ContainsFunc(methods, func(method string) bool {
+			return strings.EqualFold(method, req.Method)
+		})
+	}
+}
+
+// SkipPathPrefixes returns a SkipFunc skipping requests whose path starts with one of the
+// prefixes; "/static/" skips the files below /static, "/healthz" also /healthz/ready
+func SkipPathPrefixes(prefixes ...string) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		return slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(req.URL.Path, prefix)
+		})
+	}
+}
+
+// SkipContentTypes returns a SkipFunc skipping requests whose body has one of the media types,
+// e.g. "application/grpc"; "type/*" matches every subtype
+func SkipContentTypes(mediaTypes ...string) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		contentType, _, _ := strings.Cut(req.Header.Get("Content-Type"), ";")
+		contentType = strings.ToLower(strings.TrimSpace(contentType))
+		if contentType == "" {
+			return false
+		}
+		return slices.ContainsFunc(mediaTypes, func(mediaType string) bool {
+			mediaType = strings.ToLower(mediaType)
+			if prefix, ok := strings.CutSuffix(mediaType, "/*"); ok {
+				return strings.HasPrefix(contentType, prefix+"/")
+			}
+			return contentType == mediaType
+		})
+	}
+}
+
+// And returns a SkipFunc skipping requests all of funcs skip
+func And(funcs ...func(*http.Request) bool) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		for _, skip := range funcs {
+			if !skip(req) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a SkipFunc skipping requests any of funcs skips
+func Or(funcs ...func(*http.Request) bool) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		for _, skip := range funcs {
+			if skip(req) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf returns a SkipFunc skipping requests all of funcs skip; it is And, named to read along
+// with AnyOf and Not
+func AllOf(funcs ...func(*http.Request) bool) func(*http.Request) bool {
+	return And(funcs...)
+}
+
+// AnyOf returns a SkipFunc skipping requests any of funcs skips; it is Or, named to read along
+// with AllOf and Not
+func AnyOf(funcs ...func(*http.Request) bool) func(*http.Request) bool {
+	return Or(funcs...)
+}
+
+// Not returns a SkipFunc skipping the requests skip does not
+func Not(skip func(*http.Request) bool) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		return !skip(req)
+	}
+}
+
+// HasValidAPIKey returns a SkipFunc skipping requests whose header carries one of the keys,
+// e.g. for server-to-server API clients. Keys are compared in constant time, and every key is
+// compared so the time taken does not tell which one matched.
+func HasValidAPIKey(headerName string, keys ...string) func(*http.Request) bool {
+	digests := make([][sha256.Size]byte, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			digests = append(digests, sha256.Sum256([]byte(key)))
+		}
+	}
+	return func(req *http.Request) bool {
+		key := req.Header.Get(headerName)
+		if key == "" {
+			return false
+		}
+		digest := sha256.Sum256([]byte(key))
+		valid := 0
+		for _, expected := range digests {
+			valid |= subtle.ConstantTimeCompare(digest[:], expected[:])
+		}
+		return valid == 1
+	}
+}
+
+// SkipInternalIPs returns a SkipFunc skipping requests from clients within the prefixes, or from
+// the addresses IsInternalIP reports when none are given. The client IP is resolved with
+// resolver, so forwarded headers only count behind its trusted proxies; a nil resolver uses the
+// connection's address.
+func SkipInternalIPs(resolver *ClientIPResolver, prefixes ...netip.Prefix) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		addr, err := resolver.Resolve(req)
+		if err != nil {
+			return false
+		}
+		addr = normalizeAddr(addr)
+		if len(prefixes) == 0 {
+			return IsInternalIP(addr)
+		}
+		return slices.ContainsFunc(prefixes, func(prefix netip.Prefix) bool {
+			return prefix.Contains(addr)
+		})
+	}
+}
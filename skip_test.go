@@ -0,0 +1,128 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSkipFuncs(t *testing.T) {
+	skip := Or(
+		SkipMethods("OPTIONS"),
+		SkipPathPrefixes("/static/", "/healthz"),
+		And(SkipMethods("POST"), SkipContentTypes("application/grpc", "image/*")),
+	)
+	tests := []struct {
+		method      string
+		path        string
+		contentType string
+		expected    bool
+	}{
+		{"OPTIONS", "/api", "", true},
+		{"options", "/api", "", true},
+		{"GET", "/static/app.js", "", true},
+		{"GET", "/healthz/ready", "", true},
+		{"GET", "/staticky", "", false},
+		{"POST", "/svc.Method", "application/grpc", true},
+		{"POST", "/upload", "image/png; q=1", true},
+		{"POST", "/form", "application/x-www-form-urlencoded", false},
+		{"PUT", "/svc.Method", "application/grpc", false},
+		{"POST", "/empty", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		if got := skip(req); got != tt.expected {
+			t.Errorf("%s %s (%s): expected skip %t, got %t", tt.method, tt.path, tt.contentType, tt.expected, got)
+		}
+	}
+}
+
+func TestSkipCombinators(t *testing.T) {
+	static := SkipPathPrefixes("/static/")
+	head := SkipMethods("HEAD")
+	tests := []struct {
+		name     string
+		skip     func(*http.Request) bool
+		method   string
+		path     string
+		expected bool
+	}{
+		{"AllOf both", AllOf(static, head), "HEAD", "/static/a.js", true},
+		{"AllOf one", AllOf(static, head), "GET", "/static/a.js", false},
+		{"AllOf none", AllOf(), "GET", "/", true},
+		{"AnyOf one", AnyOf(static, head), "HEAD", "/", true},
+		{"AnyOf none", AnyOf(), "GET", "/", false},
+		{"Not", Not(static), "GET", "/static/a.js", false},
+		{"Not AnyOf", Not(AnyOf(static, head)), "GET", "/", true},
+	}
+	for _, tt := range tests {
+		if got := tt.skip(httptest.NewRequest(tt.method, tt.path, nil)); got != tt.expected {
+			t.Errorf("%s %s %s: expected skip %t, got %t", tt.name, tt.method, tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestHasValidAPIKey(t *testing.T) {
+	skip := HasValidAPIKey("X-API-Key", "key-one", "", "key-two")
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{"key-one", true},
+		{"key-two", true},
+		{"key-three", false},
+		{"key-on", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		if got := skip(req); got != tt.expected {
+			t.Errorf("%q: expected skip %t, got %t", tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestSkipInternalIPs(t *testing.T) {
+	resolver, err := NewClientIPResolver("10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefixes, err := ParsePrefixes("192.0.2.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		skip      func(*http.Request) bool
+		remote    string
+		forwarded string
+		expected  bool
+	}{
+		{"private", SkipInternalIPs(nil), "10.1.2.3:1234", "", true},
+		{"loopback", SkipInternalIPs(nil), "[::1]:1234", "", true},
+		{"mapped", SkipInternalIPs(nil), "[::ffff:192.168.1.1]:1234", "", true},
+		{"public", SkipInternalIPs(nil), "203.0.113.7:1234", "", false},
+		{"spoofed", SkipInternalIPs(nil), "203.0.113.7:1234", "10.1.2.3", false},
+		{"proxied public", SkipInternalIPs(resolver), "10.0.0.1:1234", "203.0.113.7", false},
+		{"proxied private", SkipInternalIPs(resolver), "10.0.0.1:1234", "10.1.2.3", true},
+		{"prefixes", SkipInternalIPs(nil, prefixes...), "192.0.2.9:1234", "", true},
+		{"outside prefixes", SkipInternalIPs(nil, prefixes...), "10.1.2.3:1234", "", false},
+		{"invalid", SkipInternalIPs(nil), "unix", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = tt.remote
+		if tt.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if got := tt.skip(req); got != tt.expected {
+			t.Errorf("%s: expected skip %t, got %t", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package gogobot
+
+import (
+	"context"
+	"time"
+)
+
+// Store persists per-client state across requests for session tracking and behavioral
+// detectors. Implementations must be safe for concurrent use; operations map directly onto
+// Redis commands so that state can be shared between replicas, and onto the atomic counters,
+// conditional writes and item TTLs of other backends such as Memcached or DynamoDB. Operations
+// on a key holding another kind of value return ErrWrongType. Wrap a remote Store in an
+// AggregatingStore to count without a round trip per request.
+type Store interface {
+	// Incr adds delta to the counter at key and returns the new value. A new counter
+	// expires after ttl; incrementing does not extend it.
+	Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// Get returns the value stored at key, or false if it is absent or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value at key, expiring after ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// AddToSet adds member to the set at key and returns the number of members. A new set
+	// expires after ttl; adding members does not extend it.
+	AddToSet(ctx context.Context, key, member string, ttl time.Duration) (int64, error)
+	// Delete removes key
+	Delete(ctx context.Context, key string) error
+}
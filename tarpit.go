@@ -0,0 +1,125 @@
+package gogobot
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTarpitDelay is how long ActionTarpit holds a request without a Tarpit configuration
+const DefaultTarpitDelay = 10 * time.Second
+
+// DefaultTarpitMaxConcurrent is how many requests a tarpit holds at once before blocking
+// further ones right away
+const DefaultTarpitMaxConcurrent = 100
+
+// Tarpit configures ActionTarpit, which holds the requests of bots before blocking them. This
+// raises the cost of scraping without a fast, cacheable refusal telling the bot to move on.
+type Tarpit struct {
+	// MinDelay and MaxDelay bound the random time a request is held
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// DripInterval, when set, sends the status right after the delay and then the plain text
+	// BlockedMessage one byte per interval, tying up the client's connection as well
+	DripInterval time.Duration
+	// Kinds are bot kinds tarpitted whatever MiddlewareConfig.Actions holds for their category
+	Kinds []BotKind
+	// MaxConcurrent caps the requests held at once, each tying up a goroutine and a connection,
+	// so a flood of bots cannot exhaust the server; requests over it are blocked right away.
+	// Zero uses DefaultTarpitMaxConcurrent and a negative value lifts the cap.
+	MaxConcurrent int
+
+	held atomic.Int64
+}
+
+// untunedTarpitHeld counts the requests held without a Tarpit configuration
+var untunedTarpitHeld atomic.Int64
+
+// DefaultTarpit returns a tarpit holding up to DefaultTarpitMaxConcurrent requests for 5 to 15
+// seconds
+func DefaultTarpit() *Tarpit {
+	return &Tarpit{
+		MinDelay:      5 * time.Second,
+		MaxDelay:      15 * time.Second,
+		DripInterval:  0,
+		Kinds:         nil,
+		MaxConcurrent: DefaultTarpitMaxConcurrent,
+	}
+}
+
+// slots returns the counter of held requests and their cap, negative for none
+func (t *Tarpit) slots() (*atomic.Int64, int64) {
+	if t == nil {
+		return &untunedTarpitHeld, DefaultTarpitMaxConcurrent
+	}
+	if t.MaxConcurrent == 0 {
+		return &t.held, DefaultTarpitMaxConcurrent
+	}
+	return &t.held, int64(t.MaxConcurrent)
+}
+
+// delay returns a random delay between MinDelay and MaxDelay, DefaultTarpitDelay for a nil
+// tarpit
+func (t *Tarpit) delay() time.Duration {
+	if t == nil {
+		return DefaultTarpitDelay
+	}
+	if t.MaxDelay <= t.MinDelay {
+		return t.MinDelay
+	}
+	return t.MinDelay + rand.N(t.MaxDelay-t.MinDelay)
+}
+
+// serve holds the request, then blocks it at once or byte by byte. Clients going away end the
+// tarpit without a response, and requests over MaxConcurrent are blocked without holding them.
+func (t *Tarpit) serve(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, result *BotDetectionResult) {
+	held, limit := t.slots()
+	if n := held.Add(1); limit >= 0 && n > limit {
+		held.Add(-1)
+		blockBot(w, req, config, result)
+		return
+	}
+	defer held.Add(-1)
+
+	ctx := req.Context()
+	if !sleepContext(ctx, t.delay()) {
+		return
+	}
+	if t == nil || t.DripInterval <= 0 {
+		blockBot(w, req, config, result)
+		return
+	}
+
+	statusCode, message := config.blocked()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	controller := http.NewResponseController(w)
+	body := []byte(message + "\n")
+	for i := range body {
+		if i > 0 && !sleepContext(ctx, t.DripInterval) {
+			return
+		}
+		if _, err := w.Write(body[i : i+1]); err != nil {
+			return
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// sleepContext waits for d, reporting false when the context ends first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
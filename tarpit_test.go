@@ -0,0 +1,78 @@
+package gogobot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTarpit_Delay(t *testing.T) {
+	tarpit := &Tarpit{MinDelay: time.Second, MaxDelay: 2 * time.Second}
+	for range 100 {
+		if delay := tarpit.delay(); delay < time.Second || delay >= 2*time.Second {
+			t.Fatalf("Expected delay within bounds, got %v", delay)
+		}
+	}
+	if delay := (*Tarpit)(nil).delay(); delay != DefaultTarpitDelay {
+		t.Errorf("Expected default delay without a tarpit, got %v", delay)
+	}
+}
+
+func TestMiddleware_TarpitDrip(t *testing.T) {
+	config := DefaultMiddlewareConfig()
+	config.BlockedMessage = "go away"
+	config.Tarpit = &Tarpit{MinDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, DripInterval: time.Millisecond, Kinds: []BotKind{BotKindCurl}}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden || rec.Body.String() != "go away\n" || !rec.Flushed {
+		t.Errorf("Expected dripped block, got %d %q", rec.Code, rec.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed < 8*time.Millisecond {
+		t.Errorf("Expected the response to be held, took %v", elapsed)
+	}
+}
+
+func TestMiddleware_TarpitMaxConcurrent(t *testing.T) {
+	config := DefaultMiddlewareConfig()
+	config.Tarpit = &Tarpit{MinDelay: time.Hour, MaxConcurrent: 1, Kinds: []BotKind{BotKindCurl}}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	newRequest := func(ctx context.Context) *http.Request {
+		req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		return req
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest(ctx))
+	}()
+	for config.Tarpit.held.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The tarpit is full, so the next bot is refused without being held
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(rec, newRequest(context.Background()))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected a block over the cap, got %d", rec.Code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the request over the cap not to be held, took %v", elapsed)
+	}
+
+	cancel()
+	<-done
+	if held := config.Tarpit.held.Load(); held != 0 {
+		t.Errorf("Expected the slot to be released, %d held", held)
+	}
+}
@@ -0,0 +1,227 @@
+package gogobot
+
+import (
+	"encoding/binary"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TimingAnalysis configures detection of inhuman request cadence from the intervals between a
+// visitor's page requests and the hours of the day it is active in
+type TimingAnalysis struct {
+	// Samples is the number of recent navigation intervals analyzed
+	Samples int
+	// MinInterval is the page-to-page interval below which navigation is too fast for a person
+	MinInterval time.Duration
+	// MaxJitter is the coefficient of variation of the intervals below which their cadence
+	// looks scheduled rather than human
+	MaxJitter float64
+	// MaxActiveHours is the number of distinct hours of the last day a visitor may be active in
+	// before it looks like a client running around the clock
+	MaxActiveHours int
+}
+
+// DefaultTimingAnalysis analyzes the last 16 navigations, flagging sub-100ms navigation,
+// intervals varying by less than 10% and activity in more than 20 hours of a day
+func DefaultTimingAnalysis() *TimingAnalysis {
+	return &TimingAnalysis{
+		Samples:        16,
+		MinInterval:    100 * time.Millisecond,
+		MaxJitter:      0.1,
+		MaxActiveHours: 20,
+	}
+}
+
+// RequestTiming is a visitor's recent request cadence
+type RequestTiming struct {
+	// Intervals are the recent page-to-page intervals, oldest first
+	Intervals []time.Duration `json:"intervals"`
+	// ActiveHours is the number of distinct hours of the last day the visitor sent requests in
+	ActiveHours int `json:"activeHours"`
+	// Samples, MinInterval, MaxJitter and MaxActiveHours are the configured thresholds
+	Samples        int           `json:"samples"`
+	MinInterval    time.Duration `json:"minInterval"`
+	MaxJitter      float64       `json:"maxJitter"`
+	MaxActiveHours int           `json:"maxActiveHours"`
+}
+
+// Jitter returns the coefficient of variation of the intervals, the standard deviation relative
+// to the mean, or -1 without at least two intervals
+func (t RequestTiming) Jitter() float64 {
+	if len(t.Intervals) < 2 {
+		return -1
+	}
+	var sum float64
+	for _, interval := range t.Intervals {
+		sum += float64(interval)
+	}
+	mean := sum / float64(len(t.Intervals))
+	if mean == 0 {
+		return 0
+	}
+	var variance float64
+	for _, interval := range t.Intervals {
+		variance += (float64(interval) - mean) * (float64(interval) - mean)
+	}
+	return math.Sqrt(variance/float64(len(t.Intervals))) / mean
+}
+
+// FastNavigations returns the number of intervals shorter than MinInterval
+func (t RequestTiming) FastNavigations() int {
+	fast := 0
+	for _, interval := range t.Intervals {
+		if interval < t.MinInterval {
+			fast++
+		}
+	}
+	return fast
+}
+
+// isNavigation reports whether the request loads a page rather than a subresource. Browsers name
+// the destination in Sec-Fetch-Dest; other clients are assumed to navigate unless they ask for
+// images or stylesheets. Prefetches are not navigations, as browsers fetch several pages the
+// user may visit in quick succession.
+func isNavigation(req *http.Request) bool {
+	if IsPrefetch(req) {
+		return false
+	}
+	if dest := req.Header.Get("Sec-Fetch-Dest"); dest != "" {
+		return dest == "document"
+	}
+	accept := req.Header.Get("Accept")
+	return !strings.HasPrefix(accept, "image/") && !strings.HasPrefix(accept, "text/css")
+}
+
+// timingHourSlots is the number of hours of the day tracked for round-the-clock activity
+const timingHourSlots = 24
+
+// encodeTimingState packs the last active hour of each hour of the day, as hours since the
+// epoch, followed by the recent navigation timestamps
+func encodeTimingState(hours [timingHourSlots]uint32, navigations []time.Time) []byte {
+	buf := make([]byte, 4*timingHourSlots+8*len(navigations))
+	for i, hour := range hours {
+		binary.BigEndian.PutUint32(buf[4*i:], hour)
+	}
+	for i, navigation := range navigations {
+		binary.BigEndian.PutUint64(buf[4*timingHourSlots+8*i:], uint64(navigation.UnixNano()))
+	}
+	return buf
+}
+
+func decodeTimingState(buf []byte) (hours [timingHourSlots]uint32, navigations []time.Time, ok bool) {
+	if len(buf) < 4*timingHourSlots || (len(buf)-4*timingHourSlots)%8 != 0 {
+		return hours, nil, false
+	}
+	for i := range hours {
+		hours[i] = binary.BigEndian.Uint32(buf[4*i:])
+	}
+	for offset := 4 * timingHourSlots; offset < len(buf); offset += 8 {
+		navigations = append(navigations, time.Unix(0, int64(binary.BigEndian.Uint64(buf[offset:]))))
+	}
+	return hours, navigations, true
+}
+
+// getRequestTiming records the request in the visitor's timing state and returns its cadence.
+// The state is kept for a day so that round-the-clock activity can be seen.
+func getRequestTiming(req *http.Request, components *ComponentDict, tracker *SessionTracker, analysis *TimingAnalysis) Component[RequestTiming] {
+	if tracker == nil || analysis == nil {
+		return ErrorComponent[RequestTiming]{
+			State: StateUndefined,
+			Error: "timing analysis requires a session tracker",
+		}
+	}
+	key := tracker.key(req, components)
+	if key == "" {
+		return ErrorComponent[RequestTiming]{
+			State: StateNull,
+			Error: errNoSessionKey.Error(),
+		}
+	}
+
+	storeKey := tracker.Prefix + "timing:" + key
+	state, found, err := tracker.store.Get(req.Context(), storeKey)
+	if err != nil {
+		return ErrorComponent[RequestTiming]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+	var (
+		hours       [timingHourSlots]uint32
+		navigations []time.Time
+	)
+	if found {
+		hours, navigations, _ = decodeTimingState(state)
+	}
+
+	now := tracker.now()
+	hour := uint32(now.Unix() / 3600)
+	hours[hour%timingHourSlots] = hour
+	if isNavigation(req) {
+		navigations = append(navigations, now)
+		if samples := max(analysis.Samples, 1); len(navigations) > samples+1 {
+			navigations = navigations[len(navigations)-samples-1:]
+		}
+	}
+	if err := tracker.store.Set(req.Context(), storeKey, encodeTimingState(hours, navigations), 25*time.Hour); err != nil {
+		return ErrorComponent[RequestTiming]{
+			State: StateUnexpectedBehaviour,
+			Error: "session store: " + err.Error(),
+		}
+	}
+
+	timing := RequestTiming{
+		Samples:        analysis.Samples,
+		MinInterval:    analysis.MinInterval,
+		MaxJitter:      analysis.MaxJitter,
+		MaxActiveHours: analysis.MaxActiveHours,
+	}
+	for i := 1; i < len(navigations); i++ {
+		timing.Intervals = append(timing.Intervals, navigations[i].Sub(navigations[i-1]))
+	}
+	for _, active := range hours {
+		if active != 0 && hour-active < timingHourSlots {
+			timing.ActiveHours++
+		}
+	}
+	return SuccessComponent[RequestTiming]{
+		State: StateSuccess,
+		Value: timing,
+	}
+}
+
+// detectRequestTiming scores inhuman cadence: mostly sub-MinInterval navigation, near-constant
+// intervals over a full sample, and activity around the clock. Each pattern is a soft signal on
+// its own; any two together flag the visitor.
+func detectRequestTiming(components *ComponentDict) *BotDetectionResult {
+	if components.RequestTiming.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	timing := components.RequestTiming.GetValue()
+	var scores []float64
+	if len(timing.Intervals) >= 3 && 2*timing.FastNavigations() >= len(timing.Intervals) {
+		scores = append(scores, 0.6)
+	}
+	if len(timing.Intervals) >= max(timing.Samples, 3) && timing.Jitter() < timing.MaxJitter {
+		scores = append(scores, 0.6)
+	}
+	if timing.MaxActiveHours > 0 && timing.ActiveHours > timing.MaxActiveHours {
+		scores = append(scores, 0.55)
+	}
+	if len(scores) == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	remaining := 1.0
+	for _, score := range scores {
+		remaining *= 1 - score
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindInhumanTiming,
+		Score:   1 - remaining,
+	}
+}
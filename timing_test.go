@@ -0,0 +1,178 @@
+package gogobot
+
+import (
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetRequestTiming(t *testing.T) {
+	tracker := NewSessionTracker(NewMemoryStore(0))
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+	analysis := &TimingAnalysis{Samples: 3, MinInterval: 100 * time.Millisecond, MaxJitter: 0.1, MaxActiveHours: 20}
+
+	collect := func(dest string) RequestTiming {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("Sec-Fetch-Dest", dest)
+		timing := getRequestTiming(req, collectAllSources(req), tracker, analysis)
+		if timing.GetState() != StateSuccess {
+			t.Fatalf("Expected request timing, got %v", timing)
+		}
+		return timing.GetValue()
+	}
+
+	if timing := collect("document"); len(timing.Intervals) != 0 || timing.ActiveHours != 1 || timing.Samples != 3 {
+		t.Errorf("Expected first request without intervals, got %+v", timing)
+	}
+	for _, step := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second} {
+		now = now.Add(step)
+		collect("document")
+		// Subresources do not count as navigation
+		now = now.Add(10 * time.Millisecond)
+		collect("image")
+	}
+	timing := collect("image")
+	expected := []time.Duration{2010 * time.Millisecond, 3010 * time.Millisecond, 4010 * time.Millisecond}
+	if len(timing.Intervals) != len(expected) {
+		t.Fatalf("Expected the last %d intervals, got %v", len(expected), timing.Intervals)
+	}
+	for i := range expected {
+		if timing.Intervals[i] != expected[i] {
+			t.Errorf("Expected intervals %v, got %v", expected, timing.Intervals)
+			break
+		}
+	}
+
+	// Active hours cover the last day
+	for range 30 {
+		now = now.Add(time.Hour)
+		timing = collect("document")
+	}
+	if timing.ActiveHours != 24 {
+		t.Errorf("Expected 24 active hours, got %d", timing.ActiveHours)
+	}
+	// After a 12 hour pause, the first 12 hours of the last day remain alongside the current one
+	now = now.Add(12 * time.Hour)
+	if timing = collect("document"); timing.ActiveHours != 13 {
+		t.Errorf("Expected hours older than a day to be dropped, got %d", timing.ActiveHours)
+	}
+}
+
+func TestGetRequestTiming_Undefined(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if timing := getRequestTiming(req, collectAllSources(req), nil, DefaultTimingAnalysis()); timing.GetState() != StateUndefined {
+		t.Errorf("Expected undefined timing without a tracker, got %v", timing.GetState())
+	}
+}
+
+func TestIsNavigation(t *testing.T) {
+	tests := []struct {
+		dest     string
+		accept   string
+		expected bool
+	}{
+		{"document", "text/html", true},
+		{"script", "*/*", false},
+		{"", "*/*", true},
+		{"", "", true},
+		{"", "image/webp,*/*", false},
+		{"", "text/css,*/*;q=0.1", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Sec-Fetch-Dest", tt.dest)
+		req.Header.Set("Accept", tt.accept)
+		if got := isNavigation(req); got != tt.expected {
+			t.Errorf("isNavigation(%q, %q) = %t, expected %t", tt.dest, tt.accept, got, tt.expected)
+		}
+	}
+}
+
+func TestRequestTiming_Jitter(t *testing.T) {
+	if jitter := (RequestTiming{Intervals: []time.Duration{time.Second}}).Jitter(); jitter != -1 {
+		t.Errorf("Expected -1 for a single interval, got %f", jitter)
+	}
+	if jitter := (RequestTiming{Intervals: []time.Duration{time.Second, time.Second}}).Jitter(); jitter != 0 {
+		t.Errorf("Expected constant intervals to have no jitter, got %f", jitter)
+	}
+	if jitter := (RequestTiming{Intervals: []time.Duration{time.Second, 3 * time.Second}}).Jitter(); math.Abs(jitter-0.5) > 1e-9 {
+		t.Errorf("Expected jitter 0.5, got %f", jitter)
+	}
+}
+
+func TestDetectRequestTiming(t *testing.T) {
+	uniform := []time.Duration{5 * time.Second, 5 * time.Second, 5 * time.Second, 5 * time.Second}
+	human := []time.Duration{3 * time.Second, 40 * time.Second, 8 * time.Second, 90 * time.Second}
+	fast := []time.Duration{20 * time.Millisecond, 5 * time.Second, 30 * time.Millisecond, 45 * time.Second}
+	fastUniform := []time.Duration{50 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	tests := []struct {
+		name        string
+		intervals   []time.Duration
+		activeHours int
+		expected    float64
+		bot         bool
+	}{
+		{"human", human, 8, 0, false},
+		{"too few intervals", fastUniform[:2], 8, 0, false},
+		{"fast navigation", fast, 8, 0.6, false},
+		{"uniform cadence", uniform, 8, 0.6, false},
+		{"around the clock", human, 24, 0.55, false},
+		{"fast and uniform", fastUniform, 8, 0.84, true},
+		{"uniform around the clock", uniform, 24, 0.82, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timing := RequestTiming{
+				Intervals:      tt.intervals,
+				ActiveHours:    tt.activeHours,
+				Samples:        4,
+				MinInterval:    100 * time.Millisecond,
+				MaxJitter:      0.1,
+				MaxActiveHours: 20,
+			}
+			result := detectRequestTiming(&ComponentDict{RequestTiming: SuccessComponent[RequestTiming]{State: StateSuccess, Value: timing}})
+			if result.Bot || math.Abs(result.Score-tt.expected) > 1e-9 {
+				t.Errorf("Expected score %f, got %+v", tt.expected, result)
+			}
+			if tt.expected > 0 && result.BotKind != BotKindInhumanTiming {
+				t.Errorf("Expected inhuman_timing, got %q", result.BotKind)
+			}
+			if bot := result.Score >= DefaultScoreThreshold; bot != tt.bot {
+				t.Errorf("Expected flagged=%t with score %f", tt.bot, result.Score)
+			}
+		})
+	}
+}
+
+func TestDetector_RequestTiming(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.TimingAnalysis = DefaultTimingAnalysis()
+	now := time.Unix(1700000000, 0)
+	config.SessionTracker.now = func() time.Time { return now }
+	detector := NewDetectorWithConfig(config)
+
+	// A browser-like client navigating every 50ms like clockwork
+	var result BotDetectionResult
+	for range 20 {
+		now = now.Add(50 * time.Millisecond)
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("User-Agent", testBrowserUA)
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Accept-Language", "en-US")
+		req.Header.Set("Accept-Encoding", "gzip")
+		var err error
+		result, err = detector.DetectFromRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !result.Bot || detector.GetDetections().RequestTiming.BotKind != BotKindInhumanTiming {
+		t.Errorf("Expected clockwork navigation to be flagged, got %+v", result)
+	}
+}
@@ -0,0 +1,137 @@
+package gogobot
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTorExitListURL is the Tor Project's list of current exit node addresses
+const DefaultTorExitListURL = "https://check.torproject.org/torbulkexitlist"
+
+// TorExitList is an AnonymityProvider reporting Tor exit nodes as proxies. The list starts
+// empty; call Update or Run to fetch it.
+type TorExitList struct {
+	// URL is the exit list to fetch, one address per line
+	URL        string
+	HTTPClient *http.Client
+	// OnError is called with fetch or parse errors from Run; errors are otherwise ignored
+	OnError func(error)
+
+	exits atomic.Pointer[map[netip.Addr]struct{}]
+}
+
+// NewTorExitList creates an empty exit list fetching from DefaultTorExitListURL
+func NewTorExitList() *TorExitList {
+	return &TorExitList{
+		URL:        DefaultTorExitListURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Set atomically replaces the exit addresses
+func (l *TorExitList) Set(addrs []netip.Addr) {
+	exits := make(map[netip.Addr]struct{}, len(addrs))
+	for _, addr := range addrs {
+		exits[normalizeAddr(addr)] = struct{}{}
+	}
+	l.exits.Store(&exits)
+}
+
+// Len returns the number of exit addresses
+func (l *TorExitList) Len() int {
+	exits := l.exits.Load()
+	if exits == nil {
+		return 0
+	}
+	return len(*exits)
+}
+
+// IsTor implements TorChecker
+func (l *TorExitList) IsTor(addr netip.Addr) bool {
+	exits := l.exits.Load()
+	if exits == nil {
+		return false
+	}
+	_, ok := (*exits)[normalizeAddr(addr)]
+	return ok
+}
+
+func (l *TorExitList) IsVPN(netip.Addr) bool        { return false }
+func (l *TorExitList) IsProxy(addr netip.Addr) bool { return l.IsTor(addr) }
+func (l *TorExitList) IsHosting(netip.Addr) bool    { return false }
+
+// Update fetches the exit list once and installs it. The current list is kept on failure.
+func (l *TorExitList) Update(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	client := l.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tor exit list: unexpected status %s", resp.Status)
+	}
+	addrs, err := ParseTorExitList(resp.Body)
+	if err != nil {
+		return err
+	}
+	l.Set(addrs)
+	return nil
+}
+
+// Run calls Update immediately and then every interval until ctx is cancelled
+func (l *TorExitList) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := l.Update(ctx); err != nil && l.OnError != nil {
+			l.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ParseTorExitList parses one IP address per line. Blank lines and lines starting with "#"
+// are ignored.
+func ParseTorExitList(r io.Reader) ([]netip.Addr, error) {
+	var addrs []netip.Addr
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addr, err := netip.ParseAddr(line)
+		if err != nil {
+			return nil, fmt.Errorf("tor exit list line %d: %w", lineNo, err)
+		}
+		addrs = append(addrs, addr)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
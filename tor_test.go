@@ -0,0 +1,91 @@
+package gogobot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestParseTorExitList(t *testing.T) {
+	addrs, err := ParseTorExitList(strings.NewReader("# exits\n185.220.101.1\n\n2a0b:f4c2::1\n"))
+	if err != nil {
+		t.Fatalf("ParseTorExitList returned error: %v", err)
+	}
+	if len(addrs) != 2 || addrs[1] != netip.MustParseAddr("2a0b:f4c2::1") {
+		t.Errorf("Unexpected addresses %v", addrs)
+	}
+
+	if _, err := ParseTorExitList(strings.NewReader("185.220.101.1\nnot-an-ip\n")); err == nil {
+		t.Error("Expected error for invalid line")
+	}
+}
+
+func TestTorExitList_Update(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte("185.220.101.1\n185.220.101.2\n"))
+	}))
+	defer server.Close()
+
+	list := NewTorExitList()
+	list.URL = server.URL
+	if list.IsTor(netip.MustParseAddr("185.220.101.1")) {
+		t.Error("Expected empty list before the first update")
+	}
+	if err := list.Update(context.Background()); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if list.Len() != 2 || !list.IsTor(netip.MustParseAddr("::ffff:185.220.101.1")) {
+		t.Errorf("Expected fetched exits, got %d", list.Len())
+	}
+
+	// Failed updates keep the current list
+	status = http.StatusServiceUnavailable
+	if err := list.Update(context.Background()); err == nil {
+		t.Error("Expected error for failed fetch")
+	}
+	if list.Len() != 2 {
+		t.Errorf("Expected list to be kept, got %d", list.Len())
+	}
+}
+
+func TestBotDetector_NetworkFields(t *testing.T) {
+	tor := NewTorExitList()
+	tor.Set([]netip.Addr{netip.MustParseAddr("185.220.101.1")})
+
+	config := DefaultDetectorConfig()
+	config.AnonymityProvider = tor
+	config.GeoProvider = staticGeoProvider{netip.MustParseAddr("185.220.101.1"): {Country: "DE"}}
+	config.ASNProvider = staticASNProvider{netip.MustParseAddr("185.220.101.1"): {Number: 24940, Organization: "Hetzner Online GmbH"}}
+	detector := NewDetectorWithConfig(config)
+
+	req := createTestRequest("GET", "/", map[string]string{"User-Agent": "curl/8.0"})
+	req.RemoteAddr = "185.220.101.1:443"
+	result, _ := detector.DetectFromRequest(req)
+
+	if !result.IsTor || result.Country != "DE" || result.ASN != 24940 || !result.IsDatacenter || result.Organization != "Hetzner Online GmbH" {
+		t.Errorf("Unexpected network fields %+v", result)
+	}
+	if detector.GetDetections().Anonymity.BotKind != BotKindProxy {
+		t.Errorf("Expected Tor exit to be scored as a proxy, got %+v", detector.GetDetections().Anonymity)
+	}
+
+	data, _ := json.Marshal(result)
+	for _, field := range []string{`"country":"DE"`, `"asn":24940`, `"isDatacenter":true`, `"isTor":true`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("Expected %s in %s", field, data)
+		}
+	}
+
+	// Without IP subsystems only the embedded datacenter data contributes
+	req.RemoteAddr = "203.0.113.5:443"
+	result, _ = NewDetector().DetectFromRequest(req)
+	if result.IsTor || result.Country != "" || result.ASN != 0 || result.IsDatacenter {
+		t.Errorf("Expected no network fields, got %+v", result)
+	}
+}
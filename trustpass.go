@@ -0,0 +1,217 @@
+package gogobot
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default trust pass parameters
+const (
+	DefaultTrustPassCookieName = "gogobot_pass"
+	DefaultTrustPassTTL        = 10 * time.Minute
+)
+
+// TrustPass issues a short-lived signed cookie to visitors that passed detection or solved the
+// middleware's challenge. The middleware admits visitors holding a valid pass without running
+// the detectors again, which saves work on every request of a session and keeps verdicts from
+// flapping between requests. Passes are HS256 JSON Web Tokens bound to the visitor key; they
+// can be revoked through the Store before they expire. Requests to honeypot trap and probe paths
+// are always detected, and a visitor flagged while holding a pass has it revoked.
+type TrustPass struct {
+	secret []byte
+	// CookieName is the name of the pass cookie
+	CookieName string
+	// TTL is how long a pass stays valid
+	TTL time.Duration
+	// Prefix is prepended to store keys
+	Prefix string
+	// OnError is called when the store cannot be reached
+	OnError func(error)
+
+	store Store
+	now   func() time.Time
+}
+
+// NewTrustPass creates a trust pass signing cookies with secret, which should be at least 32
+// random bytes shared by all replicas. Revocations are recorded in store; a nil store disables
+// revocation.
+func NewTrustPass(secret []byte, store Store) *TrustPass {
+	return &TrustPass{
+		secret:     secret,
+		CookieName: DefaultTrustPassCookieName,
+		TTL:        DefaultTrustPassTTL,
+		Prefix:     "gogobot:pass:",
+		store:      store,
+		now:        time.Now,
+	}
+}
+
+// trustPassHeader is the encoded JOSE header of every pass; passes with any other header,
+// including other algorithms, are rejected
+var trustPassHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// trustPassClaims are the registered JWT claims of a pass
+type trustPassClaims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// sign returns the encoded signature of the JWT signing input
+func (p *TrustPass) sign(input string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// issue returns a pass for the visitor
+func (p *TrustPass) issue(visitor string) string {
+	now := p.now()
+	claims, _ := json.Marshal(trustPassClaims{
+		Subject:   visitor,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(p.TTL).Unix(),
+	})
+	input := trustPassHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return input + "." + p.sign(input)
+}
+
+// parse verifies the pass signature, subject and expiry and returns its claims
+func (p *TrustPass) parse(token, visitor string) (trustPassClaims, bool) {
+	header, rest, ok := strings.Cut(token, ".")
+	if !ok || header != trustPassHeader {
+		return trustPassClaims{}, false
+	}
+	payload, signature, ok := strings.Cut(rest, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(p.sign(header+"."+payload))) {
+		return trustPassClaims{}, false
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return trustPassClaims{}, false
+	}
+	var claims trustPassClaims
+	if err := json.Unmarshal(decoded, &claims); err != nil {
+		return trustPassClaims{}, false
+	}
+	if claims.Subject != visitor || !p.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return trustPassClaims{}, false
+	}
+	return claims, true
+}
+
+// revokedKey is the store key holding the time the visitor's passes were revoked
+func (p *TrustPass) revokedKey(visitor string) string {
+	return p.Prefix + "revoked:" + visitor
+}
+
+// valid reports whether the request carries an unrevoked pass for the visitor. A store error
+// invalidates the pass, so the request is detected as usual.
+func (p *TrustPass) valid(req *http.Request, visitor string) bool {
+	cookie, err := req.Cookie(p.CookieName)
+	if err != nil {
+		return false
+	}
+	claims, ok := p.parse(cookie.Value, visitor)
+	if !ok {
+		return false
+	}
+	if p.store == nil {
+		return true
+	}
+
+	revoked, found, err := p.store.Get(req.Context(), p.revokedKey(visitor))
+	if err != nil {
+		if p.OnError != nil {
+			p.OnError(err)
+		}
+		return false
+	}
+	if !found {
+		return true
+	}
+	revokedAt, err := strconv.ParseInt(string(revoked), 10, 64)
+	return err == nil && claims.IssuedAt > revokedAt
+}
+
+// Revoke invalidates the passes issued to the visitor so far. It requires a store.
+func (p *TrustPass) Revoke(ctx context.Context, visitor string) error {
+	if p.store == nil {
+		return nil
+	}
+	revokedAt := strconv.FormatInt(p.now().Unix(), 10)
+	return p.store.Set(ctx, p.revokedKey(visitor), []byte(revokedAt), p.TTL)
+}
+
+// setCookie issues a pass to the visitor
+func (p *TrustPass) setCookie(w http.ResponseWriter, req *http.Request, visitor string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     p.CookieName,
+		Value:    p.issue(visitor),
+		Path:     "/",
+		MaxAge:   int(p.TTL.Seconds()),
+		Secure:   req.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// admitTrusted handles the request without detection when it carries a valid pass, reporting
+// whether it did and the action refusing it, if any. Pass holders are held to the
+// RateLimiter's human rate, so a replayed pass cannot be used to flood. Trap and probe
+// requests are always detected.
+func (d *BotDetector) admitTrusted(w http.ResponseWriter, req *http.Request, config MiddlewareConfig, next http.Handler) (Action, bool) {
+	if config.TrustPass == nil || d.alwaysDetect(req) {
+		return "", false
+	}
+	visitor := d.visitorKey(req, nil)
+	if visitor == "" || !config.TrustPass.valid(req, visitor) {
+		return "", false
+	}
+
+	result := BotDetectionResult{Trusted: true}
+	req = req.WithContext(context.WithValue(req.Context(), DetectionResultKey, &result))
+	config.ResultHeaders.annotate(w, req, &result)
+	if d.limit(w, req, nil, config, &result) {
+		return ActionRateLimit, true
+	}
+	if d.requireJSChallenge(w, req, nil, config) {
+		return ActionChallenge, true
+	}
+	d.serveRecorded(w, req, nil, next)
+	return "", true
+}
+
+// updateTrustPass issues a pass to visitors that passed detection or the challenge, and revokes
+// the passes of flagged visitors presenting one. Visitors still going through the cookie
+// challenge get no pass, so that the challenge is not skipped.
+func (d *BotDetector) updateTrustPass(w http.ResponseWriter, req *http.Request, components *ComponentDict, config MiddlewareConfig, result BotDetectionResult) {
+	pass := config.TrustPass
+	if pass == nil {
+		return
+	}
+	visitor := d.visitorKey(req, components)
+	if visitor == "" {
+		return
+	}
+
+	if result.Bot && !result.ChallengePassed {
+		if _, err := req.Cookie(pass.CookieName); err == nil {
+			if err := pass.Revoke(req.Context(), visitor); err != nil && pass.OnError != nil {
+				pass.OnError(err)
+			}
+		}
+		return
+	}
+	if components != nil && components.CookieChallenge.GetState() == StateSuccess && !components.CookieChallenge.GetValue().Passed {
+		return
+	}
+	pass.setCookie(w, req, visitor)
+}
@@ -0,0 +1,179 @@
+package gogobot
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrustPass_Parse(t *testing.T) {
+	pass := NewTrustPass([]byte("secret"), nil)
+	now := time.Unix(1700000000, 0)
+	pass.now = func() time.Time { return now }
+
+	token := pass.issue("ip:203.0.113.7/32")
+	if claims, ok := pass.parse(token, "ip:203.0.113.7/32"); !ok || claims.IssuedAt != now.Unix() {
+		t.Errorf("Expected issued pass to verify, got %+v %t", claims, ok)
+	}
+	if _, ok := pass.parse(token, "ip:203.0.113.8/32"); ok {
+		t.Error("Expected pass to be bound to the visitor")
+	}
+
+	parts := strings.Split(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"ip:203.0.113.7/32","iat":1700000000,"exp":4102444800}`))
+	if _, ok := pass.parse(parts[0]+"."+forged+"."+parts[2], "ip:203.0.113.7/32"); ok {
+		t.Error("Expected tampered claims to fail")
+	}
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	if _, ok := pass.parse(none+"."+parts[1]+".", "ip:203.0.113.7/32"); ok {
+		t.Error("Expected unsigned pass to fail")
+	}
+	if _, ok := pass.parse("garbage", "ip:203.0.113.7/32"); ok {
+		t.Error("Expected malformed pass to fail")
+	}
+
+	now = now.Add(DefaultTrustPassTTL)
+	if _, ok := pass.parse(token, "ip:203.0.113.7/32"); ok {
+		t.Error("Expected expired pass to fail")
+	}
+}
+
+func TestMiddleware_TrustPass(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.SessionTracker = NewSessionTracker(NewMemoryStore(0))
+	config.Honeypot = NewHoneypot("/trap")
+	detector := NewDetectorWithConfig(config)
+	detections := 0
+	detector.AddDetector("counter", func(components *ComponentDict) *BotDetectionResult {
+		detections++
+		return &BotDetectionResult{}
+	})
+
+	pass := NewTrustPass([]byte("secret"), NewMemoryStore(0))
+	now := time.Unix(1700000000, 0)
+	pass.now = func() time.Time { return now }
+	var last *BotDetectionResult
+	handler := detector.MiddlewareWithConfig(MiddlewareConfig{BlockBots: true, TrustPass: pass})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			last, _ = GetResultFromContext(r.Context())
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	serve := func(path string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("User-Agent", testBrowserUA)
+		req.Header.Set("Accept", "text/html")
+		req.Header.Set("Accept-Language", "en-US")
+		req.Header.Set("Accept-Encoding", "gzip")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/", nil)
+	cookies := rec.Result().Cookies()
+	if rec.Code != http.StatusOK || len(cookies) != 1 || cookies[0].Name != DefaultTrustPassCookieName {
+		t.Fatalf("Expected pass for a visitor passing detection, got %d %+v", rec.Code, cookies)
+	}
+	if detections != 1 || last.Trusted {
+		t.Errorf("Expected first request to be detected, got %d detections", detections)
+	}
+
+	for range 5 {
+		if rec := serve("/", cookies); rec.Code != http.StatusOK || len(rec.Result().Cookies()) != 0 {
+			t.Fatalf("Expected pass holder to be served, got %d", rec.Code)
+		}
+	}
+	if detections != 1 || !last.Trusted {
+		t.Errorf("Expected pass holder to skip detection, got %d detections", detections)
+	}
+
+	// Fetching a trap is detected despite the pass, which is revoked
+	now = now.Add(time.Second)
+	if rec := serve("/trap", cookies); rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected trap request to be blocked, got %d", rec.Code)
+	}
+	if rec := serve("/", cookies); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected revoked pass to be detected again, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware_TrustPassRateLimited(t *testing.T) {
+	limiter := NewRateLimiter(NewMemoryStore(0))
+	limiter.HumanRate = Rate{PerSecond: 1, Burst: 2}
+	limiter.now = func() time.Time { return time.Unix(1700000000, 0) }
+	var observed []Observation
+	config := MiddlewareConfig{
+		TrustPass:   NewTrustPass([]byte("secret"), nil),
+		RateLimiter: limiter,
+		Observer: ObserverFunc(func(r *http.Request, observation Observation) {
+			observed = append(observed, observation)
+		}),
+	}
+	handler := NewDetector().MiddlewareWithConfig(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// A pass replayed after one solved challenge is held to the human rate like any visitor
+	pass := &http.Cookie{Name: DefaultTrustPassCookieName, Value: config.TrustPass.issue("ip:203.0.113.7/32")}
+	var codes []int
+	for range 3 {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.AddCookie(pass)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("Expected the pass holder to be limited after the burst, got %v", codes)
+	}
+	if first := observed[0]; !first.Result.Trusted || first.Action != ActionAllow {
+		t.Errorf("Expected a served trusted request to be observed, got %+v", first)
+	}
+	if last := observed[len(observed)-1]; !last.Result.Trusted || last.Action != ActionRateLimit {
+		t.Errorf("Expected a rate limited trusted request to be observed, got %+v", last)
+	}
+}
+
+func TestTrustPass_Revoke(t *testing.T) {
+	store := newMapStore()
+	pass := NewTrustPass([]byte("secret"), store)
+	now := time.Unix(1700000000, 0)
+	pass.now = func() time.Time { return now }
+
+	withPass := func() *http.Request {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: DefaultTrustPassCookieName, Value: pass.issue("visitor")})
+		return req
+	}
+
+	req := withPass()
+	if !pass.valid(req, "visitor") {
+		t.Fatal("Expected pass to be valid")
+	}
+	if err := pass.Revoke(context.Background(), "visitor"); err != nil {
+		t.Fatal(err)
+	}
+	if pass.valid(req, "visitor") {
+		t.Error("Expected revoked pass to be invalid")
+	}
+	now = now.Add(time.Second)
+	if !pass.valid(withPass(), "visitor") {
+		t.Error("Expected pass issued after revocation to be valid")
+	}
+
+	var reported error
+	pass.OnError = func(err error) { reported = err }
+	store.err = errors.New("connection refused")
+	if pass.valid(withPass(), "visitor") || reported == nil {
+		t.Error("Expected store errors to invalidate the pass and be reported")
+	}
+}
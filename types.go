@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/netip"
 )
 
 // State represents the source collection state
@@ -21,60 +22,122 @@ const (
 type BotKind string
 
 const (
-	BotKindAwesomium      BotKind = "awesomium"
-	BotKindCef            BotKind = "cef"
-	BotKindCefSharp       BotKind = "cefsharp"
-	BotKindCoachJS        BotKind = "coachjs"
-	BotKindElectron       BotKind = "electron"
-	BotKindFMiner         BotKind = "fminer"
-	BotKindGeb            BotKind = "geb"
-	BotKindNightmareJS    BotKind = "nightmarejs"
-	BotKindPhantomas      BotKind = "phantomas"
-	BotKindPhantomJS      BotKind = "phantomjs"
-	BotKindRhino          BotKind = "rhino"
-	BotKindSelenium       BotKind = "selenium"
-	BotKindSequentum      BotKind = "sequentum"
-	BotKindSlimerJS       BotKind = "slimerjs"
-	BotKindWebDriverIO    BotKind = "webdriverio"
-	BotKindWebDriver      BotKind = "webdriver"
-	BotKindHeadlessChrome BotKind = "headless_chrome"
-	BotKindPlaywright     BotKind = "playwright"
-	BotKindPuppeteer      BotKind = "puppeteer"
-	BotKindCurl           BotKind = "curl"
-	BotKindWget           BotKind = "wget"
-	BotKindBot            BotKind = "bot"
-	BotKindCrawler        BotKind = "crawler"
-	BotKindSpider         BotKind = "spider"
-	BotKindGPTBot         BotKind = "gptbot"
-	BotKindChatGPT        BotKind = "chatgpt"
-	BotKindOpenAI         BotKind = "openai"
-	BotKindClaude         BotKind = "claude"
-	BotKindAIAgent        BotKind = "ai_agent"
-	BotKindUnknown        BotKind = "unknown"
+	BotKindAwesomium          BotKind = "awesomium"
+	BotKindCef                BotKind = "cef"
+	BotKindCefSharp           BotKind = "cefsharp"
+	BotKindCoachJS            BotKind = "coachjs"
+	BotKindElectron           BotKind = "electron"
+	BotKindFMiner             BotKind = "fminer"
+	BotKindGeb                BotKind = "geb"
+	BotKindNightmareJS        BotKind = "nightmarejs"
+	BotKindPhantomas          BotKind = "phantomas"
+	BotKindPhantomJS          BotKind = "phantomjs"
+	BotKindRhino              BotKind = "rhino"
+	BotKindSelenium           BotKind = "selenium"
+	BotKindSequentum          BotKind = "sequentum"
+	BotKindSlimerJS           BotKind = "slimerjs"
+	BotKindWebDriverIO        BotKind = "webdriverio"
+	BotKindWebDriver          BotKind = "webdriver"
+	BotKindHeadlessChrome     BotKind = "headless_chrome"
+	BotKindPlaywright         BotKind = "playwright"
+	BotKindPuppeteer          BotKind = "puppeteer"
+	BotKindCurl               BotKind = "curl"
+	BotKindWget               BotKind = "wget"
+	BotKindBot                BotKind = "bot"
+	BotKindCrawler            BotKind = "crawler"
+	BotKindSpider             BotKind = "spider"
+	BotKindGPTBot             BotKind = "gptbot"
+	BotKindChatGPT            BotKind = "chatgpt"
+	BotKindOpenAI             BotKind = "openai"
+	BotKindClaude             BotKind = "claude"
+	BotKindAIAgent            BotKind = "ai_agent"
+	BotKindDatacenter         BotKind = "datacenter"
+	BotKindVPN                BotKind = "vpn"
+	BotKindProxy              BotKind = "proxy"
+	BotKindAbusiveIP          BotKind = "abusive_ip"
+	BotKindDenylisted         BotKind = "denylisted"
+	BotKindImpersonator       BotKind = "impersonator"
+	BotKindSpoofedIP          BotKind = "spoofed_ip"
+	BotKindRateAbuse          BotKind = "rate_abuse"
+	BotKindScraper            BotKind = "scraper"
+	BotKindHoneypot           BotKind = "honeypot"
+	BotKindNoCookies          BotKind = "no_cookies"
+	BotKindInhumanTiming      BotKind = "inhuman_timing"
+	BotKindRobotsViolation    BotKind = "robots_violation"
+	BotKindScanner            BotKind = "scanner"
+	BotKindCredentialStuffing BotKind = "credential_stuffing"
+	BotKindUnknown            BotKind = "unknown"
 )
 
 // BotDetectionResult represents the result of bot detection
 type BotDetectionResult struct {
 	Bot     bool    `json:"bot"`
 	BotKind BotKind `json:"botKind,omitempty"`
+	// Score is the combined weight (0-1) of soft signals that do not flag a bot on their own.
+	// A request is flagged once the score reaches DetectorConfig.ScoreThreshold.
+	Score float64 `json:"score,omitempty"`
+	// Reasons describe the behavior behind the verdict, such as the URL pattern a scraper
+	// enumerated; the combined result lists those of every detector that found a bot or signal
+	Reasons []string `json:"reasons,omitempty"`
+	// Verified is true when a crawler's claimed identity was confirmed by reverse and forward DNS
+	Verified bool `json:"verified,omitempty"`
+	// VerifiedByCDN is true when a trusted CDN reported the client as a verified bot
+	VerifiedByCDN bool `json:"verifiedByCDN,omitempty"`
+	// ChallengePassed is true when a suspected bot was admitted by solving the middleware's
+	// challenge
+	ChallengePassed bool `json:"challengePassed,omitempty"`
+	// Trusted is true when detection was skipped for a visitor holding a valid trust pass
+	Trusted bool `json:"trusted,omitempty"`
+	// Shadow is true when the middleware ran in ModeShadow and served the request regardless of
+	// the verdict
+	Shadow bool `json:"shadow,omitempty"`
+	// Unsampled is true when only the user agent and access list verdict of a request outside
+	// the middleware's SampleRate were checked
+	Unsampled bool `json:"unsampled,omitempty"`
+	// Organization is the company owning the client IP, when known
+	Organization string `json:"organization,omitempty"`
+	// Country is the ISO 3166-1 country code from the GeoProvider
+	Country string `json:"country,omitempty"`
+	// ASN is the autonomous system number from the ASNProvider
+	ASN uint32 `json:"asn,omitempty"`
+	// IsDatacenter is true when the client IP belongs to a hosting or cloud provider
+	IsDatacenter bool `json:"isDatacenter,omitempty"`
+	// IsTor is true when the AnonymityProvider reports a Tor exit node
+	IsTor bool `json:"isTor,omitempty"`
 }
 
 // BrowserName represents different browser types
 type BrowserName string
 
 const (
-	BrowserChrome    BrowserName = "Chrome"
-	BrowserFirefox   BrowserName = "Firefox"
-	BrowserSafari    BrowserName = "Safari"
-	BrowserEdge      BrowserName = "Edge"
-	BrowserIE        BrowserName = "IE"
-	BrowserOpera     BrowserName = "Opera"
-	BrowserSamsung   BrowserName = "Samsung"
-	BrowserUCBrowser BrowserName = "UCBrowser"
-	BrowserYandex    BrowserName = "Yandex"
-	BrowserVivaldi   BrowserName = "Vivaldi"
-	BrowserBrave     BrowserName = "Brave"
-	BrowserUnknown   BrowserName = "Unknown"
+	BrowserChrome      BrowserName = "Chrome"
+	BrowserFirefox     BrowserName = "Firefox"
+	BrowserSafari      BrowserName = "Safari"
+	BrowserEdge        BrowserName = "Edge"
+	BrowserIE          BrowserName = "IE"
+	BrowserOpera       BrowserName = "Opera"
+	BrowserSamsung     BrowserName = "Samsung"
+	BrowserUCBrowser   BrowserName = "UCBrowser"
+	BrowserYandex      BrowserName = "Yandex"
+	BrowserVivaldi     BrowserName = "Vivaldi"
+	BrowserBrave       BrowserName = "Brave"
+	BrowserTizen       BrowserName = "Tizen"
+	BrowserWebOS       BrowserName = "WebOS"
+	BrowserPlayStation BrowserName = "PlayStation"
+	BrowserNintendo    BrowserName = "Nintendo"
+	BrowserUnknown     BrowserName = "Unknown"
+)
+
+// DeviceType represents the class of device a browser runs on
+type DeviceType string
+
+const (
+	DeviceDesktop DeviceType = "desktop"
+	DeviceMobile  DeviceType = "mobile"
+	DeviceTablet  DeviceType = "tablet"
+	DeviceTV      DeviceType = "tv"
+	DeviceConsole DeviceType = "console"
+	DeviceUnknown DeviceType = "unknown"
 )
 
 // BrowserInfo represents parsed browser information
@@ -83,6 +146,18 @@ type BrowserInfo struct {
 	Version string      `json:"version"`
 	BotKind BotKind     `json:"botKind,omitempty"`
 	RawUA   string      `json:"rawUserAgent,omitempty"`
+	// DeviceType is the class of device inferred from the user agent
+	DeviceType DeviceType `json:"deviceType,omitempty"`
+	// Engine is the rendering engine inferred from the user agent (e.g. "Blink", "WebKit", "Gecko")
+	Engine string `json:"engine,omitempty"`
+	// IsReducedUA is true when Chromium reported its frozen/reduced user agent
+	IsReducedUA bool `json:"isReducedUA,omitempty"`
+	// IsEmbedded is true when the browser engine is embedded in a host application (e.g. Electron)
+	IsEmbedded bool `json:"isEmbedded,omitempty"`
+	// EmbeddedApp is the name of the host application for embedded clients
+	EmbeddedApp string `json:"embeddedApp,omitempty"`
+	// WebviewApp is the name of the app whose in-app browser sent the request (e.g. "Instagram")
+	WebviewApp string `json:"webviewApp,omitempty"`
 }
 
 // IsAIAgent returns true if the browser is detected as an AI agent
@@ -95,6 +170,11 @@ func (b *BrowserInfo) IsAIAgent() bool {
 	}
 }
 
+// IsWebview returns true if the request comes from an in-app browser webview
+func (b *BrowserInfo) IsWebview() bool {
+	return b.WebviewApp != ""
+}
+
 // IsBot returns true if the browser is detected as a bot
 func (b *BrowserInfo) IsBot() bool {
 	return b.BotKind != "" && b.BotKind != BotKindUnknown
@@ -149,18 +229,76 @@ type ComponentDict struct {
 	HeaderOrder          Component[[]string]
 	HeaderCount          Component[int]
 	MissingCommonHeaders Component[[]string]
+	ResolvedClientIP     Component[netip.Addr]
+	ForwardedChain       Component[[]ForwardedElement]
+	ForwardedFor         Component[[]string]
+	Datacenter           Component[DatacenterProvider]
+	ASN                  Component[ASNInfo]
+	Geo                  Component[GeoInfo]
+	CrawlerVerification  Component[CrawlerVerification]
+	Anonymity            Component[AnonymityInfo]
+	Reputation           Component[Reputation]
+	IPAccess             Component[IPAccess]
+	CDN                  Component[CDNVerdict]
+	Organization         Component[string]
+	Session              Component[Session]
+	RequestRates         Component[[]RequestRate]
+	CrawlBreadth         Component[CrawlBreadth]
+	RequestTiming        Component[RequestTiming]
+	RobotsCompliance     Component[RobotsCompliance]
+	Enumeration          Component[Enumeration]
+	ErrorRate            Component[ErrorRate]
+	ProbePath            Component[string]
+	LoginFailures        Component[LoginFailures]
+	Trapped              Component[bool]
+	CookieChallenge      Component[CookieChallengeState]
+	JSTrust              Component[bool]
+	TLSFingerprint       Component[string]
+	Protocol             Component[Protocol]
+	SecFetch             Component[SecFetch]
+	NavigationAccept     Component[NavigationAccept]
+	ClaimedEncodings     Component[ClaimedEncodings]
+	ConnectionOptions    Component[ConnectionOptions]
+	H3Usage              Component[H3Usage]
+	RawRequest           Component[RawRequest]
+	Cookies              Component[CookieInfo]
+	CookieSession        Component[CookieSession]
+	Prefetch             Component[Prefetch]
 }
 
 // DetectionDict holds detection results for each detector
 type DetectionDict struct {
-	UserAgent      BotDetectionResult
-	Headers        BotDetectionResult
-	HeaderOrder    BotDetectionResult
-	HeaderCount    BotDetectionResult
-	MissingHeaders BotDetectionResult
-	AcceptHeaders  BotDetectionResult
-	Connection     BotDetectionResult
-	ContentLength  BotDetectionResult
+	UserAgent            BotDetectionResult
+	Headers              BotDetectionResult
+	HeaderOrder          BotDetectionResult
+	HeaderCount          BotDetectionResult
+	MissingHeaders       BotDetectionResult
+	AcceptHeaders        BotDetectionResult
+	Connection           BotDetectionResult
+	ContentLength        BotDetectionResult
+	Plausibility         BotDetectionResult
+	Datacenter           BotDetectionResult
+	Anonymity            BotDetectionResult
+	Reputation           BotDetectionResult
+	Impersonator         BotDetectionResult
+	CDN                  BotDetectionResult
+	ForwardedFor         BotDetectionResult
+	RequestRate          BotDetectionResult
+	CrawlBreadth         BotDetectionResult
+	RequestTiming        BotDetectionResult
+	RobotsViolation      BotDetectionResult
+	Enumeration          BotDetectionResult
+	ErrorRate            BotDetectionResult
+	ProbePath            BotDetectionResult
+	CredentialStuffing   BotDetectionResult
+	Protocol             BotDetectionResult
+	SecFetch             BotDetectionResult
+	AcceptPlausibility   BotDetectionResult
+	EncodingPlausibility BotDetectionResult
+	H3Adoption           BotDetectionResult
+	CookielessSession    BotDetectionResult
+	Honeypot             BotDetectionResult
+	CookieChallenge      BotDetectionResult
 }
 
 // BotDetectorInterface defines the interface for bot detectors
@@ -201,6 +339,9 @@ type contextKey string
 const (
 	DetectionResultKey contextKey = "gogobot_detection_result"
 	ComponentsKey      contextKey = "gogobot_components"
+	TLSFingerprintKey  contextKey = "gogobot_tls_fingerprint"
+	BrowserInfoKey     contextKey = "gogobot_browser_info"
+	AIInfoKey          contextKey = "gogobot_ai_info"
 )
 
 // GetResultFromContext retrieves the detection result from request context
@@ -214,3 +355,33 @@ func GetComponentsFromContext(ctx context.Context) (*ComponentDict, bool) {
 	components, ok := ctx.Value(ComponentsKey).(*ComponentDict)
 	return components, ok
 }
+
+// GetBrowserInfoFromContext retrieves the browser the middleware parsed from the request
+func GetBrowserInfoFromContext(ctx context.Context) (*BrowserInfo, bool) {
+	info, ok := ctx.Value(BrowserInfoKey).(*BrowserInfo)
+	return info, ok
+}
+
+// AIInfo is the AI agent classification of a request, as GetAIAgentInfo reports it
+type AIInfo struct {
+	// IsAI reports whether the client is an AI crawler or agent
+	IsAI bool `json:"isAI"`
+	// AgentType is the bot kind of the AI client
+	AgentType BotKind `json:"agentType,omitempty"`
+	// Verified reports whether the client's claimed identity was verified
+	Verified bool `json:"verified,omitempty"`
+}
+
+// newAIInfo classifies a detection result
+func newAIInfo(result *BotDetectionResult) AIInfo {
+	if !result.Bot || result.BotKind.Category() != BotCategoryAI {
+		return AIInfo{}
+	}
+	return AIInfo{IsAI: true, AgentType: result.BotKind, Verified: result.Verified}
+}
+
+// GetAIInfoFromContext retrieves the AI agent classification the middleware made of the request
+func GetAIInfoFromContext(ctx context.Context) (*AIInfo, bool) {
+	info, ok := ctx.Value(AIInfoKey).(*AIInfo)
+	return info, ok
+}
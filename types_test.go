@@ -2,6 +2,9 @@ package gogobot
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -201,3 +204,51 @@ func TestDetectionDict_Structure(t *testing.T) {
 		t.Error("HeaderCount detection result should be true")
 	}
 }
+
+func TestGetBrowserAndAIInfoFromContext(t *testing.T) {
+	handler := NewDetector().Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		browser, ok := GetBrowserInfoFromContext(r.Context())
+		if !ok {
+			t.Fatal("Expected browser info in context")
+		}
+		ai, ok := GetAIInfoFromContext(r.Context())
+		if !ok {
+			t.Fatal("Expected AI info in context")
+		}
+		json.NewEncoder(w).Encode(map[string]any{"browser": browser, "ai": ai})
+	}))
+
+	tests := []struct {
+		userAgent string
+		browser   BrowserName
+		ai        AIInfo
+	}{
+		{testBrowserUA, BrowserChrome, AIInfo{}},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.2; +https://openai.com/gptbot)", BrowserUnknown, AIInfo{IsAI: true, AgentType: BotKindGPTBot}},
+		{"curl/8.4.0", BrowserUnknown, AIInfo{}},
+	}
+	for _, tt := range tests {
+		req := newBrowserRequest("/", "203.0.113.7:1234")
+		req.Header.Set("User-Agent", tt.userAgent)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var body struct {
+			Browser BrowserInfo `json:"browser"`
+			AI      AIInfo      `json:"ai"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: %v", tt.userAgent, err)
+		}
+		if body.Browser.Name != tt.browser || body.AI != tt.ai {
+			t.Errorf("%s: expected %s %+v, got %s %+v", tt.userAgent, tt.browser, tt.ai, body.Browser.Name, body.AI)
+		}
+	}
+
+	if _, ok := GetBrowserInfoFromContext(context.Background()); ok {
+		t.Error("Expected no browser info in an empty context")
+	}
+	if _, ok := GetAIInfoFromContext(context.Background()); ok {
+		t.Error("Expected no AI info in an empty context")
+	}
+}
@@ -0,0 +1,235 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// DNSResolver performs the lookups used for crawler verification; *net.Resolver implements it
+type DNSResolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// verifiableCrawler describes a crawler whose operator documents reverse DNS verification
+type verifiableCrawler struct {
+	name     string
+	tokens   []string
+	suffixes []string
+}
+
+// verifiableCrawlers lists crawlers verifiable by reverse DNS and forward confirmation
+var verifiableCrawlers = []verifiableCrawler{
+	{name: "Googlebot", tokens: []string{"googlebot", "google-inspectiontool", "googleother"}, suffixes: []string{"googlebot.com", "google.com"}},
+	{name: "Bingbot", tokens: []string{"bingbot", "msnbot", "adidxbot"}, suffixes: []string{"search.msn.com"}},
+	{name: "Applebot", tokens: []string{"applebot"}, suffixes: []string{"applebot.apple.com"}},
+	{name: "DuckDuckBot", tokens: []string{"duckduckbot"}, suffixes: []string{"duckduckgo.com"}},
+}
+
+// claimedCrawler returns the verifiable crawler a user agent claims to be
+func claimedCrawler(userAgent string) (verifiableCrawler, bool) {
+	ua := strings.ToLower(userAgent)
+	for _, crawler := range verifiableCrawlers {
+		for _, token := range crawler.tokens {
+			if strings.Contains(ua, token) {
+				return crawler, true
+			}
+		}
+	}
+	return verifiableCrawler{}, false
+}
+
+// CrawlerVerification is the outcome of verifying a crawler's claimed identity
+type CrawlerVerification struct {
+	// Crawler is the crawler the user agent claims to be, e.g. "Googlebot"
+	Crawler string `json:"crawler"`
+	// Hostname is the forward-confirmed reverse DNS name of the client IP
+	Hostname string `json:"hostname,omitempty"`
+	// Verified is true when the client IP reverse-resolves to the crawler's domain and
+	// that name resolves back to the client IP
+	Verified bool `json:"verified"`
+	// Inconclusive is true when verification could not complete, e.g. a DNS timeout, so an
+	// unverified result says nothing about the claim
+	Inconclusive bool `json:"inconclusive,omitempty"`
+}
+
+// CrawlerVerificationMode selects how a CrawlerVerifier confirms a crawler's identity
+type CrawlerVerificationMode int
+
+const (
+	// CrawlerVerificationDNS uses reverse DNS and forward confirmation
+	CrawlerVerificationDNS CrawlerVerificationMode = iota
+	// CrawlerVerificationRanges only checks the published crawler IP ranges (see SetCrawlerRanges),
+	// for environments where outbound DNS is restricted. Crawlers without published ranges
+	// cannot be verified in this mode.
+	CrawlerVerificationRanges
+	// CrawlerVerificationRangesOrDNS checks the published ranges first and falls back to DNS
+	CrawlerVerificationRangesOrDNS
+)
+
+// CrawlerVerifier verifies crawler identities using reverse DNS and forward confirmation,
+// or the IP ranges published by crawler operators. DNS lookups go through the shared
+// VerificationCache (see SetVerificationCache).
+type CrawlerVerifier struct {
+	// Resolver performs DNS lookups; nil uses net.DefaultResolver
+	Resolver DNSResolver
+	// Timeout bounds the DNS lookups of a single verification
+	Timeout time.Duration
+	// Mode selects DNS, published ranges, or both; the zero value uses DNS
+	Mode CrawlerVerificationMode
+}
+
+// NewCrawlerVerifier creates a CrawlerVerifier using the system resolver
+func NewCrawlerVerifier() *CrawlerVerifier {
+	return &CrawlerVerifier{
+		Resolver: net.DefaultResolver,
+		Timeout:  2 * time.Second,
+	}
+}
+
+// defaultCrawlerVerifier is used by IsVerifiedBot
+var defaultCrawlerVerifier = NewCrawlerVerifier()
+
+// Verify checks whether addr belongs to the crawler claimed by userAgent. It returns false
+// for user agents that do not claim a verifiable crawler.
+func (v *CrawlerVerifier) Verify(ctx context.Context, userAgent string, addr netip.Addr) (CrawlerVerification, bool) {
+	crawler, ok := claimedCrawler(userAgent)
+	if !ok {
+		return CrawlerVerification{}, false
+	}
+
+	verification := CrawlerVerification{Crawler: crawler.name}
+	if !addr.IsValid() {
+		verification.Inconclusive = true
+		return verification, true
+	}
+	addr = normalizeAddr(addr)
+
+	if v.Mode == CrawlerVerificationRanges || v.Mode == CrawlerVerificationRangesOrDNS {
+		if name, ok := GetCrawlerRanges().Lookup(addr); ok && name == crawler.name {
+			verification.Verified = true
+			return verification, true
+		}
+		if v.Mode == CrawlerVerificationRanges {
+			// Crawlers that publish no ranges cannot be checked without DNS
+			verification.Inconclusive = !GetCrawlerRanges().Has(crawler.name)
+			return verification, true
+		}
+	}
+
+	if v.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.Timeout)
+		defer cancel()
+	}
+
+	resolver := v.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	if cache := activeVerificationCache.Load(); cache != nil {
+		resolver = cachingResolver{next: resolver, cache: cache}
+	}
+
+	names, err := resolver.LookupAddr(ctx, addr.String())
+	if err != nil {
+		verification.Inconclusive = isInconclusiveLookup(ctx, err)
+		return verification, true
+	}
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSuffix(name, "."))
+		if !hasDomainSuffix(name, crawler.suffixes) {
+			continue
+		}
+
+		// Forward confirmation: anyone can publish a PTR record claiming googlebot.com
+		addrs, err := resolver.LookupNetIP(ctx, "ip", name)
+		if err != nil {
+			verification.Inconclusive = verification.Inconclusive || isInconclusiveLookup(ctx, err)
+			continue
+		}
+		for _, forward := range addrs {
+			if normalizeAddr(forward) == addr {
+				verification.Hostname = name
+				verification.Verified = true
+				return verification, true
+			}
+		}
+	}
+
+	return verification, true
+}
+
+// isInconclusiveLookup reports whether a failed lookup was cut short rather than answered.
+// A missing record proves the claim false; a timeout or SERVFAIL proves nothing.
+func isInconclusiveLookup(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// hasDomainSuffix reports whether name is one of the domains or a subdomain of one
+func hasDomainSuffix(name string, domains []string) bool {
+	for _, domain := range domains {
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// getCrawlerVerification verifies the claimed crawler identity of the request
+func getCrawlerVerification(ctx context.Context, userAgent Component[string], clientIP Component[netip.Addr], verifier *CrawlerVerifier) Component[CrawlerVerification] {
+	if verifier == nil {
+		return ErrorComponent[CrawlerVerification]{
+			State: StateUndefined,
+			Error: "no crawler verifier configured",
+		}
+	}
+	if userAgent.GetState() != StateSuccess {
+		return ErrorComponent[CrawlerVerification]{
+			State: StateUndefined,
+			Error: "User-Agent header is missing",
+		}
+	}
+
+	verification, claimed := verifier.Verify(ctx, userAgent.GetValue(), clientIP.GetValue())
+	if !claimed {
+		return ErrorComponent[CrawlerVerification]{
+			State: StateNull,
+			Error: "user agent does not claim a verifiable crawler",
+		}
+	}
+	return SuccessComponent[CrawlerVerification]{
+		State: StateSuccess,
+		Value: verification,
+	}
+}
+
+// detectImpersonator flags requests whose user agent claims a verifiable crawler that the
+// client IP could not be confirmed as. Inconclusive verifications are not flagged.
+func detectImpersonator(components *ComponentDict) *BotDetectionResult {
+	if components.CrawlerVerification.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	verification := components.CrawlerVerification.GetValue()
+	if verification.Verified || verification.Inconclusive || isCDNVerifiedBot(components) {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	return &BotDetectionResult{
+		Bot:     true,
+		BotKind: BotKindImpersonator,
+	}
+}
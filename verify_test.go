@@ -0,0 +1,178 @@
+package gogobot
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+// fakeDNSResolver serves PTR and forward records from maps
+type fakeDNSResolver struct {
+	ptr     map[string][]string
+	forward map[string][]netip.Addr
+	lookups int
+}
+
+func (r *fakeDNSResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	r.lookups++
+	names, ok := r.ptr[addr]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return names, nil
+}
+
+func (r *fakeDNSResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	r.lookups++
+	addrs, ok := r.forward[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+func newFakeCrawlerDNS() *fakeDNSResolver {
+	return &fakeDNSResolver{
+		ptr: map[string][]string{
+			"66.249.66.1":   {"crawl-66-249-66-1.googlebot.com."},
+			"157.55.39.1":   {"msnbot-157-55-39-1.search.msn.com."},
+			"203.0.113.5":   {"crawl-fake.googlebot.com."},
+			"198.51.100.20": {"googlebot.com.evil.example."},
+		},
+		forward: map[string][]netip.Addr{
+			"crawl-66-249-66-1.googlebot.com":   {netip.MustParseAddr("66.249.66.1")},
+			"msnbot-157-55-39-1.search.msn.com": {netip.MustParseAddr("157.55.39.1")},
+			"crawl-fake.googlebot.com":          {netip.MustParseAddr("66.249.66.99")},
+		},
+	}
+}
+
+const googlebotUA = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+func TestCrawlerVerifier_Verify(t *testing.T) {
+	verifier := &CrawlerVerifier{Resolver: newFakeCrawlerDNS()}
+
+	tests := []struct {
+		name      string
+		userAgent string
+		addr      string
+		claimed   bool
+		verified  bool
+		crawler   string
+	}{
+		{"Genuine Googlebot", googlebotUA, "66.249.66.1", true, true, "Googlebot"},
+		{"Genuine Bingbot", "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", "157.55.39.1", true, true, "Bingbot"},
+		{"PTR without forward confirmation", googlebotUA, "203.0.113.5", true, false, "Googlebot"},
+		{"PTR outside crawler domain", googlebotUA, "198.51.100.20", true, false, "Googlebot"},
+		{"No PTR record", googlebotUA, "192.0.2.1", true, false, "Googlebot"},
+		{"Wrong crawler for domain", "Mozilla/5.0 (compatible; bingbot/2.0)", "66.249.66.1", true, false, "Bingbot"},
+		{"Not a crawler", "Mozilla/5.0 (Windows NT 10.0; Win64; x64)", "66.249.66.1", false, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verification, claimed := verifier.Verify(context.Background(), tt.userAgent, netip.MustParseAddr(tt.addr))
+			if claimed != tt.claimed || verification.Verified != tt.verified || verification.Crawler != tt.crawler {
+				t.Errorf("Verify() = %+v, claimed=%t; want crawler=%q verified=%t claimed=%t",
+					verification, claimed, tt.crawler, tt.verified, tt.claimed)
+			}
+		})
+	}
+}
+
+func TestBotDetector_VerifiedCrawler(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.CrawlerVerifier = &CrawlerVerifier{Resolver: newFakeCrawlerDNS()}
+	detector := NewDetectorWithConfig(config)
+
+	req := createTestRequest("GET", "/", map[string]string{"User-Agent": googlebotUA})
+	req.RemoteAddr = "66.249.66.1:1234"
+	result, _ := detector.DetectFromRequest(req)
+	if !result.Bot || result.BotKind != BotKindCrawler || !result.Verified {
+		t.Errorf("Expected verified crawler, got %+v", result)
+	}
+
+	// Same user agent from an address that is not Google's
+	req.RemoteAddr = "203.0.113.5:1234"
+	result, _ = detector.DetectFromRequest(req)
+	if !result.Bot || result.BotKind != BotKindImpersonator || result.Verified {
+		t.Errorf("Expected impersonator, got %+v", result)
+	}
+
+	// Without a verifier nothing is verified
+	req.RemoteAddr = "66.249.66.1:1234"
+	result, _ = NewDetector().DetectFromRequest(req)
+	if result.Verified {
+		t.Errorf("Expected no verification without a verifier, got %+v", result)
+	}
+}
+
+func TestCrawlerVerifier_Inconclusive(t *testing.T) {
+	defer SetVerificationCache(GetVerificationCache())
+	SetVerificationCache(nil)
+
+	tests := []struct {
+		name         string
+		err          error
+		inconclusive bool
+	}{
+		{"NXDOMAIN", &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{"Timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, true},
+		{"SERVFAIL", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, true},
+		{"Deadline", context.DeadlineExceeded, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := &CrawlerVerifier{Resolver: erroringDNSResolver{err: tt.err}}
+			verification, _ := verifier.Verify(context.Background(), googlebotUA, netip.MustParseAddr("203.0.113.5"))
+			if verification.Verified || verification.Inconclusive != tt.inconclusive {
+				t.Errorf("Verify() = %+v, want inconclusive=%t", verification, tt.inconclusive)
+			}
+		})
+	}
+
+	// Applebot publishes no ranges, so ranges-only verification cannot decide
+	verifier := &CrawlerVerifier{Mode: CrawlerVerificationRanges}
+	verification, _ := verifier.Verify(context.Background(), "Mozilla/5.0 (compatible; Applebot/0.1)", netip.MustParseAddr("203.0.113.5"))
+	if !verification.Inconclusive {
+		t.Errorf("Expected inconclusive Applebot verification, got %+v", verification)
+	}
+}
+
+// erroringDNSResolver fails every lookup with err
+type erroringDNSResolver struct {
+	err error
+}
+
+func (r erroringDNSResolver) LookupAddr(context.Context, string) ([]string, error) {
+	return nil, r.err
+}
+
+func (r erroringDNSResolver) LookupNetIP(context.Context, string, string) ([]netip.Addr, error) {
+	return nil, r.err
+}
+
+func TestDetectImpersonator(t *testing.T) {
+	tests := []struct {
+		name         string
+		verification Component[CrawlerVerification]
+		bot          bool
+	}{
+		{"Verified", SuccessComponent[CrawlerVerification]{State: StateSuccess, Value: CrawlerVerification{Crawler: "Googlebot", Verified: true}}, false},
+		{"Spoofed", SuccessComponent[CrawlerVerification]{State: StateSuccess, Value: CrawlerVerification{Crawler: "Googlebot"}}, true},
+		{"Inconclusive", SuccessComponent[CrawlerVerification]{State: StateSuccess, Value: CrawlerVerification{Crawler: "Googlebot", Inconclusive: true}}, false},
+		{"No claim", ErrorComponent[CrawlerVerification]{State: StateNull}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detectImpersonator(&ComponentDict{CrawlerVerification: tt.verification})
+			if result.Bot != tt.bot || (tt.bot && result.BotKind != BotKindImpersonator) {
+				t.Errorf("detectImpersonator() = %+v, want bot=%t", result, tt.bot)
+			}
+		})
+	}
+}
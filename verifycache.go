@@ -0,0 +1,151 @@
+package gogobot
+
+import (
+	"context"
+	"net/netip"
+	"sync/atomic"
+	"time"
+)
+
+// Default sizing for the shared verification cache
+const (
+	DefaultVerificationCacheSize   = 10000
+	DefaultVerificationCacheTTL    = time.Hour
+	DefaultVerificationNegativeTTL = 5 * time.Minute
+)
+
+// VerificationCache is a bounded TTL+LRU cache for the DNS lookups performed during crawler
+// verification, so verifying a crawler does not add a DNS round-trip to every request.
+// Failed lookups are cached for a shorter negative TTL.
+type VerificationCache struct {
+	entries     *lruCache[string, verificationCacheEntry]
+	ttl         time.Duration
+	negativeTTL time.Duration
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	now         func() time.Time
+}
+
+// VerificationCacheStats holds hit/miss statistics for a VerificationCache
+type VerificationCacheStats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+}
+
+// HitRate returns the fraction of lookups served from the cache
+func (s VerificationCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type verificationCacheEntry struct {
+	names   []string
+	addrs   []netip.Addr
+	err     error
+	expires time.Time
+}
+
+// activeVerificationCache is shared by all crawler verification paths
+var activeVerificationCache atomic.Pointer[VerificationCache]
+
+func init() {
+	activeVerificationCache.Store(NewVerificationCache(DefaultVerificationCacheSize, DefaultVerificationCacheTTL, DefaultVerificationNegativeTTL))
+}
+
+// NewVerificationCache creates a cache holding up to capacity lookups. Successful lookups are
+// kept for ttl and failed lookups for negativeTTL; a zero negativeTTL disables negative caching.
+func NewVerificationCache(capacity int, ttl, negativeTTL time.Duration) *VerificationCache {
+	return &VerificationCache{
+		entries:     newLRUCache[string, verificationCacheEntry](capacity),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+	}
+}
+
+// SetVerificationCache installs the cache shared by crawler verification. Passing nil disables caching.
+func SetVerificationCache(cache *VerificationCache) {
+	activeVerificationCache.Store(cache)
+}
+
+// GetVerificationCache returns the installed verification cache, or nil if caching is disabled
+func GetVerificationCache() *VerificationCache {
+	return activeVerificationCache.Load()
+}
+
+// Stats returns the current hit/miss statistics
+func (c *VerificationCache) Stats() VerificationCacheStats {
+	return VerificationCacheStats{
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Size:     c.entries.len(),
+		Capacity: c.entries.capacity,
+	}
+}
+
+// Purge removes all cached entries and resets the statistics
+func (c *VerificationCache) Purge() {
+	c.entries.purge()
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+func (c *VerificationCache) get(key string) (verificationCacheEntry, bool) {
+	entry, ok := c.entries.get(key)
+	if !ok || !c.now().Before(entry.expires) {
+		c.misses.Add(1)
+		return verificationCacheEntry{}, false
+	}
+	c.hits.Add(1)
+	return entry, true
+}
+
+func (c *VerificationCache) set(key string, entry verificationCacheEntry) {
+	ttl := c.ttl
+	if entry.err != nil {
+		ttl = c.negativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	entry.expires = c.now().Add(ttl)
+	c.entries.set(key, entry)
+}
+
+// cachingResolver serves DNS lookups from a VerificationCache
+type cachingResolver struct {
+	next  DNSResolver
+	cache *VerificationCache
+}
+
+func (r cachingResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	key := "ptr:" + addr
+	if entry, ok := r.cache.get(key); ok {
+		return entry.names, entry.err
+	}
+
+	names, err := r.next.LookupAddr(ctx, addr)
+	if ctx.Err() == nil {
+		// Lookups cut short by the caller's deadline say nothing about the record
+		r.cache.set(key, verificationCacheEntry{names: names, err: err})
+	}
+	return names, err
+}
+
+func (r cachingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	key := "ip:" + network + ":" + host
+	if entry, ok := r.cache.get(key); ok {
+		return entry.addrs, entry.err
+	}
+
+	addrs, err := r.next.LookupNetIP(ctx, network, host)
+	if ctx.Err() == nil {
+		r.cache.set(key, verificationCacheEntry{addrs: addrs, err: err})
+	}
+	return addrs, err
+}
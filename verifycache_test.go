@@ -0,0 +1,94 @@
+package gogobot
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestVerificationCache_SharedLookups(t *testing.T) {
+	cache := NewVerificationCache(100, time.Hour, time.Minute)
+	SetVerificationCache(cache)
+	defer SetVerificationCache(NewVerificationCache(DefaultVerificationCacheSize, DefaultVerificationCacheTTL, DefaultVerificationNegativeTTL))
+
+	dns := newFakeCrawlerDNS()
+	verifier := &CrawlerVerifier{Resolver: dns}
+	addr := netip.MustParseAddr("66.249.66.1")
+
+	for i := 0; i < 3; i++ {
+		verification, _ := verifier.Verify(context.Background(), googlebotUA, addr)
+		if !verification.Verified {
+			t.Fatalf("Expected verified crawler on attempt %d", i)
+		}
+	}
+	if dns.lookups != 2 {
+		t.Errorf("Expected 2 DNS lookups (PTR + forward), got %d", dns.lookups)
+	}
+
+	// A second verifier shares the cache
+	other := &CrawlerVerifier{Resolver: dns}
+	other.Verify(context.Background(), googlebotUA, addr)
+	if dns.lookups != 2 {
+		t.Errorf("Expected cached lookups across verifiers, got %d lookups", dns.lookups)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 6 || stats.Misses != 2 || stats.Size != 2 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if stats.HitRate() != 0.75 {
+		t.Errorf("Expected hit rate 0.75, got %f", stats.HitRate())
+	}
+}
+
+func TestVerificationCache_TTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewVerificationCache(100, time.Hour, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	dns := newFakeCrawlerDNS()
+	resolver := cachingResolver{next: dns, cache: cache}
+	ctx := context.Background()
+
+	// Negative results are cached for the negative TTL
+	if _, err := resolver.LookupAddr(ctx, "192.0.2.1"); err == nil {
+		t.Fatal("Expected lookup error")
+	}
+	resolver.LookupAddr(ctx, "192.0.2.1")
+	if dns.lookups != 1 {
+		t.Errorf("Expected negative result to be cached, got %d lookups", dns.lookups)
+	}
+	now = now.Add(2 * time.Minute)
+	resolver.LookupAddr(ctx, "192.0.2.1")
+	if dns.lookups != 2 {
+		t.Errorf("Expected negative entry to expire, got %d lookups", dns.lookups)
+	}
+
+	// Positive results live for the full TTL
+	resolver.LookupAddr(ctx, "66.249.66.1")
+	now = now.Add(30 * time.Minute)
+	resolver.LookupAddr(ctx, "66.249.66.1")
+	if dns.lookups != 3 {
+		t.Errorf("Expected positive result to be cached, got %d lookups", dns.lookups)
+	}
+	now = now.Add(time.Hour)
+	resolver.LookupAddr(ctx, "66.249.66.1")
+	if dns.lookups != 4 {
+		t.Errorf("Expected positive entry to expire, got %d lookups", dns.lookups)
+	}
+}
+
+func TestVerificationCache_CancelledLookupNotCached(t *testing.T) {
+	cache := NewVerificationCache(100, time.Hour, time.Minute)
+	dns := newFakeCrawlerDNS()
+	resolver := cachingResolver{next: dns, cache: cache}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resolver.LookupAddr(ctx, "192.0.2.1")
+
+	if cache.Stats().Size != 0 {
+		t.Error("Expected lookups under a cancelled context not to be cached")
+	}
+}
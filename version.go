@@ -0,0 +1,176 @@
+package gogobot
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareBrowserVersions compares two browser version strings.
+// Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2.
+//
+// Numeric components are compared numerically with missing components treated as zero
+// ("1.0" == "1.0.0"). Build metadata after "+" is ignored. Pre-release labels, whether
+// introduced by "-", whitespace ("120.0.6099.224 beta") or a letter suffix ("17.1b1"),
+// sort before the corresponding release and are compared semver-style.
+func CompareBrowserVersions(v1, v2 string) int {
+	return compareVersions(v1, v2)
+}
+
+// compareVersions compares two version strings
+// Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
+func compareVersions(v1, v2 string) int {
+	if v1 == v2 {
+		return 0
+	}
+
+	p1 := parseVersion(v1)
+	p2 := parseVersion(v2)
+
+	maxLen := len(p1.core)
+	if len(p2.core) > maxLen {
+		maxLen = len(p2.core)
+	}
+
+	for i := 0; i < maxLen; i++ {
+		var n1, n2 int
+		if i < len(p1.core) {
+			n1 = p1.core[i]
+		}
+		if i < len(p2.core) {
+			n2 = p2.core[i]
+		}
+
+		if n1 < n2 {
+			return -1
+		}
+		if n1 > n2 {
+			return 1
+		}
+	}
+
+	return comparePrerelease(p1.pre, p2.pre)
+}
+
+// parsedVersion holds the numeric core and pre-release identifiers of a version string
+type parsedVersion struct {
+	core []int
+	pre  []string
+}
+
+// parseVersion splits a version string into numeric components and pre-release identifiers
+func parseVersion(v string) parsedVersion {
+	v = strings.ToLower(strings.TrimSpace(v))
+	v = strings.TrimPrefix(v, "v")
+
+	// Build metadata never affects precedence
+	v, _, _ = strings.Cut(v, "+")
+
+	var pre []string
+	if i := strings.IndexAny(v, " -_"); i >= 0 {
+		pre = splitPrerelease(v[i+1:])
+		v = v[:i]
+	}
+
+	var parsed parsedVersion
+	if v == "" {
+		parsed.pre = pre
+		return parsed
+	}
+
+	parts := strings.Split(v, ".")
+	for i, part := range parts {
+		digits := leadingDigits(part)
+		n, _ := strconv.Atoi(digits)
+		parsed.core = append(parsed.core, n)
+
+		// A non-numeric suffix ("1b1") starts the pre-release section
+		if len(digits) < len(part) {
+			suffix := append([]string{part[len(digits):]}, parts[i+1:]...)
+			parsed.pre = append(splitPrerelease(strings.Join(suffix, ".")), pre...)
+			return parsed
+		}
+	}
+
+	parsed.pre = pre
+	return parsed
+}
+
+// leadingDigits returns the leading run of ASCII digits in s
+func leadingDigits(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// splitPrerelease splits a pre-release label into identifiers at separators and
+// letter/digit boundaries, so "beta2" compares before "beta10"
+func splitPrerelease(s string) []string {
+	var ids []string
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_' || r == ' '
+	}) {
+		start := 0
+		for i := 1; i < len(field); i++ {
+			if isDigit(field[i]) != isDigit(field[i-1]) {
+				ids = append(ids, field[start:i])
+				start = i
+			}
+		}
+		ids = append(ids, field[start:])
+	}
+	return ids
+}
+
+// comparePrerelease compares pre-release identifiers; a release sorts after any pre-release
+func comparePrerelease(pre1, pre2 []string) int {
+	switch {
+	case len(pre1) == 0 && len(pre2) == 0:
+		return 0
+	case len(pre1) == 0:
+		return 1
+	case len(pre2) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(pre1) && i < len(pre2); i++ {
+		id1, id2 := pre1[i], pre2[i]
+		num1, err1 := strconv.Atoi(id1)
+		num2, err2 := strconv.Atoi(id2)
+
+		switch {
+		case err1 == nil && err2 == nil:
+			if num1 != num2 {
+				if num1 < num2 {
+					return -1
+				}
+				return 1
+			}
+		case err1 == nil:
+			// Numeric identifiers have lower precedence than alphanumeric ones
+			return -1
+		case err2 == nil:
+			return 1
+		default:
+			if c := strings.Compare(id1, id2); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(pre1) < len(pre2):
+		return -1
+	case len(pre1) > len(pre2):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// isDigit reports whether b is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,43 @@
+package gogobot
+
+import (
+	"testing"
+)
+
+func TestCompareBrowserVersions(t *testing.T) {
+	tests := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"1.0", "1.0.0", 0},
+		{"17.1", "17.1b1", 1},
+		{"17.1b1", "17.1b2", -1},
+		{"17.1b2", "17.1", -1},
+		{"17.1b1", "17.0", 1},
+		{"120.0.6099.224 beta", "120.0.6099.224", -1},
+		{"120.0.6099.224 beta", "120.0.6099.223", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta2", "1.0.0-beta10", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0+build.5", "1.0.0+build.9", 0},
+		{"v2.1", "2.1.0", 0},
+		{"121.0a1", "120.0.6099.224", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.v1+"_vs_"+tt.v2, func(t *testing.T) {
+			result := CompareBrowserVersions(tt.v1, tt.v2)
+			if result != tt.expected {
+				t.Errorf("CompareBrowserVersions(%s, %s) = %d, expected %d", tt.v1, tt.v2, result, tt.expected)
+			}
+
+			// Comparison must be antisymmetric
+			if reverse := CompareBrowserVersions(tt.v2, tt.v1); reverse != -tt.expected {
+				t.Errorf("CompareBrowserVersions(%s, %s) = %d, expected %d", tt.v2, tt.v1, reverse, -tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package gogobot
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedAnomaly describes a suspicious property of an X-Forwarded-For chain
+type ForwardedAnomaly string
+
+const (
+	// ForwardedAnomalyMalformed means a hop is not an IP address
+	ForwardedAnomalyMalformed ForwardedAnomaly = "malformed"
+	// ForwardedAnomalyTooLong means the chain has more hops than any real proxy path
+	ForwardedAnomalyTooLong ForwardedAnomaly = "too_long"
+	// ForwardedAnomalyDuplicate means the same address appears more than once
+	ForwardedAnomalyDuplicate ForwardedAnomaly = "duplicate"
+	// ForwardedAnomalyPrivateHop means a private address sits between public hops
+	ForwardedAnomalyPrivateHop ForwardedAnomaly = "private_hop"
+	// ForwardedAnomalyForgedLocal means a loopback, unspecified, multicast or reserved address
+	// arrived from a public peer, typically to impersonate a request from the server itself
+	ForwardedAnomalyForgedLocal ForwardedAnomaly = "forged_local"
+)
+
+// maxForwardedHops is the longest X-Forwarded-For chain considered plausible
+const maxForwardedHops = 10
+
+// forwardedAnomalyWeights is the score contributed by each anomaly
+var forwardedAnomalyWeights = map[ForwardedAnomaly]float64{
+	ForwardedAnomalyMalformed:   0.3,
+	ForwardedAnomalyTooLong:     0.4,
+	ForwardedAnomalyDuplicate:   0.3,
+	ForwardedAnomalyPrivateHop:  0.3,
+	ForwardedAnomalyForgedLocal: 0.6,
+}
+
+// ForwardedForAnomalies inspects an X-Forwarded-For chain received from peer, the address
+// that connected to the server. Private hops are normal next to the peer (internal load
+// balancers) and at the origin (corporate proxies), so they are only reported when
+// sandwiched between public hops.
+func ForwardedForAnomalies(chain []string, peer netip.Addr) []ForwardedAnomaly {
+	found := make(map[ForwardedAnomaly]bool)
+	if len(chain) > maxForwardedHops {
+		found[ForwardedAnomalyTooLong] = true
+	}
+
+	publicPeer := IsPublicIP(peer)
+	seen := make(map[netip.Addr]bool, len(chain))
+	hops := make([]netip.Addr, 0, len(chain))
+	for _, value := range chain {
+		if strings.EqualFold(value, "unknown") {
+			continue
+		}
+		addr, err := parseIP(value)
+		if err != nil {
+			found[ForwardedAnomalyMalformed] = true
+			continue
+		}
+		if seen[addr] {
+			found[ForwardedAnomalyDuplicate] = true
+		}
+		seen[addr] = true
+		if publicPeer && isForgedLocalAddr(addr) {
+			found[ForwardedAnomalyForgedLocal] = true
+		}
+		hops = append(hops, addr)
+	}
+
+	for i := 1; i < len(hops)-1; i++ {
+		if isPrivateHop(hops[i]) && IsPublicIP(hops[i-1]) && IsPublicIP(hops[i+1]) {
+			found[ForwardedAnomalyPrivateHop] = true
+		}
+	}
+
+	// Report in a stable order
+	var anomalies []ForwardedAnomaly
+	for _, anomaly := range []ForwardedAnomaly{
+		ForwardedAnomalyMalformed,
+		ForwardedAnomalyTooLong,
+		ForwardedAnomalyDuplicate,
+		ForwardedAnomalyPrivateHop,
+		ForwardedAnomalyForgedLocal,
+	} {
+		if found[anomaly] {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	return anomalies
+}
+
+// isPrivateHop reports whether addr belongs to a private or carrier-grade NAT network
+func isPrivateHop(addr netip.Addr) bool {
+	class := ClassifyIP(addr)
+	return class == IPClassPrivate || class == IPClassSharedNAT
+}
+
+// isForgedLocalAddr reports whether addr can never be a genuine remote client
+func isForgedLocalAddr(addr netip.Addr) bool {
+	switch ClassifyIP(addr) {
+	case IPClassLoopback, IPClassUnspecified, IPClassMulticast, IPClassLinkLocal, IPClassReserved:
+		return true
+	default:
+		return false
+	}
+}
+
+// getForwardedFor collects all X-Forwarded-For hops, across repeated headers
+func getForwardedFor(req *http.Request) Component[[]string] {
+	chain := forwardedForChain(req.Header)
+	if len(chain) == 0 {
+		return ErrorComponent[[]string]{
+			State: StateNull,
+			Error: "X-Forwarded-For header is missing",
+		}
+	}
+	return SuccessComponent[[]string]{
+		State: StateSuccess,
+		Value: chain,
+	}
+}
+
+// detectForwardedFor scores anomalies in the X-Forwarded-For chain as a weighted signal
+func detectForwardedFor(components *ComponentDict) *BotDetectionResult {
+	if components.ForwardedFor.GetState() != StateSuccess {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	peer, _ := ParseRemoteAddr(components.RemoteAddr.GetValue())
+	anomalies := ForwardedForAnomalies(components.ForwardedFor.GetValue(), peer)
+	if len(anomalies) == 0 {
+		return &BotDetectionResult{Bot: false}
+	}
+
+	unscored := 1.0
+	for _, anomaly := range anomalies {
+		unscored *= 1 - forwardedAnomalyWeights[anomaly]
+	}
+	return &BotDetectionResult{
+		Bot:     false,
+		BotKind: BotKindSpoofedIP,
+		Score:   1 - unscored,
+	}
+}
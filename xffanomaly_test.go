@@ -0,0 +1,84 @@
+package gogobot
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestForwardedForAnomalies(t *testing.T) {
+	publicPeer := netip.MustParseAddr("203.0.113.10")
+	privatePeer := netip.MustParseAddr("10.0.0.5")
+
+	longChain := make([]string, maxForwardedHops+1)
+	for i := range longChain {
+		longChain[i] = netip.AddrFrom4([4]byte{198, 51, 100, byte(i + 1)}).String()
+	}
+
+	tests := []struct {
+		name     string
+		chain    []string
+		peer     netip.Addr
+		expected []ForwardedAnomaly
+	}{
+		{"Single public hop", []string{"198.51.100.7"}, privatePeer, nil},
+		{"Corporate proxy origin", []string{"10.1.2.3", "198.51.100.7"}, privatePeer, nil},
+		{"Internal load balancers", []string{"198.51.100.7", "10.0.0.1", "10.0.0.2"}, privatePeer, nil},
+		{"Unknown hop", []string{"unknown", "198.51.100.7"}, privatePeer, nil},
+		{"Garbage hop", []string{"not-an-ip", "198.51.100.7"}, privatePeer, []ForwardedAnomaly{ForwardedAnomalyMalformed}},
+		{"Absurd chain length", longChain, privatePeer, []ForwardedAnomaly{ForwardedAnomalyTooLong}},
+		{"Duplicated entry", []string{"198.51.100.7", "198.51.100.7"}, privatePeer, []ForwardedAnomaly{ForwardedAnomalyDuplicate}},
+		{"Private between public hops", []string{"198.51.100.7", "192.168.1.1", "198.51.100.8"}, privatePeer, []ForwardedAnomaly{ForwardedAnomalyPrivateHop}},
+		{"Loopback from public peer", []string{"127.0.0.1"}, publicPeer, []ForwardedAnomaly{ForwardedAnomalyForgedLocal}},
+		{"IPv6 loopback from public peer", []string{"::1"}, publicPeer, []ForwardedAnomaly{ForwardedAnomalyForgedLocal}},
+		{"Reserved address from public peer", []string{"240.0.0.1"}, publicPeer, []ForwardedAnomaly{ForwardedAnomalyForgedLocal}},
+		{"Loopback from local proxy", []string{"127.0.0.1"}, netip.MustParseAddr("127.0.0.1"), nil},
+		{
+			"Several anomalies",
+			[]string{"127.0.0.1", "127.0.0.1", "bogus"},
+			publicPeer,
+			[]ForwardedAnomaly{ForwardedAnomalyMalformed, ForwardedAnomalyDuplicate, ForwardedAnomalyForgedLocal},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomalies := ForwardedForAnomalies(tt.chain, tt.peer)
+			if !reflect.DeepEqual(anomalies, tt.expected) {
+				t.Errorf("ForwardedForAnomalies(%v) = %v, want %v", tt.chain, anomalies, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectForwardedFor(t *testing.T) {
+	detector := NewDetector()
+
+	req := createTestRequest("GET", "/admin", map[string]string{
+		"X-Forwarded-For": "127.0.0.1",
+	})
+	req.RemoteAddr = "203.0.113.10:5555"
+	detector.DetectFromRequest(req)
+	detection := detector.GetDetections().ForwardedFor
+	if detection.BotKind != BotKindSpoofedIP || detection.Score != forwardedAnomalyWeights[ForwardedAnomalyForgedLocal] {
+		t.Errorf("Expected forged local signal, got %+v", detection)
+	}
+
+	// Repeated headers are inspected as one chain
+	req = createTestRequest("GET", "/", nil)
+	req.Header.Add("X-Forwarded-For", "198.51.100.7")
+	req.Header.Add("X-Forwarded-For", strings.Repeat("198.51.100.8, ", maxForwardedHops)+"198.51.100.9")
+	req.RemoteAddr = "10.0.0.5:5555"
+	detector.DetectFromRequest(req)
+	if detection := detector.GetDetections().ForwardedFor; detection.Score < 0.5 {
+		t.Errorf("Expected long duplicated chain to score highly, got %+v", detection)
+	}
+
+	// No header, no signal
+	req = createTestRequest("GET", "/", nil)
+	detector.DetectFromRequest(req)
+	if detection := detector.GetDetections().ForwardedFor; detection.Score != 0 {
+		t.Errorf("Expected no signal without X-Forwarded-For, got %+v", detection)
+	}
+}